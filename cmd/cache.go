@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
 )
 
@@ -15,11 +24,182 @@ var cacheUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update local questions cache",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		err := config.Get().CheckCacheDir()
+		if err != nil {
+			return err
+		}
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		return leetcode.GetCache(c).Update()
 	},
 }
 
+var cacheDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show questions added, removed or re-rated since the last cache update",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		old, err := leetcode.LoadSnapshot()
+		if err != nil {
+			return err
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		ratings, _ := leetcode.LoadRatings()
+		current := leetcode.NewSnapshot(leetcode.GetCache(c).GetAllQuestions(), ratings)
+		diff := leetcode.DiffSnapshots(old, current)
+		if diff.Empty() {
+			log.Info("no changes since last cache update", "snapshot", old.TakenAt.Format(time.DateTime))
+			return nil
+		}
+		showCacheDiff(cmd, diff)
+		return nil
+	},
+}
+
+func formatDifficulty(q leetcode.QuestionSnapshot) string {
+	if q.Rating > 0 {
+		return fmt.Sprintf("%s (%d)", q.Difficulty, int(q.Rating))
+	}
+	return q.Difficulty
+}
+
+func showCacheDiff(cmd *cobra.Command, diff leetcode.SnapshotDiff) {
+	if config.Get().UsePlainOutput() {
+		for _, q := range diff.Added {
+			cmd.Printf("Added: %s. %s, %s\n", q.FrontendID, q.Title, formatDifficulty(q))
+		}
+		for _, q := range diff.Removed {
+			cmd.Printf("Removed: %s. %s, %s\n", q.FrontendID, q.Title, formatDifficulty(q))
+		}
+		for _, ch := range diff.Changed {
+			cmd.Printf(
+				"Re-rated: %s. %s, %s to %s\n",
+				ch.New.FrontendID, ch.New.Title, formatDifficulty(ch.Old), formatDifficulty(ch.New),
+			)
+		}
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"Change", "#", "Title", "Difficulty"})
+	for _, q := range diff.Added {
+		w.AppendRow(table.Row{config.PassedStyle.Render("Added"), q.FrontendID, q.Title, formatDifficulty(q)})
+	}
+	for _, q := range diff.Removed {
+		w.AppendRow(table.Row{config.FailedStyle.Render("Removed"), q.FrontendID, q.Title, formatDifficulty(q)})
+	}
+	for _, ch := range diff.Changed {
+		w.AppendRow(
+			table.Row{
+				"Re-rated",
+				ch.New.FrontendID,
+				ch.New.Title,
+				formatDifficulty(ch.Old) + " → " + formatDifficulty(ch.New),
+			},
+		)
+	}
+	w.Render()
+}
+
+var cacheRatingsCmd = &cobra.Command{
+	Use:   "ratings",
+	Short: "Manage question difficulty ratings",
+}
+
+var cacheRatingsUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download community ratings from zerotrac/leetcode_problem_rating",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ratings, err := leetcode.FetchZerotracRatings()
+		if err != nil {
+			return err
+		}
+		err = leetcode.SaveRatings(ratings)
+		if err != nil {
+			return err
+		}
+		log.Info("ratings updated", "count", len(ratings))
+		return nil
+	},
+}
+
+var (
+	ratingsFormat string
+	ratingsMerge  bool
+)
+
+var cacheRatingsImportCmd = &cobra.Command{
+	Use:   "import file",
+	Short: "Import a ratings dataset that maps question ids or slugs to ratings",
+	Example: `leetgo cache ratings import ratings.csv
+leetgo cache ratings import ratings.txt --format tsv --merge`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := ratingsFormat
+		if format == "" {
+			format = ratingsFormatFromExt(args[0])
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		cache := leetcode.GetCache(c)
+		ratings, err := leetcode.ImportRatings(
+			f, format, func(id string) string {
+				if q := cache.GetById(id); q != nil {
+					return q.TitleSlug
+				}
+				return ""
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if ratingsMerge {
+			ratings, err = leetcode.MergeWithSaved(ratings)
+			if err != nil {
+				return err
+			}
+		}
+		err = leetcode.SaveRatings(ratings)
+		if err != nil {
+			return err
+		}
+		log.Info("ratings imported", "count", len(ratings))
+		return nil
+	},
+}
+
+func ratingsFormatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".tsv", ".txt":
+		return "tsv"
+	default:
+		return "csv"
+	}
+}
+
 func init() {
+	cacheRatingsImportCmd.Flags().StringVar(&ratingsFormat, "format", "", "format of the dataset: csv, tsv or json, guessed from the file extension by default")
+	cacheRatingsImportCmd.Flags().BoolVar(&ratingsMerge, "merge", false, "merge with existing ratings instead of replacing them")
+	_ = cacheRatingsImportCmd.RegisterFlagCompletionFunc(
+		"format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"csv", "tsv", "json"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	cacheRatingsCmd.AddCommand(cacheRatingsUpdateCmd)
+	cacheRatingsCmd.AddCommand(cacheRatingsImportCmd)
 	cacheCmd.AddCommand(cacheUpdateCmd)
+	cacheCmd.AddCommand(cacheDiffCmd)
+	cacheCmd.AddCommand(cacheRatingsCmd)
 }
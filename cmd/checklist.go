@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var errSubmitCancelled = errors.New("submission cancelled by checklist")
+
+// checklist asks the configured pre-submit checklist once for a batch of submissions,
+// and logs the answers to the note of each submitted question.
+type checklist struct {
+	items   []string
+	asked   bool
+	answers []bool
+	err     error
+}
+
+// newChecklist returns the checklist of the config, which is empty with --yes or in safe mode.
+func newChecklist() *checklist {
+	if viper.GetBool("yes") || config.SafeMode() {
+		return &checklist{}
+	}
+	return &checklist{items: config.Get().Submit.Checklist}
+}
+
+func (c *checklist) run(q *leetcode.QuestionData) error {
+	if len(c.items) == 0 {
+		return nil
+	}
+	if !c.asked {
+		c.asked = true
+		c.answers, c.err = askChecklist(c.items)
+	}
+	if c.answers != nil {
+		err := logChecklist(q, c.items, c.answers)
+		if err != nil {
+			log.Warn("failed to log checklist answers", "err", err)
+		}
+	}
+	return c.err
+}
+
+func askChecklist(items []string) ([]bool, error) {
+	answers := make([]bool, len(items))
+	allChecked := true
+	for i, item := range items {
+		err := askOne(&survey.Confirm{Message: item}, &answers[i])
+		if err != nil {
+			return nil, err
+		}
+		allChecked = allChecked && answers[i]
+	}
+	if allChecked {
+		return answers, nil
+	}
+
+	proceed := false
+	err := askOne(&survey.Confirm{Message: i18n.T("Not all items are checked, submit anyway?")}, &proceed)
+	if err != nil {
+		return nil, err
+	}
+	if !proceed {
+		return answers, errSubmitCancelled
+	}
+	return answers, nil
+}
+
+// logChecklist appends the answers to the note of the question, which is created if not exists.
+func logChecklist(q *leetcode.QuestionData, items []string, answers []bool) error {
+	file := config.Get().NoteFile(q.TitleSlug)
+	if !utils.IsExist(file) {
+		err := utils.WriteFile(file, []byte(noteHeader(q)))
+		if err != nil {
+			return err
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n## Pre-submit checklist (%s)\n\n", time.Now().Format(time.DateTime)))
+	for i, item := range items {
+		mark := " "
+		if answers[i] {
+			mark = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", mark, item))
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(sb.String())
+	return err
+}
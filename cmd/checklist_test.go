@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestChecklistAskedOncePerBatch(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	chdir(t, t.TempDir())
+	asked := 0
+	old := askOne
+	askOne = func(p survey.Prompt, response any, _ ...survey.AskOpt) error {
+		asked++
+		// The edge cases are not checked, submitting anyway is declined.
+		*response.(*bool) = p.(*survey.Confirm).Message == "Tested locally?"
+		return nil
+	}
+	t.Cleanup(func() { askOne = old })
+
+	c := leetcode.NewClient(leetcode.NonAuth())
+	checks := &checklist{items: []string{"Tested locally?", "Edge cases?"}}
+	for _, slug := range []string{"two-sum", "add-two-numbers"} {
+		q := &leetcode.QuestionData{TitleSlug: slug}
+		q.SetClient(c)
+		if err := checks.run(q); err != errSubmitCancelled {
+			t.Errorf("%s: run() = %v, want errSubmitCancelled", slug, err)
+		}
+		note, _ := os.ReadFile(config.Get().NoteFile(slug))
+		if !strings.Contains(string(note), "- [x] Tested locally?\n- [ ] Edge cases?\n") {
+			t.Errorf("%s: note = %q, want the answers logged", slug, note)
+		}
+	}
+	if asked != 3 {
+		t.Errorf("asked %d questions, want the checklist asked once", asked)
+	}
+
+	if err := (&checklist{}).run(&leetcode.QuestionData{TitleSlug: "two-sum"}); err != nil || asked != 3 {
+		t.Errorf("empty checklist: run() = %v, asked %d", err, asked)
+	}
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// archiveDir is the directory under the project root to move files of unsolved questions to.
+const archiveDir = "archive"
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "List, archive or delete generated files of questions never solved",
+	Long: `Find the questions generated in the current language that have never been accepted, neither by leetgo nor on LeetCode,
+and list them, move their files to the archive directory in the project root with --archive, or delete them with --delete.
+
+Questions submitted but not accepted yet are kept, use --attempted to include them.
+Files are moved or deleted along with the directory of the question if the language generates one.`,
+	Example: `leetgo clean
+leetgo clean --older-than 30d --archive
+leetgo clean --attempted --delete`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		attempted, _ := cmd.Flags().GetBool("attempted")
+		archive, _ := cmd.Flags().GetBool("archive")
+		remove, _ := cmd.Flags().GetBool("delete")
+
+		var age time.Duration
+		if olderThan != "" {
+			var err error
+			age, err = utils.ParseAge(olderThan)
+			if err != nil {
+				return err
+			}
+		}
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return err
+		}
+		state := config.LoadState()
+		items := []cleanItem{}
+		for _, q := range questionsGeneratedIn(c, gen) {
+			it, ok := newCleanItem(q, state, age, attempted)
+			if ok {
+				items = append(items, it)
+			}
+		}
+
+		if !archive && !remove {
+			if config.JSONOutput() {
+				return encodeJSON(cmd, items)
+			}
+			showCleanItems(cmd, items)
+			return nil
+		}
+		if len(items) == 0 {
+			log.Info("no unsolved questions to clean")
+			return nil
+		}
+
+		action := "delete"
+		if archive {
+			action = "archive"
+		}
+		if lang.DryRun() {
+			for _, it := range items {
+				cmd.Printf("would %s %s: %s\n", action, it.Slug, strings.Join(it.Paths, ", "))
+			}
+			return nil
+		}
+		if config.SafeMode() && !viper.GetBool("yes") {
+			return fmt.Errorf("clean: %w, use --yes to confirm", config.ErrSafeMode)
+		} else if !viper.GetBool("yes") {
+			msg := i18n.Sprintf("Delete files of %d unsolved questions?", len(items))
+			if archive {
+				msg = i18n.Sprintf("Archive files of %d unsolved questions to %s?", len(items), archiveDir)
+			}
+			accept := false
+			err = config.AskOne(&survey.Confirm{Message: msg}, &accept)
+			if err != nil || !accept {
+				return err
+			}
+		}
+
+		root := config.Get().ProjectRoot()
+		cleaned := 0
+		for _, it := range items {
+			if archive {
+				err = archiveFiles(root, it.Paths)
+			} else {
+				err = deleteFiles(it.Paths)
+			}
+			if err != nil {
+				log.Error("failed to clean", "action", action, "question", it.Slug, "err", err)
+				continue
+			}
+			if err = lang.DropFromWorkspace(it.result); err != nil {
+				log.Error("failed to update the workspace", "question", it.Slug, "err", err)
+			}
+			state.ForgetGenerated(it.Slug, gen.Slug())
+			cleaned++
+		}
+		config.SaveState(state)
+		log.Info("cleaned unsolved questions", "action", action, "count", cleaned)
+		return nil
+	},
+}
+
+func init() {
+	cleanCmd.Flags().String("older-than", "", "only questions generated longer ago than this, e.g. 30d, 2w or 12h")
+	cleanCmd.Flags().Bool("attempted", false, "include questions submitted but not accepted")
+	cleanCmd.Flags().Bool("archive", false, "move the files to the archive directory in the project root")
+	cleanCmd.Flags().Bool("delete", false, "delete the files")
+	cleanCmd.MarkFlagsMutuallyExclusive("archive", "delete")
+}
+
+// cleanItem is an unsolved question to clean, Paths are the files and directories to move or delete.
+type cleanItem struct {
+	FrontendID  string    `json:"frontend_id"`
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Paths       []string  `json:"paths"`
+	result      *lang.GenerateResult
+}
+
+// newCleanItem returns the question to clean if it has never been accepted and was generated longer than age ago.
+// Questions with unknown generation time are only cleaned without age.
+func newCleanItem(q *leetcode.QuestionData, state config.State, age time.Duration, attempted bool) (cleanItem, bool) {
+	status, ok := cleanStatus(q, state, attempted)
+	if !ok {
+		return cleanItem{}, false
+	}
+	qs, _ := state.Question(q.TitleSlug)
+	if age > 0 && (qs.GeneratedAt.IsZero() || time.Since(qs.GeneratedAt) < age) {
+		return cleanItem{}, false
+	}
+
+	result, err := lang.GeneratePathsOnly(q)
+	if err != nil {
+		log.Warn("failed to get generated files", "question", q.TitleSlug, "err", err)
+		return cleanItem{}, false
+	}
+	paths := cleanPaths(result)
+	if len(paths) == 0 {
+		return cleanItem{}, false
+	}
+	return cleanItem{
+		FrontendID:  q.QuestionFrontendId,
+		Slug:        q.TitleSlug,
+		Title:       q.GetTitle(),
+		Status:      status,
+		GeneratedAt: qs.GeneratedAt,
+		Paths:       paths,
+		result:      result,
+	}, true
+}
+
+// cleanStatus returns the status of a question never accepted, "new" or "attempted".
+// Attempted questions are only cleaned with attempted set.
+func cleanStatus(q *leetcode.QuestionData, state config.State, attempted bool) (string, bool) {
+	switch {
+	case questionStatus(q, state) == "accepted":
+		return "", false
+	case state.Submitted(q.TitleSlug) || strings.EqualFold(q.Status, "notac") || strings.EqualFold(q.Status, "TRIED"):
+		return "attempted", attempted
+	default:
+		return "new", true
+	}
+}
+
+// cleanPaths returns the existing files of the question, or the directory of it if the language generates one.
+func cleanPaths(result *lang.GenerateResult) []string {
+	if result.SubDir != "" {
+		if utils.IsExist(result.TargetDir()) {
+			return []string{result.TargetDir()}
+		}
+		return nil
+	}
+	var paths []string
+	for _, f := range result.Files {
+		if utils.IsExist(f.GetPath()) {
+			paths = append(paths, f.GetPath())
+		}
+	}
+	return paths
+}
+
+// archiveFiles moves the paths under the archive directory, keeping their paths relative to the project root.
+func archiveFiles(root string, paths []string) error {
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%s is outside the project", p)
+		}
+		dst := filepath.Join(root, archiveDir, rel)
+		if utils.IsExist(dst) {
+			return fmt.Errorf("%s already exists", dst)
+		}
+		err = utils.MakeDir(filepath.Dir(dst))
+		if err != nil {
+			return err
+		}
+		err = os.Rename(p, dst)
+		if err != nil {
+			return err
+		}
+		log.Info("archived", "from", utils.RelToCwd(p), "to", utils.RelToCwd(dst))
+	}
+	return nil
+}
+
+func deleteFiles(paths []string) error {
+	var errs []error
+	for _, p := range paths {
+		err := os.RemoveAll(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		log.Info("deleted", "path", utils.RelToCwd(p))
+	}
+	return errors.Join(errs...)
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	days := int(time.Since(t).Hours() / 24)
+	if days == 0 {
+		return "today"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
+
+func showCleanItems(cmd *cobra.Command, items []cleanItem) {
+	if config.Get().UsePlainOutput() {
+		for _, it := range items {
+			cmd.Printf("%s. %s, %s, generated %s\n", it.FrontendID, it.Title, it.Status, formatAge(it.GeneratedAt))
+		}
+		cmd.Printf("%d unsolved questions\n", len(items))
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.SetTitle(fmt.Sprintf("%d unsolved questions", len(items)))
+	w.AppendHeader(table.Row{"#", "Title", "Status", "Generated", "Files"})
+	for _, it := range items {
+		paths := make([]string, len(it.Paths))
+		for i, p := range it.Paths {
+			paths[i] = utils.RelToCwd(p)
+		}
+		w.AppendRow(table.Row{it.FrontendID, it.Title, it.Status, formatAge(it.GeneratedAt), strings.Join(paths, "\n")})
+	}
+	w.Render()
+}
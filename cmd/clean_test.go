@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestCleanStatus(t *testing.T) {
+	var state config.State
+	state.MarkGenerated("two-sum", "1", "go")
+	state.MarkGenerated("add-two-numbers", "2", "go")
+	state.MarkAccepted("add-two-numbers", "2", "go", "hash")
+	state.MarkGenerated("median-of-two-sorted-arrays", "4", "go")
+	state.AddSubmission(
+		config.SubmissionRecord{Site: config.Get().LeetCode.Site.Short(), Slug: "median-of-two-sorted-arrays"},
+	)
+
+	cases := []struct {
+		q          *leetcode.QuestionData
+		attempted  bool
+		wantStatus string
+		wantOk     bool
+	}{
+		{&leetcode.QuestionData{TitleSlug: "two-sum"}, false, "new", true},
+		{&leetcode.QuestionData{TitleSlug: "add-two-numbers"}, true, "", false},
+		{&leetcode.QuestionData{TitleSlug: "longest-palindromic-substring", Status: "ac"}, true, "", false},
+		{&leetcode.QuestionData{TitleSlug: "median-of-two-sorted-arrays"}, false, "attempted", false},
+		{&leetcode.QuestionData{TitleSlug: "median-of-two-sorted-arrays"}, true, "attempted", true},
+		{&leetcode.QuestionData{TitleSlug: "reverse-integer", Status: "notac"}, true, "attempted", true},
+	}
+	for _, c := range cases {
+		status, ok := cleanStatus(c.q, state, c.attempted)
+		if ok != c.wantOk || ok && status != c.wantStatus {
+			t.Errorf("cleanStatus(%s, %v) = %q, %v, want %q, %v", c.q.TitleSlug, c.attempted, status, ok, c.wantStatus, c.wantOk)
+		}
+	}
+}
+
+func TestCleanPaths(t *testing.T) {
+	dir := t.TempDir()
+	code := filepath.Join(dir, "0001.two-sum.go")
+	if err := os.WriteFile(code, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result := &lang.GenerateResult{OutDir: dir}
+	result.AddFile(lang.FileOutput{Filename: "0001.two-sum.go", Type: lang.CodeFile})
+	result.AddFile(lang.FileOutput{Filename: "0001.two-sum.txt", Type: lang.TestCasesFile})
+	if got := cleanPaths(result); !slices.Equal(got, []string{code}) {
+		t.Errorf("cleanPaths() = %v, want only the existing file", got)
+	}
+
+	result = &lang.GenerateResult{OutDir: dir, SubDir: "0001.two-sum"}
+	if got := cleanPaths(result); len(got) != 0 {
+		t.Errorf("cleanPaths() = %v, want nothing for a missing directory", got)
+	}
+	if err := os.Mkdir(result.TargetDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := cleanPaths(result); !slices.Equal(got, []string{result.TargetDir()}) {
+		t.Errorf("cleanPaths() = %v, want the question directory", got)
+	}
+}
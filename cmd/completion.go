@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// cachedQuestions returns the questions in the local cache, without any network request.
+// Completion runs without the pre-run hooks, so the configuration is loaded here, which reads files only.
+func cachedQuestions() []*leetcode.QuestionData {
+	_ = config.Load(false)
+	c := leetcode.NewClient(leetcode.NonAuth())
+	return leetcode.GetCache(c).GetAllQuestions()
+}
+
+// completeQuestions completes slugs and frontend ids of questions from the local cache, along with the special qids.
+// At most maxArgs questions are completed, -1 means no limit.
+func completeQuestions(maxArgs int, special ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var completions []string
+		for _, s := range special {
+			if strings.HasPrefix(s, toComplete) {
+				completions = append(completions, s)
+			}
+		}
+		if toComplete == "" {
+			// Listing all questions is too noisy, wait for the first character.
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		_, err := strconv.Atoi(toComplete)
+		byId := err == nil
+		for _, q := range cachedQuestions() {
+			switch {
+			case byId && strings.HasPrefix(q.QuestionFrontendId, toComplete):
+				completions = append(completions, q.QuestionFrontendId+"\t"+q.GetTitle())
+			case !byId && strings.HasPrefix(q.TitleSlug, toComplete):
+				completions = append(completions, q.TitleSlug+"\t"+q.QuestionFrontendId+". "+q.GetTitle())
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTags completes tag slugs of the questions in the local cache.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var tags []string
+	for _, q := range cachedQuestions() {
+		for _, tag := range q.TagSlugs() {
+			if strings.HasPrefix(tag, toComplete) && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContests completes contest slugs known locally: the last generated contest and the virtual contest,
+// along with "last". Upcoming contests are completed too if upcoming is true, which requires a network request.
+func completeContests(upcoming bool) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		_ = config.Load(false)
+		state := config.LoadState()
+		slugs := []string{"last"}
+		if state.LastContest != "" {
+			slugs = append(slugs, state.LastContest)
+		}
+		if v := state.Virtual; v != nil && v.Site == config.Get().LeetCode.Site.Short() {
+			slugs = append(slugs, v.Slug)
+		}
+		if upcoming {
+			list, _ := getUpcomingContests()
+			slugs = append(slugs, list...)
+		}
+
+		var completions []string
+		for _, slug := range slugs {
+			if strings.HasPrefix(slug, toComplete) && !slices.Contains(completions, slug) {
+				completions = append(completions, slug)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeCompanyPeriods completes the recent periods of company questions.
+func completeCompanyPeriods(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	periods := make([]string, 0, len(leetcode.CompanyPeriods))
+	for _, p := range leetcode.CompanyPeriods {
+		periods = append(periods, p.Name)
+	}
+	return periods, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+)
+
+func TestCompleteContests(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	t.Setenv("LEETGO_STATE_DIR", t.TempDir())
+	config.SaveState(
+		config.State{
+			LastContest: "weekly-contest-330",
+			Virtual: &config.VirtualContest{
+				Site: config.Get().LeetCode.Site.Short(),
+				Slug: "biweekly-contest-100",
+			},
+		},
+	)
+
+	cases := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"last", "weekly-contest-330", "biweekly-contest-100"}},
+		{"w", []string{"weekly-contest-330"}},
+		{"la", []string{"last"}},
+		{"x", nil},
+	}
+	complete := completeContests(false)
+	for _, c := range cases {
+		got, _ := complete(contestVirtualCmd, nil, c.toComplete)
+		if !slices.Equal(got, c.want) {
+			t.Errorf("completeContests(%q) = %q, want %q", c.toComplete, got, c.want)
+		}
+	}
+	if got, _ := complete(contestVirtualCmd, []string{"w330"}, ""); got != nil {
+		t.Errorf("completeContests() after the qid = %q, want nothing", got)
+	}
+}
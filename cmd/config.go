@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration",
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate filename templates and preview the generated files of a sample question",
+	Example: `leetgo config check
+leetgo config check -l rust`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Language: %s\n", gen.Name())
+
+		var invalid bool
+		samples := []struct {
+			name string
+			q    *leetcode.QuestionData
+		}{
+			{"Question", leetcode.SampleQuestion()},
+			{"Contest question", leetcode.SampleContestQuestion()},
+		}
+		for _, s := range samples {
+			result, err := lang.GeneratePathsOnly(s.q)
+			if err != nil {
+				cmd.Printf("%s: %s\n", s.name, config.FailedStyle.Render(err.Error()))
+				invalid = true
+				continue
+			}
+			cmd.Printf("%s %s:\n", s.name, s.q.TitleSlug)
+			for _, f := range result.Files {
+				path := f.GetPath()
+				line := fmt.Sprintf("  %-9s %s", f.Type, utils.RelToCwd(path))
+				if err := validatePath(path); err != nil {
+					line += "  " + config.FailedStyle.Render(err.Error())
+					invalid = true
+				}
+				cmd.Println(line)
+			}
+		}
+		if invalid {
+			return errors.New("invalid filename template")
+		}
+		return nil
+	},
+}
+
+var showOrigin bool
+
+// flagKeys are the configuration keys that can be overridden by global flags.
+var flagKeys = map[string]string{
+	"code.lang":     "lang",
+	"leetcode.site": "site",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long: `Show the effective configuration, merged from the global config file, the project config file and LEETGO_* environment variables.
+
+Values from later sources take precedence, e.g. LEETGO_CODE_LANG overrides code.lang in config files.`,
+	Example: `leetgo config show
+leetgo config show --origin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if !showOrigin {
+			return cfg.Write(cmd.OutOrStdout(), false)
+		}
+		for _, key := range config.Keys() {
+			origin := config.Origin(key)
+			switch origin {
+			case config.OriginEnv:
+				origin = "env " + config.EnvName(key)
+			case config.OriginGlobal:
+				origin = utils.RelToCwd(cfg.GlobalConfigFile())
+			case config.OriginProject:
+				origin = utils.RelToCwd(cfg.ConfigFile())
+			}
+			if flag, ok := flagKeys[key]; ok && rootCmd.PersistentFlags().Changed(flag) {
+				origin = "flag --" + flag
+			}
+			cmd.Printf("%s: %s  (%s)\n", key, formatValue(viper.Get(key)), origin)
+		}
+		return nil
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move cache and state files of older versions to their current locations",
+	Long: `Move cache and state files of older versions under ~/.config/leetgo to the XDG base directories:
+cache files to $XDG_CACHE_HOME/leetgo (~/.cache/leetgo), the state file to $XDG_STATE_HOME/leetgo (~/.local/state/leetgo).
+LEETGO_CONFIG_DIR, LEETGO_CACHE_DIR and LEETGO_STATE_DIR override these directories.
+Nothing is moved if LEETGO_HOME is set, everything stays under it.
+
+Then the question cache and snapshot files shared by both sites are renamed to the per-site names of the configured site,
+and notes directly in notes_dir are moved to the subdirectory of the configured site.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		moves, err := cfg.MigrateDirs(lang.DryRun())
+		if err == nil {
+			var siteMoves []config.Move
+			siteMoves, err = cfg.MigrateSiteFiles(lang.DryRun())
+			moves = append(moves, siteMoves...)
+		}
+		for _, m := range moves {
+			cmd.Printf("%s -> %s\n", m.From, m.To)
+		}
+		if err != nil {
+			return err
+		}
+		if len(moves) == 0 {
+			cmd.Println("Nothing to migrate")
+		}
+		return nil
+	},
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// invalidFilenameChars are not allowed in filenames on Windows.
+const invalidFilenameChars = `<>:"|?*`
+
+// validatePath checks the path rendered from templates is usable on all platforms.
+func validatePath(path string) error {
+	// Skip the volume name of absolute paths on Windows, e.g. C:
+	path = path[len(filepath.VolumeName(path)):]
+	for _, part := range strings.Split(filepath.ToSlash(path), "/")[1:] {
+		switch {
+		case part == "":
+			return errors.New("empty path segment")
+		case strings.TrimSpace(part) != part:
+			return fmt.Errorf("leading or trailing spaces in %q", part)
+		case strings.ContainsAny(part, invalidFilenameChars):
+			return fmt.Errorf("invalid characters in %q", part)
+		}
+	}
+	return nil
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&showOrigin, "origin", false, "show where each value comes from")
+
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configMigrateCmd)
+}
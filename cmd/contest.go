@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,11 +14,12 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/cli/browser"
 	"github.com/hako/durafmt"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
-	"github.com/j178/leetgo/editor"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 )
@@ -32,6 +34,9 @@ var (
 )
 
 func selectUpcomingContest(c leetcode.Client, registeredOnly bool) (string, error) {
+	if config.SafeMode() {
+		return "", fmt.Errorf("selecting contest interactively: %w", config.ErrSafeMode)
+	}
 	contestList, err := c.GetUpcomingContests()
 	if err != nil {
 		return "", err
@@ -58,7 +63,7 @@ func selectUpcomingContest(c leetcode.Client, registeredOnly bool) (string, erro
 	for i, ct := range contestList {
 		mark := " "
 		if ct.Registered {
-			mark = "√"
+			mark = config.PassedMark()
 		}
 		contestNames[i] = fmt.Sprintf(
 			"%s %s at %s",
@@ -69,10 +74,10 @@ func selectUpcomingContest(c leetcode.Client, registeredOnly bool) (string, erro
 	}
 	var idx int
 	prompt := &survey.Select{
-		Message: "Select a contest:",
+		Message: i18n.T("Select a contest:"),
 		Options: contestNames,
 	}
-	err = survey.AskOne(prompt, &idx)
+	err = config.AskOne(prompt, &idx)
 	if err != nil {
 		return "", err
 	}
@@ -89,8 +94,8 @@ func waitContestStart(cmd *cobra.Command, ct *leetcode.Contest) error {
 	spin.PreUpdate = func(s *spinner.Spinner) {
 		mu.Lock()
 		defer mu.Unlock()
-		s.Suffix = fmt.Sprintf(
-			" %s begins in %s, waiting...",
+		s.Suffix = " " + i18n.Sprintf(
+			"%s begins in %s, waiting...",
 			contestTitleStyle.Render(ct.Title),
 			timeStyle.Render(durafmt.Parse(ct.TimeTillStart()).LimitFirstN(2).String()),
 		)
@@ -137,15 +142,9 @@ var contestCmd = &cobra.Command{
 leetgo contest w330
 leetgo contest left w330
 `,
-	Aliases: []string{"c"},
-	Args:    cobra.MaximumNArgs(1),
-	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := getUpcomingContests()
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveError
-		}
-		return list, cobra.ShellCompDirectiveNoFileComp
-	},
+	Aliases:           []string{"c"},
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContests(true),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		cfg := config.Get()
@@ -178,7 +177,9 @@ leetgo contest left w330
 
 		if !contest.HasFinished() && !contest.Registered {
 			register := true
-			if !viper.GetBool("yes") {
+			if config.SafeMode() && !viper.GetBool("yes") {
+				register = false
+			} else if !viper.GetBool("yes") {
 				prompt := survey.Confirm{
 					Message: fmt.Sprintf(
 						"Register for %s as %s?",
@@ -186,7 +187,7 @@ leetgo contest left w330
 						nameStyle.Render(user.Whoami(c)),
 					),
 				}
-				err := survey.AskOne(&prompt, &register)
+				err := config.AskOne(&prompt, &register)
 				if err != nil {
 					return err
 				}
@@ -213,13 +214,12 @@ leetgo contest left w330
 		}
 
 		isSet := cmd.Flags().Lookup("browser").Changed
-		if (isSet && openInBrowser) || (!isSet && cfg.Contest.OpenInBrowser) {
+		if !config.SafeMode() && ((isSet && openInBrowser) || (!isSet && cfg.Contest.OpenInBrowser)) {
 			for _, r := range generated {
 				_ = browser.OpenURL(r.Question.ContestUrl())
 			}
 		}
-		err = editor.Open(generated[0])
-		return err
+		return finishGenerate(cmd, generated...)
 	},
 }
 
@@ -267,6 +267,9 @@ var unregisterCmd = &cobra.Command{
 			return err
 		}
 		unregister := true
+		if config.SafeMode() && !viper.GetBool("yes") {
+			return fmt.Errorf("unregister without --yes: %w", config.ErrSafeMode)
+		}
 		if !viper.GetBool("yes") {
 			prompt := survey.Confirm{
 				Message: fmt.Sprintf(
@@ -275,7 +278,7 @@ var unregisterCmd = &cobra.Command{
 					nameStyle.Render(user.Whoami(c)),
 				),
 			}
-			err = survey.AskOne(&prompt, &unregister)
+			err = config.AskOne(&prompt, &unregister)
 			if err != nil {
 				return err
 			}
@@ -292,7 +295,477 @@ var unregisterCmd = &cobra.Command{
 	},
 }
 
+var difficultyPages int
+
+var contestDifficultyCmd = &cobra.Command{
+	Use:   "difficulty [qid]",
+	Short: "Estimate relative difficulty of contest questions from the standings",
+	Long: `Estimate relative difficulty of contest questions from solve counts of users sampled over the standings.
+Questions are listed from the easiest to the hardest, to help choosing which one to attempt next.
+
+In a running virtual contest, only solves made within the elapsed time of the original contest are counted.`,
+	Example: `leetgo contest difficulty w330
+leetgo contest difficulty last --pages 20`,
+	Aliases:           []string{"stats"},
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContests(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qid := "last"
+		if len(args) > 0 {
+			qid = args[0]
+		}
+		if !strings.HasSuffix(qid, "/") {
+			qid += "/"
+		}
+		contest, _, err := leetcode.ParseContestQID(qid, c, false)
+		if err != nil {
+			return err
+		}
+		if !contest.HasStarted() {
+			return leetcode.ErrContestNotStarted
+		}
+		solves, err := contest.EstimateDifficulty(difficultyPages)
+		if err != nil {
+			return err
+		}
+
+		if config.JSONOutput() {
+			type questionSolves struct {
+				Title     string  `json:"title"`
+				Slug      string  `json:"slug"`
+				Credit    int     `json:"credit"`
+				Solved    int     `json:"solved"`
+				Sampled   int     `json:"sampled"`
+				AvgTimeMs float64 `json:"avg_time_ms"`
+			}
+			result := make([]questionSolves, 0, len(solves))
+			for _, s := range solves {
+				result = append(
+					result, questionSolves{
+						Title:     s.Question.GetTitle(),
+						Slug:      s.Question.TitleSlug,
+						Credit:    s.Credit,
+						Solved:    s.Solved,
+						Sampled:   s.Sampled,
+						AvgTimeMs: float64(s.AvgTime.Milliseconds()),
+					},
+				)
+			}
+			return encodeJSON(cmd, result)
+		}
+
+		showSolves(cmd, contest, solves)
+		return nil
+	},
+}
+
+func showSolves(cmd *cobra.Command, contest *leetcode.Contest, solves []leetcode.QuestionSolves) {
+	type row struct {
+		num     int
+		title   string
+		credit  int
+		solved  string
+		avgTime string
+	}
+	rows := make([]row, 0, len(solves))
+	for _, s := range solves {
+		r := row{title: s.Question.GetTitle(), credit: s.Credit, solved: "0", avgTime: "-"}
+		r.num, _ = contest.GetQuestionNumber(s.Question.TitleSlug)
+		if s.Sampled > 0 {
+			r.solved = fmt.Sprintf("%d/%d (%.0f%%)", s.Solved, s.Sampled, float64(s.Solved)/float64(s.Sampled)*100)
+		}
+		if s.Solved > 0 {
+			r.avgTime = s.AvgTime.Round(time.Second).String()
+		}
+		rows = append(rows, r)
+	}
+
+	if config.Get().UsePlainOutput() {
+		for _, r := range rows {
+			cmd.Printf("Question %d: %s, %d points, solved by %s, average time %s\n", r.num, r.title, r.credit, r.solved, r.avgTime)
+		}
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"#", "Title", "Credit", "Solved", "Avg time"})
+	for _, r := range rows {
+		w.AppendRow(table.Row{r.num, r.title, r.credit, r.solved, r.avgTime})
+	}
+	w.Render()
+}
+
+var (
+	rankFriends  []string
+	rankPages    int
+	rankWatch    bool
+	rankInterval time.Duration
+)
+
+// minRankInterval keeps the watcher from hammering the ranking API.
+const minRankInterval = 5 * time.Second
+
+var contestRankCmd = &cobra.Command{
+	Use:   "rank [qid]",
+	Short: "Show your rank and the ranks of friends in a contest",
+	Long: `Show the rank, score, finish time, penalty and accepted questions of you and your friends in a contest.
+Friends are configured by contest.friends, and looked up by username or user slug from the top of the standings.
+
+With --watch, the standings are refreshed periodically until the contest finishes.`,
+	Example: `leetgo contest rank
+leetgo contest rank w330 --friend alice --friend bob
+leetgo contest rank --watch --interval 1m`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContests(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rankInterval < minRankInterval {
+			return fmt.Errorf("interval must be at least %s", minRankInterval)
+		}
+		if rankWatch && config.JSONOutput() {
+			return errors.New("--watch can't be used with JSON output")
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qid := "last"
+		if len(args) > 0 {
+			qid = args[0]
+		}
+		if !strings.HasSuffix(qid, "/") {
+			qid += "/"
+		}
+		contest, _, err := leetcode.ParseContestQID(qid, c, false)
+		if err != nil {
+			return err
+		}
+		if !contest.HasStarted() {
+			return leetcode.ErrContestNotStarted
+		}
+		questions, err := contest.GetAllQuestions()
+		if err != nil {
+			return err
+		}
+		friends := slices.Concat(config.Get().Contest.Friends, rankFriends)
+
+		for {
+			standings, err := contest.Standings(friends, rankPages)
+			if err != nil {
+				if !rankWatch {
+					return err
+				}
+				log.Warn("failed to get standings", "err", err)
+			} else {
+				if config.JSONOutput() {
+					return encodeJSON(cmd, newStandingsResult(questions, standings))
+				}
+				if rankWatch && !config.Get().UsePlainOutput() {
+					// Clear the screen to redraw the standings in place.
+					cmd.Print("\033[H\033[2J")
+				}
+				showStandings(cmd, contest, questions, standings)
+			}
+			if !rankWatch || contest.HasFinished() {
+				return nil
+			}
+			cmd.Println(timeStyle.Render(fmt.Sprintf("Updated at %s, refreshing every %s", time.Now().Format(time.TimeOnly), rankInterval)))
+			time.Sleep(rankInterval)
+		}
+	},
+}
+
+type userStandingResult struct {
+	Handle     string  `json:"handle"`
+	Found      bool    `json:"found"`
+	Username   string  `json:"username"`
+	Rank       int     `json:"rank"`
+	Score      int     `json:"score"`
+	FinishTime int64   `json:"finish_time"`
+	PenaltyMs  float64 `json:"penalty_ms"`
+	// Accepted maps slugs of accepted questions to the number of wrong submissions.
+	Accepted map[string]int `json:"accepted"`
+}
+
+type standingsResult struct {
+	UserNum int                  `json:"user_num"`
+	Me      *userStandingResult  `json:"me"`
+	Friends []userStandingResult `json:"friends"`
+}
+
+func newStandingsResult(questions []*leetcode.QuestionData, standings *leetcode.ContestStandings) standingsResult {
+	newUser := func(s leetcode.UserStanding) userStandingResult {
+		r := userStandingResult{
+			Handle:     s.Handle,
+			Found:      s.Found,
+			Username:   s.Username,
+			Rank:       s.Rank,
+			Score:      s.Score,
+			FinishTime: s.FinishTime,
+			PenaltyMs:  float64(s.Penalty().Milliseconds()),
+			Accepted:   map[string]int{},
+		}
+		for _, q := range questions {
+			if sub, ok := s.Submissions[q.QuestionId]; ok {
+				r.Accepted[q.TitleSlug] = sub.FailCount
+			}
+		}
+		return r
+	}
+	result := standingsResult{UserNum: standings.UserNum, Friends: []userStandingResult{}}
+	if standings.Me != nil {
+		me := newUser(*standings.Me)
+		result.Me = &me
+	}
+	for _, f := range standings.Friends {
+		result.Friends = append(result.Friends, newUser(f))
+	}
+	return result
+}
+
+func showStandings(
+	cmd *cobra.Command,
+	contest *leetcode.Contest,
+	questions []*leetcode.QuestionData,
+	standings *leetcode.ContestStandings,
+) {
+	users := standings.Friends
+	if standings.Me != nil {
+		users = append([]leetcode.UserStanding{*standings.Me}, users...)
+	}
+	elapsed := func(ts int64) string {
+		return (time.Duration(ts-standings.Start) * time.Second).String()
+	}
+	type row struct {
+		rank     string
+		user     string
+		score    string
+		finish   string
+		penalty  string
+		problems []string
+	}
+	rows := make([]row, 0, len(users))
+	for _, u := range users {
+		r := row{rank: "-", user: u.Handle, score: "-", finish: "-", penalty: "-"}
+		if u.Found {
+			r.rank = fmt.Sprintf("%d/%d", u.Rank, standings.UserNum)
+			r.score = fmt.Sprint(u.Score)
+			r.finish = elapsed(u.FinishTime)
+			r.penalty = u.Penalty().String()
+		}
+		for _, q := range questions {
+			status := "-"
+			if sub, ok := u.Submissions[q.QuestionId]; ok {
+				status = elapsed(sub.Date)
+				if sub.FailCount > 0 {
+					status += fmt.Sprintf(" (%d)", sub.FailCount)
+				}
+			}
+			r.problems = append(r.problems, status)
+		}
+		rows = append(rows, r)
+	}
+
+	if config.Get().UsePlainOutput() {
+		cmd.Printf("%s, %d participants\n", contest.Title, standings.UserNum)
+		for _, r := range rows {
+			cmd.Printf("%s: rank %s, score %s, finish time %s, penalty %s", r.user, r.rank, r.score, r.finish, r.penalty)
+			for i, p := range r.problems {
+				cmd.Printf(", Q%d %s", i+1, p)
+			}
+			cmd.Println()
+		}
+		return
+	}
+
+	cmd.Println(contestTitleStyle.Render(contest.Title))
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	header := table.Row{"Rank", "User", "Score", "Finish", "Penalty"}
+	for i := range questions {
+		header = append(header, fmt.Sprintf("Q%d", i+1))
+	}
+	w.AppendHeader(header)
+	for _, r := range rows {
+		tr := table.Row{r.rank, r.user, r.score, r.finish, r.penalty}
+		for _, p := range r.problems {
+			tr = append(tr, p)
+		}
+		w.AppendRow(tr)
+	}
+	w.Render()
+	if standings.Me == nil {
+		log.Warn("not signed in, your own rank is not shown")
+	}
+	for _, u := range standings.Friends {
+		if !u.Found {
+			log.Warn("friend not found in the searched pages", "friend", u.Handle, "pages", rankPages)
+		}
+	}
+}
+
+var virtualEnd bool
+
+var contestVirtualCmd = &cobra.Command{
+	Use:   "virtual [qid]",
+	Short: "Simulate a past contest with a local timer",
+	Long: `Simulate a past contest: questions of the contest are generated, and a local timer starts with the duration of it.
+Submissions of the contest questions judged before the time is up are recorded, detached submissions are not.
+
+Run without qid to see the remaining time and your results. Once the time is up, or with --end,
+your score is ranked against the standings of the original contest.`,
+	Example: `leetgo contest virtual w330
+leetgo contest virtual
+leetgo contest virtual --end`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContests(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		state := config.LoadState()
+		if len(args) > 0 {
+			if state.Virtual != nil && !viper.GetBool("force") {
+				return fmt.Errorf(
+					"virtual contest %s is running, finish it with --end, or use --force to replace it",
+					state.Virtual.Title,
+				)
+			}
+			return startVirtual(cmd, c, args[0])
+		}
+
+		v := state.Virtual
+		if v == nil {
+			return errors.New("no virtual contest running, start one with 'leetgo contest virtual <qid>'")
+		}
+		if !v.HasFinished() && !virtualEnd {
+			showVirtual(cmd, v)
+			return nil
+		}
+
+		contest, err := c.GetContest(v.Slug)
+		if err != nil {
+			return err
+		}
+		score, err := contest.ScoreVirtual(v)
+		if err != nil {
+			return err
+		}
+		state.Virtual = nil
+		config.SaveState(state)
+
+		if config.JSONOutput() {
+			return encodeJSON(
+				cmd, map[string]any{
+					"contest":    v.Slug,
+					"score":      score.Score,
+					"solved":     score.Solved,
+					"finish_ms":  score.Finish.Milliseconds(),
+					"penalty_ms": score.Penalty.Milliseconds(),
+					"rank":       score.Rank,
+					"user_num":   score.UserNum,
+				},
+			)
+		}
+		cmd.Printf(
+			"%s finished: score %d, %d/%d solved, finish time %s (penalty %s), rank %d/%d\n",
+			contestTitleStyle.Render(v.Title),
+			score.Score,
+			score.Solved,
+			len(v.Questions),
+			score.Finish,
+			score.Penalty,
+			score.Rank,
+			score.UserNum,
+		)
+		return nil
+	},
+}
+
+func startVirtual(cmd *cobra.Command, c leetcode.Client, qid string) error {
+	if !strings.HasSuffix(qid, "/") {
+		qid += "/"
+	}
+	contest, _, err := leetcode.ParseContestQID(qid, c, false)
+	if err != nil {
+		return err
+	}
+	if !contest.HasFinished() {
+		return fmt.Errorf("%s has not finished, use 'leetgo contest' to join it", contest.Title)
+	}
+	generated, err := lang.GenerateContest(contest)
+	if err != nil {
+		return err
+	}
+	if lang.DryRun() {
+		log.Info("would start virtual contest", "contest", contest.Title)
+		return nil
+	}
+
+	v := &config.VirtualContest{
+		Site:      config.Get().LeetCode.Site.Short(),
+		Slug:      contest.TitleSlug,
+		Title:     contest.Title,
+		StartedAt: time.Now(),
+		Duration:  time.Duration(contest.Duration) * time.Second,
+	}
+	for _, q := range contest.Questions {
+		v.Questions = append(v.Questions, q.TitleSlug)
+	}
+	state := config.LoadState()
+	state.Virtual = v
+	config.SaveState(state)
+	log.Info("virtual contest started", "contest", v.Title, "ends", v.EndsAt().Format(time.TimeOnly))
+	return finishGenerate(cmd, generated...)
+}
+
+func showVirtual(cmd *cobra.Command, v *config.VirtualContest) {
+	if config.JSONOutput() {
+		_ = encodeJSON(cmd, v)
+		return
+	}
+	cmd.Printf(
+		"%s, %s left\n",
+		contestTitleStyle.Render(v.Title),
+		timeStyle.Render(durafmt.Parse(time.Until(v.EndsAt())).LimitFirstN(2).String()),
+	)
+	for i, slug := range v.Questions {
+		status := "-"
+		if r, ok := v.Results[slug]; ok {
+			if r.AcceptedAt.IsZero() {
+				status = fmt.Sprintf("%d wrong", r.FailCount)
+			} else {
+				status = fmt.Sprintf("accepted at %s", r.AcceptedAt.Sub(v.StartedAt).Round(time.Second))
+				if r.FailCount > 0 {
+					status += fmt.Sprintf(", %d wrong", r.FailCount)
+				}
+			}
+		}
+		cmd.Printf("Q%d %s: %s\n", i+1, slug, status)
+	}
+}
+
+// recordVirtual records the judged submission in the running virtual contest, if the question is of it.
+func recordVirtual(q *leetcode.QuestionData, accepted bool) {
+	state := config.LoadState()
+	v := state.Virtual
+	if v == nil || v.Site != config.Get().LeetCode.Site.Short() || !v.Record(q.TitleSlug, accepted) {
+		return
+	}
+	config.SaveState(state)
+	if accepted {
+		log.Info("accepted in virtual contest", "contest", v.Title, "time", time.Since(v.StartedAt).Round(time.Second))
+	}
+}
+
 func init() {
 	contestCmd.Flags().BoolVarP(&openInBrowser, "browser", "b", false, "open question page in browser")
+	contestDifficultyCmd.Flags().IntVar(&difficultyPages, "pages", 8, "number of standing pages to sample, 25 users per page")
+	contestRankCmd.Flags().StringSliceVar(&rankFriends, "friend", nil, "handle of a friend to watch, in addition to contest.friends")
+	contestRankCmd.Flags().IntVar(&rankPages, "pages", 20, "number of standing pages to search for friends, 25 users per page")
+	contestRankCmd.Flags().BoolVarP(&rankWatch, "watch", "w", false, "refresh the standings periodically until the contest finishes")
+	contestRankCmd.Flags().DurationVar(&rankInterval, "interval", 30*time.Second, "interval to refresh the standings")
 	contestCmd.AddCommand(unregisterCmd)
+	contestCmd.AddCommand(contestDifficultyCmd)
+	contestVirtualCmd.Flags().BoolVar(&virtualEnd, "end", false, "end the running virtual contest before the time is up")
+	contestCmd.AddCommand(contestRankCmd)
+	contestCmd.AddCommand(contestVirtualCmd)
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/log"
+	"github.com/cli/browser"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var copyOpenBrowser bool
+
+func init() {
+	copyCmd.Flags().BoolVarP(&copyOpenBrowser, "browser", "b", false, "open the question page in browser to paste the code")
+}
+
+var copyCmd = &cobra.Command{
+	Use:   "copy [qid]",
+	Short: "Copy solution code to clipboard",
+	Long: `Copy the solution code between the code markers to the system clipboard, to paste it into the web editor.
+The question defaults to the last generated one. On Linux, xclip, xsel or wl-clipboard is required.`,
+	Example: `leetgo copy
+leetgo copy 1
+leetgo copy w330/1 -b`,
+	Aliases:           []string{"cp"},
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if config.SafeMode() {
+			return fmt.Errorf("copy: %w", config.ErrSafeMode)
+		}
+		if clipboard.Unsupported {
+			return errors.New("clipboard is not supported, install xclip, xsel or wl-clipboard")
+		}
+		qid := "last"
+		if len(args) > 0 {
+			qid = args[0]
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(qid, c)
+		if err != nil {
+			return err
+		}
+		code, err := lang.GetSolutionCode(q)
+		if err != nil {
+			return err
+		}
+		err = clipboard.WriteAll(code)
+		if err != nil {
+			return fmt.Errorf("failed to copy to clipboard: %w", err)
+		}
+		log.Info("copied to clipboard", "question", q.TitleSlug, "lines", len(utils.SplitLines(code)))
+
+		if copyOpenBrowser {
+			url := q.Url()
+			if q.IsContest() {
+				url = q.ContestUrl()
+			}
+			return browser.OpenURL(url)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Manage LeetCode credentials saved in the OS keychain",
+	Long: `Manage LeetCode cookies saved in the OS keychain (Keychain on macOS, Secret Service on Linux, Credential Manager on Windows).
+
+If the keychain is not available, cookies are saved to an encrypted file in the state directory instead,
+the passphrase is read from the LEETGO_CREDENTIALS_PASSPHRASE environment variable.
+
+Set leetcode.credentials.from to keyring to use the saved cookies.`,
+}
+
+var saveFrom string
+
+var credentialsSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save cookies of the current site to the OS keychain",
+	Long: `Save cookies of the current site to the OS keychain.
+
+Cookies are read from the source given by --from, defaults to leetcode.credentials.from,
+or LEETCODE_SESSION, LEETCODE_CSRFTOKEN and LEETCODE_CFCLEARANCE environment variables if it's keyring or none.`,
+	Example: `leetgo credentials save
+leetgo credentials save --from browser`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		from := saveFrom
+		if from == "" {
+			from = cfg.LeetCode.Credentials.From
+		}
+		switch from {
+		case "keyring", "none":
+			from = "cookies"
+		case "browser", "cookies", "password":
+		default:
+			return fmt.Errorf("invalid source %s, only browser, cookies or password is supported", from)
+		}
+
+		provider := leetcode.CredentialsFrom(from)
+		c := leetcode.NewClient(provider)
+		cookies, err := leetcode.CookiesOf(c, provider)
+		if err != nil {
+			return fmt.Errorf("failed to read credentials from %s: %w", from, err)
+		}
+		where, err := leetcode.SaveCookies(cfg.LeetCode.Site, cookies)
+		if err != nil {
+			return err
+		}
+		log.Info("credentials saved", "site", cfg.LeetCode.Site, "to", where)
+
+		if cfg.LeetCode.Credentials.From != "keyring" {
+			log.Info("set leetcode.credentials.from to keyring to use the saved credentials")
+		}
+		if from == "cookies" {
+			log.Info("LEETCODE_SESSION, LEETCODE_CSRFTOKEN and LEETCODE_CFCLEARANCE can be removed from .env now")
+		}
+		return nil
+	},
+}
+
+var credentialsDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete saved cookies of the current site",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		site := config.Get().LeetCode.Site
+		err := leetcode.DeleteCookies(site)
+		if err != nil {
+			return err
+		}
+		log.Info("credentials deleted", "site", site)
+		return nil
+	},
+}
+
+func init() {
+	credentialsSaveCmd.Flags().StringVar(&saveFrom, "from", "", "read cookies from: browser, cookies or password")
+	_ = credentialsSaveCmd.RegisterFlagCompletionFunc(
+		"from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"browser", "cookies", "password"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	credentialsCmd.AddCommand(credentialsSaveCmd)
+	credentialsCmd.AddCommand(credentialsDeleteCmd)
+}
@@ -63,7 +63,9 @@ var debugCmd = &cobra.Command{
 		cmd.Println("```")
 		cmd.Println(buildVersion())
 		cmd.Println("```")
-		cmd.Println("Home dir             :", cfg.HomeDir())
+		cmd.Println("Config dir           :", cfg.HomeDir())
+		cmd.Println("Cache dir            :", cfg.CacheDir())
+		cmd.Println("State dir            :", cfg.StateDir())
 		cmd.Println("Project root         :", cfg.ProjectRoot())
 		cmd.Println("Working dir          :", cwd)
 		cmd.Println("Project config file  :", cfg.ConfigFile())
@@ -5,17 +5,19 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/editor"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
 )
 
 var editCmd = &cobra.Command{
-	Use:       "edit qid",
-	Short:     "Open solution in editor",
-	Aliases:   []string{"e"},
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last"},
+	Use:               "edit qid",
+	Short:             "Open solution in editor",
+	Aliases:           []string{"e"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qs, err := leetcode.ParseQID(args[0], c)
@@ -29,16 +31,27 @@ var editCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if config.SafeMode() {
+			return fmt.Errorf("edit: %w", config.ErrSafeMode)
+		}
 		return editor.Open(result)
 	},
 }
 
 var extractCmd = &cobra.Command{
-	Use:    "extract qid",
+	Use:    "extract qid|file",
 	Short:  "Extract solution code from generated file",
 	Args:   cobra.ExactArgs(1),
 	Hidden: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if utils.IsExist(args[0]) {
+			code, err := lang.ExtractCode(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.Println(code)
+			return nil
+		}
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qs, err := leetcode.ParseQID(args[0], c)
 		if err != nil {
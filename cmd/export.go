@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	exportOutput     string
+	exportFormat     string
+	exportDeck       string
+	exportDifficulty string
+	exportTags       []string
+)
+
+func init() {
+	exportAnkiCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write, defaults to leetgo-<lang>.<format> in the project root")
+	exportAnkiCmd.Flags().StringVar(&exportFormat, "format", "", "csv or apkg, defaults to the extension of --output, or csv")
+	exportAnkiCmd.Flags().StringVar(&exportDeck, "deck", "LeetCode", "name of the deck")
+	exportAnkiCmd.Flags().StringVarP(&exportDifficulty, "difficulty", "d", "", "only export questions of the difficulty: easy, medium or hard")
+	exportAnkiCmd.Flags().StringSliceVarP(&exportTags, "tag", "t", nil, "only export questions having the tag slugs, e.g. array, dynamic-programming")
+
+	_ = exportAnkiCmd.RegisterFlagCompletionFunc(
+		"format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"csv", "apkg"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	_ = exportAnkiCmd.RegisterFlagCompletionFunc("tag", completeTags)
+
+	exportCmd.AddCommand(exportAnkiCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export solved questions to other tools",
+}
+
+var exportAnkiCmd = &cobra.Command{
+	Use:   "anki",
+	Short: "Export accepted solutions as an Anki deck",
+	Long: `Export questions accepted in the current language as an Anki deck, for spaced repetition.
+The front of a card is the question description, the back is your solution and the note of the question.
+Solutions changed since they were accepted are skipped, submit them again to export them.
+A CSV file can be imported by Anki directly, .apkg files require leetgo to be built with the sqlite tag.`,
+	Example: `leetgo export anki
+leetgo export anki -l go -d medium -t dynamic-programming -o dp.csv
+leetgo export anki -o leetcode.apkg`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := exportFormat
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(exportOutput), ".")
+		}
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "apkg" {
+			return fmt.Errorf("invalid format %s, only csv or apkg is supported", format)
+		}
+		if err := checkDeckName(exportDeck); err != nil {
+			return err
+		}
+		switch strings.ToLower(exportDifficulty) {
+		case "", "easy", "medium", "hard":
+		default:
+			return errors.New("invalid difficulty, only easy, medium or hard is supported")
+		}
+
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return err
+		}
+		output := exportOutput
+		if output == "" {
+			output = filepath.Join(config.Get().ProjectRoot(), fmt.Sprintf("leetgo-%s.%s", gen.ShortName(), format))
+		}
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		filter := leetcode.LocalFilter{Difficulty: exportDifficulty, Tags: exportTags}
+		cards := buildAnkiCards(c, gen, filter)
+		if len(cards) == 0 {
+			return errors.New("no accepted questions to export")
+		}
+		if lang.DryRun() {
+			log.Info("would export", "cards", len(cards), "file", utils.RelToCwd(output))
+			return nil
+		}
+
+		if format == "apkg" {
+			err = writeAnkiPackage(output, exportDeck, cards)
+		} else {
+			err = writeAnkiCSVFile(output, exportDeck, cards)
+		}
+		if err != nil {
+			return err
+		}
+		log.Info("exported", "cards", len(cards), "file", utils.RelToCwd(output))
+		return nil
+	},
+}
+
+type ankiCard struct {
+	// ID identifies the card across exports, so that importing again updates it instead of adding a duplicate.
+	ID    string
+	Front string
+	Back  string
+	Tags  []string
+}
+
+var errSolutionChanged = errors.New("solution changed since it was accepted, submit it again to export it")
+
+// acceptedSolution returns the solution of the question if it's the accepted one, i.e. it has the accepted hash.
+func acceptedSolution(q *leetcode.QuestionData, acceptedHash string) (string, error) {
+	code, err := lang.GetSolutionCode(q)
+	if err != nil {
+		return "", err
+	}
+	if solutionHash(code) != acceptedHash {
+		return "", errSolutionChanged
+	}
+	return code, nil
+}
+
+// buildAnkiCards builds cards of the questions accepted in the language, failures of single questions are only logged.
+// The back of a card is the solution file, questions whose solutions changed since they were accepted are skipped.
+func buildAnkiCards(c leetcode.Client, gen lang.Lang, filter leetcode.LocalFilter) []ankiCard {
+	state := config.LoadState()
+	hashes := state.AcceptedHashes(gen.Slug())
+	slugs := make([]string, 0, len(hashes))
+	for slug := range hashes {
+		slugs = append(slugs, slug)
+	}
+	slices.Sort(slugs)
+
+	var (
+		qs    []*leetcode.QuestionData
+		codes []string
+	)
+	for _, slug := range slugs {
+		q, err := leetcode.QuestionBySlug(slug, c)
+		if err != nil {
+			log.Warn("failed to get question", "question", slug, "err", err)
+			continue
+		}
+		if !filter.Match(q) {
+			continue
+		}
+		code, err := acceptedSolution(q, hashes[slug])
+		if err != nil {
+			log.Warn("skipped question", "question", slug, "err", err)
+			continue
+		}
+		qs = append(qs, q)
+		codes = append(codes, code)
+	}
+
+	var cards []ankiCard
+	for i, err := range leetcode.FetchQuestions(qs) {
+		q := qs[i]
+		if err != nil {
+			log.Warn("failed to get question data", "question", q.TitleSlug, "err", err)
+			continue
+		}
+		note, _ := os.ReadFile(config.Get().NoteFile(q.TitleSlug))
+		cards = append(cards, newAnkiCard(q, gen, codes[i], string(note)))
+	}
+	return cards
+}
+
+func newAnkiCard(q *leetcode.QuestionData, gen lang.Lang, code string, note string) ankiCard {
+	var front strings.Builder
+	front.WriteString(
+		fmt.Sprintf(
+			"<h3>%s. %s</h3><p>%s</p>",
+			html.EscapeString(q.QuestionFrontendId),
+			html.EscapeString(q.GetTitle()),
+			html.EscapeString(q.Difficulty),
+		),
+	)
+	content, _ := q.GetPreferContent()
+	if q.EditorType == leetcode.EditorTypeCKEditor {
+		front.WriteString(content)
+	} else {
+		front.WriteString("<pre>" + html.EscapeString(content) + "</pre>")
+	}
+
+	back := "<pre><code>" + html.EscapeString(strings.TrimSpace(code)) + "</code></pre>"
+	if note = strings.TrimSpace(note); note != "" {
+		back += "<hr><pre>" + html.EscapeString(note) + "</pre>"
+	}
+
+	tags := []string{constants.CmdName, strings.ToLower(q.Difficulty)}
+	tags = append(tags, q.TagSlugs()...)
+	return ankiCard{
+		ID:    config.Get().LeetCode.Site.Short() + "/" + q.TitleSlug + "/" + gen.Slug(),
+		Front: front.String(),
+		Back:  back,
+		Tags:  tags,
+	}
+}
+
+// checkDeckName checks that the deck name fits in the #deck header of the text format.
+func checkDeckName(deck string) error {
+	if strings.TrimSpace(deck) == "" {
+		return errors.New("deck name is empty")
+	}
+	if strings.ContainsAny(deck, "\r\n") {
+		return fmt.Errorf("deck name %q must be a single line", deck)
+	}
+	return nil
+}
+
+// writeAnkiCSV writes cards in the text format imported by Anki, with file headers describing the columns.
+func writeAnkiCSV(w io.Writer, deck string, cards []ankiCard) error {
+	if err := checkDeckName(deck); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "#separator:Comma\n#html:true\n#deck:%s\n#tags column:3\n", deck)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for _, card := range cards {
+		err = cw.Write([]string{card.Front, card.Back, strings.Join(card.Tags, " ")})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeAnkiCSVFile(path string, deck string, cards []ankiCard) error {
+	var sb strings.Builder
+	err := writeAnkiCSV(&sb, deck, cards)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(path, []byte(sb.String()))
+}
@@ -0,0 +1,281 @@
+//go:build sqlite
+
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/binary"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+
+	"github.com/j178/leetgo/utils"
+)
+
+// ankiSchema is the schema of the collection in .apkg files, version 11, which every Anki version can import.
+const ankiSchema = `
+create table col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null,
+    dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null,
+    decks text not null, dconf text not null, tags text not null
+);
+create table notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null,
+    tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null,
+    data text not null
+);
+create table cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null,
+    usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null,
+    factor integer not null, reps integer not null, lapses integer not null, left integer not null,
+    odue integer not null, odid integer not null, flags integer not null, data text not null
+);
+create table revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null,
+    lastIvl integer not null, factor integer not null, time integer not null, type integer not null
+);
+create table graves (usn integer not null, oid integer not null, type integer not null);
+`
+
+const ankiCSS = `.card { font-family: arial; font-size: 16px; text-align: left; color: black; background-color: white; }
+pre { white-space: pre-wrap; }`
+
+// ankiID derives a stable id from the name, so that importing a deck again updates it instead of adding a new one.
+func ankiID(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	// Anki ids are positive and fit in a JavaScript number.
+	return int64(h.Sum64() >> 12)
+}
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// ankiChecksum is the checksum of the sort field Anki uses to find duplicates.
+func ankiChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(htmlTagRe.ReplaceAllString(field, "")))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+func ankiCollection(deck string, deckID int64, modelID int64, now int64) map[string]any {
+	model := map[string]any{
+		"id":    modelID,
+		"name":  "leetgo",
+		"type":  0,
+		"mod":   now,
+		"usn":   -1,
+		"sortf": 0,
+		"did":   deckID,
+		"tmpls": []map[string]any{
+			{
+				"name":  "Card 1",
+				"ord":   0,
+				"qfmt":  "{{Front}}",
+				"afmt":  "{{FrontSide}}<hr id=answer>{{Back}}",
+				"did":   nil,
+				"bqfmt": "",
+				"bafmt": "",
+			},
+		},
+		"flds": []map[string]any{
+			{"name": "Front", "ord": 0, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{}},
+			{"name": "Back", "ord": 1, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{}},
+		},
+		"css":       ankiCSS,
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []string{},
+		"req":       []any{[]any{0, "any", []int{0}}},
+	}
+	newDeck := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id":        id,
+			"name":      name,
+			"desc":      "",
+			"mod":       now,
+			"usn":       -1,
+			"collapsed": false,
+			"newToday":  []int{0, 0},
+			"revToday":  []int{0, 0},
+			"lrnToday":  []int{0, 0},
+			"timeToday": []int{0, 0},
+			"dyn":       0,
+			"conf":      1,
+			"extendNew": 10,
+			"extendRev": 50,
+		}
+	}
+	dconf := map[string]any{
+		"id":       1,
+		"name":     "Default",
+		"mod":      0,
+		"usn":      0,
+		"maxTaken": 60,
+		"autoplay": true,
+		"timer":    0,
+		"replayq":  true,
+		"dyn":      false,
+		"new": map[string]any{
+			"delays":        []float64{1, 10},
+			"ints":          []int{1, 4, 7},
+			"initialFactor": 2500,
+			"order":         1,
+			"perDay":        20,
+			"bury":          true,
+			"separate":      true,
+		},
+		"rev": map[string]any{
+			"perDay":   100,
+			"ease4":    1.3,
+			"fuzz":     0.05,
+			"minSpace": 1,
+			"ivlFct":   1,
+			"maxIvl":   36500,
+			"bury":     true,
+		},
+		"lapse": map[string]any{
+			"delays":      []float64{10},
+			"mult":        0,
+			"minInt":      1,
+			"leechFails":  8,
+			"leechAction": 0,
+		},
+	}
+	conf := map[string]any{
+		"nextPos":       1,
+		"estTimes":      true,
+		"activeDecks":   []int64{1},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       1,
+		"newBury":       true,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      strconv.FormatInt(modelID, 10),
+		"collapseTime":  1200,
+	}
+	return map[string]any{
+		"conf":   conf,
+		"models": map[string]any{strconv.FormatInt(modelID, 10): model},
+		"decks": map[string]any{
+			"1":                           newDeck(1, "Default"),
+			strconv.FormatInt(deckID, 10): newDeck(deckID, deck),
+		},
+		"dconf": map[string]any{"1": dconf},
+	}
+}
+
+func writeAnkiCollection(db *sqlite.Conn, deck string, cards []ankiCard) error {
+	err := sqlitex.ExecuteScript(db, ankiSchema, nil)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	deckID := ankiID("deck/" + deck)
+	modelID := ankiID("model/leetgo")
+	col := ankiCollection(deck, deckID, modelID, now)
+	jsonField := func(key string) string {
+		data, _ := json.Marshal(col[key])
+		return string(data)
+	}
+	err = sqlitex.Execute(
+		db,
+		"insert into col values (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')",
+		&sqlitex.ExecOptions{
+			Args: []any{now, now * 1000, now * 1000, jsonField("conf"), jsonField("models"), jsonField("decks"), jsonField("dconf")},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, card := range cards {
+		noteID := ankiID("note/" + card.ID)
+		guid := strconv.FormatInt(noteID, 36)
+		tags := " " + strings.Join(card.Tags, " ") + " "
+		err = sqlitex.Execute(
+			db,
+			"insert into notes values (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')",
+			&sqlitex.ExecOptions{
+				Args: []any{
+					noteID, guid, modelID, now, tags, card.Front + "\x1f" + card.Back, card.Front,
+					ankiChecksum(card.Front),
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		err = sqlitex.Execute(
+			db,
+			"insert into cards values (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')",
+			&sqlitex.ExecOptions{
+				Args: []any{ankiID("card/" + card.ID), noteID, deckID, now, i + 1},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAnkiPackage writes cards as an .apkg file, a zip of the collection database and the media index.
+func writeAnkiPackage(path string, deck string, cards []ankiCard) error {
+	tmp, err := os.MkdirTemp("", "leetgo-anki-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	dbFile := filepath.Join(tmp, "collection.anki2")
+	db, err := sqlite.OpenConn(dbFile)
+	if err != nil {
+		return err
+	}
+	err = writeAnkiCollection(db, deck, cards)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	collection, err := os.ReadFile(dbFile)
+	if err != nil {
+		return err
+	}
+
+	err = utils.CreateIfNotExists(filepath.Dir(path), true)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	zw := zip.NewWriter(f)
+	for name, data := range map[string][]byte{"collection.anki2": collection, "media": []byte("{}")} {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
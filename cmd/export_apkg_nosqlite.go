@@ -0,0 +1,11 @@
+//go:build !sqlite
+
+package cmd
+
+import (
+	"errors"
+)
+
+func writeAnkiPackage(path string, deck string, cards []ankiCard) error {
+	return errors.New("exporting .apkg requires leetgo built with the sqlite tag, export to csv instead")
+}
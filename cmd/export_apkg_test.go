@@ -0,0 +1,70 @@
+//go:build sqlite
+
+package cmd
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestWriteAnkiPackage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out", "leetcode.apkg")
+	cards := []ankiCard{
+		{ID: "us/two-sum/golang", Front: "<h3>1. Two Sum</h3>", Back: "back", Tags: []string{"leetgo", "easy"}},
+		{ID: "us/add-two-numbers/golang", Front: "<h3>2. Add Two Numbers</h3>", Back: "back"},
+	}
+	if err := writeAnkiPackage(path, "LeetCode", cards); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = zr.Close() }()
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(r)
+		_ = r.Close()
+	}
+	if string(files["media"]) != "{}" || files["collection.anki2"] == nil {
+		t.Fatalf("unexpected package files: %v", zr.File)
+	}
+
+	dbFile := filepath.Join(dir, "collection.anki2")
+	if err := os.WriteFile(dbFile, files["collection.anki2"], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.OpenConn(dbFile, sqlite.OpenReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	count := func(query string) int {
+		n, err := sqlitex.ResultInt(db.Prep(query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := count("select count(*) from notes"); n != len(cards) {
+		t.Errorf("notes = %d, want %d", n, len(cards))
+	}
+	if n := count("select count(*) from cards c join notes n on c.nid = n.id"); n != len(cards) {
+		t.Errorf("cards = %d, want %d", n, len(cards))
+	}
+	if n := count("select count(*) from notes where tags = ' leetgo easy '"); n != 1 {
+		t.Errorf("notes with tags = %d, want 1", n)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestNewAnkiCard(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	gen, err := lang.GetGenerator("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := &leetcode.QuestionData{
+		TitleSlug:          "two-sum",
+		QuestionFrontendId: "1",
+		Title:              "Two <Sum>",
+		Difficulty:         "Easy",
+		Content:            "<p>Given an array</p>",
+		EditorType:         leetcode.EditorTypeCKEditor,
+		TopicTags:          []leetcode.TopicTag{{Slug: "array"}, {Slug: "hash-table"}},
+	}
+
+	card := newAnkiCard(q, gen, "\nfunc twoSum() []int { return a<b }\n", "  use a map\n")
+	if want := "<h3>1. Two &lt;Sum&gt;</h3><p>Easy</p><p>Given an array</p>"; card.Front != want {
+		t.Errorf("Front = %q, want %q", card.Front, want)
+	}
+	if want := "<pre><code>func twoSum() []int { return a&lt;b }</code></pre><hr><pre>use a map</pre>"; card.Back != want {
+		t.Errorf("Back = %q, want %q", card.Back, want)
+	}
+	if want := []string{"leetgo", "easy", "array", "hash-table"}; !slices.Equal(card.Tags, want) {
+		t.Errorf("Tags = %q, want %q", card.Tags, want)
+	}
+	if !strings.HasSuffix(card.ID, "/two-sum/golang") {
+		t.Errorf("ID = %q, want it keyed by site, question and language", card.ID)
+	}
+
+	// Markdown content is kept as is, without a note there is no separator.
+	q.EditorType = leetcode.EditorTypeMarkdown
+	q.Content = "Given <an> array"
+	card = newAnkiCard(q, gen, "code", "")
+	if !strings.HasSuffix(card.Front, "<pre>Given &lt;an&gt; array</pre>") || card.Back != "<pre><code>code</code></pre>" {
+		t.Errorf("card of a markdown question = %+v", card)
+	}
+}
+
+func TestWriteAnkiCSV(t *testing.T) {
+	cards := []ankiCard{
+		{Front: "<h3>1. Two Sum</h3>", Back: "<pre><code>a, b := \"x\",\ny</code></pre>", Tags: []string{"leetgo", "easy"}},
+		{Front: "second", Back: "back", Tags: nil},
+	}
+	var sb strings.Builder
+	if err := writeAnkiCSV(&sb, "LeetCode::Go", cards); err != nil {
+		t.Fatal(err)
+	}
+	header, body, _ := strings.Cut(sb.String(), "#tags column:3\n")
+	if header != "#separator:Comma\n#html:true\n#deck:LeetCode::Go\n" {
+		t.Errorf("header = %q", header)
+	}
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{cards[0].Front, cards[0].Back, "leetgo easy"},
+		{"second", "back", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %q, want %q", records, want)
+	}
+	for i := range want {
+		if !slices.Equal(records[i], want[i]) {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+
+	for _, deck := range []string{"", "LeetCode\n#html:false", "LeetCode\r"} {
+		if err := writeAnkiCSV(&sb, deck, cards); err == nil {
+			t.Errorf("writeAnkiCSV() with deck %q should fail", deck)
+		}
+	}
+}
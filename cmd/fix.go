@@ -16,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 	"github.com/j178/leetgo/utils"
@@ -28,9 +30,9 @@ var fixCmd = &cobra.Command{
 	Short: "Use ChatGPT API to fix your solution code (just for fun)",
 	Long: `Use ChatGPT API to fix your solution code.
 Set OPENAI_API_KEY environment variable to your OpenAI API key before using this command.`,
-	Example:   `leetgo fix 429`,
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last"},
+	Example:           `leetgo fix 429`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qs, err := leetcode.ParseQID(args[0], c)
@@ -67,10 +69,12 @@ Set OPENAI_API_KEY environment variable to your OpenAI API key before using this
 		cmd.Println(output)
 
 		accept := true
-		if !viper.GetBool("yes") {
-			err = survey.AskOne(
+		if config.SafeMode() && !viper.GetBool("yes") {
+			accept = false
+		} else if !viper.GetBool("yes") {
+			err = config.AskOne(
 				&survey.Confirm{
-					Message: "Do you want to accept the fix?",
+					Message: i18n.T("Do you want to accept the fix?"),
 				}, &accept,
 			)
 			if err != nil {
@@ -122,7 +126,7 @@ func askOpenAI(cmd *cobra.Command, q *leetcode.QuestionData, code string) (strin
 	)
 	log.Debug("requesting openai", "prompt", prompt)
 	spin := newSpinner(cmd.OutOrStdout())
-	spin.Suffix = " Waiting for OpenAI..."
+	spin.Suffix = " " + i18n.T("Waiting for OpenAI...")
 	spin.Start()
 	defer spin.Stop()
 
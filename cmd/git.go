@@ -10,6 +10,7 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 )
@@ -21,10 +22,10 @@ var gitCmd = &cobra.Command{
 }
 
 var gitPushCmd = &cobra.Command{
-	Use:       "push qid",
-	Short:     "Add, commit and push your solution to remote repository",
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last"},
+	Use:               "push qid",
+	Short:             "Add, commit and push your solution to remote repository",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qid := args[0]
@@ -49,6 +50,9 @@ func init() {
 }
 
 func gitAddCommitPush(genResult *lang.GenerateResult) error {
+	if config.SafeMode() {
+		return fmt.Errorf("git push: %w", config.ErrSafeMode)
+	}
 	files := make([]string, 0, len(genResult.Files))
 	for _, f := range genResult.Files {
 		files = append(files, f.GetPath())
@@ -65,7 +69,7 @@ func gitAddCommitPush(genResult *lang.GenerateResult) error {
 			genResult.Question.TitleSlug,
 		),
 	}
-	err = survey.AskOne(prompt, &msg)
+	err = config.AskOne(prompt, &msg)
 	if err != nil {
 		return fmt.Errorf("git commit message: %w", err)
 	}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+func runRecord(r *leetcode.SubmitCheckResult, hash string) config.RunRecord {
+	runtime, ok := r.RuntimeMs()
+	if !ok {
+		runtime = -1
+	}
+	return config.RunRecord{
+		SubmissionID:      r.SubmissionId,
+		AcceptedAt:        time.Now(),
+		Hash:              hash,
+		RuntimeMs:         runtime,
+		RuntimePercentile: r.RuntimePercentile,
+		Memory:            r.Memory,
+		MemoryPercentile:  r.MemoryPercentile,
+	}
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history qid",
+	Short: "Compare runtime and memory of accepted submissions of a question",
+	Long: `Compare runtime and memory of accepted submissions of a question in the current language, oldest first.
+The changes from the previous submission show whether a refactor actually improved the reported runtime.
+
+Submissions are recorded when accepted by leetgo, at most 30 for each question and language.`,
+	Example: `leetgo history 1
+leetgo history last -l cpp`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return err
+		}
+		gen = lang.GeneratorFor(q, gen)
+
+		state := config.LoadState()
+		qs, _ := state.Question(q.TitleSlug)
+		runs := qs.Runs[gen.Slug()]
+		if config.JSONOutput() {
+			if runs == nil {
+				runs = []config.RunRecord{}
+			}
+			return encodeJSON(cmd, runs)
+		}
+		if len(runs) == 0 {
+			log.Info("no accepted submissions recorded", "question", q.TitleSlug, "lang", gen.Slug())
+			return nil
+		}
+		showHistory(cmd, q, runs)
+		return nil
+	},
+}
+
+func formatRuntime(ms int) string {
+	if ms < 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%d ms", ms)
+}
+
+func showHistory(cmd *cobra.Command, q *leetcode.QuestionData, runs []config.RunRecord) {
+	type row struct {
+		acceptedAt    string
+		code          string
+		runtime       string
+		runtimeChange string
+		runtimeBeats  string
+		memory        string
+		memoryChange  string
+		memoryBeats   string
+	}
+	rows := make([]row, 0, len(runs))
+	for i, r := range runs {
+		code := r.Hash
+		if len(code) > 7 {
+			code = code[:7]
+		}
+		rw := row{
+			acceptedAt:   r.AcceptedAt.Local().Format(time.DateTime),
+			code:         code,
+			runtime:      formatRuntime(r.RuntimeMs),
+			runtimeBeats: fmt.Sprintf("%.0f%%", r.RuntimePercentile),
+			memory:       utils.FormatBytes(int64(r.Memory)),
+			memoryBeats:  fmt.Sprintf("%.0f%%", r.MemoryPercentile),
+		}
+		if i > 0 {
+			prev := runs[i-1]
+			rw.runtimeChange = "N/A"
+			if r.RuntimeMs >= 0 && prev.RuntimeMs >= 0 {
+				rw.runtimeChange = fmt.Sprintf("%+d ms", r.RuntimeMs-prev.RuntimeMs)
+			}
+			delta := r.Memory - prev.Memory
+			sign := "+"
+			if delta < 0 {
+				sign, delta = "-", -delta
+			}
+			rw.memoryChange = sign + utils.FormatBytes(int64(delta))
+		}
+		rows = append(rows, rw)
+	}
+
+	if config.Get().UsePlainOutput() {
+		cmd.Printf("%s. %s\n", q.QuestionFrontendId, q.GetTitle())
+		for i, r := range rows {
+			cmd.Printf(
+				"%d. accepted at %s, code %s, runtime %s beats %s, memory %s beats %s",
+				i+1, r.acceptedAt, r.code, r.runtime, r.runtimeBeats, r.memory, r.memoryBeats,
+			)
+			if i > 0 {
+				cmd.Printf(", changed by %s and %s", r.runtimeChange, r.memoryChange)
+			}
+			cmd.Println()
+		}
+		return
+	}
+
+	cmd.Printf("%s. %s\n", q.QuestionFrontendId, q.GetTitle())
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"#", "Accepted", "Code", "Runtime", "Change", "Beats", "Memory", "Change", "Beats"})
+	for i, r := range rows {
+		w.AppendRow(
+			table.Row{
+				i + 1, r.acceptedAt, r.code, r.runtime, r.runtimeChange, r.runtimeBeats, r.memory, r.memoryChange,
+				r.memoryBeats,
+			},
+		)
+	}
+	w.Render()
+}
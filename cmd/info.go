@@ -7,11 +7,13 @@ import (
 	"io"
 	"strings"
 
+	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/log"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
 )
 
@@ -37,39 +39,49 @@ func (e *outputFormat) Type() string {
 
 var (
 	flagFull   bool
+	flagHint   int
 	flagFormat outputFormat = "default"
 )
 
 func init() {
 	infoCmd.Flags().BoolVar(&flagFull, "full", false, "show full question info")
+	infoCmd.Flags().IntVar(&flagHint, "hint", 0, "reveal the first N hints")
 	infoCmd.Flags().Var(&flagFormat, "format", "show question info in specific format (json)")
 }
 
 // A simplified version of the leetcode.QuestionData struct
 type question struct {
-	FrontendId         string   `json:"frontend_id"`
-	Title              string   `json:"title"`
-	Slug               string   `json:"slug"`
-	Difficulty         string   `json:"difficulty"`
-	Url                string   `json:"url"`
-	Tags               []string `json:"tags"`
-	IsPaidOnly         bool     `json:"is_paid_only"`
-	TotalAccepted      string   `json:"total_accepted"`
-	TotalAcceptedRaw   int      `json:"total_accepted_raw"`
-	TotalSubmission    string   `json:"total_submission"`
-	TotalSubmissionRaw int      `json:"total_submission_raw"`
-	ACRate             string   `json:"ac_rate"`
-	Content            string   `json:"content"`
-	Hints              []string `json:"hints"`
+	FrontendId         string    `json:"frontend_id"`
+	Title              string    `json:"title"`
+	Slug               string    `json:"slug"`
+	Difficulty         string    `json:"difficulty"`
+	Url                string    `json:"url"`
+	Tags               []string  `json:"tags"`
+	IsPaidOnly         bool      `json:"is_paid_only"`
+	TotalAccepted      string    `json:"total_accepted"`
+	TotalAcceptedRaw   int       `json:"total_accepted_raw"`
+	TotalSubmission    string    `json:"total_submission"`
+	TotalSubmissionRaw int       `json:"total_submission_raw"`
+	ACRate             string    `json:"ac_rate"`
+	Content            string    `json:"content"`
+	Hints              []string  `json:"hints"`
+	SimilarQuestions   []similar `json:"similar_questions"`
+	markdown           string
+}
+
+type similar struct {
+	Title      string `json:"title"`
+	Slug       string `json:"slug"`
+	Difficulty string `json:"difficulty"`
 }
 
 var infoCmd = &cobra.Command{
-	Use:       "info qid...",
-	Short:     "Show question info",
-	Example:   "leetgo info 145\nleetgo info two-sum",
-	Args:      cobra.MinimumNArgs(1),
-	Aliases:   []string{"i"},
-	ValidArgs: []string{"today", "last"},
+	Use:               "info qid...",
+	Short:             "Show question info",
+	Example:           "leetgo info 145\nleetgo info two-sum --full --hint 1",
+	Args:              cobra.MinimumNArgs(1),
+	Aliases:           []string{"i"},
+	ValidArgsFunction: completeQuestions(-1, "today", "last"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 
@@ -81,38 +93,23 @@ var infoCmd = &cobra.Command{
 				continue
 			}
 			for _, q := range qs {
-				_ = q.Fulfill()
-				content := ""
-				if flagFull {
-					content = q.GetFormattedContent()
-				}
-				questions = append(
-					questions, question{
-						FrontendId:         q.QuestionFrontendId,
-						Title:              q.GetTitle(),
-						Slug:               q.TitleSlug,
-						Difficulty:         q.Difficulty,
-						Url:                q.Url(),
-						Tags:               q.TagSlugs(),
-						IsPaidOnly:         q.IsPaidOnly,
-						TotalAccepted:      q.Stats.TotalAccepted,
-						TotalAcceptedRaw:   q.Stats.TotalAcceptedRaw,
-						TotalSubmission:    q.Stats.TotalSubmission,
-						TotalSubmissionRaw: q.Stats.TotalSubmissionRaw,
-						ACRate:             q.Stats.ACRate,
-						Content:            content,
-						Hints:              q.Hints,
-					},
-				)
+				questions = append(questions, newQuestionInfo(q, flagFull))
 			}
 		}
 		if len(questions) == 0 {
 			return errors.New("no questions found")
 		}
 
+		if config.JSONOutput() {
+			flagFormat = "json"
+		}
 		switch flagFormat {
 		default:
-			outputHuman(questions, cmd.OutOrStdout())
+			if config.Get().UsePlainOutput() {
+				outputPlain(questions, cmd.OutOrStdout())
+			} else {
+				outputHuman(questions, cmd.OutOrStdout())
+			}
 		case "json":
 			outputJson(questions, cmd.OutOrStdout())
 		}
@@ -121,6 +118,44 @@ var infoCmd = &cobra.Command{
 	},
 }
 
+// newQuestionInfo fetches the full data of the question, content is only included if full is set.
+func newQuestionInfo(q *leetcode.QuestionData, full bool) question {
+	_ = q.Fulfill()
+	content, markdown := "", ""
+	if full {
+		content = q.GetFormattedContent()
+		markdown, _ = q.GetMarkdownContent()
+	}
+	similarQuestions := make([]similar, 0, len(q.SimilarQuestions))
+	for _, sq := range q.SimilarQuestions {
+		similarQuestions = append(
+			similarQuestions, similar{
+				Title:      sq.GetTitle(),
+				Slug:       sq.TitleSlug,
+				Difficulty: sq.Difficulty,
+			},
+		)
+	}
+	return question{
+		FrontendId:         q.QuestionFrontendId,
+		Title:              q.GetTitle(),
+		Slug:               q.TitleSlug,
+		Difficulty:         q.Difficulty,
+		Url:                q.Url(),
+		Tags:               q.TagSlugs(),
+		IsPaidOnly:         q.IsPaidOnly,
+		TotalAccepted:      q.Stats.TotalAccepted,
+		TotalAcceptedRaw:   q.Stats.TotalAcceptedRaw,
+		TotalSubmission:    q.Stats.TotalSubmission,
+		TotalSubmissionRaw: q.Stats.TotalSubmissionRaw,
+		ACRate:             q.Stats.ACRate,
+		Content:            content,
+		Hints:              q.Hints,
+		SimilarQuestions:   similarQuestions,
+		markdown:           markdown,
+	}
+}
+
 func outputHuman(qs []question, out io.Writer) {
 	w := table.NewWriter()
 	w.SetOutputMirror(out)
@@ -149,15 +184,64 @@ func outputHuman(qs []question, out io.Writer) {
 				fmt.Sprintf("%s/%s %s", q.TotalAccepted, q.TotalSubmission, q.ACRate),
 			},
 		)
-		if q.Content != "" {
-			w.AppendRow(table.Row{"Content", q.Content})
+		hints, hidden := revealedHints(q.Hints)
+		for i, h := range hints {
+			w.AppendRow(table.Row{fmt.Sprintf("Hint %d", i+1), h})
 		}
-		for _, h := range q.Hints {
-			w.AppendRow(table.Row{"Hint", h})
+		if hidden > 0 {
+			w.AppendRow(table.Row{"Hints", fmt.Sprintf("%d more, use --hint %d to reveal", hidden, len(hints)+1)})
+		}
+		for _, sq := range q.SimilarQuestions {
+			w.AppendRow(table.Row{"Similar", fmt.Sprintf("%s (%s, %s)", sq.Title, sq.Slug, sq.Difficulty)})
 		}
 		w.AppendSeparator()
 	}
 	w.Render()
+
+	for _, q := range qs {
+		if q.markdown == "" {
+			continue
+		}
+		content, err := glamour.Render(q.markdown, "dark")
+		if err != nil {
+			content = q.Content
+		}
+		_, _ = fmt.Fprintf(out, "\n%s. %s\n%s", q.FrontendId, q.Title, content)
+	}
+}
+
+// revealedHints returns the hints revealed by --hint and the number of hidden ones.
+func revealedHints(hints []string) ([]string, int) {
+	n := min(max(flagHint, 0), len(hints))
+	return hints[:n], len(hints) - n
+}
+
+func outputPlain(qs []question, out io.Writer) {
+	for i, q := range qs {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "Question: %s. %s\n", q.FrontendId, q.Title)
+		_, _ = fmt.Fprintf(out, "Slug: %s\n", q.Slug)
+		_, _ = fmt.Fprintf(out, "Difficulty: %s\n", q.Difficulty)
+		_, _ = fmt.Fprintf(out, "URL: %s\n", q.Url)
+		_, _ = fmt.Fprintf(out, "Tags: %s\n", strings.Join(q.Tags, ", "))
+		_, _ = fmt.Fprintf(out, "Paid Only: %v\n", q.IsPaidOnly)
+		_, _ = fmt.Fprintf(out, "AC Rate: %s/%s %s\n", q.TotalAccepted, q.TotalSubmission, q.ACRate)
+		if q.Content != "" {
+			_, _ = fmt.Fprintf(out, "Content:\n%s\n", q.Content)
+		}
+		hints, hidden := revealedHints(q.Hints)
+		for j, h := range hints {
+			_, _ = fmt.Fprintf(out, "Hint %d: %s\n", j+1, h)
+		}
+		if hidden > 0 {
+			_, _ = fmt.Fprintf(out, "Hints: %d more, use --hint %d to reveal\n", hidden, len(hints)+1)
+		}
+		for _, sq := range q.SimilarQuestions {
+			_, _ = fmt.Fprintf(out, "Similar: %s (%s, %s)\n", sq.Title, sq.Slug, sq.Difficulty)
+		}
+	}
 }
 
 func outputJson(qs []question, out io.Writer) {
@@ -6,27 +6,32 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 	"github.com/j178/leetgo/utils"
 )
 
 var (
-	force        bool
-	initTemplate string
+	initTemplate    string
+	initInteractive bool
 )
 
 var initCmd = &cobra.Command{
-	Use:     "init [DIR]",
-	Short:   "Init a leetcode workspace",
-	Example: "leetgo init -t us -l cpp",
-	Args:    cobra.MaximumNArgs(1),
+	Use:   "init [DIR]",
+	Short: "Init a leetcode workspace",
+	Example: `leetgo init -t us -l cpp
+leetgo init -i`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir := "."
 		if len(args) > 0 {
@@ -36,6 +41,9 @@ var initCmd = &cobra.Command{
 		if initTemplate != "" && initTemplate != "us" && initTemplate != "cn" {
 			return fmt.Errorf("invalid template %s, only us or cn is supported", initTemplate)
 		}
+		if initInteractive && config.SafeMode() {
+			return fmt.Errorf("interactive init: %w", config.ErrSafeMode)
+		}
 		err := utils.CreateIfNotExists(dir, true)
 		if err != nil {
 			return err
@@ -51,6 +59,7 @@ var initCmd = &cobra.Command{
 		if gitAvailable() && !isInsideGitRepo(dir) {
 			_ = initGitRepo(dir)
 		}
+		config.InitState(dir)
 		err = createQuestionCache()
 		if err != nil {
 			return err
@@ -61,7 +70,7 @@ var initCmd = &cobra.Command{
 
 func init() {
 	initCmd.Flags().StringVarP(&initTemplate, "template", "t", "", "template to use, cn or us")
-	initCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite config file if exists")
+	initCmd.Flags().BoolVarP(&initInteractive, "interactive", "i", false, "ask for the configuration interactively")
 
 	_ = initCmd.RegisterFlagCompletionFunc(
 		"template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -101,8 +110,15 @@ func createConfigFile(dir string) error {
 	cfg.Author = author
 
 	projectFile := filepath.Join(dir, constants.ConfigFilename)
-	if utils.IsExist(projectFile) && !force {
-		return fmt.Errorf("config file %s already exists, use -f to overwrite", utils.RelToCwd(projectFile))
+	if utils.IsExist(projectFile) && !viper.GetBool("force") {
+		return fmt.Errorf("config file %s already exists, use --force to overwrite", utils.RelToCwd(projectFile))
+	}
+
+	if initInteractive {
+		err := askConfig(cfg)
+		if err != nil {
+			return err
+		}
 	}
 
 	f, err := os.Create(projectFile)
@@ -118,12 +134,159 @@ func createConfigFile(dir string) error {
 	return nil
 }
 
+// outDirLayouts are the choices of code.out_dir_template in the init wizard.
+var outDirLayouts = []struct {
+	name     string
+	template string
+}{
+	{"One directory per language", ""},
+	{"Grouped by difficulty", "{{ .Lang }}/{{ .Difficulty | lower }}"},
+	{"Grouped by the first tag", "{{ .Lang }}/{{ .FirstTag }}"},
+}
+
+// askOne asks a question of the init wizard or the checklist, it's replaced in tests.
+var askOne = config.AskOne
+
+// askConfig asks for the main settings, the rest are kept as is.
+func askConfig(cfg *config.Config) error {
+	allLangs := lang.AllLangs()
+	langs := make([]string, 0, len(allLangs))
+	for _, l := range allLangs {
+		langs = append(langs, l.Slug())
+	}
+	defaultLang := cfg.Code.Lang
+	if gen, err := lang.GetGenerator(defaultLang); err == nil {
+		defaultLang = gen.Slug()
+	}
+	err := askOne(
+		&survey.Select{
+			Message: "Language of generated code:",
+			Options: langs,
+			Default: defaultLang,
+			Description: func(value string, index int) string {
+				return allLangs[index].Name()
+			},
+		}, &cfg.Code.Lang,
+	)
+	if err != nil {
+		return err
+	}
+
+	var site string
+	err = askOne(
+		&survey.Select{
+			Message: "LeetCode site:",
+			Options: []string{string(config.LeetCodeUS), string(config.LeetCodeCN)},
+			Default: string(cfg.LeetCode.Site),
+		}, &site,
+	)
+	if err != nil {
+		return err
+	}
+	cfg.LeetCode.Site = config.LeetcodeSite(site)
+	if cfg.LeetCode.Site == config.LeetCodeUS {
+		cfg.Language = config.EN
+	} else {
+		cfg.Language = config.ZH
+	}
+
+	var language string
+	err = askOne(
+		&survey.Select{
+			Message: "Language of messages and question descriptions:",
+			Options: []string{string(config.EN), string(config.ZH)},
+			Default: string(cfg.Language),
+		}, &language,
+	)
+	if err != nil {
+		return err
+	}
+	cfg.Language = config.Language(language)
+
+	err = askOne(&survey.Input{Message: "Your name:", Default: cfg.Author}, &cfg.Author)
+	if err != nil {
+		return err
+	}
+
+	err = askOne(
+		&survey.Select{
+			Message: "Editor to open generated files:",
+			Options: []string{"none", "vim", "neovim", "vscode", "custom"},
+			Default: cfg.Editor.Use,
+		}, &cfg.Editor.Use,
+	)
+	if err != nil {
+		return err
+	}
+	if cfg.Editor.Use == "custom" {
+		err = askOne(
+			&survey.Input{Message: "Editor command:"},
+			&cfg.Editor.Command,
+			survey.WithValidator(survey.Required),
+		)
+		if err != nil {
+			return err
+		}
+		err = askOne(&survey.Input{Message: "Editor arguments:", Default: "{{.Files}}"}, &cfg.Editor.Args)
+		if err != nil {
+			return err
+		}
+	}
+
+	layouts := make([]string, len(outDirLayouts))
+	for i, l := range outDirLayouts {
+		layouts[i] = l.name
+	}
+	var layout int
+	err = askOne(&survey.Select{Message: "Layout of generated questions:", Options: layouts}, &layout)
+	if err != nil {
+		return err
+	}
+	cfg.Code.OutDirTemplate = outDirLayouts[layout].template
+
+	options := credentialOptions(cfg.LeetCode.Site)
+	if !slices.Contains(options, cfg.LeetCode.Credentials.From) {
+		cfg.LeetCode.Credentials.From = options[0]
+	}
+	return askOne(
+		&survey.Select{
+			Message: "How to provide LeetCode credentials:",
+			Options: options,
+			Default: cfg.LeetCode.Credentials.From,
+			Description: func(value string, index int) string {
+				return credentialDescriptions[value]
+			},
+		}, &cfg.LeetCode.Credentials.From,
+	)
+}
+
+// credentialOptions are the ways to provide credentials for the site, leetcode.com doesn't accept passwords.
+func credentialOptions(site config.LeetcodeSite) []string {
+	options := []string{"browser", "cookies", "password", "keyring", "none"}
+	if site == config.LeetCodeUS {
+		options = slices.DeleteFunc(options, func(o string) bool { return o == "password" })
+	}
+	return options
+}
+
+var credentialDescriptions = map[string]string{
+	"browser":  "read cookies from your browser",
+	"cookies":  "LEETCODE_SESSION and LEETCODE_CSRFTOKEN in .env",
+	"password": "LEETCODE_USERNAME and LEETCODE_PASSWORD in .env",
+	"keyring":  "cookies saved in the OS keychain by 'leetgo credentials save'",
+	"none":     "no login, only public features",
+}
+
 func createQuestionCache() error {
 	c := leetcode.NewClient(leetcode.ReadCredentials())
 	cache := leetcode.GetCache(c)
 	if !cache.Outdated() {
 		return nil
 	}
+	if config.Get().LowBandwidth {
+		log.Info("low bandwidth mode, skipped downloading the question list, run `leetgo cache update` when needed")
+		return nil
+	}
 	err := cache.Update()
 	if err != nil {
 		return err
@@ -148,6 +311,9 @@ func defaultUser() string {
 }
 
 func gitAvailable() bool {
+	if config.SafeMode() {
+		return false
+	}
 	cmd := exec.Command("git", "--version")
 	err := cmd.Run()
 	return err == nil
@@ -159,7 +325,7 @@ func initGitRepo(dir string) error {
 	if err != nil {
 		return err
 	}
-	err = utils.WriteOrAppendFile(filepath.Join(dir, ".gitignore"), []byte(".env\n"))
+	err = utils.WriteOrAppendFile(filepath.Join(dir, ".gitignore"), []byte(".env\n.leetgo/\n"))
 	return err
 }
 
@@ -170,6 +336,9 @@ func isInsideGitRepo(dir string) bool {
 }
 
 func getGitUsername() string {
+	if config.SafeMode() {
+		return ""
+	}
 	cmd := exec.Command("git", "config", "user.name")
 	out, err := cmd.Output()
 	if err != nil {
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/j178/leetgo/config"
+)
+
+// fakeWizard answers the prompts of the init wizard by message, and records the options of each select.
+type fakeWizard struct {
+	answers map[string]any
+	options map[string][]string
+}
+
+func (w *fakeWizard) askOne(p survey.Prompt, response any, _ ...survey.AskOpt) error {
+	var message string
+	switch p := p.(type) {
+	case *survey.Select:
+		message = p.Message
+		w.options[message] = p.Options
+		if p.Default != nil && !slices.Contains(p.Options, p.Default.(string)) {
+			return fmt.Errorf("%s: default %v is not an option", message, p.Default)
+		}
+	case *survey.Input:
+		message = p.Message
+	default:
+		return fmt.Errorf("unexpected prompt %T", p)
+	}
+	answer, ok := w.answers[message]
+	if !ok {
+		return fmt.Errorf("unexpected prompt %q", message)
+	}
+	switch r := response.(type) {
+	case *string:
+		*r = answer.(string)
+	case *int:
+		*r = answer.(int)
+	default:
+		return fmt.Errorf("unexpected response type %T", response)
+	}
+	return nil
+}
+
+func TestAskConfig(t *testing.T) {
+	w := &fakeWizard{
+		answers: map[string]any{
+			"Language of generated code:":                     "python3",
+			"LeetCode site:":                                  string(config.LeetCodeUS),
+			"Language of messages and question descriptions:": string(config.EN),
+			"Your name:":                                      "alice",
+			"Editor to open generated files:":                 "vim",
+			"Layout of generated questions:":                  1,
+			"How to provide LeetCode credentials:":            "keyring",
+		},
+		options: map[string][]string{},
+	}
+	old := askOne
+	askOne = w.askOne
+	t.Cleanup(func() { askOne = old })
+
+	c := *config.Get()
+	cfg := &c
+	cfg.LeetCode.Site = config.LeetCodeCN
+	cfg.LeetCode.Credentials.From = "password"
+	if err := askConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Code.Lang != "python3" || cfg.LeetCode.Site != config.LeetCodeUS || cfg.Author != "alice" ||
+		cfg.Editor.Use != "vim" || cfg.Code.OutDirTemplate != outDirLayouts[1].template ||
+		cfg.LeetCode.Credentials.From != "keyring" {
+		t.Errorf("config = %+v", cfg)
+	}
+	// The password default of leetcode.cn is not offered for leetcode.com.
+	if opts := w.options["How to provide LeetCode credentials:"]; slices.Contains(opts, "password") {
+		t.Errorf("credential options for leetcode.com = %q, want no password", opts)
+	}
+}
+
+func TestCredentialOptions(t *testing.T) {
+	if got := credentialOptions(config.LeetCodeCN); !slices.Contains(got, "password") {
+		t.Errorf("credentialOptions(cn) = %q, want password", got)
+	}
+	if got := credentialOptions(config.LeetCodeUS); slices.Contains(got, "password") || len(got) != 4 {
+		t.Errorf("credentialOptions(us) = %q, want all but password", got)
+	}
+}
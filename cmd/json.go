@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+// verdict is the result of a remote test or submission in JSON output.
+type verdict struct {
+	Status            string  `json:"status"`
+	StatusCode        int     `json:"status_code"`
+	Accepted          bool    `json:"accepted"`
+	PassedCases       int     `json:"passed_cases"`
+	TotalCases        int     `json:"total_cases"`
+	Runtime           string  `json:"runtime,omitempty"`
+	RuntimePercentile float64 `json:"runtime_percentile,omitempty"`
+	Memory            string  `json:"memory,omitempty"`
+	MemoryPercentile  float64 `json:"memory_percentile,omitempty"`
+	Input             string  `json:"input,omitempty"`
+	Output            string  `json:"output,omitempty"`
+	Expected          string  `json:"expected,omitempty"`
+	Stdout            string  `json:"stdout,omitempty"`
+	Error             string  `json:"error,omitempty"`
+}
+
+func runVerdict(r *leetcode.RunCheckResult) *verdict {
+	return &verdict{
+		Status:            r.StatusMsg,
+		StatusCode:        r.StatusCode,
+		Accepted:          r.Accepted() && r.CorrectAnswer,
+		PassedCases:       strings.Count(r.CompareResult, "1"),
+		TotalCases:        len(r.CompareResult),
+		Runtime:           r.StatusRuntime,
+		RuntimePercentile: r.RuntimePercentile,
+		Memory:            r.StatusMemory,
+		MemoryPercentile:  r.MemoryPercentile,
+		Input:             r.InputData,
+		Output:            strings.Join(r.CodeAnswer, "\n"),
+		Expected:          strings.Join(r.ExpectedCodeAnswer, "\n"),
+		Stdout:            strings.Join(r.CodeOutput, "\n"),
+		Error:             r.FullCompileError + r.FullRuntimeError,
+	}
+}
+
+func submitVerdict(r *leetcode.SubmitCheckResult) *verdict {
+	return &verdict{
+		Status:            r.StatusMsg,
+		StatusCode:        r.StatusCode,
+		Accepted:          r.Accepted(),
+		PassedCases:       r.TotalCorrect,
+		TotalCases:        r.TotalTestcases,
+		Runtime:           r.StatusRuntime,
+		RuntimePercentile: r.RuntimePercentile,
+		Memory:            r.StatusMemory,
+		MemoryPercentile:  r.MemoryPercentile,
+		Input:             r.LastTestcase,
+		Output:            r.CodeOutput,
+		Expected:          r.ExpectedOutput,
+		Stdout:            r.StdOutput,
+		Error:             r.FullCompileError + r.FullRuntimeError,
+	}
+}
+
+// questionResult collects the results of testing or submitting a question in JSON output.
+type questionResult struct {
+	FrontendId string   `json:"frontend_id"`
+	Slug       string   `json:"slug"`
+	Title      string   `json:"title"`
+	Lang       string   `json:"lang"`
+	Local      *bool    `json:"local_passed,omitempty"`
+	Remote     *verdict `json:"remote,omitempty"`
+	Submit     *verdict `json:"submit,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+func newQuestionResult(q *leetcode.QuestionData, lang string) *questionResult {
+	return &questionResult{
+		FrontendId: q.QuestionFrontendId,
+		Slug:       q.TitleSlug,
+		Title:      q.GetTitle(),
+		Lang:       lang,
+	}
+}
+
+func encodeJSON(cmd *cobra.Command, v any) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
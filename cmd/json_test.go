@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestVerdicts(t *testing.T) {
+	run := runVerdict(&leetcode.RunCheckResult{
+		StatusCode:         int(leetcode.Accepted),
+		StatusMsg:          "Accepted",
+		CompareResult:      "101",
+		CodeAnswer:         []string{"[0,1]", "[1,2]"},
+		ExpectedCodeAnswer: []string{"[0,1]", "[0,2]"},
+	})
+	// Runs that finish are accepted by LeetCode, but fail with wrong answers.
+	if run.Accepted || run.PassedCases != 2 || run.TotalCases != 3 || run.Output != "[0,1]\n[1,2]" || run.Expected != "[0,1]\n[0,2]" {
+		t.Errorf("runVerdict() = %+v", run)
+	}
+
+	submit := submitVerdict(&leetcode.SubmitCheckResult{
+		StatusCode:       int(leetcode.CompileError),
+		StatusMsg:        "Compile Error",
+		TotalTestcases:   10,
+		FullCompileError: "line 1: error",
+	})
+	if submit.Accepted || submit.Status != "Compile Error" || submit.TotalCases != 10 || submit.Error != "line 1: error" {
+		t.Errorf("submitVerdict() = %+v", submit)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	passed := true
+	qr := newQuestionResult(&leetcode.QuestionData{QuestionFrontendId: "1", TitleSlug: "two-sum", Title: "Two Sum"}, "golang")
+	qr.Local = &passed
+	qr.Errors = []string{"failed to run test remotely"}
+
+	var out strings.Builder
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := encodeJSON(cmd, []*questionResult{qr}); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "frontend_id": "1",
+    "slug": "two-sum",
+    "title": "Two Sum",
+    "lang": "golang",
+    "local_passed": true,
+    "errors": [
+      "failed to run test remotely"
+    ]
+  }
+]
+`
+	if out.String() != want {
+		t.Errorf("encodeJSON() = %s, want %s", out.String(), want)
+	}
+}
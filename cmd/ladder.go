@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// maxLadderRating is higher than the ratings of all existing questions.
+const maxLadderRating = 4000
+
+var (
+	ladderStart int
+	ladderStep  int
+	ladderReset bool
+)
+
+func init() {
+	planLadderCmd.Flags().IntVar(&ladderStart, "start", 1200, "rating of the first rung")
+	planLadderCmd.Flags().IntVar(&ladderStep, "step", 100, "rating increment between rungs")
+	planLadderCmd.Flags().BoolVar(&ladderReset, "reset", false, "discard the current ladder progress and start over")
+	planLadderCmd.Flags().BoolVarP(&skipEditor, "skip-editor", "", false, "Skip opening the editor")
+
+	planCmd.AddCommand(planLadderCmd)
+}
+
+var planLadderCmd = &cobra.Command{
+	Use:   "ladder",
+	Short: "Climb a rating ladder built from community difficulty ratings",
+	Long: `Climb a rating ladder built from community difficulty ratings.
+
+Each run generates a question from the current rung, whose rating is within [start+rung*step, start+(rung+1)*step).
+Once the question is accepted, the next run moves to the next rung.
+Ratings from https://github.com/zerotrac/leetcode_problem_rating are downloaded if none have been imported.`,
+	Example: `leetgo plan ladder
+leetgo plan ladder --start 1600 --step 50 --reset`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if ladderStep <= 0 {
+			return errors.New("step must be positive")
+		}
+		ratings, err := leetcode.LoadOrFetchRatings()
+		if err != nil {
+			return err
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		cache := leetcode.GetCache(c)
+
+		state := config.LoadState()
+		ladder := state.Ladder
+		flagsChanged := cmd.Flags().Changed("start") || cmd.Flags().Changed("step")
+		if ladder == nil || ladderReset ||
+			(flagsChanged && (ladder.Start != ladderStart || ladder.Step != ladderStep)) {
+			ladder = &config.LadderProgress{Start: ladderStart, Step: ladderStep}
+		}
+
+		if ladder.Current != "" {
+			q := cache.GetBySlug(ladder.Current)
+			qs, _ := state.Question(ladder.Current)
+			accepted := qs.Accepted() || (q != nil && q.IsSolved())
+			if !accepted {
+				log.Info(
+					"current question is not accepted yet",
+					"question", ladder.Current,
+					"rung", ladderRungRange(ladder),
+				)
+				return nil
+			}
+			ladder.Climbed = append(ladder.Climbed, ladder.Current)
+			ladder.Current = ""
+			ladder.Rung++
+		}
+
+		var q *leetcode.QuestionData
+		for ; ladder.Start+ladder.Rung*ladder.Step < maxLadderRating; ladder.Rung++ {
+			q = pickLadderQuestion(cache, ratings, state, ladder)
+			if q != nil {
+				break
+			}
+			log.Info("no available question in rung, skip", "rung", ladderRungRange(ladder))
+		}
+		if q == nil {
+			return errors.New("reached the top of the ladder, no more questions")
+		}
+		q.SetClient(c)
+		rating, _ := ratings.Get(q.TitleSlug)
+		log.Info("picked", "question", q.TitleSlug, "rating", int(rating), "rung", ladderRungRange(ladder))
+
+		result, err := lang.Generate(q)
+		if err != nil {
+			return err
+		}
+		if lang.DryRun() {
+			return nil
+		}
+
+		// Reload state, as it has been updated by `Generate`.
+		state = config.LoadState()
+		ladder.Current = q.TitleSlug
+		state.Ladder = ladder
+		config.SaveState(state)
+
+		return finishGenerate(cmd, result)
+	},
+}
+
+func ladderRungRange(ladder *config.LadderProgress) string {
+	lo := ladder.Start + ladder.Rung*ladder.Step
+	return fmt.Sprintf("%d-%d", lo, lo+ladder.Step)
+}
+
+func pickLadderQuestion(
+	cache leetcode.QuestionsCache,
+	ratings leetcode.Ratings,
+	state config.State,
+	ladder *config.LadderProgress,
+) *leetcode.QuestionData {
+	lo := float64(ladder.Start + ladder.Rung*ladder.Step)
+	hi := lo + float64(ladder.Step)
+
+	var candidates []*leetcode.QuestionData
+	for slug, r := range ratings {
+		if r.Rating < lo || r.Rating >= hi {
+			continue
+		}
+		if _, ok := state.Question(slug); ok {
+			continue
+		}
+		q := cache.GetBySlug(slug)
+		if q == nil || q.IsPaidOnly || q.IsSolved() {
+			continue
+		}
+		candidates = append(candidates, q)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rand.IntN(len(candidates))]
+}
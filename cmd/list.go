@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var (
+	listDifficulty string
+	listTags       []string
+	listFreeOnly   bool
+	listMinRating  float64
+	listMaxRating  float64
+	listStatus     string
+	listSearch     string
+	listCompany    string
+	listRecent     string
+	listSort       string
+	listFormat     string
+	listLimit      int
+)
+
+func init() {
+	listCmd.Flags().StringVarP(&listDifficulty, "difficulty", "d", "", "difficulty of questions: easy, medium or hard")
+	listCmd.Flags().StringSliceVarP(&listTags, "tag", "t", nil, "tag slugs the questions must have, e.g. array, dynamic-programming")
+	listCmd.Flags().BoolVar(&listFreeOnly, "free-only", false, "exclude paid only questions")
+	listCmd.Flags().Float64Var(&listMinRating, "min-rating", 0, "minimum difficulty rating of questions")
+	listCmd.Flags().Float64Var(&listMaxRating, "max-rating", 0, "maximum difficulty rating of questions")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "status of questions: accepted, attempted or new")
+	listCmd.Flags().StringVarP(&listSearch, "search", "s", "", "keyword in the id, slug or title")
+	listCmd.Flags().StringVar(&listCompany, "company", "", "slug of a company that asked the questions, e.g. amazon, requires LeetCode Premium")
+	listCmd.Flags().StringVar(&listRecent, "recent", "all", "period the company asked the questions in: 30d, 3m, 6m or all")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "sort by id, acceptance, frequency or rating, defaults to the order of the cache")
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", "table", "output format: table, tsv or json")
+	listCmd.Flags().IntVarP(&listLimit, "limit", "n", 0, "list at most n questions, 0 means no limit")
+
+	_ = listCmd.RegisterFlagCompletionFunc(
+		"difficulty",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"easy", "medium", "hard"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	_ = listCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	_ = listCmd.RegisterFlagCompletionFunc(
+		"status",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"accepted", "attempted", "new"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	_ = listCmd.RegisterFlagCompletionFunc("recent", completeCompanyPeriods)
+	_ = listCmd.RegisterFlagCompletionFunc(
+		"sort",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"id", "acceptance", "frequency", "rating"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	_ = listCmd.RegisterFlagCompletionFunc(
+		"format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"table", "tsv", "json"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List questions in the local cache",
+	Long: `List questions in the local cache, with their status, acceptance rate, frequency and rating.
+
+The status is "accepted" if the question is solved on LeetCode or accepted by leetgo, "attempted" if it is tried
+or generated, and "new" otherwise. Frequency is only provided by leetcode.com to premium users, and ratings are
+shown if imported by 'leetgo cache ratings'. With --company, frequency is how often the company asked the question
+in the recent period, the questions of a company are cached for a week.
+
+The tsv format prints one question per line without header, to feed fuzzy finders and editor pickers:
+id, slug, title, difficulty, status, acceptance, frequency, rating. Unknown values are empty.`,
+	Example: `leetgo list -d hard -t graph --status new
+leetgo list --sort frequency -n 50
+leetgo list --company amazon --recent 6m --sort frequency
+leetgo list -f tsv | fzf --with-nth 1,3 --delimiter '\t' | cut -f2 | xargs leetgo pick`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch strings.ToLower(listDifficulty) {
+		case "", "easy", "medium", "hard":
+		default:
+			return errors.New("invalid difficulty, only easy, medium or hard is supported")
+		}
+		switch listStatus {
+		case "", "accepted", "attempted", "new":
+		default:
+			return errors.New("invalid status, only accepted, attempted or new is supported")
+		}
+		switch listSort {
+		case "", "id", "acceptance", "frequency", "rating":
+		default:
+			return errors.New("invalid sort, only id, acceptance, frequency or rating is supported")
+		}
+		format := listFormat
+		if config.JSONOutput() {
+			format = "json"
+		}
+		switch format {
+		case "table", "tsv", "json":
+		default:
+			return errors.New("invalid format, only table, tsv or json is supported")
+		}
+
+		var (
+			ratings leetcode.Ratings
+			err     error
+		)
+		if listMinRating > 0 || listMaxRating > 0 || listSort == "rating" {
+			ratings, err = leetcode.LoadOrFetchRatings()
+		} else {
+			ratings, err = leetcode.LoadRatings()
+			if errors.Is(err, leetcode.ErrRatingsNotFound) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		all := leetcode.GetCache(c).GetAllQuestions()
+		if len(all) == 0 {
+			return errors.New("no questions in cache, try updating with `leetgo cache update`")
+		}
+		if len(listTags) > 0 && !leetcode.HasTags(all) {
+			return errors.New("cached questions have no tags, filtering by tags is not supported for this site")
+		}
+
+		var company *leetcode.CompanyQuestions
+		if listCompany != "" {
+			company, err = leetcode.LoadOrFetchCompanyQuestions(c, listCompany, listRecent)
+			if err != nil {
+				return err
+			}
+		}
+		frequency := func(q *leetcode.QuestionData) float64 {
+			if company != nil {
+				f, _ := company.Frequency(q.TitleSlug)
+				return f
+			}
+			return q.Frequency
+		}
+
+		state := config.LoadState()
+		filter := leetcode.LocalFilter{
+			Difficulty: listDifficulty,
+			Tags:       listTags,
+			FreeOnly:   listFreeOnly,
+			Keyword:    listSearch,
+			MinRating:  listMinRating,
+			MaxRating:  listMaxRating,
+			Ratings:    ratings,
+			Company:    company,
+		}
+		if listStatus != "" {
+			filter.Exclude = func(q *leetcode.QuestionData) bool {
+				return questionStatus(q, state) != listStatus
+			}
+		}
+		qs := leetcode.FilterQuestions(all, filter)
+		sortQuestions(qs, listSort, ratings, frequency)
+		if listLimit > 0 && len(qs) > listLimit {
+			qs = qs[:listLimit]
+		}
+
+		items := make([]listItem, 0, len(qs))
+		for _, q := range qs {
+			items = append(items, newListItem(q, questionStatus(q, state), frequency(q), ratings))
+		}
+		switch format {
+		case "json":
+			return encodeJSON(cmd, items)
+		case "tsv":
+			showListTSV(cmd, items)
+		default:
+			showList(cmd, items)
+		}
+		return nil
+	},
+}
+
+// questionStatus returns the status of the question on LeetCode or in the local state: accepted, attempted or new.
+func questionStatus(q *leetcode.QuestionData, state config.State) string {
+	qs, generated := state.Question(q.TitleSlug)
+	switch {
+	case q.IsSolved() || generated && qs.Accepted():
+		return "accepted"
+	case generated || strings.EqualFold(q.Status, "notac") || strings.EqualFold(q.Status, "TRIED"):
+		return "attempted"
+	}
+	return "new"
+}
+
+func sortQuestions(
+	qs []*leetcode.QuestionData,
+	by string,
+	ratings leetcode.Ratings,
+	frequency func(q *leetcode.QuestionData) float64,
+) {
+	switch by {
+	case "id":
+		slices.SortStableFunc(
+			qs, func(a, b *leetcode.QuestionData) int {
+				ia, errA := strconv.Atoi(a.QuestionFrontendId)
+				ib, errB := strconv.Atoi(b.QuestionFrontendId)
+				if errA != nil || errB != nil {
+					return cmp.Compare(a.QuestionFrontendId, b.QuestionFrontendId)
+				}
+				return cmp.Compare(ia, ib)
+			},
+		)
+	case "acceptance":
+		slices.SortStableFunc(
+			qs, func(a, b *leetcode.QuestionData) int {
+				ra, _ := a.AcceptanceRate()
+				rb, _ := b.AcceptanceRate()
+				return cmp.Compare(rb, ra)
+			},
+		)
+	case "frequency":
+		slices.SortStableFunc(
+			qs, func(a, b *leetcode.QuestionData) int {
+				return cmp.Compare(frequency(b), frequency(a))
+			},
+		)
+	case "rating":
+		leetcode.SortByRating(qs, ratings)
+	}
+}
+
+// listItem is a question in the output of list.
+type listItem struct {
+	FrontendID string   `json:"frontend_id"`
+	Slug       string   `json:"slug"`
+	Title      string   `json:"title"`
+	Difficulty string   `json:"difficulty"`
+	PaidOnly   bool     `json:"paid_only"`
+	Status     string   `json:"status"`
+	Tags       []string `json:"tags,omitempty"`
+	Acceptance *float64 `json:"acceptance,omitempty"`
+	Frequency  *float64 `json:"frequency,omitempty"`
+	Rating     *float64 `json:"rating,omitempty"`
+	URL        string   `json:"url"`
+}
+
+func newListItem(q *leetcode.QuestionData, status string, frequency float64, ratings leetcode.Ratings) listItem {
+	item := listItem{
+		FrontendID: q.QuestionFrontendId,
+		Slug:       q.TitleSlug,
+		Title:      q.GetTitle(),
+		Difficulty: q.Difficulty,
+		PaidOnly:   q.IsPaidOnly,
+		Status:     status,
+		Tags:       q.TagSlugs(),
+		URL:        q.Url(),
+	}
+	if rate, ok := q.AcceptanceRate(); ok {
+		item.Acceptance = &rate
+	}
+	if frequency > 0 {
+		item.Frequency = &frequency
+	}
+	if rating, ok := ratings.Get(q.TitleSlug); ok {
+		item.Rating = &rating
+	}
+	return item
+}
+
+func formatOptional(v *float64, format string) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf(format, *v)
+}
+
+func showListTSV(cmd *cobra.Command, items []listItem) {
+	for _, it := range items {
+		cmd.Println(
+			strings.Join(
+				[]string{
+					it.FrontendID,
+					it.Slug,
+					it.Title,
+					it.Difficulty,
+					it.Status,
+					formatOptional(it.Acceptance, "%.1f"),
+					formatOptional(it.Frequency, "%.1f"),
+					formatOptional(it.Rating, "%.0f"),
+				}, "\t",
+			),
+		)
+	}
+}
+
+func showList(cmd *cobra.Command, items []listItem) {
+	if config.Get().UsePlainOutput() {
+		for _, it := range items {
+			cmd.Printf("%s. %s, %s, %s", it.FrontendID, it.Title, it.Difficulty, it.Status)
+			if it.Acceptance != nil {
+				cmd.Printf(", acceptance %.1f%%", *it.Acceptance)
+			}
+			if it.Frequency != nil {
+				cmd.Printf(", frequency %.1f", *it.Frequency)
+			}
+			if it.Rating != nil {
+				cmd.Printf(", rating %.0f", *it.Rating)
+			}
+			cmd.Println()
+		}
+		cmd.Printf("%d questions\n", len(items))
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.SetTitle(fmt.Sprintf("%d questions", len(items)))
+	w.AppendHeader(table.Row{"#", "Title", "Difficulty", "Status", "Acceptance", "Frequency", "Rating"})
+	for _, it := range items {
+		status := it.Status
+		switch status {
+		case "accepted":
+			status = config.PassedStyle.Render(status)
+		case "attempted":
+			status = config.FailedStyle.Render(status)
+		}
+		w.AppendRow(
+			table.Row{
+				it.FrontendID,
+				it.Title,
+				it.Difficulty,
+				status,
+				formatOptional(it.Acceptance, "%.1f%%"),
+				formatOptional(it.Frequency, "%.1f"),
+				formatOptional(it.Rating, "%.0f"),
+			},
+		)
+	}
+	w.Render()
+}
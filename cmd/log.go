@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/utils"
+)
+
+// Formats of logs on stderr, set by --log-format.
+const (
+	logFormatAuto = "auto"
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+const (
+	logFileMaxSize = 5 << 20
+	logFileBackups = 3
+)
+
+// initLogger sets up logs on stderr: pretty text in a terminal, JSON lines when stderr is redirected.
+// --verbose shows debug logs, --quiet shows only warnings and errors. DEBUG=1 shows debug logs with timestamps as well.
+func initLogger() error {
+	format := viper.GetString("log-format")
+	switch format {
+	case logFormatAuto:
+		format = logFormatText
+		if !isatty.IsTerminal(os.Stderr.Fd()) && !isatty.IsCygwinTerminal(os.Stderr.Fd()) {
+			format = logFormatJSON
+		}
+	case logFormatText, logFormatJSON:
+	default:
+		return fmt.Errorf("invalid log format %q, only auto, text or json is supported", format)
+	}
+
+	level := log.InfoLevel
+	if config.Debug || viper.GetBool("verbose") {
+		level = log.DebugLevel
+	} else if viper.GetBool("quiet") {
+		level = log.WarnLevel
+	}
+	log.SetLevel(level)
+
+	if format == logFormatJSON {
+		log.SetFormatter(log.JSONFormatter)
+		log.SetReportTimestamp(true)
+		log.SetTimeFormat(time.RFC3339)
+		return nil
+	}
+	log.SetFormatter(log.TextFormatter)
+	if config.Debug {
+		log.SetReportTimestamp(true)
+		return nil
+	}
+	style := log.DefaultStyles()
+	style.Levels[log.DebugLevel] = style.Levels[log.DebugLevel].SetString("●")
+	style.Levels[log.InfoLevel] = style.Levels[log.InfoLevel].SetString("●")
+	style.Levels[log.WarnLevel] = style.Levels[log.WarnLevel].SetString("●")
+	style.Levels[log.ErrorLevel] = style.Levels[log.ErrorLevel].SetString("×")
+	style.Levels[log.FatalLevel] = style.Levels[log.FatalLevel].SetString("×")
+	// Only text logs are translated, JSON logs are for machines.
+	style.Message = style.Message.Transform(i18n.T)
+	log.SetStyles(style)
+	log.SetReportTimestamp(false)
+	return nil
+}
+
+// initLogFile writes debug logs to the log file besides stderr if log.file is enabled.
+// It's called after the config is loaded and the stderr logger is set up.
+func initLogFile() {
+	if !config.Get().Log.File {
+		return
+	}
+	file := config.Get().LogFile()
+	f, err := utils.OpenLogFile(file, logFileMaxSize, logFileBackups)
+	if err != nil {
+		log.Warn("failed to open log file", "file", file, "err", err)
+		return
+	}
+	logger := log.NewWithOptions(
+		&logTee{file: f, console: log.Default()},
+		log.Options{Level: log.DebugLevel, Formatter: log.JSONFormatter, ReportTimestamp: true, TimeFormat: time.RFC3339},
+	)
+	log.SetDefault(logger)
+	log.Debug("command started", "args", os.Args[1:], "version", constants.Version)
+}
+
+// logTee writes every log entry to the file, and passes the entries above the level of the console logger on to it.
+// Entries come as JSON lines from a logger at debug level. Numbers are passed on as written,
+// errors and durations are already formatted as strings by the JSON formatter, so the console shows the same text.
+type logTee struct {
+	file    io.Writer
+	console *log.Logger
+}
+
+func (t *logTee) Write(p []byte) (int, error) {
+	_, _ = t.file.Write(p)
+
+	var entry map[string]any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil {
+		return len(p), nil
+	}
+	level, _ := log.ParseLevel(fmt.Sprint(entry[log.LevelKey]))
+	if level < t.console.GetLevel() {
+		return len(p), nil
+	}
+	msg := entry[log.MessageKey]
+	delete(entry, log.MessageKey)
+	delete(entry, log.LevelKey)
+	delete(entry, log.TimestampKey)
+	delete(entry, log.CallerKey)
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	keyvals := make([]any, 0, len(entry)*2)
+	for _, k := range keys {
+		keyvals = append(keyvals, k, entry[k])
+	}
+	t.console.Log(level, msg, keyvals...)
+	return len(p), nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+)
+
+func TestLogTee(t *testing.T) {
+	var file, console bytes.Buffer
+	consoleLogger := log.NewWithOptions(&console, log.Options{Level: log.InfoLevel, Formatter: log.TextFormatter})
+	logger := log.NewWithOptions(
+		&logTee{file: &file, console: consoleLogger},
+		log.Options{Level: log.DebugLevel, Formatter: log.JSONFormatter, ReportTimestamp: true, TimeFormat: time.RFC3339},
+	)
+
+	logger.Debug("request sent", "url", "https://leetcode.com")
+	logger.Info(
+		"downloaded", "size", 1048576, "ratio", 0.5, "err", errors.New("connection reset"),
+		"took", 1500*time.Millisecond, "cached", true,
+	)
+
+	if got := strings.Count(file.String(), "\n"); got != 2 {
+		t.Errorf("file has %d entries, want 2:\n%s", got, file.String())
+	}
+	want := "INFO downloaded cached=true err=\"connection reset\" ratio=0.5 size=1048576 took=1.5s\n"
+	if got := console.String(); got != want {
+		t.Errorf("console = %q, want %q", got, want)
+	}
+}
+
+func TestInitLogger(t *testing.T) {
+	old := log.Default()
+	t.Cleanup(
+		func() {
+			log.SetDefault(old)
+			viper.Set("log-format", logFormatAuto)
+			viper.Set("verbose", false)
+			viper.Set("quiet", false)
+		},
+	)
+	log.SetDefault(log.New(&bytes.Buffer{}))
+
+	viper.Set("log-format", "xml")
+	if err := initLogger(); err == nil {
+		t.Errorf("initLogger() with an invalid format should fail")
+	}
+
+	cases := []struct {
+		format         string
+		verbose, quiet bool
+		level          log.Level
+	}{
+		{logFormatJSON, false, false, log.InfoLevel},
+		{logFormatText, true, false, log.DebugLevel},
+		{logFormatAuto, false, true, log.WarnLevel},
+	}
+	for _, c := range cases {
+		viper.Set("log-format", c.format)
+		viper.Set("verbose", c.verbose)
+		viper.Set("quiet", c.quiet)
+		if err := initLogger(); err != nil {
+			t.Fatal(err)
+		}
+		if got := log.GetLevel(); got != c.level {
+			t.Errorf("%s: level = %s, want %s", c.format, got, c.level)
+		}
+	}
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	viper.Set("log-format", logFormatJSON)
+	viper.Set("quiet", false)
+	if err := initLogger(); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("hello")
+	if !strings.HasPrefix(out.String(), "{") || !strings.Contains(out.String(), `"msg":"hello"`) {
+		t.Errorf("JSON log = %q", out.String())
+	}
+
+	// Messages of text logs are translated, JSON logs stay in English.
+	t.Setenv("LC_ALL", "zh_CN.UTF-8")
+	out.Reset()
+	log.Info("cache updated")
+	if !strings.Contains(out.String(), `"msg":"cache updated"`) {
+		t.Errorf("JSON log = %q, want it in English", out.String())
+	}
+	viper.Set("log-format", logFormatText)
+	if err := initLogger(); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	log.Info("cache updated")
+	if !strings.Contains(out.String(), "缓存已更新") {
+		t.Errorf("text log = %q, want it translated", out.String())
+	}
+}
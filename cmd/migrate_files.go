@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// migrateJobName is the job recording the progress of migrate-files.
+const migrateJobName = "migrate-files"
+
+var migrateResume bool
+
+func init() {
+	migrateFilesCmd.Flags().BoolVar(&migrateResume, "resume", false, "continue the last interrupted migration, skipping questions done")
+}
+
+var migrateFilesCmd = &cobra.Command{
+	Use:   "migrate-files [qid...]",
+	Short: "Upgrade files generated by older versions to the current template",
+	Long: `Upgrade code files generated by older versions of leetgo to the current template format.
+A file is outdated if it uses the code markers of older versions, or the version in its header is older than leetgo.
+The solution code between the code markers is kept, generated test files are rewritten.
+Without qid, all questions generated in the current language in this project are checked.`,
+	Example: `leetgo migrate-files
+leetgo migrate-files 1 2 --dry-run
+leetgo migrate-files --resume`,
+	ValidArgsFunction: completeQuestions(-1, "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return err
+		}
+
+		if migrateResume && len(args) == 0 {
+			job := config.LoadJob(migrateJobName)
+			if job == nil {
+				return errors.New("no interrupted migration to resume")
+			}
+			args = job.Pending()
+			if len(args) == 0 {
+				job.Finish()
+				log.Info("all questions already upgraded")
+				return nil
+			}
+		}
+
+		var qs []*leetcode.QuestionData
+		if len(args) == 0 {
+			qs = questionsGeneratedIn(c, gen)
+		}
+		for _, qid := range args {
+			found, err := leetcode.ParseQID(qid, c)
+			if err != nil {
+				return err
+			}
+			qs = append(qs, found...)
+		}
+
+		var job *config.Job
+		if !lang.DryRun() {
+			slugs := make([]string, len(qs))
+			for i, q := range qs {
+				slugs[i] = q.TitleSlug
+			}
+			job = config.StartJob(migrateJobName, slugs, migrateResume)
+			pending := job.Pending()
+			qs = slices.DeleteFunc(qs, func(q *leetcode.QuestionData) bool { return !slices.Contains(pending, q.TitleSlug) })
+		}
+
+		state := config.LoadState()
+		upgraded := 0
+		for _, q := range qs {
+			ok, err := lang.MigrateFiles(q)
+			if err != nil {
+				log.Error("failed to upgrade", "question", q.TitleSlug, "err", err)
+				job.Fail(q.TitleSlug, err)
+				continue
+			}
+			if ok {
+				upgraded++
+				state.MarkGenerated(q.TitleSlug, q.QuestionFrontendId, gen.Slug())
+			}
+			job.Done(q.TitleSlug)
+		}
+		job.Finish()
+		if lang.DryRun() {
+			cmd.Printf("%d of %d questions would be upgraded\n", upgraded, len(qs))
+			return nil
+		}
+		if upgraded > 0 {
+			config.SaveState(state)
+		}
+		cmd.Printf("%d of %d questions upgraded\n", upgraded, len(qs))
+		return nil
+	},
+}
+
+// questionsGeneratedIn returns the questions generated in the language in this project on the configured site.
+func questionsGeneratedIn(c leetcode.Client, gen lang.Lang) []*leetcode.QuestionData {
+	state := config.LoadState()
+	slugs := state.GeneratedSlugs(gen.Slug())
+
+	var qs []*leetcode.QuestionData
+	for _, slug := range slugs {
+		q, err := leetcode.QuestionBySlug(slug, c)
+		if err != nil {
+			log.Warn("failed to get question", "question", slug, "err", err)
+			continue
+		}
+		qs = append(qs, q)
+	}
+	return qs
+}
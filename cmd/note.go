@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/editor"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Manage notes of questions",
+	Long: `Manage notes of questions, stored as markdown files in notes_dir of the project.
+Notes can be synced with the notes on LeetCode by 'leetgo note pull' and 'leetgo note push'.
+Templates can link to the note by {{ .NoteFile }}, or embed it by {{ .Note }}.`,
+}
+
+func init() {
+	noteCmd.AddCommand(noteEditCmd, noteListCmd, noteSearchCmd, notePullCmd, notePushCmd)
+}
+
+// parseSingleQuestion parses the qid that must refer to exactly one question.
+func parseSingleQuestion(qid string, c leetcode.Client) (*leetcode.QuestionData, error) {
+	qs, err := leetcode.ParseQID(qid, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(qs) > 1 {
+		return nil, errors.New("multiple questions found")
+	}
+	return qs[0], nil
+}
+
+func noteHeader(q *leetcode.QuestionData) string {
+	return fmt.Sprintf("# %s. %s\n\n%s\n\n", q.QuestionFrontendId, q.GetTitle(), q.Url())
+}
+
+var noteEditCmd = &cobra.Command{
+	Use:               "edit qid",
+	Short:             "Open the note of a question in editor, it's created if not exists",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if config.SafeMode() {
+			return fmt.Errorf("note edit: %w", config.ErrSafeMode)
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		file := config.Get().NoteFile(q.TitleSlug)
+		if lang.DryRun() {
+			log.Info("would edit note", "file", utils.RelToCwd(file))
+			return nil
+		}
+		if !utils.IsExist(file) {
+			err = utils.WriteFile(file, []byte(noteHeader(q)))
+			if err != nil {
+				return err
+			}
+			log.Info("note created", "file", utils.RelToCwd(file))
+		}
+
+		result := &lang.GenerateResult{Question: q, OutDir: filepath.Dir(file)}
+		result.AddFile(lang.FileOutput{Filename: filepath.Base(file), Type: lang.DocFile})
+		return editor.Open(result)
+	},
+}
+
+type noteInfo struct {
+	Slug       string    `json:"slug"`
+	FrontendId string    `json:"frontend_id"`
+	Title      string    `json:"title"`
+	File       string    `json:"file"`
+	Lines      int       `json:"lines"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// listNotes returns the notes of questions on the configured site, most recently updated first.
+func listNotes(c leetcode.Client) ([]noteInfo, error) {
+	cfg := config.Get()
+	if legacy, _ := filepath.Glob(filepath.Join(cfg.NotesDir(), "*.md")); len(legacy) > 0 {
+		log.Warn("notes of older versions are not shown, run 'leetgo config migrate' to move them", "count", len(legacy))
+	}
+	notes, err := readNotes(cfg.SiteNotesDir())
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range notes {
+		if q, err := leetcode.QuestionFromCacheBySlug(n.Slug, c); err == nil {
+			notes[i].FrontendId = q.QuestionFrontendId
+			notes[i].Title = q.GetTitle()
+		}
+	}
+	return notes, nil
+}
+
+// readNotes returns the notes in the directory, most recently updated first.
+func readNotes(dir string) ([]noteInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var notes []noteInfo
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		n := noteInfo{
+			Slug:      strings.TrimSuffix(e.Name(), ".md"),
+			File:      file,
+			Lines:     len(utils.SplitLines(strings.TrimSpace(string(content)))),
+			UpdatedAt: info.ModTime(),
+		}
+		notes = append(notes, n)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].UpdatedAt.After(notes[j].UpdatedAt) })
+	return notes, nil
+}
+
+var noteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List notes of questions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		notes, err := listNotes(c)
+		if err != nil {
+			return err
+		}
+		if config.JSONOutput() {
+			if notes == nil {
+				notes = []noteInfo{}
+			}
+			return encodeJSON(cmd, notes)
+		}
+		if len(notes) == 0 {
+			log.Info("no notes found", "dir", utils.RelToCwd(config.Get().SiteNotesDir()))
+			return nil
+		}
+		if config.Get().UsePlainOutput() {
+			for _, n := range notes {
+				cmd.Printf(
+					"%s. %s (%s), %d lines, updated at %s\n",
+					n.FrontendId, n.Title, n.Slug, n.Lines, n.UpdatedAt.Format(time.DateTime),
+				)
+			}
+			return nil
+		}
+		w := table.NewWriter()
+		w.SetOutputMirror(cmd.OutOrStdout())
+		w.SetStyle(table.StyleColoredDark)
+		w.AppendHeader(table.Row{"ID", "Question", "Lines", "Updated"})
+		for _, n := range notes {
+			title := n.Title
+			if title == "" {
+				title = n.Slug
+			}
+			w.AppendRow(table.Row{n.FrontendId, title, n.Lines, n.UpdatedAt.Format(time.DateTime)})
+		}
+		w.Render()
+		return nil
+	},
+}
+
+type noteMatch struct {
+	Slug string `json:"slug"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+var noteSearchCmd = &cobra.Command{
+	Use:     "search pattern",
+	Short:   "Search notes of questions, the pattern is a case-insensitive regular expression",
+	Example: `leetgo note search "monotonic stack"`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		re, err := regexp.Compile("(?i)" + args[0])
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		notes, err := listNotes(c)
+		if err != nil {
+			return err
+		}
+		matches := []noteMatch{}
+		for _, n := range notes {
+			content, err := os.ReadFile(n.File)
+			if err != nil {
+				continue
+			}
+			for i, line := range utils.SplitLines(string(content)) {
+				if re.MatchString(line) {
+					matches = append(matches, noteMatch{Slug: n.Slug, File: n.File, Line: i + 1, Text: strings.TrimSpace(line)})
+				}
+			}
+		}
+		if config.JSONOutput() {
+			return encodeJSON(cmd, matches)
+		}
+		if len(matches) == 0 {
+			log.Info("no matches found")
+			return nil
+		}
+		for _, m := range matches {
+			cmd.Printf("%s:%d: %s\n", utils.RelToCwd(m.File), m.Line, m.Text)
+		}
+		return nil
+	},
+}
+
+var notePullCmd = &cobra.Command{
+	Use:               "pull qid",
+	Short:             "Download the note of a question from LeetCode",
+	Long:              "Download the note of a question from LeetCode, an existing local note is only overwritten with --force.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		return pullNote(c, q, viper.GetBool("force"))
+	},
+}
+
+func pullNote(c leetcode.Client, q *leetcode.QuestionData, force bool) error {
+	err := q.Fulfill()
+	if err != nil {
+		return err
+	}
+	content, err := c.GetNote(q)
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		log.Info("no note on LeetCode", "question", q.TitleSlug)
+		return nil
+	}
+	file := config.Get().NoteFile(q.TitleSlug)
+	if utils.IsExist(file) && !force {
+		return fmt.Errorf("note %s already exists, use --force to overwrite it", utils.RelToCwd(file))
+	}
+	if lang.DryRun() {
+		log.Info("would write note", "file", utils.RelToCwd(file))
+		return nil
+	}
+	err = utils.WriteFile(file, []byte(utils.EnsureTrailingNewline(content)))
+	if err != nil {
+		return err
+	}
+	log.Info("note pulled", "file", utils.RelToCwd(file))
+	return nil
+}
+
+var notePushCmd = &cobra.Command{
+	Use:               "push qid",
+	Short:             "Upload the note of a question to LeetCode, replacing the note there",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		return pushNote(c, q)
+	},
+}
+
+func pushNote(c leetcode.Client, q *leetcode.QuestionData) error {
+	file := config.Get().NoteFile(q.TitleSlug)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+	if lang.DryRun() {
+		log.Info("would push note", "file", utils.RelToCwd(file))
+		return nil
+	}
+	err = q.Fulfill()
+	if err != nil {
+		return err
+	}
+	err = c.UpdateNote(q, string(content))
+	if err != nil {
+		return err
+	}
+	log.Info("note pushed", "question", q.TitleSlug)
+	return nil
+}
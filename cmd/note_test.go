@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+type fakeNoteClient struct {
+	leetcode.Client
+	note   string
+	pushed string
+}
+
+func (c *fakeNoteClient) GetNote(*leetcode.QuestionData) (string, error) {
+	return c.note, nil
+}
+
+func (c *fakeNoteClient) UpdateNote(_ *leetcode.QuestionData, content string) error {
+	c.pushed = content
+	return nil
+}
+
+// chdir changes the working directory, and so the project root, for the test.
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestPullAndPushNote(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	chdir(t, t.TempDir())
+	q := &leetcode.QuestionData{TitleSlug: "two-sum"}
+	file := config.Get().NoteFile(q.TitleSlug)
+	c := &fakeNoteClient{note: "use a map"}
+
+	viper.Set("dry-run", true)
+	t.Cleanup(func() { viper.Set("dry-run", false) })
+	if err := pullNote(c, q, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("dry run should not write the note: %v", err)
+	}
+	viper.Set("dry-run", false)
+
+	if err := pullNote(c, q, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "use a map\n" {
+		t.Errorf("pulled note = %q", data)
+	}
+	c.note = "use two pointers"
+	if err := pullNote(c, q, false); err == nil {
+		t.Errorf("pullNote() should not overwrite the local note without force")
+	}
+	if err := pullNote(c, q, true); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "use two pointers\n" {
+		t.Errorf("pulled note with force = %q", data)
+	}
+
+	if err := pushNote(c, q); err != nil {
+		t.Fatal(err)
+	}
+	if c.pushed != "use two pointers\n" {
+		t.Errorf("pushed note = %q", c.pushed)
+	}
+	if err := pushNote(c, &leetcode.QuestionData{TitleSlug: "add-two-numbers"}); err == nil {
+		t.Errorf("pushNote() without a local note should fail")
+	}
+}
+
+func TestReadNotes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string, modTime time.Time) {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write("two-sum.md", "# 1. Two Sum\n\nuse a map\n", now.Add(-time.Hour))
+	write("add-two-numbers.md", "carry\n", now)
+	write("notes.txt", "not a note", now)
+	if err := os.Mkdir(filepath.Join(dir, "dir.md"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := readNotes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[0].Slug != "add-two-numbers" || notes[1].Slug != "two-sum" {
+		t.Fatalf("readNotes() = %+v, want the two notes, most recent first", notes)
+	}
+	if notes[1].Lines != 3 || notes[1].File != filepath.Join(dir, "two-sum.md") {
+		t.Errorf("note = %+v", notes[1])
+	}
+
+	if notes, err = readNotes(filepath.Join(dir, "missing")); err != nil || notes != nil {
+		t.Errorf("readNotes() of a missing directory = %v, %v", notes, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/cli/browser"
 	"github.com/spf13/cobra"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
 )
 
@@ -13,8 +14,8 @@ var openCmd = &cobra.Command{
 	Example: `leetgo open today
 leetgo open 549
 leetgo open w330/`,
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qid := args[0]
@@ -23,6 +24,10 @@ leetgo open w330/`,
 			return err
 		}
 		for _, q := range qs {
+			if config.SafeMode() {
+				cmd.Println(q.Url())
+				continue
+			}
 			if q.IsContest() {
 				err = browser.OpenURL(q.ContestUrl())
 			} else {
@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
-	"github.com/j178/leetgo/editor"
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 )
@@ -29,7 +34,7 @@ func askFilter(c leetcode.Client) (filter leetcode.QuestionFilter, err error) {
 		{
 			Name: "Difficulty",
 			Prompt: &survey.Select{
-				Message: "Select a difficulty level",
+				Message: i18n.T("Select a difficulty level"),
 				Options: []string{"All", "Easy", "Medium", "Hard"},
 			},
 			Transform: func(ans interface{}) (newAns interface{}) {
@@ -44,7 +49,7 @@ func askFilter(c leetcode.Client) (filter leetcode.QuestionFilter, err error) {
 		{
 			Name: "Status",
 			Prompt: &survey.Select{
-				Message: "Select question status",
+				Message: i18n.T("Select question status"),
 				Options: []string{"All", "Not Started", "Tried", "Ac"},
 			},
 			Transform: func(ans interface{}) (newAns interface{}) {
@@ -59,7 +64,7 @@ func askFilter(c leetcode.Client) (filter leetcode.QuestionFilter, err error) {
 		{
 			Name: "Tags",
 			Prompt: &survey.MultiSelect{
-				Message: "Select tags",
+				Message: i18n.T("Select tags"),
 				Options: tagNames,
 			},
 			Transform: func(ans interface{}) (newAns interface{}) {
@@ -78,7 +83,7 @@ func askFilter(c leetcode.Client) (filter leetcode.QuestionFilter, err error) {
 		},
 	}
 
-	err = survey.Ask(qs, &filter, survey.WithRemoveSelectAll())
+	err = config.Ask(qs, &filter, survey.WithRemoveSelectAll())
 	if err != nil {
 		return
 	}
@@ -86,42 +91,118 @@ func askFilter(c leetcode.Client) (filter leetcode.QuestionFilter, err error) {
 	return filter, nil
 }
 
-var skipEditor bool
+var (
+	skipEditor       bool
+	pickSortByRating bool
+	pickFromJSON     string
+	pickFreeOnly     bool
+	pickRandom       bool
+	pickSeed         string
+	pickResume       bool
+)
 
 func init() {
 	pickCmd.Flags().BoolVarP(&skipEditor, "skip-editor", "", false, "Skip opening the editor")
+	pickCmd.Flags().BoolVar(&pickSortByRating, "sort-by-rating", false, "sort questions by imported difficulty ratings")
+	pickCmd.Flags().StringVar(&pickFromJSON, "from-json", "", "generate from a saved question data or GraphQL response file, without fetching")
+	pickCmd.Flags().BoolVar(&pickFreeOnly, "free-only", false, "exclude paid only questions")
+	pickCmd.Flags().BoolVar(&pickRandom, "random", false, "pick a random unsolved question, see also `leetgo random`")
+	pickCmd.Flags().StringVar(&pickSeed, "seed", "", "pick deterministically from the seed, 'daily' for the date of today, implies --random")
+	pickCmd.Flags().BoolVar(&pickResume, "resume", false, "continue the last interrupted pick of multiple questions, skipping questions generated")
+	_ = pickCmd.MarkFlagFilename("from-json", "json")
 }
 
 var pickCmd = &cobra.Command{
-	Use:   "pick [qid]",
+	Use:   "pick [qid...]",
 	Short: "Generate a new question",
 	Example: `leetgo pick  # show a list of questions to pick
 leetgo pick today
 leetgo pick 549
-leetgo pick two-sum`,
-	Args:      cobra.MaximumNArgs(1),
-	Aliases:   []string{"p"},
-	ValidArgs: []string{"today", "yesterday"},
+leetgo pick two-sum
+leetgo pick 1 2 3
+leetgo pick --random --seed daily
+leetgo gen --from-json question.json`,
+	Args:              cobra.ArbitraryArgs,
+	Aliases:           []string{"p", "gen"},
+	ValidArgsFunction: completeQuestions(-1, "today", "yesterday"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		var q *leetcode.QuestionData
 
-		if len(args) > 0 {
-			qid := args[0]
-			qs, err := leetcode.ParseQID(qid, c)
+		if pickSeed != "" {
+			pickRandom = true
+		}
+		if pickRandom && (pickFromJSON != "" || len(args) > 0) {
+			return errors.New("--random cannot be used with qid or --from-json")
+		}
+		if pickResume && (pickRandom || pickFromJSON != "") {
+			return errors.New("--resume cannot be used with --random or --from-json")
+		}
+		if pickResume && len(args) == 0 {
+			job := config.LoadJob(pickJobName)
+			if job == nil {
+				return errors.New("no interrupted pick to resume")
+			}
+			args = job.Pending()
+			if len(args) == 0 {
+				job.Finish()
+				log.Info("all questions already generated")
+				return nil
+			}
+		}
+		if pickRandom {
+			var err error
+			q, err = randomQuestion(c, leetcode.LocalFilter{FreeOnly: pickFreeOnly}, pickSeed)
+			if err != nil {
+				return err
+			}
+		} else if pickFromJSON != "" {
+			if len(args) > 0 {
+				return errors.New("qid cannot be used with --from-json")
+			}
+			data, err := os.ReadFile(pickFromJSON)
 			if err != nil {
 				return err
 			}
-			if len(qs) > 1 {
-				return fmt.Errorf("`leetgo pick` cannot handle multiple contest questions, use `leetgo contest` instead")
+			q, err = leetcode.QuestionFromJSON(data, c)
+			if err != nil {
+				return err
+			}
+		} else if len(args) > 0 {
+			var qs []*leetcode.QuestionData
+			for _, qid := range args {
+				found, err := leetcode.ParseQID(qid, c)
+				if err != nil {
+					return err
+				}
+				if len(found) > 1 {
+					return fmt.Errorf("`leetgo pick` cannot handle multiple contest questions, use `leetgo contest` instead")
+				}
+				if pickFreeOnly && found[0].IsPaidOnly {
+					log.Warn("skipped paid only question", "question", found[0].TitleSlug)
+					continue
+				}
+				qs = append(qs, found[0])
+			}
+			if len(qs) == 0 {
+				return nil
+			}
+			if len(qs) > 1 || pickResume {
+				return pickAll(cmd, qs)
 			}
 			q = qs[0]
 		} else {
+			if config.SafeMode() {
+				return fmt.Errorf("picking question interactively: %w", config.ErrSafeMode)
+			}
 			filter, err := askFilter(c)
 			if err != nil {
 				return err
 			}
-			m := newTuiModel(filter, c)
+			if pickFreeOnly {
+				filter.PremiumOnly = new(bool)
+			}
+			m := newTuiModel(filter, c, pickSortByRating)
 			p := tea.NewProgram(m)
 			if _, err := p.Run(); err != nil {
 				return err
@@ -136,10 +217,40 @@ leetgo pick two-sum`,
 		if err != nil {
 			return err
 		}
-		if !skipEditor {
-			err = editor.Open(result)
-			return err
-		}
-		return nil
+		return finishGenerate(cmd, result)
 	},
 }
+
+// pickJobName is the job recording the progress of picking multiple questions.
+const pickJobName = "pick"
+
+// pickAll generates multiple questions, questions failed to generate are reported at the end.
+// The progress is recorded as a job, with --resume questions generated by the last interrupted pick are skipped.
+func pickAll(cmd *cobra.Command, qs []*leetcode.QuestionData) error {
+	var job *config.Job
+	if !lang.DryRun() {
+		slugs := make([]string, len(qs))
+		for i, q := range qs {
+			slugs[i] = q.TitleSlug
+		}
+		job = config.StartJob(pickJobName, slugs, pickResume)
+		pending := job.Pending()
+		qs = slices.DeleteFunc(qs, func(q *leetcode.QuestionData) bool { return !slices.Contains(pending, q.TitleSlug) })
+		if len(qs) == 0 {
+			job.Finish()
+			log.Info("all questions already generated")
+			return nil
+		}
+	}
+	results, err := lang.GenerateAll(qs, job)
+	job.Finish()
+	if len(results) > 0 {
+		if finishErr := finishGenerate(cmd, results...); finishErr != nil {
+			return errors.Join(finishErr, err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("some questions failed to generate:\n%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var (
+	planNext     bool
+	planFreeOnly bool
+)
+
+func init() {
+	planCmd.Flags().BoolVarP(&planNext, "next", "n", false, "generate the next unsolved question of the plan")
+	planCmd.Flags().BoolVar(&planFreeOnly, "free-only", false, "skip paid only questions when picking the next question")
+	planCmd.Flags().BoolVarP(&skipEditor, "skip-editor", "", false, "Skip opening the editor")
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan slug",
+	Short: "Show study plan progress and pick questions from it",
+	Example: `leetgo plan leetcode-75
+leetgo plan top-interview-150 --next`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		plan, err := c.GetStudyPlan(args[0])
+		if err != nil {
+			return err
+		}
+
+		state := config.LoadState()
+		progress := state.Plans[plan.Slug]
+
+		if !planNext {
+			showPlan(cmd, plan, progress)
+			return nil
+		}
+
+		q, err := plan.NextQuestion(progress.Picked, planFreeOnly)
+		if err != nil {
+			return err
+		}
+		result, err := lang.Generate(q)
+		if err != nil {
+			return err
+		}
+		if lang.DryRun() {
+			return nil
+		}
+
+		// Reload state, as it has been updated by `Generate`.
+		state = config.LoadState()
+		if state.Plans == nil {
+			state.Plans = make(map[string]config.PlanProgress)
+		}
+		progress = state.Plans[plan.Slug]
+		if !slices.Contains(progress.Picked, q.TitleSlug) {
+			progress.Picked = append(progress.Picked, q.TitleSlug)
+		}
+		state.Plans[plan.Slug] = progress
+		config.SaveState(state)
+
+		return finishGenerate(cmd, result)
+	},
+}
+
+func planQuestionStatus(q *leetcode.QuestionData, progress config.PlanProgress) string {
+	switch {
+	case q.IsSolved():
+		return "Solved"
+	case slices.Contains(progress.Picked, q.TitleSlug):
+		return "Picked"
+	}
+	return ""
+}
+
+func showPlan(cmd *cobra.Command, plan *leetcode.StudyPlan, progress config.PlanProgress) {
+	solved, total := plan.Progress()
+
+	if config.Get().UsePlainOutput() {
+		cmd.Printf("Plan: %s, %d of %d solved\n", plan.Name, solved, total)
+		for _, g := range plan.Groups {
+			cmd.Printf("\nGroup: %s\n", g.Name)
+			for _, q := range g.Questions {
+				status := planQuestionStatus(q, progress)
+				if status == "" {
+					status = "Not started"
+				}
+				cmd.Printf("%s. %s, %s, %s\n", q.QuestionFrontendId, q.GetTitle(), q.Difficulty, status)
+			}
+		}
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.SetTitle(fmt.Sprintf("%s (%d/%d solved)", plan.Name, solved, total))
+	w.AppendHeader(table.Row{"#", "Title", "Difficulty", "Status"})
+	for _, g := range plan.Groups {
+		w.AppendRow(
+			table.Row{g.Name, g.Name, g.Name, g.Name},
+			table.RowConfig{AutoMerge: true, AutoMergeAlign: text.AlignLeft},
+		)
+		for _, q := range g.Questions {
+			w.AppendRow(table.Row{q.QuestionFrontendId, q.GetTitle(), q.Difficulty, planQuestionStatus(q, progress)})
+		}
+		w.AppendSeparator()
+	}
+	w.Render()
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var (
+	randomDifficulty string
+	randomTags       []string
+	randomFreeOnly   bool
+	randomMinRating  float64
+	randomMaxRating  float64
+	randomSeed       string
+	randomCompany    string
+	randomRecent     string
+)
+
+func init() {
+	randomCmd.Flags().StringVarP(&randomDifficulty, "difficulty", "d", "", "difficulty of the question: easy, medium or hard")
+	randomCmd.Flags().StringSliceVarP(&randomTags, "tag", "t", nil, "tag slugs the question must have, e.g. array, dynamic-programming")
+	randomCmd.Flags().BoolVar(&randomFreeOnly, "free-only", false, "exclude paid only questions")
+	randomCmd.Flags().Float64Var(&randomMinRating, "min-rating", 0, "minimum difficulty rating of the question")
+	randomCmd.Flags().Float64Var(&randomMaxRating, "max-rating", 0, "maximum difficulty rating of the question")
+	randomCmd.Flags().StringVar(&randomCompany, "company", "", "slug of a company that asked the question, e.g. amazon, requires LeetCode Premium")
+	randomCmd.Flags().StringVar(&randomRecent, "recent", "all", "period the company asked the question in: 30d, 3m, 6m or all")
+	randomCmd.Flags().StringVar(&randomSeed, "seed", "", "pick deterministically from the seed, 'daily' for the UTC date of today, to share with a group")
+	randomCmd.Flags().BoolVarP(&skipEditor, "skip-editor", "", false, "Skip opening the editor")
+
+	_ = randomCmd.RegisterFlagCompletionFunc(
+		"difficulty",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"easy", "medium", "hard"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	_ = randomCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	_ = randomCmd.RegisterFlagCompletionFunc("recent", completeCompanyPeriods)
+}
+
+var randomCmd = &cobra.Command{
+	Use:   "random",
+	Short: "Pick a random unsolved question",
+	Example: `leetgo random
+leetgo random -d medium -t array --free-only
+leetgo random --min-rating 1600 --max-rating 1800
+leetgo random -d medium --seed daily
+leetgo random --company google --recent 3m`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch strings.ToLower(randomDifficulty) {
+		case "", "easy", "medium", "hard":
+		default:
+			return errors.New("invalid difficulty, only easy, medium or hard is supported")
+		}
+
+		var ratings leetcode.Ratings
+		if randomMinRating > 0 || randomMaxRating > 0 {
+			var err error
+			ratings, err = leetcode.LoadOrFetchRatings()
+			if err != nil {
+				return err
+			}
+		}
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		var company *leetcode.CompanyQuestions
+		if randomCompany != "" {
+			var err error
+			company, err = leetcode.LoadOrFetchCompanyQuestions(c, randomCompany, randomRecent)
+			if err != nil {
+				return err
+			}
+		}
+		filter := leetcode.LocalFilter{
+			Difficulty: randomDifficulty,
+			Tags:       randomTags,
+			FreeOnly:   randomFreeOnly,
+			MinRating:  randomMinRating,
+			MaxRating:  randomMaxRating,
+			Ratings:    ratings,
+			Company:    company,
+		}
+		q, err := randomQuestion(c, filter, randomSeed)
+		if err != nil {
+			return err
+		}
+
+		result, err := lang.Generate(q)
+		if err != nil {
+			return err
+		}
+		return finishGenerate(cmd, result)
+	},
+}
+
+// randomQuestion picks a random question matching the filter from the cache, skipping questions solved or generated.
+// With a seed, the pick is deterministic and ignores the local progress, so everyone using the same seed and filter
+// gets the same question. The seed "daily" stands for the UTC date of today.
+func randomQuestion(c leetcode.Client, filter leetcode.LocalFilter, seed string) (*leetcode.QuestionData, error) {
+	all := leetcode.GetCache(c).GetAllQuestions()
+	if len(all) == 0 {
+		return nil, errors.New("no questions in cache, try updating with `leetgo cache update`")
+	}
+	if len(filter.Tags) > 0 && !leetcode.HasTags(all) {
+		return nil, errors.New("cached questions have no tags, filtering by tags is not supported for this site")
+	}
+
+	if seed == "" {
+		state := config.LoadState()
+		filter.Exclude = func(q *leetcode.QuestionData) bool {
+			if q.IsSolved() {
+				return true
+			}
+			_, ok := state.Question(q.TitleSlug)
+			return ok
+		}
+	}
+	candidates := leetcode.FilterQuestions(all, filter)
+	if len(candidates) == 0 {
+		return nil, errors.New("no question matches the filter")
+	}
+
+	var q *leetcode.QuestionData
+	if seed != "" {
+		if seed == "daily" {
+			// The same date for members of a group in different time zones.
+			seed = time.Now().UTC().Format(time.DateOnly)
+		}
+		q = leetcode.PickSeeded(candidates, seed)
+		log.Info("picked", "question", q.TitleSlug, "seed", seed)
+	} else {
+		q = candidates[rand.IntN(len(candidates))]
+		log.Info("picked", "question", q.TitleSlug, "candidates", len(candidates))
+	}
+	q.SetClient(c)
+	return q, nil
+}
@@ -7,15 +7,21 @@ import (
 	"runtime"
 	"runtime/debug"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/fatih/color"
 	cc "github.com/ivanpirog/coloredcobra"
 	"github.com/joho/godotenv"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/utils"
 )
 
 func buildVersion() string {
@@ -49,7 +55,13 @@ func (e exitCode) Error() string {
 }
 
 func Execute() {
+	translateHelp(rootCmd)
 	err := rootCmd.Execute()
+	utils.StopProfile(os.Stderr)
+	if n := utils.BytesTransferred(); n > 0 && config.Get().LowBandwidth {
+		_, _ = fmt.Fprintf(os.Stderr, "%s transferred\n", utils.FormatBytes(n))
+	}
+	printUpgradeNotice()
 	if err != nil {
 		var e exitCode
 		if errors.As(err, &e) {
@@ -59,9 +71,30 @@ func Execute() {
 	}
 }
 
+// translateHelp translates the short help of the commands and the usage of their flags.
+// Help is shown before the configuration is loaded, so it follows the locale rather than `language`.
+func translateHelp(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	translate := func(f *pflag.Flag) { f.Usage = i18n.T(f.Usage) }
+	cmd.Flags().VisitAll(translate)
+	cmd.PersistentFlags().VisitAll(translate)
+	for _, sub := range cmd.Commands() {
+		translateHelp(sub)
+	}
+}
+
 func preRun(cmd *cobra.Command, _ []string) error {
-	initLogger()
-	err := initWorkDir()
+	err := initLogger()
+	if err != nil {
+		return err
+	}
+	if viper.GetBool("profile") || viper.GetString("pprof") != "" {
+		err := utils.StartProfile(viper.GetString("pprof"))
+		if err != nil {
+			return err
+		}
+	}
+	err = initWorkDir()
 	if err != nil {
 		return err
 	}
@@ -69,10 +102,18 @@ func preRun(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	if config.Get().UsePlainOutput() {
+		initPlainOutput()
+	}
+	initLogFile()
+	if config.Get().LowBandwidth {
+		utils.TrackDefaultTransport()
+	}
 	err = godotenv.Load()
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
+	checkUpgrade(cmd)
 	return nil
 }
 
@@ -88,21 +129,12 @@ func initWorkDir() error {
 	return nil
 }
 
-func initLogger() {
-	if config.Debug {
-		log.SetReportTimestamp(true)
-		log.SetLevel(log.DebugLevel)
-	} else {
-		style := log.DefaultStyles()
-		style.Levels[log.DebugLevel] = style.Levels[log.DebugLevel].SetString("●")
-		style.Levels[log.InfoLevel] = style.Levels[log.InfoLevel].SetString("●")
-		style.Levels[log.WarnLevel] = style.Levels[log.WarnLevel].SetString("●")
-		style.Levels[log.ErrorLevel] = style.Levels[log.ErrorLevel].SetString("×")
-		style.Levels[log.FatalLevel] = style.Levels[log.FatalLevel].SetString("×")
-		log.SetStyles(style)
-		log.SetReportTimestamp(false)
-		log.SetLevel(log.InfoLevel)
-	}
+// initPlainOutput disables colors and replaces the symbol log levels with words.
+func initPlainOutput() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	log.SetColorProfile(termenv.Ascii)
+	color.NoColor = true
+	log.SetStyles(log.DefaultStyles())
 }
 
 func initCommands() {
@@ -114,20 +146,53 @@ func initCommands() {
 	rootCmd.PersistentFlags().StringP("lang", "l", "", "language of code to generate: cpp, go, python ...")
 	rootCmd.PersistentFlags().StringP("site", "", "", "leetcode site: cn, us")
 	rootCmd.PersistentFlags().BoolP("yes", "y", false, "answer yes to all prompts")
+	rootCmd.PersistentFlags().Bool("plain", false, "plain output without colors, spinners and box-drawing characters")
+	rootCmd.PersistentFlags().Bool("safe", false, "never spawn external processes or prompt, print generated files as JSON")
+	rootCmd.PersistentFlags().Bool("json", false, "print structured JSON on stdout, logs and other output go to stderr")
+	rootCmd.PersistentFlags().Bool("offline", false, "work from the local cache without network, commands requiring network fail at once")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "print the files to generate and their diffs against existing files, without writing")
+	rootCmd.PersistentFlags().Bool("force", false, "overwrite existing files without asking")
+	rootCmd.PersistentFlags().Bool("skip-existing", false, "keep existing files without asking, even with --yes")
+	rootCmd.PersistentFlags().Bool("verbose", false, "show debug logs, including requests to LeetCode")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "show only warnings and errors")
+	rootCmd.PersistentFlags().String("log-format", logFormatAuto, "format of logs on stderr: auto, text or json, auto uses json when stderr is not a terminal")
+	rootCmd.PersistentFlags().Bool("profile", false, "report where time was spent: network, disk, subprocesses and templates")
+	rootCmd.PersistentFlags().String("pprof", "", "write a CPU profile in pprof format to the file")
+	rootCmd.MarkFlagsMutuallyExclusive("force", "skip-existing")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
 	rootCmd.InitDefaultHelpFlag()
 	_ = viper.BindPFlag("code.lang", rootCmd.PersistentFlags().Lookup("lang"))
 	_ = viper.BindPFlag("leetcode.site", rootCmd.PersistentFlags().Lookup("site"))
 	_ = viper.BindPFlag("yes", rootCmd.PersistentFlags().Lookup("yes"))
+	_ = viper.BindPFlag("plain", rootCmd.PersistentFlags().Lookup("plain"))
+	_ = viper.BindPFlag("safe", rootCmd.PersistentFlags().Lookup("safe"))
+	_ = viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json"))
+	_ = viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+	_ = viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	_ = viper.BindPFlag("force", rootCmd.PersistentFlags().Lookup("force"))
+	_ = viper.BindPFlag("skip-existing", rootCmd.PersistentFlags().Lookup("skip-existing"))
+	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	_ = viper.BindPFlag("log-format", rootCmd.PersistentFlags().Lookup("log-format"))
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("pprof", rootCmd.PersistentFlags().Lookup("pprof"))
 
 	_ = rootCmd.RegisterFlagCompletionFunc(
 		"lang", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			langs := make([]string, 0, len(lang.SupportedLangs))
-			for _, l := range lang.SupportedLangs {
+			allLangs := lang.AllLangs()
+			langs := make([]string, 0, len(allLangs))
+			for _, l := range allLangs {
 				langs = append(langs, l.Slug())
 			}
 			return langs, cobra.ShellCompDirectiveNoFileComp
 		},
 	)
+	_ = rootCmd.RegisterFlagCompletionFunc(
+		"log-format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{logFormatAuto, logFormatText, logFormatJSON}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
 	_ = rootCmd.RegisterFlagCompletionFunc(
 		"site",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -138,19 +203,36 @@ func initCommands() {
 	commands := []*cobra.Command{
 		initCmd,
 		pickCmd,
+		planCmd,
+		randomCmd,
+		listCmd,
 		infoCmd,
 		testCmd,
+		stressCmd,
 		submitCmd,
+		submissionsCmd,
+		solutionCmd,
+		noteCmd,
+		exportCmd,
+		historyCmd,
+		timeCmd,
+		copyCmd,
 		fixCmd,
 		editCmd,
 		extractCmd,
 		contestCmd,
 		cacheCmd,
+		configCmd,
+		migrateFilesCmd,
+		cleanCmd,
+		credentialsCmd,
 		debugCmd,
 		gitCmd,
 		inspectCmd,
 		whoamiCmd,
 		openCmd,
+		serveCmd,
+		upgradeCmd,
 	}
 	for _, cmd := range commands {
 		cmd.Flags().SortFlags = false
@@ -158,7 +240,6 @@ func initCommands() {
 		rootCmd.AddCommand(cmd)
 	}
 	rootCmd.InitDefaultHelpCmd()
-	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 	cc.Init(
 		&cc.Config{
 			RootCmd:         rootCmd,
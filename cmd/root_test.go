@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/j178/leetgo/i18n"
+)
+
+func TestNoShadowedGlobalFlags(t *testing.T) {
+	var check func(cmd *cobra.Command)
+	check = func(cmd *cobra.Command) {
+		cmd.LocalNonPersistentFlags().VisitAll(
+			func(f *pflag.Flag) {
+				if rootCmd.PersistentFlags().Lookup(f.Name) != nil {
+					t.Errorf("flag --%s of %q shadows the global one", f.Name, cmd.CommandPath())
+				}
+			},
+		)
+		for _, sub := range cmd.Commands() {
+			check(sub)
+		}
+	}
+	for _, cmd := range rootCmd.Commands() {
+		check(cmd)
+	}
+}
+
+func TestHelpTranslated(t *testing.T) {
+	t.Setenv("LC_ALL", "zh_CN.UTF-8")
+	// Help added by cobra itself is not translated.
+	builtin := map[string]bool{"help": true, "version": true, "completion": true}
+	var check func(cmd *cobra.Command)
+	check = func(cmd *cobra.Command) {
+		if cmd.Hidden || builtin[cmd.Name()] {
+			return
+		}
+		if cmd != rootCmd && i18n.T(cmd.Short) == cmd.Short {
+			t.Errorf("short help of %q is not translated: %s", cmd.CommandPath(), cmd.Short)
+		}
+		cmd.LocalFlags().VisitAll(
+			func(f *pflag.Flag) {
+				if !f.Hidden && !builtin[f.Name] && i18n.T(f.Usage) == f.Usage {
+					t.Errorf("usage of --%s of %q is not translated: %s", f.Name, cmd.CommandPath(), f.Usage)
+				}
+			},
+		)
+		for _, sub := range cmd.Commands() {
+			check(sub)
+		}
+	}
+	check(rootCmd)
+
+	cmd := &cobra.Command{Use: "submit", Short: "Submit solution"}
+	cmd.Flags().Bool("local", false, "run test locally")
+	cmd.PersistentFlags().Bool("yes", false, "answer yes to all prompts")
+	translateHelp(cmd)
+	if cmd.Short != "提交代码" || cmd.Flag("local").Usage != "在本地运行测试" || cmd.Flag("yes").Usage != "对所有提示回答是" {
+		t.Errorf("translated help = %q, %q, %q", cmd.Short, cmd.Flag("local").Usage, cmd.Flag("yes").Usage)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/editor"
+	"github.com/j178/leetgo/lang"
+)
+
+type generatedFile struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Written bool   `json:"written"`
+}
+
+type generatedQuestion struct {
+	FrontendId string          `json:"frontend_id"`
+	Slug       string          `json:"slug"`
+	Title      string          `json:"title"`
+	Difficulty string          `json:"difficulty"`
+	Url        string          `json:"url"`
+	Lang       string          `json:"lang"`
+	Dir        string          `json:"dir"`
+	Files      []generatedFile `json:"files"`
+}
+
+// finishGenerate opens the first generated question in editor unless --skip-editor is set.
+// In safe mode or with --json, no editor is spawned, generated files are printed as JSON instead.
+func finishGenerate(cmd *cobra.Command, results ...*lang.GenerateResult) error {
+	if config.SafeMode() || config.JSONOutput() {
+		return outputGenerated(cmd, results)
+	}
+	if skipEditor || lang.DryRun() || len(results) == 0 {
+		return nil
+	}
+	return editor.Open(results[0])
+}
+
+func outputGenerated(cmd *cobra.Command, results []*lang.GenerateResult) error {
+	return encodeJSON(cmd, generatedQuestions(results))
+}
+
+func generatedQuestions(results []*lang.GenerateResult) []generatedQuestion {
+	questions := make([]generatedQuestion, 0, len(results))
+	for _, r := range results {
+		files := make([]generatedFile, 0, len(r.Files))
+		for _, f := range r.Files {
+			files = append(
+				files, generatedFile{
+					Path:    f.GetPath(),
+					Type:    f.Type.String(),
+					Written: f.Written,
+				},
+			)
+		}
+		questions = append(
+			questions, generatedQuestion{
+				FrontendId: r.Question.QuestionFrontendId,
+				Slug:       r.Question.TitleSlug,
+				Title:      r.Question.GetTitle(),
+				Difficulty: r.Question.Difficulty,
+				Url:        r.Question.Url(),
+				Lang:       r.Lang.Slug(),
+				Dir:        r.TargetDir(),
+				Files:      files,
+			},
+		)
+	}
+	return questions
+}
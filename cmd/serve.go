@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var serveSocket string
+
+func init() {
+	serveCmd.Flags().StringVar(&serveSocket, "socket", "", "listen on a unix socket instead of stdio")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve JSON-RPC requests from editor plugins",
+	Long: `Serve JSON-RPC 2.0 requests on stdio or a unix socket, one JSON message per line.
+Logs are written to stderr.
+
+Methods:
+  resolve    {"qid": "1"}                                      question metadata
+  statement  {"qid": "1"}                                      question statement in markdown
+  generate   {"qid": "1", "force": false}                      generated files, existing files are kept unless force is set
+  test       {"qid": "1", "local": true, "remote": false}      test results
+  submit     {"qid": "1"}                                      submission verdicts`,
+	Example: `leetgo serve
+leetgo serve --socket /tmp/leetgo.sock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Nothing but JSON-RPC responses may be written to stdout, and there is no one to answer prompts.
+		// These hold for the whole server, options of a request are passed to its method.
+		viper.Set("json", true)
+		viper.Set("yes", true)
+
+		s := newServer(cmd, leetcode.NewClient(leetcode.ReadCredentials()))
+		if serveSocket == "" {
+			log.Info("serving on stdio")
+			return s.serveConn(os.Stdin, cmd.OutOrStdout())
+		}
+
+		err := removeStaleSocket(serveSocket)
+		if err != nil {
+			return err
+		}
+		l, err := net.Listen("unix", serveSocket)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			_ = l.Close()
+		}()
+		log.Info("serving", "socket", serveSocket)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				err := s.serveConn(conn, conn)
+				if err != nil {
+					log.Error("connection closed", "err", err)
+				}
+			}()
+		}
+	},
+}
+
+// removeStaleSocket removes the socket left by a previous server, other files are never removed.
+func removeStaleSocket(path string) error {
+	stat, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stat.Mode().Type() != os.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcParams struct {
+	Qid    string `json:"qid"`
+	Force  bool   `json:"force"`
+	Local  bool   `json:"local"`
+	Remote bool   `json:"remote"`
+}
+
+// server keeps the client and rate limiters across requests, so that login is done only once.
+type server struct {
+	// mu serializes requests, commands depend on global configuration.
+	mu            sync.Mutex
+	cmd           *cobra.Command
+	c             leetcode.Client
+	methods       map[string]func(rpcParams) (any, error)
+	user          *leetcode.UserStatus
+	testLimiter   *utils.RateLimiter
+	submitLimiter *utils.RateLimiter
+}
+
+func newServer(cmd *cobra.Command, c leetcode.Client) *server {
+	s := &server{cmd: cmd, c: c}
+	s.methods = map[string]func(rpcParams) (any, error){
+		"resolve":   func(p rpcParams) (any, error) { return s.resolve(p, false) },
+		"statement": func(p rpcParams) (any, error) { return s.resolve(p, true) },
+		"generate":  func(p rpcParams) (any, error) { return s.generate(p) },
+		"test":      func(p rpcParams) (any, error) { return s.test(p) },
+		"submit":    func(p rpcParams) (any, error) { return s.submit(p) },
+	}
+	return s
+}
+
+func (s *server) serveConn(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	enc := json.NewEncoder(w)
+	for {
+		var req rpcRequest
+		err := dec.Decode(&req)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			_ = enc.Encode(
+				rpcResponse{
+					JSONRPC: "2.0",
+					ID:      json.RawMessage("null"),
+					Error:   &rpcError{rpcParseError, err.Error()},
+				},
+			)
+			return err
+		}
+
+		resp := s.handle(req)
+		// Notifications have no id and get no response.
+		if req.ID == nil {
+			continue
+		}
+		err = enc.Encode(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers the request, a panicking method is reported as an internal error and doesn't stop the server.
+func (s *server) handle(req rpcRequest) (resp rpcResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("request panicked", "method", req.Method, "panic", r, "stack", string(debug.Stack()))
+			resp = rpcResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error:   &rpcError{rpcInternalError, fmt.Sprintf("internal error: %v", r)},
+			}
+		}
+	}()
+	return s.dispatch(req)
+}
+
+func (s *server) dispatch(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{rpcInvalidRequest, "invalid request"}
+		return resp
+	}
+	var params rpcParams
+	if len(req.Params) > 0 {
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			resp.Error = &rpcError{rpcInvalidParams, err.Error()}
+			return resp
+		}
+	}
+	method, ok := s.methods[req.Method]
+	if !ok {
+		resp.Error = &rpcError{rpcMethodNotFound, fmt.Sprintf("method not found: %s", req.Method)}
+		return resp
+	}
+	// All methods work on questions.
+	if params.Qid == "" {
+		resp.Error = &rpcError{rpcInvalidParams, "qid is required"}
+		return resp
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := method(params)
+	if err != nil {
+		resp.Error = &rpcError{rpcInternalError, err.Error()}
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+func (s *server) questions(params rpcParams) ([]*leetcode.QuestionData, error) {
+	return leetcode.ParseQID(params.Qid, s.c)
+}
+
+func (s *server) limiters() {
+	if s.user != nil {
+		return
+	}
+	user, err := s.c.GetUserStatus()
+	if err != nil {
+		user = &leetcode.UserStatus{}
+	}
+	s.user = user
+	s.testLimiter = newLimiter(user)
+	s.submitLimiter = newLimiter(user)
+}
+
+func (s *server) resolve(params rpcParams, full bool) ([]question, error) {
+	qs, err := s.questions(params)
+	if err != nil {
+		return nil, err
+	}
+	questions := make([]question, 0, len(qs))
+	for _, q := range qs {
+		info := newQuestionInfo(q, full)
+		if full {
+			info.Content = info.markdown
+		}
+		questions = append(questions, info)
+	}
+	return questions, nil
+}
+
+func (s *server) generate(params rpcParams) ([]generatedQuestion, error) {
+	qs, err := s.questions(params)
+	if err != nil {
+		return nil, err
+	}
+	existing := lang.ExistingFilesSkip
+	if params.Force {
+		existing = lang.ExistingFilesOverwrite
+	}
+
+	var results []*lang.GenerateResult
+	for _, q := range qs {
+		result, err := lang.GenerateWith(q, existing)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return generatedQuestions(results), nil
+}
+
+func (s *server) test(params rpcParams) ([]*questionResult, error) {
+	qs, err := s.questions(params)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := lang.GetGenerator(config.Get().Code.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if !params.Local && !params.Remote {
+		params.Remote = true
+	}
+	s.limiters()
+
+	results := make([]*questionResult, 0, len(qs))
+	for _, q := range qs {
+		gen := lang.GeneratorFor(q, gen)
+		qr := newQuestionResult(q, gen.Slug())
+		results = append(results, qr)
+		if params.Local {
+			passed, err := lang.RunLocalTest(q, lang.TestOptions{TargetCase: "-"})
+			if err != nil {
+				qr.Errors = append(qr.Errors, err.Error())
+			}
+			qr.Local = &passed
+		}
+		if params.Remote {
+			result, err := runTestRemotely(s.cmd, q, s.c, gen, s.testLimiter)
+			if err != nil {
+				qr.Errors = append(qr.Errors, err.Error())
+			} else {
+				qr.Remote = runVerdict(result)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (s *server) submit(params rpcParams) ([]*questionResult, error) {
+	qs, err := s.questions(params)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := lang.GetGenerator(config.Get().Code.Lang)
+	if err != nil {
+		return nil, err
+	}
+	s.limiters()
+
+	results := make([]*questionResult, 0, len(qs))
+	checks := newChecklist()
+	for _, q := range qs {
+		gen := lang.GeneratorFor(q, gen)
+		qr := newQuestionResult(q, gen.Slug())
+		results = append(results, qr)
+		result, err := submitSolution(s.cmd, q, s.c, gen, s.submitLimiter, checks, false)
+		if err != nil {
+			qr.Errors = append(qr.Errors, err.Error())
+			continue
+		}
+		qr.Submit = submitVerdict(result)
+		if result.Accepted() {
+			continue
+		}
+		if added, _ := appendToTestCases(q, result); added {
+			log.Info("added failed case to testcases.txt")
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeConn(t *testing.T) {
+	s := newServer(nil, nil)
+	s.methods["echo"] = func(p rpcParams) (any, error) { return p, nil }
+	s.methods["panic"] = func(rpcParams) (any, error) { panic("boom") }
+
+	requests := []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "echo", "params": {"qid": "1", "force": true}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "panic", "params": {"qid": "1"}}`,
+		`{"jsonrpc": "2.0", "method": "echo", "params": {"qid": "1"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "echo", "params": {"qid": "2"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "unknown", "params": {"qid": "1"}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "echo"}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "echo", "params": {"qid": 1}}`,
+		`{"jsonrpc": "1.0", "id": 7, "method": "echo"}`,
+		`{"jsonrpc": "2.0", "id": 8,`,
+	}
+	var out bytes.Buffer
+	err := s.serveConn(strings.NewReader(strings.Join(requests, "\n")), &out)
+	if err == nil {
+		t.Errorf("serveConn() should fail on malformed JSON")
+	}
+
+	type response struct {
+		ID     json.RawMessage `json:"id"`
+		Result *rpcParams      `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	var got []response
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r response
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	want := []struct {
+		id   string
+		code int
+	}{
+		{"1", 0},
+		{"2", rpcInternalError},
+		// The notification gets no response, and the panic didn't stop the server.
+		{"3", 0},
+		{"4", rpcMethodNotFound},
+		{"5", rpcInvalidParams},
+		{"6", rpcInvalidParams},
+		{"7", rpcInvalidRequest},
+		{"null", rpcParseError},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d responses, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		r := got[i]
+		code := 0
+		if r.Error != nil {
+			code = r.Error.Code
+		}
+		if string(r.ID) != w.id || code != w.code {
+			t.Errorf("response %d: id %s, error %+v, want id %s, code %d", i, r.ID, r.Error, w.id, w.code)
+		}
+	}
+	// Options are passed to the method of each request, nothing is carried over to the next one.
+	if p := got[0].Result; p == nil || !p.Force {
+		t.Errorf("first request should be forced: %+v", p)
+	}
+	if p := got[2].Result; p == nil || p.Force || p.Qid != "2" {
+		t.Errorf("later request should not be forced: %+v", p)
+	}
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, too short for t.TempDir() on some systems.
+	dir, err := os.MkdirTemp("", "leetgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("removeStaleSocket() of a missing file = %v", err)
+	}
+
+	sock := filepath.Join(dir, "leetgo.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("unix sockets not supported:", err)
+	}
+	// Keep the socket file after closing, as a crashed server does.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = l.Close()
+	if err := removeStaleSocket(sock); err != nil {
+		t.Errorf("removeStaleSocket() of a socket = %v", err)
+	}
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Errorf("socket should be removed: %v", err)
+	}
+
+	file := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(file, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeStaleSocket(file); err == nil {
+		t.Errorf("removeStaleSocket() of a regular file should fail")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("regular file should be kept: %v", err)
+	}
+}
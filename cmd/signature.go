@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/mattn/go-isatty"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// checkSignatures warns if LeetCode changed the function signatures since the code was generated,
+// and offers to update them while keeping the function bodies.
+func checkSignatures(q *leetcode.QuestionData) {
+	changes, err := lang.CheckSignatures(q)
+	if err != nil {
+		log.Debug("failed to check signatures", "err", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	fixable := false
+	for _, c := range changes {
+		if c.Old == "" {
+			log.Warn("function missing in generated code", "question", q.TitleSlug, "signature", c.New)
+			continue
+		}
+		fixable = true
+		log.Warn("signature changed", "question", q.TitleSlug, "old", c.Old, "new", c.New)
+	}
+	// The code is never rewritten without asking, not even with --yes: the bodies are kept, but anything else
+	// the user changed on these lines is lost. Nobody can answer in JSON mode or without a terminal.
+	if !fixable || config.SafeMode() || config.JSONOutput() || !isatty.IsTerminal(os.Stdin.Fd()) {
+		return
+	}
+
+	update := false
+	err = config.AskOne(&survey.Confirm{Message: i18n.T("Update signatures and keep the function bodies?")}, &update)
+	if err != nil || !update {
+		return
+	}
+	err = lang.UpdateSignatures(q, changes)
+	if err != nil {
+		log.Error("failed to update signatures", "err", err)
+	}
+}
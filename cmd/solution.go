@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+const solutionFilename = "solution.md"
+
+var (
+	solutionLimit int
+	solutionIndex int
+	solutionList  bool
+	solutionSave  bool
+	editorial     bool
+)
+
+func init() {
+	solutionCmd.Flags().IntVarP(&solutionLimit, "limit", "n", 10, "number of solutions to list")
+	solutionCmd.Flags().IntVar(&solutionIndex, "index", 0, "show the solution at this position of the list (1-based) without prompting")
+	solutionCmd.Flags().BoolVar(&solutionList, "list", false, "only list the solutions")
+	solutionCmd.Flags().BoolVar(&solutionSave, "save", false, "save the solution as "+solutionFilename+" next to your code")
+	solutionCmd.Flags().BoolVar(&editorial, "editorial", false, "show the official solution instead of community solutions")
+}
+
+var solutionCmd = &cobra.Command{
+	Use:   "solution qid",
+	Short: "Browse top voted community solutions of a question",
+	Long: `List top voted community solutions of a question, and render the chosen one in the terminal.
+Solutions are filtered by language if -l/--lang is given.
+With --editorial, the official solution is shown instead, it may require a Premium subscription on leetcode.com.`,
+	Example: `leetgo solution 1
+leetgo solution two-sum -l go --index 1 --save
+leetgo solution last --list
+leetgo solution 1 --editorial`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if solutionLimit <= 0 {
+			return errors.New("--limit must be positive")
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qs, err := leetcode.ParseQID(args[0], c)
+		if err != nil {
+			return err
+		}
+		if len(qs) > 1 {
+			return errors.New("multiple questions found")
+		}
+		q := qs[0]
+
+		var s *leetcode.Solution
+		if editorial {
+			s, err = c.GetEditorial(q.TitleSlug)
+		} else {
+			s, err = chooseSolution(cmd, c, q)
+		}
+		if err != nil || s == nil {
+			return err
+		}
+		content := solutionMarkdown(q, s)
+		if solutionSave {
+			err = saveSolution(q, content)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case config.JSONOutput():
+			return encodeJSON(cmd, s)
+		case config.Get().UsePlainOutput():
+			cmd.Print(content)
+		default:
+			output, err := glamour.Render(content, "dark")
+			if err != nil {
+				return err
+			}
+			cmd.Print(output)
+		}
+		return nil
+	},
+}
+
+// chooseSolution lists the community solutions, and fetches the one chosen by the user.
+// It returns nil if the solutions are only listed.
+func chooseSolution(cmd *cobra.Command, c leetcode.Client, q *leetcode.QuestionData) (*leetcode.Solution, error) {
+	langSlug := ""
+	if cmd.Flags().Changed("lang") {
+		gen, err := lang.GetGenerator(config.Get().Code.Lang)
+		if err != nil {
+			return nil, err
+		}
+		langSlug = gen.Slug()
+	}
+	solutions, err := c.GetSolutions(q.TitleSlug, langSlug, solutionLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(solutions) == 0 {
+		return nil, errors.New("no solutions found")
+	}
+
+	if solutionIndex == 0 && (solutionList || config.SafeMode() || config.JSONOutput()) {
+		if config.JSONOutput() {
+			return nil, encodeJSON(cmd, solutions)
+		}
+		showSolutions(cmd, solutions)
+		return nil, nil
+	}
+	idx := solutionIndex - 1
+	if solutionIndex == 0 {
+		idx, err = selectSolution(solutions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if idx < 0 || idx >= len(solutions) {
+		return nil, fmt.Errorf("--index out of range, %d solutions found", len(solutions))
+	}
+	return c.GetSolution(q.TitleSlug, solutions[idx].ID)
+}
+
+func solutionLangs(s *leetcode.Solution) string {
+	return strings.Join(s.Languages(), ", ")
+}
+
+func showSolutions(cmd *cobra.Command, solutions []*leetcode.Solution) {
+	if config.Get().UsePlainOutput() {
+		for i, s := range solutions {
+			cmd.Printf("%d. %s, by %s, %d votes, languages: %s\n", i+1, s.Title, s.Author, s.Votes, solutionLangs(s))
+		}
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"#", "Title", "Author", "Votes", "Languages"})
+	for i, s := range solutions {
+		w.AppendRow(table.Row{i + 1, s.Title, s.Author, s.Votes, solutionLangs(s)})
+	}
+	w.Render()
+}
+
+func selectSolution(solutions []*leetcode.Solution) (int, error) {
+	options := make([]string, len(solutions))
+	for i, s := range solutions {
+		options[i] = fmt.Sprintf("%s (%s, %d votes) %s", s.Title, s.Author, s.Votes, solutionLangs(s))
+	}
+	var idx int
+	prompt := &survey.Select{
+		Message: i18n.T("Select a solution:"),
+		Options: options,
+	}
+	err := config.AskOne(prompt, &idx)
+	return idx, err
+}
+
+func solutionMarkdown(q *leetcode.QuestionData, s *leetcode.Solution) string {
+	header := fmt.Sprintf(
+		"# %s\n\nSolution of [%s. %s](%s) by %s, %d votes.\n\n",
+		s.Title, q.QuestionFrontendId, q.GetTitle(), q.Url(), s.Author, s.Votes,
+	)
+	return utils.EnsureTrailingNewline(header + s.Content)
+}
+
+func solutionFile(result *lang.GenerateResult) string {
+	// Questions share the directory in flat layouts, name the file after the code file instead.
+	if f := result.GetFile(lang.CodeFile); result.SubDir == "" && f != nil {
+		return strings.TrimSuffix(f.GetPath(), filepath.Ext(f.GetPath())) + "." + solutionFilename
+	}
+	return filepath.Join(result.TargetDir(), solutionFilename)
+}
+
+// saveSolution writes the solution next to the generated code of the question.
+func saveSolution(q *leetcode.QuestionData, content string) error {
+	result, err := lang.GeneratePathsOnly(q)
+	if err != nil {
+		return err
+	}
+	path := solutionFile(result)
+	if lang.DryRun() {
+		log.Info("would save solution", "file", utils.RelToCwd(path))
+		return nil
+	}
+	err = utils.WriteFile(path, []byte(content))
+	if err != nil {
+		return err
+	}
+	log.Info("solution saved", "file", utils.RelToCwd(path))
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func solutionQuestion() *leetcode.QuestionData {
+	q := &leetcode.QuestionData{
+		TitleSlug:          "two-sum",
+		QuestionFrontendId: "1",
+		QuestionId:         "1",
+		Title:              "Two Sum",
+		Difficulty:         "Easy",
+		MetaData: leetcode.MetaData{
+			Name:   "twoSum",
+			Params: []leetcode.MetaDataParam{{Name: "nums", Type: "integer[]"}, {Name: "target", Type: "integer"}},
+			Return: &leetcode.MetaDataReturn{Type: "integer[]"},
+		},
+	}
+	q.SetClient(leetcode.NewClient(leetcode.NonAuth()))
+	return q
+}
+
+func TestSolutionMarkdown(t *testing.T) {
+	q := solutionQuestion()
+	s := &leetcode.Solution{Title: "Hash map", Author: "alice", Votes: 42, Content: "Use a map."}
+	want := "# Hash map\n\nSolution of [1. Two Sum](" + q.Url() + ") by alice, 42 votes.\n\nUse a map.\n"
+	if got := solutionMarkdown(q, s); got != want {
+		t.Errorf("solutionMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestSaveSolution(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	root := t.TempDir()
+	chdir(t, root)
+	viper.Set("code.golang.out_dir", "go")
+	t.Cleanup(func() { viper.Set("code.golang.out_dir", "") })
+	q := solutionQuestion()
+	file := filepath.Join(root, "go", "cn.two-sum", "solution.md")
+
+	viper.Set("dry-run", true)
+	t.Cleanup(func() { viper.Set("dry-run", false) })
+	if err := saveSolution(q, "# Hash map\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("dry run should not write the solution: %v", err)
+	}
+	viper.Set("dry-run", false)
+
+	if err := saveSolution(q, "# Hash map\n"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(file); err != nil || string(data) != "# Hash map\n" {
+		t.Errorf("saved solution = %q, %v", data, err)
+	}
+
+	// Questions share the directory in flat layouts.
+	result, err := lang.GeneratePathsOnly(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.SubDir = ""
+	if got, want := solutionFile(result), filepath.Join(root, "go", "solution.solution.md"); got != want {
+		t.Errorf("solutionFile() in a flat layout = %q, want %q", got, want)
+	}
+}
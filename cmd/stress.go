@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"math/rand/v2"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/editor"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	stressCount    int
+	stressSeed     uint64
+	stressMaxLen   int
+	stressMaxValue int64
+	stressInit     bool
+)
+
+func init() {
+	stressCmd.Flags().IntVarP(&stressCount, "count", "n", 100, "number of random inputs to try")
+	stressCmd.Flags().Uint64Var(&stressSeed, "seed", 0, "seed of the random inputs, a random one is used if not set")
+	stressCmd.Flags().IntVar(&stressMaxLen, "max-len", 10, "max length of generated arrays and strings")
+	stressCmd.Flags().Int64Var(&stressMaxValue, "max-value", 100, "max absolute value of generated numbers")
+	stressCmd.Flags().BoolVar(&stressInit, "init", false, "create the brute-force solution from the current solution")
+}
+
+var stressCmd = &cobra.Command{
+	Use:   "stress qid",
+	Short: "Compare the solution against a brute-force solution on random inputs",
+	Long: `Compare the solution against a brute-force solution on random inputs.
+
+The brute-force solution lives in the brute/ sub directory of the question, use --init to create it.
+Random inputs are generated from the constraints in the question statement, capped by --max-len and --max-value.
+The first input on which the two solutions disagree is added to the test cases file.`,
+	Example: `leetgo stress last --init
+leetgo stress last
+leetgo stress 1 --count 1000 --seed 42`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qs, err := leetcode.ParseQID(args[0], c)
+		if err != nil {
+			return err
+		}
+		q := qs[0]
+
+		if stressInit {
+			brute, err := lang.InitBrute(q)
+			if err != nil {
+				return err
+			}
+			log.Info("brute-force solution created", "file", utils.RelToCwd(brute.GetFile(lang.CodeFile).GetPath()))
+			return editor.Open(brute)
+		}
+
+		seed := stressSeed
+		if !cmd.Flags().Changed("seed") {
+			seed = rand.Uint64()
+		}
+		failed, err := lang.RunStressTest(
+			q, lang.StressOptions{
+				Count: stressCount,
+				Seed:  seed,
+				Limits: leetcode.InputLimits{
+					MaxLen:   stressMaxLen,
+					MaxValue: stressMaxValue,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if failed == nil {
+			cmd.Println(config.PassedStyle.Render("All random inputs passed"))
+			return nil
+		}
+		added, err := addTestCase(q, *failed)
+		if err != nil {
+			log.Error("failed to add test case", "err", err)
+		} else if added {
+			log.Info("diverging input added to test cases", "seed", seed)
+		}
+		return exitCode(1)
+	},
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var (
+	submissionsPending bool
+	submissionsResume  bool
+)
+
+func init() {
+	submissionsCmd.Flags().BoolVar(&submissionsPending, "pending", false, "only show submissions that are still waiting for results")
+	submissionsCmd.Flags().BoolVar(&submissionsResume, "resume", false, "continue the last interrupted check, skipping submissions checked")
+}
+
+// submissionsJobName is the job recording the progress of checking detached submissions.
+const submissionsJobName = "submissions"
+
+var submissionsCmd = &cobra.Command{
+	Use:   "submissions",
+	Short: "Check results of detached submissions",
+	Example: `leetgo submit last --detach
+leetgo submissions --pending
+leetgo submissions --resume`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state := config.LoadState()
+		if len(state.Submissions) == 0 {
+			log.Info("no detached submissions")
+			if config.JSONOutput() {
+				return encodeJSON(cmd, []config.SubmissionRecord{})
+			}
+			return nil
+		}
+
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		site := config.Get().LeetCode.Site.Short()
+		// Submissions made on the other site can't be checked with the current client.
+		var ids []string
+		for _, r := range state.Submissions {
+			if r.Pending() && r.Site == site {
+				ids = append(ids, r.ID)
+			}
+		}
+		if submissionsResume && config.LoadJob(submissionsJobName) == nil {
+			return errors.New("no interrupted check to resume")
+		}
+		job := config.StartJob(submissionsJobName, ids, submissionsResume)
+		toCheck := job.Pending()
+
+		shown := []config.SubmissionRecord{}
+		for i, r := range state.Submissions {
+			if r.Pending() && r.Site == site && slices.Contains(toCheck, r.ID) {
+				result, err := c.CheckResult(r.ID)
+				if err != nil {
+					log.Error("failed to check submission", "id", r.ID, "err", err)
+					job.Fail(r.ID, err)
+				} else if sr, ok := result.(*leetcode.SubmitCheckResult); ok && sr.GetState() == "SUCCESS" {
+					r.Result = sr.StatusMsg
+					state.Submissions[i] = r
+					emitSubmitEvent(r.Slug, r.FrontendID, r.Lang, sr)
+					notifyVerdict(cmd, r.Slug, r.FrontendID, sr)
+					if sr.Accepted() {
+						state.MarkAccepted(r.Slug, r.FrontendID, r.Lang, r.Hash)
+						state.AddRun(r.Slug, r.Lang, runRecord(sr, r.Hash))
+					}
+					// Save every result, so that results are not lost if interrupted or rate limited.
+					config.SaveState(state)
+					job.Done(r.ID)
+					if q, err := leetcode.QuestionBySlug(r.Slug, c); err == nil && !config.JSONOutput() {
+						cmd.Print(sr.Display(q))
+					}
+				}
+			}
+			if submissionsPending && !r.Pending() {
+				continue
+			}
+			shown = append(shown, r)
+		}
+
+		job.Finish()
+
+		if config.JSONOutput() {
+			return encodeJSON(cmd, shown)
+		}
+		showSubmissions(cmd, shown)
+		return nil
+	},
+}
+
+func showSubmissions(cmd *cobra.Command, records []config.SubmissionRecord) {
+	if len(records) == 0 {
+		log.Info("no pending submissions")
+		return
+	}
+	status := func(r config.SubmissionRecord) string {
+		if r.Pending() {
+			return "Pending"
+		}
+		return r.Result
+	}
+
+	if config.Get().UsePlainOutput() {
+		for _, r := range records {
+			cmd.Printf(
+				"Submission %s: %s. %s, %s, %s, submitted at %s\n",
+				r.ID, r.FrontendID, r.Slug, r.Lang, status(r), r.SubmittedAt.Format(time.DateTime),
+			)
+		}
+		return
+	}
+
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"ID", "Question", "Lang", "Submitted", "Status"})
+	for _, r := range records {
+		w.AppendRow(
+			table.Row{
+				r.ID,
+				fmt.Sprintf("%s. %s", r.FrontendID, r.Slug),
+				r.Lang,
+				r.SubmittedAt.Format(time.DateTime),
+				status(r),
+			},
+		)
+	}
+	w.Render()
+}
@@ -1,41 +1,79 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 	"github.com/j178/leetgo/utils"
 )
 
+var (
+	submitDetach  bool
+	submitChanged bool
+)
+
+func init() {
+	submitCmd.Flags().BoolVar(&submitDetach, "detach", false, "do not wait for the judge result, check it later with `leetgo submissions --pending`")
+	submitCmd.Flags().BoolVar(&submitChanged, "changed", false, "submit all solutions changed since they were last accepted")
+}
+
 var submitCmd = &cobra.Command{
-	Use:   "submit qid",
+	Use:   "submit [qid]",
 	Short: "Submit solution",
 	Example: `leetgo submit 1
 leetgo submit two-sum
 leetgo submit last
 leetgo submit w330/1
 leetgo submit w330/
+leetgo submit last --detach
+leetgo submit --changed
 `,
-	Aliases:   []string{"s"},
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last", "last/"},
+	Aliases: []string{"s"},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if submitChanged {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	ValidArgsFunction: completeQuestions(1, "today", "last", "last/"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := config.Get()
 		c := leetcode.NewClient(leetcode.ReadCredentials())
-		qs, err := leetcode.ParseQID(args[0], c)
-		if err != nil {
-			return err
-		}
 		gen, err := lang.GetGenerator(cfg.Code.Lang)
 		if err != nil {
 			return err
 		}
+		var qs []*leetcode.QuestionData
+		if submitChanged {
+			qs = changedQuestions(c, gen)
+			if len(qs) == 0 {
+				log.Info("no solution changed since accepted")
+				return nil
+			}
+			ok, err := confirmChanged(cmd, qs)
+			if err != nil || !ok {
+				return err
+			}
+		} else {
+			qs, err = leetcode.ParseQID(args[0], c)
+			if err != nil {
+				return err
+			}
+		}
 
 		user, err := c.GetUserStatus()
 		if err != nil {
@@ -43,16 +81,30 @@ leetgo submit w330/
 		}
 		limiter := newLimiter(user)
 
-		var hasFailedCase bool
+		var (
+			hasFailedCase bool
+			results       []*questionResult
+			checks        = newChecklist()
+		)
 		for _, q := range qs {
+			gen := lang.GeneratorFor(q, gen)
+			qr := newQuestionResult(q, gen.Slug())
+			results = append(results, qr)
 			log.Info("submitting solution", "question", q.TitleSlug, "user", user.Whoami(c))
-			result, err := submitSolution(cmd, q, c, gen, limiter)
+			result, err := submitSolution(cmd, q, c, gen, limiter, checks, submitDetach)
 			if err != nil {
 				hasFailedCase = true
 				log.Error("failed to submit solution", "err", err)
+				qr.Errors = append(qr.Errors, err.Error())
 				continue
 			}
-			cmd.Print(result.Display(qs[0]))
+			if result == nil {
+				continue
+			}
+			qr.Submit = submitVerdict(result)
+			if !config.JSONOutput() {
+				cmd.Print(result.Display(q))
+			}
 
 			if !result.Accepted() {
 				hasFailedCase = true
@@ -63,6 +115,11 @@ leetgo submit w330/
 			}
 		}
 
+		if config.JSONOutput() {
+			if err := encodeJSON(cmd, results); err != nil {
+				return err
+			}
+		}
 		if hasFailedCase {
 			return exitCode(1)
 		}
@@ -77,17 +134,24 @@ func submitSolution(
 	c leetcode.Client,
 	gen lang.Lang,
 	limiter *utils.RateLimiter,
+	checks *checklist,
+	detach bool,
 ) (
 	*leetcode.SubmitCheckResult,
 	error,
 ) {
+	checkSignatures(q)
 	solution, err := lang.GetSolutionCode(q)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get solution code: %w", err)
 	}
+	err = checks.run(q)
+	if err != nil {
+		return nil, err
+	}
 
 	spin := newSpinner(cmd.ErrOrStderr())
-	spin.Suffix = " Submitting solution..."
+	spin.Suffix = " " + i18n.T("Submitting solution...")
 	spin.Reverse()
 	spin.Start()
 	defer spin.Stop()
@@ -99,19 +163,177 @@ func submitSolution(
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit solution: %w", err)
 	}
+	logTime(q, config.TimeSubmitted)
+
+	if detach {
+		spin.Stop()
+		recordSubmission(q, gen, submissionId, solution)
+		log.Info("submission detached", "question", q.TitleSlug, "id", submissionId)
+		return nil, nil
+	}
 
 	spin.Lock()
-	spin.Suffix = " Waiting for result..."
+	spin.Suffix = " " + i18n.T("Waiting for result...")
 	spin.Unlock()
 
-	testResult, err := waitResult(c, submissionId)
+	testResult, err := waitResult(c, submissionId, spin)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait submit result: %w", err)
 	}
-	return testResult.(*leetcode.SubmitCheckResult), nil
+	result := testResult.(*leetcode.SubmitCheckResult)
+	emitSubmitEvent(q.TitleSlug, q.QuestionFrontendId, gen.Slug(), result)
+	notifyVerdict(cmd, q.TitleSlug, q.QuestionFrontendId, result)
+	recordVirtual(q, result.Accepted())
+	if result.Accepted() {
+		markAccepted(q, gen, result, solution)
+	}
+	return result, nil
+}
+
+func emitSubmitEvent(slug, frontendID, lang string, r *leetcode.SubmitCheckResult) {
+	event := config.Event{
+		Type:       config.EventSubmissionAccepted,
+		Slug:       slug,
+		FrontendID: frontendID,
+		Lang:       lang,
+		Status:     r.StatusMsg,
+	}
+	if !r.Accepted() {
+		event.Type = config.EventSubmissionRejected
+	}
+	config.EmitEvent(event)
+}
+
+// notifyVerdict rings the bell or shows a desktop notification of the judge verdict, as configured by notifications.
+func notifyVerdict(cmd *cobra.Command, slug, frontendID string, r *leetcode.SubmitCheckResult) {
+	cfg := config.Get().Notifications
+	if cfg.Bell {
+		cmd.PrintErr("\a")
+	}
+	if !cfg.Desktop || config.SafeMode() {
+		return
+	}
+	msg := fmt.Sprintf("%s. %s", frontendID, slug)
+	if r.Accepted() {
+		msg += fmt.Sprintf(", runtime %s, memory %s", r.StatusRuntime, r.StatusMemory)
+	} else if r.TotalTestcases > 0 {
+		msg += fmt.Sprintf(", passed %d/%d", r.TotalCorrect, r.TotalTestcases)
+	}
+	err := utils.Notify(constants.CmdName+": "+r.StatusMsg, msg)
+	if err != nil {
+		log.Debug("failed to show notification", "err", err)
+	}
+}
+
+func recordSubmission(q *leetcode.QuestionData, gen lang.Lang, submissionId string, solution string) {
+	state := config.LoadState()
+	state.AddSubmission(
+		config.SubmissionRecord{
+			ID:          submissionId,
+			Site:        config.Get().LeetCode.Site.Short(),
+			Slug:        q.TitleSlug,
+			FrontendID:  q.QuestionFrontendId,
+			Lang:        gen.Slug(),
+			Hash:        solutionHash(solution),
+			SubmittedAt: time.Now(),
+		},
+	)
+	config.SaveState(state)
+}
+
+// markAccepted records the accepted submission with the hash of the solution submitted,
+// which may differ from the file if it's edited while judging.
+func markAccepted(q *leetcode.QuestionData, gen lang.Lang, result *leetcode.SubmitCheckResult, solution string) {
+	hash := solutionHash(solution)
+	state := config.LoadState()
+	state.MarkAccepted(q.TitleSlug, q.QuestionFrontendId, gen.Slug(), hash)
+	state.AddRun(q.TitleSlug, gen.Slug(), runRecord(result, hash))
+	config.SaveState(state)
+}
+
+// solutionHash identifies the content of a solution, changes outside the code markers are ignored.
+func solutionHash(solution string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(solution)))
+	return hex.EncodeToString(sum[:])
+}
+
+// changedQuestions returns questions whose solutions have changed since they were last accepted.
+// Questions accepted before hashes were recorded are not included.
+func changedQuestions(c leetcode.Client, gen lang.Lang) []*leetcode.QuestionData {
+	state := config.LoadState()
+	hashes := state.AcceptedHashes(gen.Slug())
+	slugs := make([]string, 0, len(hashes))
+	for slug := range hashes {
+		slugs = append(slugs, slug)
+	}
+	slices.Sort(slugs)
+
+	index := config.LoadIndex()
+	defer index.Save()
+	var qs []*leetcode.QuestionData
+	for _, slug := range slugs {
+		q, err := leetcode.QuestionBySlug(slug, c)
+		if err != nil {
+			log.Warn("failed to get question", "question", slug, "err", err)
+			continue
+		}
+		hash, err := indexedSolutionHash(index, q)
+		if err != nil {
+			log.Debug("failed to get solution code", "question", slug, "err", err)
+			continue
+		}
+		if hash != hashes[slug] {
+			qs = append(qs, q)
+		}
+	}
+	return qs
+}
+
+// indexedSolutionHash returns the hash of the solution in the code file of the question,
+// the code file is only read if it's modified since it was last indexed.
+func indexedSolutionHash(index *config.Index, q *leetcode.QuestionData) (string, error) {
+	codeFile, err := lang.GetFileOutput(q, lang.CodeFile)
+	if err != nil {
+		return "", err
+	}
+	return index.Value(
+		codeFile.GetPath(), func() (string, error) {
+			solution, err := lang.ExtractCode(codeFile.GetPath())
+			if err != nil {
+				return "", err
+			}
+			return solutionHash(solution), nil
+		},
+	)
+}
+
+func confirmChanged(cmd *cobra.Command, qs []*leetcode.QuestionData) (bool, error) {
+	cmd.PrintErrln("Solutions changed since accepted:")
+	for _, q := range qs {
+		cmd.PrintErrf("  %s. %s\n", q.QuestionFrontendId, q.GetTitle())
+	}
+	if viper.GetBool("yes") {
+		return true, nil
+	}
+	if config.SafeMode() {
+		return false, fmt.Errorf("submit changed solutions without --yes: %w", config.ErrSafeMode)
+	}
+	submit := false
+	err := config.AskOne(&survey.Confirm{Message: fmt.Sprintf("Submit %d solutions?", len(qs))}, &submit)
+	return submit, err
 }
 
 func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckResult) (bool, error) {
+	failedCase := lang.TestCase{
+		Question: q,
+		Input:    strings.Split(result.LastTestcase, "\n"),
+		Output:   result.ExpectedOutput,
+	}
+	return addTestCase(q, failedCase)
+}
+
+// addTestCase appends the case to the test cases file of the question, unless it is already there.
+func addTestCase(q *leetcode.QuestionData, c lang.TestCase) (bool, error) {
 	genResult, err := lang.GeneratePathsOnly(q)
 	if err != nil {
 		return false, err
@@ -121,13 +343,8 @@ func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckRes
 		return false, nil
 	}
 
-	failedCase := lang.TestCase{
-		Question: q,
-		Input:    strings.Split(result.LastTestcase, "\n"),
-		Output:   result.ExpectedOutput,
-	}
 	// some test cases are hidden during contest, they can be excluded by checking
-	err = failedCase.Check()
+	err = c.Check()
 	if err != nil {
 		return false, err
 	}
@@ -136,10 +353,10 @@ func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckRes
 	if err != nil {
 		return false, err
 	}
-	if tc.Contains(failedCase) {
+	if tc.Contains(c) {
 		return false, nil
 	}
-	tc.AddCase(failedCase)
+	tc.AddCase(c)
 
 	content := []byte(tc.String())
 	err = utils.WriteFile(testCasesFile.GetPath(), content)
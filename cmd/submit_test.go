@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+type fakeQuestionClient struct {
+	leetcode.Client
+	ids map[string]string
+}
+
+func (c *fakeQuestionClient) GetQuestionData(slug string) (*leetcode.QuestionData, error) {
+	return &leetcode.QuestionData{TitleSlug: slug, QuestionFrontendId: c.ids[slug]}, nil
+}
+
+func TestSolutionHash(t *testing.T) {
+	hash := solutionHash("func twoSum() {}")
+	if len(hash) != 64 {
+		t.Errorf("solutionHash() = %q, want a sha256 hex digest", hash)
+	}
+	// Blank lines around the code markers are not a change.
+	if got := solutionHash("\nfunc twoSum() {}\n\n"); got != hash {
+		t.Errorf("solutionHash() with surrounding blank lines = %q, want %q", got, hash)
+	}
+	if got := solutionHash("func twoSum() { return }"); got == hash {
+		t.Errorf("solutionHash() of different solutions should differ")
+	}
+}
+
+func TestChangedQuestions(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	t.Setenv("LEETGO_STATE_DIR", t.TempDir())
+	chdir(t, t.TempDir())
+	gen, err := lang.GetGenerator("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &fakeQuestionClient{ids: map[string]string{"two-sum": "1", "add-two-numbers": "2", "three-sum": "15"}}
+
+	writeSolution := func(slug, header, solution string) {
+		q, _ := c.GetQuestionData(slug)
+		f, err := lang.GetFileOutput(q, lang.CodeFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(f.GetPath()), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		content := fmt.Sprintf("%s\n// %s\n%s\n// %s\n", header, constants.CodeBeginMarker, solution, constants.CodeEndMarker)
+		if err := os.WriteFile(f.GetPath(), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSolution("two-sum", "package main", "func twoSum() {}")
+	writeSolution("add-two-numbers", "package main", "func addTwoNumbers() {}")
+	writeSolution("three-sum", "package main", "func threeSum() {}")
+
+	state := config.LoadState()
+	state.MarkAccepted("two-sum", "1", gen.Slug(), solutionHash("func twoSum() {}"))
+	state.MarkAccepted("add-two-numbers", "2", gen.Slug(), solutionHash("func addTwoNumbers() {}"))
+	config.SaveState(state)
+
+	// three-sum is not accepted yet, so it's not changed.
+	if qs := changedQuestions(c, gen); len(qs) != 0 {
+		t.Errorf("changedQuestions() = %v, want none", slugsOf(qs))
+	}
+
+	writeSolution("two-sum", "package main\n\nimport \"fmt\"", "func twoSum() {}")
+	writeSolution("add-two-numbers", "package main", "func addTwoNumbers() { return }")
+	// Only changes between the code markers count.
+	if qs := changedQuestions(c, gen); len(qs) != 1 || qs[0].TitleSlug != "add-two-numbers" {
+		t.Errorf("changedQuestions() = %v, want add-two-numbers", slugsOf(qs))
+	}
+}
+
+func slugsOf(qs []*leetcode.QuestionData) []string {
+	slugs := make([]string, len(qs))
+	for i, q := range qs {
+		slugs[i] = q.TitleSlug
+	}
+	return slugs
+}
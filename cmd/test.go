@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
 	"github.com/j178/leetgo/utils"
@@ -23,6 +25,8 @@ var (
 	autoSubmit  bool
 	targetCase  string
 	forceSubmit bool
+	watchTest   bool
+	failFast    bool
 )
 
 func init() {
@@ -41,20 +45,23 @@ func init() {
 		"run test both locally and remotely",
 	)
 	testCmd.Flags().BoolVarP(&autoSubmit, "submit", "s", false, "auto submit if all tests passed")
-	testCmd.Flags().BoolVarP(&forceSubmit, "force", "f", false, "force submit even if local test failed")
+	testCmd.Flags().BoolVarP(&forceSubmit, "force-submit", "f", false, "submit even if local test failed")
+	testCmd.Flags().BoolVarP(&watchTest, "watch", "w", false, "rerun local test whenever the solution or test cases file is saved")
+	testCmd.Flags().BoolVar(&failFast, "fail-fast", false, "stop at the first failed test case")
 	testCmd.Flags().StringVarP(&targetCase, "target", "t", "-", "only run the specified test case, e.g. 1, 1-3, -1, 1-")
 }
 
 var testCmd = &cobra.Command{
-	Use:       "test qid",
-	Aliases:   []string{"t"},
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"today", "last", "last/"},
-	Short:     "Run question test cases",
+	Use:               "test qid",
+	Aliases:           []string{"t"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last", "last/"),
+	Short:             "Run question test cases",
 	Example: `leetgo test 244
 leetgo test last
 leetgo test w330/1
-leetgo test w330/`,
+leetgo test w330/
+leetgo test last --watch`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if runLocally {
 			runRemotely = false
@@ -63,6 +70,13 @@ leetgo test w330/`,
 			runLocally = true
 			runRemotely = true
 		}
+		if watchTest {
+			if autoSubmit {
+				return errors.New("--watch cannot be used with --submit")
+			}
+			runLocally = true
+			runRemotely = false
+		}
 
 		cfg := config.Get()
 		c := leetcode.NewClient(leetcode.ReadCredentials())
@@ -79,6 +93,9 @@ leetgo test w330/`,
 		if runLocally && !supportLocalTest {
 			return fmt.Errorf("local test not supported for %s", cfg.Code.Lang)
 		}
+		if watchTest {
+			return watchLocalTest(cmd, qs)
+		}
 
 		user, err := c.GetUserStatus()
 		if err != nil {
@@ -87,40 +104,59 @@ leetgo test w330/`,
 		testLimiter := newLimiter(user)
 		submitLimiter := newLimiter(user)
 
-		var hasFailedCase bool
+		var (
+			hasFailedCase bool
+			results       []*questionResult
+			checks        = newChecklist()
+		)
 		for _, q := range qs {
+			gen := lang.GeneratorFor(q, gen)
 			var (
 				localPassed    = true
 				remotePassed   = true
 				submitAccepted = true
+				qr             = newQuestionResult(q, gen.Slug())
 			)
+			results = append(results, qr)
+			checkSignatures(q)
+			logTime(q, config.TimeTested)
 			if runLocally {
 				log.Info("running test locally", "question", q.TitleSlug)
-				localPassed, err = lang.RunLocalTest(q, targetCase)
+				localPassed, err = lang.RunLocalTest(q, localTestOptions())
 				if err != nil {
 					log.Error("failed to run test locally", "err", err)
+					qr.Errors = append(qr.Errors, err.Error())
 				}
+				qr.Local = &localPassed
 			}
 			if runRemotely {
 				log.Info("running test remotely", "question", q.TitleSlug)
 				result, err := runTestRemotely(cmd, q, c, gen, testLimiter)
 				if err != nil {
 					log.Error("failed to run test remotely", "err", err)
+					qr.Errors = append(qr.Errors, err.Error())
 					remotePassed = false
 				} else {
-					cmd.Print(result.Display(q))
+					qr.Remote = runVerdict(result)
+					if !config.JSONOutput() {
+						cmd.Print(result.Display(q))
+					}
 					remotePassed = result.CorrectAnswer
 				}
 			}
 
 			if autoSubmit && remotePassed && (localPassed || forceSubmit) {
 				log.Info("submitting solution", "user", user.Whoami(c))
-				result, err := submitSolution(cmd, q, c, gen, submitLimiter)
+				result, err := submitSolution(cmd, q, c, gen, submitLimiter, checks, false)
 				if err != nil {
 					submitAccepted = false
 					log.Error("failed to submit solution", "err", err)
+					qr.Errors = append(qr.Errors, err.Error())
 				} else {
-					cmd.Print(result.Display(q))
+					qr.Submit = submitVerdict(result)
+					if !config.JSONOutput() {
+						cmd.Print(result.Display(q))
+					}
 					if !result.Accepted() {
 						submitAccepted = false
 						added, _ := appendToTestCases(q, result)
@@ -133,9 +169,17 @@ leetgo test w330/`,
 
 			if !localPassed || !remotePassed || !submitAccepted {
 				hasFailedCase = true
+				if failFast {
+					break
+				}
 			}
 		}
 
+		if config.JSONOutput() {
+			if err := encodeJSON(cmd, results); err != nil {
+				return err
+			}
+		}
 		if hasFailedCase {
 			return exitCode(1)
 		}
@@ -179,7 +223,7 @@ func runTestRemotely(
 	}
 
 	spin := newSpinner(cmd.ErrOrStderr())
-	spin.Suffix = " Running tests..."
+	spin.Suffix = " " + i18n.T("Running tests...")
 	spin.Reverse()
 	spin.Start()
 	defer spin.Stop()
@@ -193,10 +237,10 @@ func runTestRemotely(
 	}
 
 	spin.Lock()
-	spin.Suffix = " Waiting for result..."
+	spin.Suffix = " " + i18n.T("Waiting for result...")
 	spin.Unlock()
 
-	testResult, err := waitResult(c, interResult.InterpretId)
+	testResult, err := waitResult(c, interResult.InterpretId, spin)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait test result: %w", err)
 	}
@@ -218,21 +262,58 @@ func runTestRemotely(
 		}
 	}
 
+	event := config.Event{
+		Type:       config.EventTestPassed,
+		Slug:       q.TitleSlug,
+		FrontendID: q.QuestionFrontendId,
+		Lang:       gen.Slug(),
+		Runner:     "remote",
+		Status:     r.StatusMsg,
+	}
+	if !r.Accepted() || !r.CorrectAnswer {
+		event.Type = config.EventTestFailed
+	}
+	config.EmitEvent(event)
 	return r, nil
 }
 
-func waitResult(c leetcode.Client, submissionId string) (
+// judgeStateNames maps states of the judge queue to readable names.
+var judgeStateNames = map[string]string{
+	"PENDING": "Pending",
+	"STARTED": "Judging",
+	"SUCCESS": "Finished",
+}
+
+// waitResult polls the result of a submission, state transitions and elapsed time are shown in the spinner.
+func waitResult(c leetcode.Client, submissionId string, spin *spinner.Spinner) (
 	leetcode.CheckResult,
 	error,
 ) {
+	start := time.Now()
+	var states []string
 	for {
 		result, err := c.CheckResult(submissionId)
 		if err != nil {
 			return nil, err
 		}
+		state := result.GetState()
+		if name, ok := judgeStateNames[state]; ok {
+			state = i18n.T(name)
+		}
+		if len(states) == 0 || states[len(states)-1] != state {
+			states = append(states, state)
+			log.Debug("judge state changed", "submission", submissionId, "state", state)
+		}
 		if result.GetState() == "SUCCESS" {
 			return result, nil
 		}
+		spin.Lock()
+		spin.Suffix = fmt.Sprintf(
+			" %s (%s)",
+			strings.Join(states, " → "),
+			time.Since(start).Truncate(time.Second),
+		)
+		spin.Unlock()
 		time.Sleep(1 * time.Second)
 	}
 }
@@ -245,6 +326,10 @@ func newLimiter(user *leetcode.UserStatus) *utils.RateLimiter {
 }
 
 func newSpinner(w io.Writer) *spinner.Spinner {
+	// Spinners are noisy for screen readers, keep them quiet in plain output mode.
+	if config.Get().UsePlainOutput() {
+		w = io.Discard
+	}
 	spin := spinner.New(
 		spinner.CharSets[11],
 		125*time.Millisecond,
@@ -254,3 +339,7 @@ func newSpinner(w io.Writer) *spinner.Spinner {
 	)
 	return spin
 }
+
+func localTestOptions() lang.TestOptions {
+	return lang.TestOptions{TargetCase: targetCase, FailFast: failFast}
+}
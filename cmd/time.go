@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// logTime records the event in the time log of the question.
+func logTime(q *leetcode.QuestionData, event string) {
+	state := config.LoadState()
+	state.LogTime(q.TitleSlug, event)
+	config.SaveState(state)
+}
+
+var timeCmd = &cobra.Command{
+	Use:   "time [qid]",
+	Short: "Show time spent on a question, or average solve time by difficulty",
+	Long: `Show the sessions of working on a question and the time spent on it.
+Without qid, show the average solve time by difficulty, to practice pacing for interviews.
+
+Time is tracked by events of the question: generated, opened in the editor, tested, submitted and accepted.
+The time between events is counted unless it's a break longer than an hour, which ends a session.
+Coding without running commands leaves no events, use 'leetgo time start' to time a session explicitly.
+The solve time is from the question was first generated, opened or timed to its first acceptance,
+it's unknown if that took longer than 4 hours.`,
+	Example: `leetgo time last
+leetgo time 1
+leetgo time start last --pomodoro 25m
+leetgo time`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		state := config.LoadState()
+		if len(args) == 0 {
+			return showSolveTimes(cmd, c, state)
+		}
+
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		qs, _ := state.Question(q.TitleSlug)
+		sessions := qs.Sessions()
+		if config.JSONOutput() {
+			if sessions == nil {
+				sessions = []config.Session{}
+			}
+			return encodeJSON(
+				cmd, map[string]any{
+					"frontend_id":        q.QuestionFrontendId,
+					"slug":               q.TitleSlug,
+					"time_spent_seconds": int(qs.TimeSpent().Seconds()),
+					"solve_time_seconds": int(qs.SolveTime.Seconds()),
+					"sessions":           sessions,
+				},
+			)
+		}
+		if len(sessions) == 0 {
+			log.Info("no time recorded", "question", q.TitleSlug)
+			return nil
+		}
+		showSessions(cmd, q, qs, sessions)
+		return nil
+	},
+}
+
+var timePomodoro time.Duration
+
+func init() {
+	timeStartCmd.Flags().DurationVar(
+		&timePomodoro, "pomodoro", 0, "wait for the duration, e.g. 25m, then stop the session and notify",
+	)
+	timeCmd.AddCommand(timeStartCmd)
+	timeCmd.AddCommand(timeStopCmd)
+}
+
+var timeStartCmd = &cobra.Command{
+	Use:   "start qid",
+	Short: "Start a timed session of a question",
+	Long: `Start a timed session of a question, it lasts until 'leetgo time stop',
+however long the pauses between commands are.
+With --pomodoro, wait for the duration in the terminal, then stop the session and show a desktop notification.
+Interrupting the wait stops the session early.`,
+	Example: `leetgo time start last
+leetgo time start 1 --pomodoro 25m`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if timePomodoro < 0 {
+			return errors.New("--pomodoro must not be negative")
+		}
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		state := config.LoadState()
+		if qs, _ := state.Question(q.TitleSlug); qs.Timing() {
+			return fmt.Errorf("a session of %s is already running, stop it with `leetgo time stop`", q.TitleSlug)
+		}
+		logTime(q, config.TimeStarted)
+		if timePomodoro == 0 {
+			log.Info("session started", "question", q.TitleSlug)
+			return nil
+		}
+
+		log.Info(
+			"pomodoro started, interrupt to stop early",
+			"question", q.TitleSlug,
+			"ends", time.Now().Add(timePomodoro).Format(time.TimeOnly),
+		)
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		timer := time.NewTimer(timePomodoro)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			logTime(q, config.TimeStopped)
+			log.Info("pomodoro finished", "question", q.TitleSlug)
+			msg := fmt.Sprintf("%s. %s, %s is up, take a break.", q.QuestionFrontendId, q.GetTitle(), formatDuration(timePomodoro))
+			if err := utils.Notify(constants.CmdName+": pomodoro finished", msg); err != nil {
+				log.Debug("failed to notify", "err", err)
+			}
+		case <-ctx.Done():
+			logTime(q, config.TimeStopped)
+			log.Info("pomodoro stopped early", "question", q.TitleSlug)
+		}
+		return nil
+	},
+}
+
+var timeStopCmd = &cobra.Command{
+	Use:               "stop qid",
+	Short:             "Stop the timed session of a question",
+	Example:           "leetgo time stop last",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeQuestions(1, "today", "last"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		q, err := parseSingleQuestion(args[0], c)
+		if err != nil {
+			return err
+		}
+		state := config.LoadState()
+		if qs, _ := state.Question(q.TitleSlug); !qs.Timing() {
+			return fmt.Errorf("no session of %s is running", q.TitleSlug)
+		}
+		logTime(q, config.TimeStopped)
+		log.Info("session stopped", "question", q.TitleSlug)
+		return nil
+	},
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+func showSessions(cmd *cobra.Command, q *leetcode.QuestionData, qs config.QuestionState, sessions []config.Session) {
+	summary := fmt.Sprintf("time spent: %s", formatDuration(qs.TimeSpent()))
+	if qs.SolveTime > 0 {
+		summary += fmt.Sprintf(", accepted after %s", formatDuration(qs.SolveTime))
+	}
+
+	if config.Get().UsePlainOutput() {
+		cmd.Printf("%s. %s\n", q.QuestionFrontendId, q.GetTitle())
+		for i, s := range sessions {
+			cmd.Printf(
+				"%d. started at %s, %s, %s\n",
+				i+1, s.Start.Local().Format(time.DateTime), formatDuration(s.Duration()), strings.Join(s.Events, ", "),
+			)
+		}
+		cmd.Println(summary)
+		return
+	}
+
+	cmd.Printf("%s. %s\n", q.QuestionFrontendId, q.GetTitle())
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.SetTitle(summary)
+	w.AppendHeader(table.Row{"#", "Started", "Duration", "Events"})
+	for i, s := range sessions {
+		w.AppendRow(
+			table.Row{i + 1, s.Start.Local().Format(time.DateTime), formatDuration(s.Duration()), strings.Join(s.Events, ", ")},
+		)
+	}
+	w.Render()
+}
+
+// solveTimeStat is the average solve time of questions of a difficulty.
+type solveTimeStat struct {
+	Difficulty     string `json:"difficulty"`
+	Solved         int    `json:"solved"`
+	AverageSeconds int    `json:"average_seconds"`
+	FastestSeconds int    `json:"fastest_seconds"`
+}
+
+func showSolveTimes(cmd *cobra.Command, c leetcode.Client, state config.State) error {
+	solveTimes := state.SolveTimes()
+	slugs := make([]string, 0, len(solveTimes))
+	for slug := range solveTimes {
+		slugs = append(slugs, slug)
+	}
+	qs, err := leetcode.QuestionsBySlugs(slugs, c)
+	if err != nil {
+		log.Debug("failed to get questions", "err", err)
+	}
+	byDifficulty := map[string][]time.Duration{}
+	for i, q := range qs {
+		if q == nil {
+			log.Debug("question not found", "question", slugs[i])
+			continue
+		}
+		byDifficulty[q.Difficulty] = append(byDifficulty[q.Difficulty], solveTimes[slugs[i]])
+	}
+
+	stats := []solveTimeStat{}
+	for _, difficulty := range []string{"Easy", "Medium", "Hard"} {
+		times := byDifficulty[difficulty]
+		if len(times) == 0 {
+			continue
+		}
+		var total time.Duration
+		for _, d := range times {
+			total += d
+		}
+		stats = append(
+			stats, solveTimeStat{
+				Difficulty:     difficulty,
+				Solved:         len(times),
+				AverageSeconds: int((total / time.Duration(len(times))).Seconds()),
+				FastestSeconds: int(slices.Min(times).Seconds()),
+			},
+		)
+	}
+
+	if config.JSONOutput() {
+		return encodeJSON(cmd, stats)
+	}
+	if len(stats) == 0 {
+		log.Info("no solve time recorded yet, it's recorded when a question generated by leetgo is accepted")
+		return nil
+	}
+	if config.Get().UsePlainOutput() {
+		for _, s := range stats {
+			cmd.Printf(
+				"%s: %d solved, average %s, fastest %s\n",
+				s.Difficulty, s.Solved,
+				formatDuration(time.Duration(s.AverageSeconds)*time.Second),
+				formatDuration(time.Duration(s.FastestSeconds)*time.Second),
+			)
+		}
+		return nil
+	}
+	w := table.NewWriter()
+	w.SetOutputMirror(cmd.OutOrStdout())
+	w.SetStyle(table.StyleColoredDark)
+	w.AppendHeader(table.Row{"Difficulty", "Solved", "Average", "Fastest"})
+	for _, s := range stats {
+		w.AppendRow(
+			table.Row{
+				s.Difficulty, s.Solved,
+				formatDuration(time.Duration(s.AverageSeconds) * time.Second),
+				formatDuration(time.Duration(s.FastestSeconds) * time.Second),
+			},
+		)
+	}
+	w.Render()
+	return nil
+}
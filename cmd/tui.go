@@ -20,7 +20,9 @@ var (
 	// textStyle         = lipgloss.NewStyle().Margin(1, 0, 2, 4)
 )
 
-type rowDelegate struct{}
+type rowDelegate struct {
+	ratings leetcode.Ratings
+}
 
 func (d rowDelegate) Height() int {
 	return 1
@@ -43,6 +45,9 @@ func (d rowDelegate) Render(w io.Writer, m list.Model, index int, listItem list.
 
 	// TODO improve display
 	str := q.GetTitle()
+	if rating, ok := d.ratings.Get(q.TitleSlug); ok {
+		str += fmt.Sprintf(" (%d)", int(rating))
+	}
 	if index == m.Index() {
 		str = selectedItemStyle.Render("> " + str)
 	} else {
@@ -62,6 +67,8 @@ func (i *item) FilterValue() string {
 type tui struct {
 	filter   leetcode.QuestionFilter
 	client   leetcode.Client
+	ratings  leetcode.Ratings
+	sort     bool
 	idx      int // nolint: unused
 	total    int
 	hasMore  bool
@@ -69,8 +76,11 @@ type tui struct {
 	selected *leetcode.QuestionData
 }
 
-func newTuiModel(filter leetcode.QuestionFilter, c leetcode.Client) *tui {
-	l := list.New(nil, rowDelegate{}, 60, 60)
+// newTuiModel creates the question picker, imported difficulty ratings are shown after titles,
+// and used to sort loaded questions if sortByRating is set.
+func newTuiModel(filter leetcode.QuestionFilter, c leetcode.Client, sortByRating bool) *tui {
+	ratings, _ := leetcode.LoadRatings()
+	l := list.New(nil, rowDelegate{ratings: ratings}, 60, 60)
 	l.Title = "Select a question"
 	l.SetShowStatusBar(true)
 	l.SetShowTitle(true)
@@ -80,9 +90,11 @@ func newTuiModel(filter leetcode.QuestionFilter, c leetcode.Client) *tui {
 
 	// TODO Implement a progressive loading list
 	return &tui{
-		filter: filter,
-		client: c,
-		list:   &l,
+		filter:  filter,
+		client:  c,
+		ratings: ratings,
+		sort:    sortByRating,
+		list:    &l,
 	}
 }
 
@@ -119,6 +131,9 @@ func (m *tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list.SetSize(msg.Width, msg.Height)
 		return m, nil
 	case qsMsg:
+		if m.sort {
+			leetcode.SortByRating(msg, m.ratings)
+		}
 		items := make([]list.Item, len(msg))
 		for i, q := range msg {
 			items[i] = (*item)(q)
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/i18n"
+	"github.com/j178/leetgo/upgrade"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade leetgo to the latest release",
+	Long: `Upgrade leetgo to the latest release on GitHub, replacing the running binary in place.
+
+The downloaded archive is verified against the checksums published with the release.
+The release channel is 'upgrade.channel' in the config, 'nightly' includes pre-releases as well.
+Binaries installed by Homebrew or Scoop should be upgraded by them, use --force to upgrade anyway.
+Set GITHUB_TOKEN if the rate limit of the GitHub API is exceeded.`,
+	Example: `leetgo upgrade
+leetgo upgrade --check
+leetgo upgrade --channel nightly`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, _ := cmd.Flags().GetString("channel")
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		force := viper.GetBool("force")
+		if channel == "" {
+			channel = config.Get().Upgrade.Channel
+		}
+		if channel != upgrade.ChannelStable && channel != upgrade.ChannelNightly {
+			return fmt.Errorf("invalid channel %q, only stable or nightly is supported", channel)
+		}
+
+		release, err := upgrade.Latest(cmd.Context(), channel)
+		if err != nil {
+			return fmt.Errorf("failed to get the latest release: %w", err)
+		}
+		upgrade.SaveCheck(channel, release.Version())
+		current, latest := constants.Version, release.Version()
+		newer := upgrade.IsNewer(latest, current)
+
+		if checkOnly {
+			if config.JSONOutput() {
+				return encodeJSON(
+					cmd, map[string]any{
+						"current": current,
+						"latest":  latest,
+						"channel": channel,
+						"newer":   newer,
+						"url":     release.HTMLURL,
+					},
+				)
+			}
+			cmd.Printf("current: %s\nlatest %s: %s\n%s\n", current, channel, latest, release.HTMLURL)
+			return nil
+		}
+
+		if !newer && !force {
+			if !upgrade.IsRelease() {
+				return fmt.Errorf("leetgo %s is not built by a release, use --force to install %s", current, latest)
+			}
+			log.Info("leetgo is up to date", "version", current, "channel", channel)
+			return nil
+		}
+		exe, err := upgrade.Executable()
+		if err != nil {
+			return err
+		}
+		if pm := upgrade.PackageManager(exe); pm != "" && !force {
+			return fmt.Errorf("leetgo is installed by a package manager, upgrade it by `%s`", pm)
+		}
+
+		if config.SafeMode() && !viper.GetBool("yes") {
+			return fmt.Errorf("upgrade: %w, use --yes to confirm", config.ErrSafeMode)
+		} else if !viper.GetBool("yes") {
+			accept := false
+			err = config.AskOne(
+				&survey.Confirm{
+					Message: i18n.Sprintf("Upgrade leetgo from %s to %s?", current, latest),
+					Default: true,
+				}, &accept,
+			)
+			if err != nil || !accept {
+				return err
+			}
+		}
+
+		bin, err := upgrade.Download(cmd.Context(), release)
+		if errors.Is(err, upgrade.ErrNoAsset) {
+			return fmt.Errorf("%w, please build it from source", err)
+		}
+		if err != nil {
+			return err
+		}
+		err = upgrade.Replace(exe, bin)
+		if err != nil {
+			return fmt.Errorf("failed to replace %s: %w", exe, err)
+		}
+		log.Info("leetgo upgraded", "from", current, "to", latest, "path", exe)
+		return nil
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().String("channel", "", "release channel: stable or nightly, defaults to upgrade.channel in the config")
+	upgradeCmd.Flags().Bool("check", false, "only print the current and the latest versions")
+	_ = upgradeCmd.RegisterFlagCompletionFunc(
+		"channel", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{upgrade.ChannelStable, upgrade.ChannelNightly}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+}
+
+// upgradeCheckTimeout bounds the background check for a new version, the notice is skipped if it takes longer than the command.
+const upgradeCheckTimeout = 5 * time.Second
+
+var (
+	// cachedLatestVersion is the latest version found by the last check.
+	cachedLatestVersion string
+	// checkedLatestVersion receives the latest version when a background check finishes.
+	checkedLatestVersion = make(chan string, 1)
+)
+
+// checkUpgrade starts a check for a new version in background if the last one is more than a day ago.
+// It's only done for release binaries running in a terminal, so that scripts are never disturbed.
+func checkUpgrade(cmd *cobra.Command) {
+	cfg := config.Get()
+	if !cfg.Upgrade.Check || cmd == upgradeCmd || !upgrade.IsRelease() || config.Offline() ||
+		!isatty.IsTerminal(os.Stderr.Fd()) {
+		return
+	}
+	channel := cfg.Upgrade.Channel
+	version, stale := upgrade.CachedLatest(channel)
+	cachedLatestVersion = version
+	if !stale {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), upgradeCheckTimeout)
+		defer cancel()
+		release, err := upgrade.Latest(ctx, channel)
+		if err != nil {
+			log.Debug("failed to check for a new version", "err", err)
+			// Do not retry on every command when GitHub is unreachable.
+			upgrade.SaveCheck(channel, version)
+			return
+		}
+		upgrade.SaveCheck(channel, release.Version())
+		checkedLatestVersion <- release.Version()
+	}()
+}
+
+// printUpgradeNotice prints a notice to stderr if a newer version is available.
+func printUpgradeNotice() {
+	latest := cachedLatestVersion
+	select {
+	case latest = <-checkedLatestVersion:
+	default:
+	}
+	if latest == "" || !upgrade.IsNewer(latest, constants.Version) {
+		return
+	}
+	_, _ = fmt.Fprintln(
+		os.Stderr,
+		i18n.Sprintf("A new version of leetgo is available: %s → %s, run `leetgo upgrade` to upgrade.", constants.Version, latest),
+	)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+)
+
+const watchDebounce = 300 * time.Millisecond
+
+// watchLocalTest runs local tests of the questions, and reruns them whenever their files are saved.
+func watchLocalTest(cmd *cobra.Command, qs []*leetcode.QuestionData) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Watch directories instead of files, as many editors save files by renaming a temporary file.
+	files := make(map[string]*leetcode.QuestionData)
+	for _, q := range qs {
+		result, err := lang.GeneratePathsOnly(q)
+		if err != nil {
+			return err
+		}
+		for _, f := range result.Files {
+			if f.Type != lang.CodeFile && f.Type != lang.TestCasesFile {
+				continue
+			}
+			files[filepath.Clean(f.GetPath())] = q
+		}
+		err = watcher.Add(result.TargetDir())
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", result.TargetDir(), err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	runLocalTests(cmd, qs)
+	log.Info("watching for changes, press Ctrl-C to exit")
+
+	var (
+		timer   *time.Timer
+		timerC  <-chan time.Time
+		changed = make(map[*leetcode.QuestionData]struct{})
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			q, ok := files[filepath.Clean(event.Name)]
+			if !ok {
+				continue
+			}
+			changed[q] = struct{}{}
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+				timerC = timer.C
+			} else {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error("watch error", "err", err)
+		case <-timerC:
+			timer, timerC = nil, nil
+			toRun := make([]*leetcode.QuestionData, 0, len(changed))
+			for _, q := range qs {
+				if _, ok := changed[q]; ok {
+					toRun = append(toRun, q)
+				}
+			}
+			clear(changed)
+			runLocalTests(cmd, toRun)
+		}
+	}
+}
+
+func runLocalTests(cmd *cobra.Command, qs []*leetcode.QuestionData) {
+	passed := 0
+	for _, q := range qs {
+		log.Info("running test locally", "question", q.TitleSlug)
+		ok, err := lang.RunLocalTest(q, localTestOptions())
+		if err != nil {
+			log.Error("failed to run test locally", "err", err)
+		}
+		if ok {
+			passed++
+		}
+	}
+	summary := fmt.Sprintf("%d/%d questions passed at %s", passed, len(qs), time.Now().Format(time.TimeOnly))
+	if passed == len(qs) {
+		cmd.Println(config.PassedStyle.Render(fmt.Sprintf(" %s %s\n", config.PassedMark(), summary)))
+	} else {
+		cmd.Println(config.FailedStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), summary)))
+	}
+}
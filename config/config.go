@@ -7,8 +7,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
 	"github.com/google/shlex"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
@@ -36,27 +40,66 @@ const (
 	EN         Language     = "en"
 )
 
+// Short returns the short name of the site, "cn" or "us".
+func (s LeetcodeSite) Short() string {
+	if s == LeetCodeUS {
+		return "us"
+	}
+	return "cn"
+}
+
 type Config struct {
-	dir         string
-	projectRoot string
-	Author      string         `yaml:"author" mapstructure:"author" comment:"Your name"`
-	Language    Language       `yaml:"language" mapstructure:"language" comment:"Language of the question description: 'zh' (Simplified Chinese) or 'en' (English)."`
-	Code        CodeConfig     `yaml:"code" mapstructure:"code"`
-	LeetCode    LeetCodeConfig `yaml:"leetcode" mapstructure:"leetcode"`
-	Contest     ContestConfig  `yaml:"contest" mapstructure:"contest"`
-	Editor      Editor         `yaml:"editor" mapstructure:"editor" comment:"Editor settings to open generated files."`
+	dir           string
+	projectRoot   string
+	Author        string              `yaml:"author" mapstructure:"author" comment:"Your name"`
+	CachePath     string              `yaml:"cache_dir" mapstructure:"cache_dir" comment:"Directory to put cache files, defaults to $XDG_CACHE_HOME/leetgo or ~/.cache/leetgo (will be overridden by LEETGO_CACHE_DIR).\nSet it to a writable directory if the default one is read-only, relative paths are resolved against the project root, ~ is allowed."`
+	Language      Language            `yaml:"language" mapstructure:"language" comment:"Language of leetgo messages and question descriptions: 'zh' (Simplified Chinese) or 'en' (English).\nEmpty to detect it from the locale (LC_ALL, LC_MESSAGES or LANG)."`
+	ContentLang   Language            `yaml:"content_language" mapstructure:"content_language" comment:"Language of question descriptions if it differs from 'language', e.g. Chinese statements with English messages."`
+	Code          CodeConfig          `yaml:"code" mapstructure:"code"`
+	LeetCode      LeetCodeConfig      `yaml:"leetcode" mapstructure:"leetcode"`
+	Contest       ContestConfig       `yaml:"contest" mapstructure:"contest"`
+	Editor        Editor              `yaml:"editor" mapstructure:"editor" comment:"Editor settings to open generated files."`
+	Submit        SubmitConfig        `yaml:"submit" mapstructure:"submit"`
+	PlainOutput   string              `yaml:"plain_output" mapstructure:"plain_output" comment:"Screen-reader friendly output without colors, spinners and box-drawing characters: auto, always or never.\n'auto' enables it when TERM is 'dumb' (will be overridden by command line flag --plain)."`
+	Events        string              `yaml:"events" mapstructure:"events" comment:"Append machine-readable events as JSON lines to this file: file_created, test_passed, test_failed, submission_accepted, submission_rejected.\nIt can be a FIFO or a unix socket as well, to build shell integrations or stream widgets on. Empty to disable.\nRelative paths are resolved against the project root, ~ is allowed."`
+	NotesPath     string              `yaml:"notes_dir" mapstructure:"notes_dir" comment:"Directory to put notes of questions edited by 'leetgo note edit', one markdown file per question named after its slug,\nin a subdirectory per site (us or cn). Relative paths are resolved against the project root, ~ is allowed."`
+	LowBandwidth  bool                `yaml:"low_bandwidth" mapstructure:"low_bandwidth" comment:"Save traffic on metered or slow connections: attachments are not downloaded, images in descriptions become links,\ninit does not download the question list (run 'leetgo cache update' when needed, questions are fetched one by one till then), the question cache is stored compressed,\nquestions are fetched with the description in the content language only (the other one is fetched when needed), and the bytes transferred are reported after each command."`
+	Notifications NotificationsConfig `yaml:"notifications" mapstructure:"notifications" comment:"Notify when a judge verdict arrives, instead of staring at the terminal while waiting."`
+	Upgrade       UpgradeConfig       `yaml:"upgrade" mapstructure:"upgrade" comment:"Upgrade leetgo from GitHub releases with 'leetgo upgrade'."`
+	Log           LogConfig           `yaml:"log" mapstructure:"log"`
+}
+
+type LogConfig struct {
+	File bool `yaml:"file" mapstructure:"file" comment:"Write debug logs, including requests to LeetCode, as JSON lines to leetgo.log in the state directory, regardless of --verbose and --quiet.\nUseful for reporting API issues. The file is rotated when it exceeds 5 MB, 3 old files are kept."`
+}
+
+type UpgradeConfig struct {
+	Channel string `yaml:"channel" mapstructure:"channel" comment:"Release channel: 'stable' for releases, 'nightly' to include pre-releases as well."`
+	Check   bool   `yaml:"check" mapstructure:"check" comment:"Check for a new version at most once a day, and show a notice after commands when one is available."`
 }
 
 type ContestConfig struct {
-	OutDir           string `yaml:"out_dir" mapstructure:"out_dir" comment:"Base directory to put generated contest questions."`
-	FilenameTemplate string `yaml:"filename_template" mapstructure:"filename_template" comment:"Template to generate filename of the question."`
-	OpenInBrowser    bool   `yaml:"open_in_browser" mapstructure:"open_in_browser" comment:"Open the contest page in browser after generating."`
+	OutDir           string   `yaml:"out_dir" mapstructure:"out_dir" comment:"Base directory to put generated contest questions.\nIt is a template too, e.g. contest/{{ .ContestShortSlug }} or contest/{{ .Lang }}"`
+	FilenameTemplate string   `yaml:"filename_template" mapstructure:"filename_template" comment:"Template to generate filename of the question."`
+	OpenInBrowser    bool     `yaml:"open_in_browser" mapstructure:"open_in_browser" comment:"Open the contest page in browser after generating."`
+	Friends          []string `yaml:"friends" mapstructure:"friends" comment:"Usernames or user slugs of friends to watch in 'leetgo contest rank'."`
+}
+
+type NotificationsConfig struct {
+	Desktop bool `yaml:"desktop" mapstructure:"desktop" comment:"Show a desktop notification: notify-send on Linux, osascript on macOS, a toast on Windows. Disabled in safe mode."`
+	Bell    bool `yaml:"bell" mapstructure:"bell" comment:"Ring the terminal bell."`
+}
+
+type SubmitConfig struct {
+	Checklist []string `yaml:"checklist" mapstructure:"checklist" comment:"Questions to confirm before submitting, e.g. 'Have you considered empty input?'\nAnswers are appended to the note of the question in notes_dir. Leave empty to disable."`
 }
 
 type Editor struct {
-	Use     string `yaml:"use" mapstructure:"use" comment:"Use a predefined editor: vim, vscode, goland\nSet to 'none' to disable, set to 'custom' to provide your own command and args."`
-	Command string `yaml:"command" mapstructure:"command" comment:"Custom command to open files."`
-	Args    string `yaml:"args" mapstructure:"args" comment:"Arguments to your custom command.\nString contains {{.CodeFile}}, {{.TestFile}}, {{.DescriptionFile}}, {{.TestCasesFile}} will be replaced with corresponding file path.\n{{.Folder}} will be substituted with the output directory.\n{{.Files}} will be substituted with the list of all file paths."`
+	Use          string   `yaml:"use" mapstructure:"use" comment:"Use a predefined editor: vim, vscode, goland\nSet to 'none' to disable, set to 'custom' to provide your own command and args.\nWhen run in the terminal of Vim or Neovim, files are opened in the running editor, already open files are focused."`
+	Command      string   `yaml:"command" mapstructure:"command" comment:"Custom command to open files."`
+	Args         string   `yaml:"args" mapstructure:"args" comment:"Arguments to your custom command.\nString contains {{.CodeFile}}, {{.TestFile}}, {{.DescriptionFile}}, {{.TestCasesFile}} will be replaced with corresponding file path.\n{{.Folder}} will be substituted with the output directory.\n{{.Files}} will be substituted with the list of files to open."`
+	OpenStrategy string   `yaml:"open_strategy" mapstructure:"open_strategy" comment:"How vim and neovim open multiple files: tabs, vsplit (vertical splits), split (horizontal splits), or none (as buffers, only the focused file is shown)."`
+	OpenFiles    []string `yaml:"open_files" mapstructure:"open_files" comment:"Types of files to open in order, the first one is focused: code, test, testcases, doc and other.\nE.g. [code] to open only the solution, [doc, code] to read the description first. Empty to open all files with the code file focused."`
 }
 
 type Block struct {
@@ -65,14 +108,17 @@ type Block struct {
 }
 
 type Modifier struct {
-	Name   string `yaml:"name" mapstructure:"name"`
-	Script string `yaml:"script,omitempty" mapstructure:"script"`
+	Name   string   `yaml:"name" mapstructure:"name"`
+	Args   []string `yaml:"args,omitempty" mapstructure:"args"`
+	Script string   `yaml:"script,omitempty" mapstructure:"script"`
 }
 
 type CodeConfig struct {
 	Lang                    string         `yaml:"lang" mapstructure:"lang" comment:"Language of code generated for questions: go, cpp, python, java... \n(will be overridden by command line flag -l/--lang)."`
-	FilenameTemplate        string         `yaml:"filename_template" mapstructure:"filename_template" comment:"The default template to generate filename (without extension), e.g. {{.Id}}.{{.Slug}}\nAvailable attributes: Id, Slug, Title, Difficulty, Lang, SlugIsMeaningful\n(Most questions have descriptive slugs, but some consist of random characters. The SlugIsMeaningful boolean indicates whether a slug is meaningful.)\nAvailable functions: lower, upper, trim, padWithZero, toUnderscore, group."`
+	FilenameTemplate        string         `yaml:"filename_template" mapstructure:"filename_template" comment:"The default template to generate filename (without extension), e.g. {{.Id}}.{{.Slug}}\nAvailable attributes: Id, Slug, Title, Difficulty, Lang, Site, FirstTag, SlugIsMeaningful\n(Most questions have descriptive slugs, but some consist of random characters. The SlugIsMeaningful boolean indicates whether a slug is meaningful.)\nId may differ between leetcode.com and leetcode.cn, so the default is keyed on Site and Slug.\nOlder versions defaulted to {{ .Id | padWithZero 4 }}{{ if .SlugIsMeaningful }}.{{ .Slug }}{{ end }}, set it to keep their filenames.\nAvailable functions: lower, upper, trim, padWithZero, toUnderscore, toCamel, substr, categorySlug, group.\nRun 'leetgo config check' to preview the generated filenames."`
+	OutDirTemplate          string         `yaml:"out_dir_template" mapstructure:"out_dir_template" comment:"Template of the directory to put generated questions of all languages, e.g. {{ .Lang }}/{{ .Difficulty | lower }}/{{ .FirstTag }}\nIt accepts the same attributes and functions as filename_template, plus FirstTag (slug of the first topic tag).\nOverrides out_dir of each language if set."`
 	SeparateDescriptionFile bool           `yaml:"separate_description_file" mapstructure:"separate_description_file" comment:"Generate question description into a separate question.md file, otherwise it will be embed in the code file."`
+	CommentLang             Language       `yaml:"comment_language" mapstructure:"comment_language" comment:"Language of the question description embedded in code comments, defaults to content_language.\nE.g. set it to 'en' to keep code files ASCII while reading Chinese statements in question.md."`
 	Blocks                  []Block        `yaml:"blocks,omitempty" mapstructure:"blocks" comment:"Default block definitions for all languages."`
 	Modifiers               []Modifier     `yaml:"modifiers,omitempty" mapstructure:"modifiers" comment:"Default modifiers for all languages."`
 	Go                      GoConfig       `yaml:"go" mapstructure:"go"`
@@ -80,19 +126,30 @@ type CodeConfig struct {
 	Cpp                     CppConfig      `yaml:"cpp" mapstructure:"cpp"`
 	Rust                    RustConfig     `yaml:"rust" mapstructure:"rust"`
 	Java                    BaseLangConfig `yaml:"java" mapstructure:"java"`
+	Kotlin                  BaseLangConfig `yaml:"kotlin" mapstructure:"kotlin"`
+	Swift                   BaseLangConfig `yaml:"swift" mapstructure:"swift"`
+	CSharp                  BaseLangConfig `yaml:"csharp" mapstructure:"csharp"`
 	// Add more languages here
 }
 
 type BaseLangConfig struct {
-	OutDir                  string     `yaml:"out_dir" mapstructure:"out_dir" comment:"Base directory to put generated questions, defaults to the language slug, e.g. go, python, cpp."`
+	OutDir                  string     `yaml:"out_dir" mapstructure:"out_dir" comment:"Base directory to put generated questions, defaults to the language slug, e.g. go, python, cpp.\nIt is a template accepting the same attributes and functions as filename_template, e.g. go/{{ .Difficulty | lower }}\nRelative paths are resolved against the project root, absolute paths and ~ are allowed."`
 	FilenameTemplate        string     `yaml:"filename_template,omitempty" mapstructure:"filename_template" comment:"Overrides the default code.filename_template, empty will be ignored."`
 	SeparateDescriptionFile bool       `yaml:"separate_description_file,omitempty" mapstructure:"separate_description_file" comment:"Generate question description into a separate question.md file, otherwise it will be embed in the code file."`
 	Blocks                  []Block    `yaml:"blocks,omitempty" mapstructure:"blocks" comment:"Replace some blocks of the generated code."`
-	Modifiers               []Modifier `yaml:"modifiers,omitempty" mapstructure:"modifiers" comment:"Functions that modify the generated code."`
+	Modifiers               []Modifier `yaml:"modifiers,omitempty" mapstructure:"modifiers" comment:"Functions that modify the generated code.\nBuiltin: removeUselessComments, removeComments, renameClass (args: [NewName]), addImports (args: packages), replace (args: [pattern, replacement]).\nOr a script defining a JavaScript function modify(code)."`
 }
 
+// Layouts of generated Go code.
+const (
+	GoLayoutFlat               = "flat"
+	GoLayoutPackagePerQuestion = "package-per-question"
+	GoLayoutModulePerQuestion  = "module-per-question"
+)
+
 type GoConfig struct {
 	BaseLangConfig `yaml:",inline" mapstructure:",squash"`
+	Layout         string `yaml:"layout" mapstructure:"layout" comment:"How to lay out generated Go code: flat, package-per-question or module-per-question.\n'flat' puts all solutions in out_dir as standalone files, 'package-per-question' puts each solution in its own directory,\n'module-per-question' additionally gives each solution its own go.mod, tied together by a go.work in out_dir."`
 }
 
 type PythonConfig struct {
@@ -111,7 +168,7 @@ type RustConfig struct {
 }
 
 type Credentials struct {
-	From     string   `yaml:"from" mapstructure:"from" comment:"How to provide credentials: browser, cookies, password or none."`
+	From     string   `yaml:"from" mapstructure:"from" comment:"How to provide credentials: browser, cookies, password, keyring or none.\n'keyring' reads cookies saved by 'leetgo credentials save' from the OS keychain."`
 	Browsers []string `yaml:"browsers" mapstructure:"browsers" comment:"Browsers to get cookies from: chrome, safari, edge or firefox. If empty, all browsers will be tried. Only used when 'from' is 'browser'."`
 }
 
@@ -120,24 +177,115 @@ type LeetCodeConfig struct {
 	Credentials Credentials  `yaml:"credentials" mapstructure:"credentials" comment:"Credentials to access LeetCode."`
 }
 
+// HomeDir returns the directory of the global config file.
+// It's LEETGO_CONFIG_DIR, LEETGO_HOME or $XDG_CONFIG_HOME/leetgo, defaults to ~/.config/leetgo.
 func (c *Config) HomeDir() string {
 	if c.dir != "" {
 		return c.dir
 	}
-	if home := os.Getenv("LEETGO_HOME"); home != "" {
-		c.dir, _ = filepath.Abs(home)
+	c.dir = xdgDir("LEETGO_CONFIG_DIR", "XDG_CONFIG_HOME", ".config")
+	return c.dir
+}
+
+// xdgDir returns the directory overridden by the env, or leetgo directory under the XDG base directory.
+// LEETGO_HOME puts everything under it, as older versions did.
+func xdgDir(env string, xdgEnv string, fallback string) string {
+	dir := os.Getenv(env)
+	if dir == "" {
+		dir = os.Getenv("LEETGO_HOME")
+	}
+	if dir == "" {
+		if base := os.Getenv(xdgEnv); filepath.IsAbs(base) {
+			dir = filepath.Join(base, constants.CmdName)
+		}
 	}
-	if c.dir == "" {
+	if dir == "" {
 		home, _ := homedir.Dir()
-		c.dir = filepath.Join(home, ".config", constants.CmdName)
+		dir = filepath.Join(home, fallback, constants.CmdName)
 	}
-	return c.dir
+	dir, _ = filepath.Abs(dir)
+	return dir
 }
 
-func (c *Config) CacheDir() string {
+// PluginsDir returns the directory of language plugins, executables named leetgo-lang-<name>.
+func (c *Config) PluginsDir() string {
+	return filepath.Join(c.HomeDir(), "plugins")
+}
+
+// LegacyCacheDir returns the cache directory used before XDG base directories were supported.
+func (c *Config) LegacyCacheDir() string {
 	return filepath.Join(c.HomeDir(), "cache")
 }
 
+// CacheDir returns the directory of question cache and dependency versions.
+// It's cache_dir (or LEETGO_CACHE_DIR), or $XDG_CACHE_HOME/leetgo, defaults to ~/.cache/leetgo.
+// With LEETGO_HOME set, or the legacy cache directory not migrated yet and LEETGO_CACHE_DIR not set,
+// it's the cache directory under the home dir.
+func (c *Config) CacheDir() string {
+	if c.CachePath != "" {
+		dir, err := homedir.Expand(c.CachePath)
+		if err != nil {
+			dir = c.CachePath
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(c.ProjectRoot(), dir)
+		}
+		return dir
+	}
+	if os.Getenv("LEETGO_HOME") != "" ||
+		(os.Getenv("LEETGO_CACHE_DIR") == "" && utils.IsExist(c.LegacyCacheDir())) {
+		return c.LegacyCacheDir()
+	}
+	return c.xdgCacheDir()
+}
+
+func (c *Config) xdgCacheDir() string {
+	return xdgDir("LEETGO_CACHE_DIR", "XDG_CACHE_HOME", ".cache")
+}
+
+// StateDir returns the directory of the state file.
+// It's LEETGO_STATE_DIR or $XDG_STATE_HOME/leetgo, defaults to ~/.local/state/leetgo.
+// With LEETGO_HOME set it's the cache directory, with the legacy state file not migrated yet it's the directory of it.
+func (c *Config) StateDir() string {
+	if os.Getenv("LEETGO_STATE_DIR") != "" {
+		return c.xdgStateDir()
+	}
+	if os.Getenv("LEETGO_HOME") != "" {
+		return c.CacheDir()
+	}
+	if dir := c.legacyStateDir(); dir != "" {
+		return dir
+	}
+	return c.xdgStateDir()
+}
+
+// legacyStateDir returns the cache directory holding the state file, as older versions did, or "" if there is none.
+func (c *Config) legacyStateDir() string {
+	for _, dir := range []string{c.CacheDir(), c.LegacyCacheDir()} {
+		if utils.IsExist(filepath.Join(dir, constants.StateFilename)) {
+			return dir
+		}
+	}
+	return ""
+}
+
+func (c *Config) xdgStateDir() string {
+	return xdgDir("LEETGO_STATE_DIR", "XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CheckCacheDir reports if the cache directory is not writable, suggesting a writable location instead.
+func (c *Config) CheckCacheDir() error {
+	err := utils.CheckWritable(c.CacheDir())
+	if err == nil || !utils.IsPermissionError(err) {
+		return err
+	}
+	hint := "set cache_dir in " + constants.ConfigFilename + " to a writable directory"
+	if dir, uerr := os.UserCacheDir(); uerr == nil {
+		hint += ", e.g. " + filepath.Join(dir, constants.CmdName)
+	}
+	return fmt.Errorf("cache directory %s is not writable: %w, %s", c.CacheDir(), err, hint)
+}
+
 func (c *Config) TempDir() string {
 	return filepath.Join(os.TempDir(), constants.CmdName)
 }
@@ -166,16 +314,198 @@ func (c *Config) ConfigFile() string {
 	return filepath.Join(c.ProjectRoot(), constants.ConfigFilename)
 }
 
+// GlobalConfigFile returns the config file shared by all projects, overridden by the project config file.
+func (c *Config) GlobalConfigFile() string {
+	return filepath.Join(c.HomeDir(), constants.GlobalConfigFilename)
+}
+
+// SessionFile returns the file describing the last generated question, for editor plugins to watch.
+func (c *Config) SessionFile() string {
+	return filepath.Join(c.ProjectRoot(), filepath.FromSlash(constants.SessionFilename))
+}
+
+// NotesDir returns the directory of notes of questions.
+func (c *Config) NotesDir() string {
+	dir, err := homedir.Expand(c.NotesPath)
+	if err != nil {
+		dir = c.NotesPath
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(c.ProjectRoot(), dir)
+	}
+	return dir
+}
+
+// SiteNotesDir returns the directory of the notes of questions on the configured site,
+// the same slug has different notes on leetcode.com and leetcode.cn.
+func (c *Config) SiteNotesDir() string {
+	return filepath.Join(c.NotesDir(), c.LeetCode.Site.Short())
+}
+
+// NoteFile returns the note file of the question on the configured site.
+func (c *Config) NoteFile(slug string) string {
+	return filepath.Join(c.SiteNotesDir(), slug+".md")
+}
+
 func (c *Config) StateFile() string {
-	return filepath.Join(c.CacheDir(), constants.StateFilename)
+	return filepath.Join(c.StateDir(), constants.StateFilename)
+}
+
+// IndexFile returns the workspace index file, see Index.
+func (c *Config) IndexFile() string {
+	return filepath.Join(c.StateDir(), constants.IndexFilename)
+}
+
+// JobsFile returns the file recording the progress of bulk operations, to resume them after interruptions.
+func (c *Config) JobsFile() string {
+	return filepath.Join(c.StateDir(), constants.JobsFilename)
+}
+
+// CredentialsFile returns the encrypted file to save cookies to when the OS keychain is not available.
+func (c *Config) CredentialsFile() string {
+	return filepath.Join(c.StateDir(), constants.CredentialsFilename)
 }
 
 func (c *Config) DepVersionFile() string {
 	return filepath.Join(c.CacheDir(), constants.DepVersionFilename)
 }
 
+// SnapshotFile returns the snapshot file of the configured site, frontend ids differ between sites.
+func (c *Config) SnapshotFile() string {
+	return filepath.Join(c.CacheDir(), c.LeetCode.Site.Short()+"-"+constants.SnapshotFilename)
+}
+
+// LogFile returns the file to write debug logs to when log.file is enabled.
+func (c *Config) LogFile() string {
+	return filepath.Join(c.StateDir(), constants.LogFilename)
+}
+
+// ReleaseCheckFile returns the file recording the latest release found by the daily check for new versions.
+func (c *Config) ReleaseCheckFile() string {
+	return filepath.Join(c.CacheDir(), constants.ReleaseCheckFilename)
+}
+
+// PluginsCacheFile returns the file caching the info of language plugins, so that they are not run by every command.
+func (c *Config) PluginsCacheFile() string {
+	return filepath.Join(c.CacheDir(), constants.PluginsCacheFilename)
+}
+
+func (c *Config) RatingsFile() string {
+	return filepath.Join(c.CacheDir(), constants.RatingsFilename)
+}
+
+// QuestionCacheFile returns the question cache file of the configured site, frontend ids differ between sites.
 func (c *Config) QuestionCacheFile(ext string) string {
-	return filepath.Join(c.CacheDir(), constants.QuestionCacheBaseName+ext)
+	return filepath.Join(c.CacheDir(), constants.QuestionCacheBaseName+"-"+c.LeetCode.Site.Short()+ext)
+}
+
+// ErrSafeMode is returned by operations that would spawn processes or prompt in safe mode.
+var ErrSafeMode = errors.New("not allowed in safe mode")
+
+// SafeMode reports whether leetgo should never spawn external processes or prompt,
+// enabled by the --safe flag or the LEETGO_SAFE_MODE environment variable.
+func SafeMode() bool {
+	return viper.GetBool("safe") || os.Getenv("LEETGO_SAFE_MODE") != ""
+}
+
+var offlineDetected atomic.Bool
+
+// Offline reports whether leetgo works from the local cache without network, enabled by the --offline flag,
+// the LEETGO_OFFLINE environment variable, or after a request failed because the network is unavailable.
+func Offline() bool {
+	return offlineDetected.Load() || viper.GetBool("offline") || os.Getenv("LEETGO_OFFLINE") != ""
+}
+
+// SetOffline switches to offline mode for the rest of the run.
+func SetOffline() {
+	offlineDetected.Store(true)
+}
+
+// JSONOutput reports whether commands should print structured JSON on stdout, enabled by the --json flag.
+func JSONOutput() bool {
+	return viper.GetBool("json")
+}
+
+// HumanOutput returns the writer for human-readable output.
+// It is stderr in JSON mode, so that stdout only carries JSON.
+func HumanOutput() io.Writer {
+	if JSONOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// promptOutput returns where prompts are written, stderr in JSON mode like HumanOutput.
+func promptOutput() *os.File {
+	if JSONOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// AskOne asks a single question on the terminal, see survey.AskOne.
+// Prompts are written to stderr in JSON mode, so that they don't corrupt the JSON on stdout.
+func AskOne(p survey.Prompt, response any, opts ...survey.AskOpt) error {
+	opts = append(opts, survey.WithStdio(os.Stdin, promptOutput(), os.Stderr))
+	return survey.AskOne(p, response, opts...)
+}
+
+// Ask asks several questions on the terminal like AskOne, see survey.Ask.
+func Ask(qs []*survey.Question, response any, opts ...survey.AskOpt) error {
+	opts = append(opts, survey.WithStdio(os.Stdin, promptOutput(), os.Stderr))
+	return survey.Ask(qs, response, opts...)
+}
+
+// UsePlainOutput reports whether output should be rendered as plain labeled lines.
+func (c *Config) UsePlainOutput() bool {
+	if viper.GetBool("plain") {
+		return true
+	}
+	switch c.PlainOutput {
+	case "always":
+		return true
+	case "never":
+		return false
+	default:
+		return os.Getenv("TERM") == "dumb"
+	}
+}
+
+// detectLanguage detects the language from the locale environment variables, English if not Chinese.
+func detectLanguage() Language {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			if strings.HasPrefix(strings.ToLower(locale), "zh") {
+				return ZH
+			}
+			return EN
+		}
+	}
+	return EN
+}
+
+// MessageLanguage returns the language of leetgo messages, detected from the locale if not configured.
+func (c *Config) MessageLanguage() Language {
+	if c.Language == "" {
+		return detectLanguage()
+	}
+	return c.Language
+}
+
+// ContentLanguage returns the language of question descriptions, it follows the message language by default.
+func (c *Config) ContentLanguage() Language {
+	if c.ContentLang != "" {
+		return c.ContentLang
+	}
+	return c.MessageLanguage()
+}
+
+// CommentLanguage returns the language of question descriptions embedded in code comments.
+func (c *Config) CommentLanguage() Language {
+	if c.Code.CommentLang != "" {
+		return c.Code.CommentLang
+	}
+	return c.ContentLanguage()
 }
 
 func (c *Config) Write(w io.Writer, withComments bool) error {
@@ -194,11 +524,10 @@ func (c *Config) Write(w io.Writer, withComments bool) error {
 
 func defaultConfig() *Config {
 	return &Config{
-		Author:   "Bob",
-		Language: ZH,
+		Author: "Bob",
 		Code: CodeConfig{
 			Lang:                    "go",
-			FilenameTemplate:        `{{ .Id | padWithZero 4 }}{{ if .SlugIsMeaningful }}.{{ .Slug }}{{ end }}`,
+			FilenameTemplate:        `{{ .Site }}.{{ .Slug }}`,
 			SeparateDescriptionFile: true,
 			Modifiers: []Modifier{
 				{Name: "removeUselessComments"},
@@ -213,6 +542,7 @@ func defaultConfig() *Config {
 						{Name: "addMod"},
 					},
 				},
+				Layout: GoLayoutPackagePerQuestion,
 			},
 			Cpp: CppConfig{
 				BaseLangConfig: BaseLangConfig{OutDir: "cpp"},
@@ -223,8 +553,11 @@ func defaultConfig() *Config {
 				BaseLangConfig: BaseLangConfig{OutDir: "python"},
 				Executable:     constants.DefaultPython,
 			},
-			Java: BaseLangConfig{OutDir: "java"},
-			Rust: RustConfig{BaseLangConfig: BaseLangConfig{OutDir: "rust"}},
+			Java:   BaseLangConfig{OutDir: "java"},
+			Kotlin: BaseLangConfig{OutDir: "kotlin"},
+			Swift:  BaseLangConfig{OutDir: "swift"},
+			CSharp: BaseLangConfig{OutDir: "csharp"},
+			Rust:   RustConfig{BaseLangConfig: BaseLangConfig{OutDir: "rust"}},
 			// Add more languages here
 		},
 		LeetCode: LeetCodeConfig{
@@ -234,7 +567,14 @@ func defaultConfig() *Config {
 			},
 		},
 		Editor: Editor{
-			Use: "none",
+			Use:          "none",
+			OpenStrategy: "tabs",
+		},
+		PlainOutput: "auto",
+		NotesPath:   "notes",
+		Upgrade: UpgradeConfig{
+			Channel: "stable",
+			Check:   true,
 		},
 		Contest: ContestConfig{
 			OutDir:           "contest",
@@ -256,12 +596,23 @@ var credentialFrom = map[string]bool{
 	"browser":  true,
 	"cookies":  true,
 	"password": true,
+	"keyring":  true,
 	"none":     true,
 }
 
 func verify(c *Config) error {
-	if c.Language != ZH && c.Language != EN {
-		return fmt.Errorf("invalid `language` value: %s, only `en` or `zh` is supported", c.Language)
+	languages := []struct {
+		key   string
+		value Language
+	}{
+		{"language", c.Language},
+		{"content_language", c.ContentLang},
+		{"code.comment_language", c.Code.CommentLang},
+	}
+	for _, l := range languages {
+		if l.value != "" && l.value != ZH && l.value != EN {
+			return fmt.Errorf("invalid `%s` value: %s, only `en` or `zh` is supported", l.key, l.value)
+		}
 	}
 	if c.Code.Lang == "" {
 		return fmt.Errorf("`code.lang` not set, please set it in config file or provide it with `--lang/-l` flag")
@@ -283,6 +634,43 @@ func verify(c *Config) error {
 		return errors.New("username/password authentication is not supported for leetcode.com")
 	}
 
+	switch c.PlainOutput {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid `plain_output` value: %s, only auto, always or never is supported", c.PlainOutput)
+	}
+
+	switch c.Upgrade.Channel {
+	case "stable", "nightly":
+	default:
+		return fmt.Errorf("invalid `upgrade.channel` value: %s, only stable or nightly is supported", c.Upgrade.Channel)
+	}
+
+	switch c.Code.Go.Layout {
+	case GoLayoutFlat, GoLayoutPackagePerQuestion, GoLayoutModulePerQuestion:
+	default:
+		return fmt.Errorf(
+			"invalid `code.go.layout` value: %s, only %s, %s or %s is supported",
+			c.Code.Go.Layout,
+			GoLayoutFlat,
+			GoLayoutPackagePerQuestion,
+			GoLayoutModulePerQuestion,
+		)
+	}
+
+	switch c.Editor.OpenStrategy {
+	case "tabs", "vsplit", "split", "none":
+	default:
+		return fmt.Errorf("invalid `editor.open_strategy` value: %s, only tabs, vsplit, split or none is supported", c.Editor.OpenStrategy)
+	}
+	for _, t := range c.Editor.OpenFiles {
+		switch t {
+		case "code", "test", "testcases", "doc", "other":
+		default:
+			return fmt.Errorf("invalid `editor.open_files` value: %s, only code, test, testcases, doc or other is supported", t)
+		}
+	}
+
 	if c.Editor.Args != "" {
 		if _, err := shlex.Split(c.Editor.Args); err != nil {
 			return fmt.Errorf("invalid `editor.args`: %w", err)
@@ -314,10 +702,21 @@ func Load(init bool) error {
 		return fmt.Errorf("read default config failed: %w", err)
 	}
 
+	for _, k := range viper.AllKeys() {
+		configKeys[k] = true
+	}
+
+	// load global configuration, shared by all projects
+	if utils.IsExist(cfg.GlobalConfigFile()) {
+		err = mergeConfigFile(cfg.GlobalConfigFile(), OriginGlobal)
+		if err != nil {
+			return fmt.Errorf("load global config file %s failed: %w", cfg.GlobalConfigFile(), err)
+		}
+	}
+
 	// load project configuration
 	if !init {
-		viper.SetConfigFile(cfg.ConfigFile())
-		err = viper.MergeInConfig()
+		err = mergeConfigFile(cfg.ConfigFile(), OriginProject)
 		if err != nil {
 			if os.IsNotExist(err) {
 				return fmt.Errorf("%s not found, run `leetgo init` first", constants.ConfigFilename)
@@ -326,6 +725,11 @@ func Load(init bool) error {
 		}
 	}
 
+	// environment variables override config files, e.g. LEETGO_CODE_LANG overrides code.lang
+	viper.SetEnvPrefix(constants.CmdName)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
 	err = viper.Unmarshal(cfg)
 	if err != nil {
 		return fmt.Errorf("unmarshal config failed: %s", err)
@@ -338,3 +742,162 @@ func Load(init bool) error {
 	globalCfg = cfg
 	return nil
 }
+
+// Origins of configuration values, in the order of precedence from low to high.
+// Command line flags take precedence over all of them.
+const (
+	OriginDefault = "default"
+	OriginGlobal  = "global"
+	OriginProject = "project"
+	OriginEnv     = "env"
+)
+
+var (
+	// configKeys are all the configuration keys, including ones only present in config files.
+	configKeys = make(map[string]bool)
+	// fileOrigins maps keys set in config files to the origin of the last file setting them.
+	fileOrigins = make(map[string]string)
+)
+
+func mergeConfigFile(file string, origin string) error {
+	v := viper.New()
+	v.SetConfigFile(file)
+	err := v.ReadInConfig()
+	if err != nil {
+		return err
+	}
+	err = viper.MergeConfigMap(v.AllSettings())
+	if err != nil {
+		return err
+	}
+	for _, k := range v.AllKeys() {
+		configKeys[k] = true
+		fileOrigins[k] = origin
+	}
+	return nil
+}
+
+// EnvName returns the environment variable that overrides the configuration key.
+func EnvName(key string) string {
+	return strings.ToUpper(constants.CmdName + "_" + strings.ReplaceAll(key, ".", "_"))
+}
+
+// Keys returns all the configuration keys in sorted order.
+func Keys() []string {
+	keys := make([]string, 0, len(configKeys))
+	for k := range configKeys {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Origin returns where the effective value of the configuration key comes from, without considering flags.
+func Origin(key string) string {
+	if _, ok := os.LookupEnv(EnvName(key)); ok {
+		return OriginEnv
+	}
+	if origin, ok := fileOrigins[key]; ok {
+		return origin
+	}
+	return OriginDefault
+}
+
+// Move is a file or directory moved by MigrateDirs.
+type Move struct {
+	From string
+	To   string
+}
+
+// MigrateDirs moves the state file and the legacy cache directory under the home dir to the XDG base directories.
+// Nothing is moved when LEETGO_HOME is set, which keeps everything under it.
+// If the new cache directory exists already, which may be shared with the state directory, the legacy cache is merged
+// into it, files in it are kept.
+func (c *Config) MigrateDirs(dryRun bool) ([]Move, error) {
+	if os.Getenv("LEETGO_HOME") != "" {
+		return nil, nil
+	}
+	var moves []Move
+	if dir := c.legacyStateDir(); dir != "" {
+		m := Move{filepath.Join(dir, constants.StateFilename), filepath.Join(c.xdgStateDir(), constants.StateFilename)}
+		if m.From != m.To {
+			moves = append(moves, m)
+		}
+	}
+	legacyCache := c.LegacyCacheDir()
+	if c.CachePath == "" && utils.IsExist(legacyCache) && legacyCache != c.xdgCacheDir() && legacyCache != c.xdgStateDir() {
+		moves = append(moves, Move{legacyCache, c.xdgCacheDir()})
+	}
+	if dryRun {
+		return moves, nil
+	}
+
+	for i, m := range moves {
+		var err error
+		switch {
+		case m.To == c.xdgCacheDir() && utils.IsExist(m.To):
+			err = mergeDir(m.From, m.To)
+		case utils.IsExist(m.To):
+			err = fmt.Errorf("%s already exists", m.To)
+		default:
+			err = utils.MakeDir(filepath.Dir(m.To))
+			if err == nil {
+				err = os.Rename(m.From, m.To)
+			}
+		}
+		if err != nil {
+			return moves[:i], fmt.Errorf("move %s to %s failed: %w", m.From, m.To, err)
+		}
+	}
+	return moves, nil
+}
+
+// mergeDir moves the entries of from into the existing directory to, then removes from.
+// Entries existing in to are kept, the ones in from are dropped.
+func mergeDir(from string, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		dst := filepath.Join(to, e.Name())
+		if utils.IsExist(dst) {
+			log.Debug("keep existing file", "file", dst)
+			continue
+		}
+		if err := os.Rename(filepath.Join(from, e.Name()), dst); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(from)
+}
+
+// MigrateSiteFiles renames the cache files and notes shared by both sites in older versions to the per-site names.
+// They are assumed to belong to the configured site, files of the new names are kept.
+func (c *Config) MigrateSiteFiles(dryRun bool) ([]Move, error) {
+	moves := []Move{{filepath.Join(c.CacheDir(), constants.SnapshotFilename), c.SnapshotFile()}}
+	for _, ext := range []string{".json", ".db"} {
+		moves = append(moves, Move{filepath.Join(c.CacheDir(), constants.QuestionCacheBaseName+ext), c.QuestionCacheFile(ext)})
+	}
+	notes, _ := filepath.Glob(filepath.Join(c.NotesDir(), "*.md"))
+	for _, note := range notes {
+		moves = append(moves, Move{note, c.NoteFile(strings.TrimSuffix(filepath.Base(note), ".md"))})
+	}
+	moves = slices.DeleteFunc(
+		moves, func(m Move) bool {
+			return !utils.IsExist(m.From) || utils.IsExist(m.To)
+		},
+	)
+	if dryRun {
+		return moves, nil
+	}
+	for i, m := range moves {
+		if err := utils.CreateIfNotExists(filepath.Dir(m.To), true); err != nil {
+			return moves[:i], err
+		}
+		if err := os.Rename(m.From, m.To); err != nil {
+			return moves[:i], fmt.Errorf("move %s to %s failed: %w", m.From, m.To, err)
+		}
+	}
+	return moves, nil
+}
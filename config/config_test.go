@@ -0,0 +1,251 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/constants"
+)
+
+// setDirs points the home, cache and state directories into a temp dir.
+func setDirs(t *testing.T, cacheDir string, stateDir string) (home string) {
+	home = t.TempDir()
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", home)
+	t.Setenv("LEETGO_CACHE_DIR", cacheDir)
+	t.Setenv("LEETGO_STATE_DIR", stateDir)
+	return home
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCacheDirEnvOverridesLegacy(t *testing.T) {
+	shared := filepath.Join(t.TempDir(), "shared")
+	home := setDirs(t, shared, "")
+	c := &Config{}
+	writeFile(t, filepath.Join(home, "cache", "leetcode-questions.json"), "[]")
+
+	if got := c.CacheDir(); got != shared {
+		t.Errorf("CacheDir() = %q, want %q", got, shared)
+	}
+	// The state file not migrated yet is still found in the legacy cache directory.
+	writeFile(t, filepath.Join(home, "cache", constants.StateFilename), "{}")
+	if got := c.StateDir(); got != c.LegacyCacheDir() {
+		t.Errorf("StateDir() = %q, want %q", got, c.LegacyCacheDir())
+	}
+}
+
+func TestMigrateDirsSharedDir(t *testing.T) {
+	shared := filepath.Join(t.TempDir(), "shared")
+	home := setDirs(t, shared, shared)
+	c := &Config{}
+	legacy := filepath.Join(home, "cache")
+	writeFile(t, filepath.Join(legacy, constants.StateFilename), "state")
+	writeFile(t, filepath.Join(legacy, "old.json"), "old")
+	writeFile(t, filepath.Join(legacy, "both.json"), "legacy")
+	writeFile(t, filepath.Join(shared, "both.json"), "new")
+
+	moves, err := c.MigrateDirs(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 2 {
+		t.Errorf("MigrateDirs() = %v, want the state file and the cache moved", moves)
+	}
+	if got := readFile(t, filepath.Join(shared, constants.StateFilename)); got != "state" {
+		t.Errorf("state file = %q, want it moved", got)
+	}
+	if got := readFile(t, filepath.Join(shared, "old.json")); got != "old" {
+		t.Errorf("old.json = %q, want it merged", got)
+	}
+	if got := readFile(t, filepath.Join(shared, "both.json")); got != "new" {
+		t.Errorf("both.json = %q, want the existing one kept", got)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy cache directory should be removed: %v", err)
+	}
+	if got := c.StateDir(); got != shared {
+		t.Errorf("StateDir() = %q, want %q", got, shared)
+	}
+}
+
+func TestMigrateDirsStateInLegacyCache(t *testing.T) {
+	home := setDirs(t, filepath.Join(t.TempDir(), "cache"), "")
+	legacy := filepath.Join(home, "cache")
+	t.Setenv("LEETGO_STATE_DIR", legacy)
+	c := &Config{}
+	writeFile(t, filepath.Join(legacy, constants.StateFilename), "state")
+
+	moves, err := c.MigrateDirs(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 0 {
+		t.Errorf("MigrateDirs() = %v, want nothing moved out of the state directory", moves)
+	}
+	if got := readFile(t, filepath.Join(legacy, constants.StateFilename)); got != "state" {
+		t.Errorf("state file = %q", got)
+	}
+}
+
+func TestDefaultMessageLanguage(t *testing.T) {
+	cases := []struct {
+		lang string
+		want Language
+	}{
+		{"zh_CN.UTF-8", ZH},
+		{"en_US.UTF-8", EN},
+		{"", EN},
+	}
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	for _, c := range cases {
+		t.Setenv("LANG", c.lang)
+		if got := defaultConfig().MessageLanguage(); got != c.want {
+			t.Errorf("MessageLanguage() with LANG=%q = %s, want %s", c.lang, got, c.want)
+		}
+	}
+}
+
+func TestMigrateSiteFiles(t *testing.T) {
+	cache := t.TempDir()
+	setDirs(t, cache, "")
+	notes := t.TempDir()
+	c := &Config{LeetCode: LeetCodeConfig{Site: LeetCodeUS}, NotesPath: notes}
+	writeFile(t, filepath.Join(cache, constants.SnapshotFilename), "snapshot")
+	writeFile(t, filepath.Join(cache, constants.QuestionCacheBaseName+".json"), "old")
+	writeFile(t, c.QuestionCacheFile(".json"), "new")
+	writeFile(t, filepath.Join(notes, "two-sum.md"), "note")
+	writeFile(t, filepath.Join(notes, "add-two-numbers.md"), "old note")
+	writeFile(t, c.NoteFile("add-two-numbers"), "new note")
+
+	moves, err := c.MigrateSiteFiles(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Move{
+		{filepath.Join(cache, constants.SnapshotFilename), c.SnapshotFile()},
+		{filepath.Join(notes, "two-sum.md"), c.NoteFile("two-sum")},
+	}
+	if !slices.Equal(moves, want) {
+		t.Fatalf("MigrateSiteFiles() = %v, want %v", moves, want)
+	}
+	if _, err = os.Stat(c.SnapshotFile()); !os.IsNotExist(err) {
+		t.Errorf("dry run should not move files: %v", err)
+	}
+
+	if _, err = c.MigrateSiteFiles(false); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, c.SnapshotFile()); got != "snapshot" {
+		t.Errorf("snapshot file = %q, want it moved", got)
+	}
+	if got := readFile(t, c.QuestionCacheFile(".json")); got != "new" {
+		t.Errorf("question cache file = %q, want the existing one kept", got)
+	}
+	if got := readFile(t, filepath.Join(notes, "us", "two-sum.md")); got != "note" {
+		t.Errorf("note = %q, want it moved", got)
+	}
+	if got := readFile(t, c.NoteFile("add-two-numbers")); got != "new note" {
+		t.Errorf("note = %q, want the existing one kept", got)
+	}
+}
+
+func TestNoteFile(t *testing.T) {
+	notes := t.TempDir()
+	us := &Config{LeetCode: LeetCodeConfig{Site: LeetCodeUS}, NotesPath: notes}
+	cn := &Config{LeetCode: LeetCodeConfig{Site: LeetCodeCN}, NotesPath: notes}
+	if got, want := us.NoteFile("two-sum"), filepath.Join(notes, "us", "two-sum.md"); got != want {
+		t.Errorf("NoteFile() = %q, want %q", got, want)
+	}
+	if us.NoteFile("two-sum") == cn.NoteFile("two-sum") {
+		t.Errorf("notes of both sites should not share a file: %q", us.NoteFile("two-sum"))
+	}
+
+	root := t.TempDir()
+	relative := &Config{LeetCode: LeetCodeConfig{Site: LeetCodeCN}, NotesPath: "notes", projectRoot: root}
+	if got, want := relative.NoteFile("two-sum"), filepath.Join(root, "notes", "cn", "two-sum.md"); got != want {
+		t.Errorf("NoteFile() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadHasNoSideEffects(t *testing.T) {
+	cache := t.TempDir()
+	setDirs(t, cache, "")
+	snapshot := filepath.Join(cache, constants.SnapshotFilename)
+	writeFile(t, snapshot, "snapshot")
+	t.Cleanup(func() { globalCfg = nil })
+
+	if err := Load(true); err != nil {
+		t.Fatal(err)
+	}
+	// Load also runs for shell completion, files are only migrated by 'leetgo config migrate'.
+	if got := readFile(t, snapshot); got != "snapshot" {
+		t.Errorf("snapshot file = %q", got)
+	}
+	if _, err := os.Stat(Get().SnapshotFile()); !os.IsNotExist(err) {
+		t.Errorf("Load() should not migrate files: %v", err)
+	}
+}
+
+func TestLoadDetectsLanguage(t *testing.T) {
+	home := setDirs(t, t.TempDir(), "")
+	t.Setenv("LC_ALL", "zh_CN.UTF-8")
+	t.Cleanup(func() { globalCfg = nil })
+	cases := []struct {
+		global string
+		want   Language
+	}{
+		// Configs without language follow the locale, as well as descriptions and code comments.
+		{"leetcode:\n  site: us\n", ZH},
+		{"language: \"\"\n", ZH},
+		{"language: en\n", EN},
+	}
+	for _, c := range cases {
+		writeFile(t, filepath.Join(home, constants.GlobalConfigFilename), c.global)
+		globalCfg = nil
+		if err := Load(true); err != nil {
+			t.Fatal(err)
+		}
+		cfg := Get()
+		if cfg.MessageLanguage() != c.want || cfg.ContentLanguage() != c.want || cfg.CommentLanguage() != c.want {
+			t.Errorf(
+				"config %q: languages %s, %s, %s, want %s", c.global,
+				cfg.MessageLanguage(), cfg.ContentLanguage(), cfg.CommentLanguage(), c.want,
+			)
+		}
+	}
+}
+
+func TestPromptOutput(t *testing.T) {
+	if promptOutput() != os.Stdout || HumanOutput() != os.Stdout {
+		t.Errorf("prompts and human output should go to stdout")
+	}
+	viper.Set("json", true)
+	t.Cleanup(func() { viper.Set("json", false) })
+	// Stdout only carries JSON.
+	if promptOutput() != os.Stderr || HumanOutput() != os.Stderr {
+		t.Errorf("prompts and human output should go to stderr in JSON mode")
+	}
+}
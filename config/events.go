@@ -0,0 +1,102 @@
+package config
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Types of events emitted for shell integrations.
+const (
+	EventFileCreated        = "file_created"
+	EventTestPassed         = "test_passed"
+	EventTestFailed         = "test_failed"
+	EventSubmissionAccepted = "submission_accepted"
+	EventSubmissionRejected = "submission_rejected"
+)
+
+// Event is written as a line of JSON to the events destination.
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Project    string    `json:"project"`
+	Site       string    `json:"site"`
+	Slug       string    `json:"slug"`
+	FrontendID string    `json:"frontend_id,omitempty"`
+	Lang       string    `json:"lang,omitempty"`
+	File       string    `json:"file,omitempty"`
+	// Where the test runs, local or remote.
+	Runner string `json:"runner,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// EventsFile returns the destination of events, empty if events are disabled.
+func (c *Config) EventsFile() string {
+	if c.Events == "" {
+		return ""
+	}
+	file, err := homedir.Expand(c.Events)
+	if err != nil {
+		file = c.Events
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(c.ProjectRoot(), file)
+	}
+	return file
+}
+
+// EmitEvent writes the event to the configured file, FIFO or unix socket.
+// Failures are only logged, events must never break the command.
+func EmitEvent(e Event) {
+	cfg := Get()
+	dest := cfg.EventsFile()
+	if dest == "" {
+		return
+	}
+	e.Time = time.Now()
+	e.Project = cfg.ProjectRoot()
+	e.Site = cfg.LeetCode.Site.Short()
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Debug("failed to encode event", "type", e.Type, "err", err)
+		return
+	}
+	data = append(data, '\n')
+	err = writeEvent(dest, data)
+	if err != nil {
+		log.Debug("failed to emit event", "type", e.Type, "dest", dest, "err", err)
+	}
+}
+
+func writeEvent(dest string, data []byte) error {
+	flag := os.O_WRONLY | os.O_APPEND | os.O_CREATE
+	if stat, err := os.Stat(dest); err == nil {
+		switch {
+		case stat.Mode()&os.ModeSocket != 0:
+			conn, err := net.DialTimeout("unix", dest, time.Second)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = conn.Close() }()
+			_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+			_, err = conn.Write(data)
+			return err
+		case stat.Mode()&os.ModeNamedPipe != 0:
+			// Opening a FIFO without readers fails instead of blocking, the event is dropped.
+			flag = os.O_WRONLY | syscall.O_NONBLOCK
+		}
+	}
+	f, err := os.OpenFile(dest, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = f.Write(data)
+	return err
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestEventsFile(t *testing.T) {
+	root := t.TempDir()
+	abs := filepath.Join(t.TempDir(), "events.jsonl")
+	cases := []struct {
+		events string
+		want   string
+	}{
+		{"", ""},
+		{abs, abs},
+		{"events.jsonl", filepath.Join(root, "events.jsonl")},
+	}
+	for _, c := range cases {
+		cfg := &Config{Events: c.events, projectRoot: root}
+		if got := cfg.EventsFile(); got != c.want {
+			t.Errorf("EventsFile() of %q = %q, want %q", c.events, got, c.want)
+		}
+	}
+}
+
+func TestWriteEventToFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	for _, line := range []string{"{\"type\":\"a\"}\n", "{\"type\":\"b\"}\n"} {
+		if err := writeEvent(file, []byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readFile(t, file); got != "{\"type\":\"a\"}\n{\"type\":\"b\"}\n" {
+		t.Errorf("events file = %q, want events appended", got)
+	}
+	if err := writeEvent(filepath.Join(file, "missing"), []byte("{}\n")); err == nil {
+		t.Errorf("writeEvent() into a file path should fail")
+	}
+}
+
+func TestEmitEvent(t *testing.T) {
+	root := t.TempDir()
+	globalCfg = &Config{Events: "events.jsonl", LeetCode: LeetCodeConfig{Site: LeetCodeCN}, projectRoot: root}
+	t.Cleanup(func() { globalCfg = nil })
+
+	EmitEvent(Event{Type: EventTestPassed, Slug: "two-sum", Runner: "local"})
+	var e Event
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(root, "events.jsonl"))), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != EventTestPassed || e.Slug != "two-sum" || e.Project != root || e.Site != "cn" || e.Time.IsZero() {
+		t.Errorf("event = %+v", e)
+	}
+
+	// Failures are only logged.
+	globalCfg.Events = filepath.Join(root, "events.jsonl", "missing")
+	EmitEvent(Event{Type: EventTestFailed})
+}
+
+func TestWriteEventToSocket(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, too short for t.TempDir() on some systems.
+	dir, err := os.MkdirTemp("", "leetgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	sock := filepath.Join(dir, "events.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("unix sockets not supported:", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+	if err := writeEvent(sock, []byte("{\"type\":\"test_passed\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; got != "{\"type\":\"test_passed\"}\n" {
+		t.Errorf("received %q", got)
+	}
+
+	// The socket of a stopped server is left behind, the event is dropped.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = l.Close()
+	if err := writeEvent(sock, []byte("{}\n")); err == nil {
+		t.Errorf("writeEvent() to a socket without a server should fail")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package config
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteEventToFIFO(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "events")
+	if err := syscall.Mkfifo(fifo, 0o644); err != nil {
+		t.Skip("FIFO not supported:", err)
+	}
+	// Without readers the event is dropped instead of blocking.
+	if err := writeEvent(fifo, []byte("{}\n")); err == nil {
+		t.Errorf("writeEvent() to a FIFO without readers should fail")
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/utils"
+)
+
+// IndexEntry is a value derived from a workspace file, valid while the file has the same modification time and size.
+type IndexEntry struct {
+	ModTime   time.Time `json:"mod_time"`
+	Size      int64     `json:"size"`
+	IndexedAt time.Time `json:"indexed_at"`
+	Value     string    `json:"value"`
+}
+
+// valid reports whether the entry is still valid for the file.
+// Files modified within racyStateWindow before they were indexed may be modified again without changing their
+// modification time and size, they are read again.
+func (e IndexEntry) valid(stat os.FileInfo) bool {
+	return e.ModTime.Equal(stat.ModTime()) && e.Size == stat.Size() && e.IndexedAt.Sub(e.ModTime) > racyStateWindow
+}
+
+// Index persists values derived from workspace files next to the state file, e.g. hashes of solutions,
+// so that commands over many questions don't read every file again.
+// Values are keyed by the path of the file, and recomputed once the file is modified.
+type Index struct {
+	all     map[string]map[string]IndexEntry
+	entries map[string]IndexEntry
+	changed bool
+}
+
+// LoadIndex loads the index of the current project.
+func LoadIndex() *Index {
+	idx := &Index{all: make(map[string]map[string]IndexEntry)}
+	data, err := os.ReadFile(Get().IndexFile())
+	if err == nil {
+		err = json.Unmarshal(data, &idx.all)
+		if err != nil {
+			log.Debug("failed to load index", "err", err)
+			idx.all = make(map[string]map[string]IndexEntry)
+		}
+	}
+	root := Get().ProjectRoot()
+	if idx.all[root] == nil {
+		idx.all[root] = make(map[string]IndexEntry)
+	}
+	idx.entries = idx.all[root]
+	return idx
+}
+
+// Value returns the value derived from the file by compute, which is only called if the file is not indexed
+// or modified since.
+func (idx *Index) Value(path string, compute func() (string, error)) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if _, ok := idx.entries[path]; ok {
+			delete(idx.entries, path)
+			idx.changed = true
+		}
+		return "", err
+	}
+	if e, ok := idx.entries[path]; ok && e.valid(stat) {
+		return e.Value, nil
+	}
+	value, err := compute()
+	if err != nil {
+		return "", err
+	}
+	idx.entries[path] = IndexEntry{ModTime: stat.ModTime(), Size: stat.Size(), IndexedAt: time.Now(), Value: value}
+	idx.changed = true
+	return value, nil
+}
+
+// Save writes the index if any value has changed.
+func (idx *Index) Save() {
+	if !idx.changed {
+		return
+	}
+	data, err := json.Marshal(idx.all)
+	if err != nil {
+		log.Debug("failed to encode index", "err", err)
+		return
+	}
+	err = utils.WriteFile(Get().IndexFile(), data)
+	if err != nil {
+		log.Debug("failed to save index", "err", err)
+		return
+	}
+	idx.changed = false
+}
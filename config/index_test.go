@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndexValue(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+	file := filepath.Join(t.TempDir(), "solution.go")
+	writeFile(t, file, "code")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	computed := 0
+	compute := func() (string, error) {
+		computed++
+		return readFile(t, file), nil
+	}
+	idx := LoadIndex()
+	for range 2 {
+		if v, err := idx.Value(file, compute); err != nil || v != "code" {
+			t.Fatalf("Value() = %q, %v", v, err)
+		}
+	}
+	idx.Save()
+	// Later commands read the persisted index instead of the file.
+	if v, _ := LoadIndex().Value(file, compute); v != "code" || computed != 1 {
+		t.Errorf("Value() = %q computed %d times, want it computed once", v, computed)
+	}
+
+	writeFile(t, file, "changed")
+	if v, _ := LoadIndex().Value(file, compute); v != "changed" || computed != 2 {
+		t.Errorf("Value() of a modified file = %q computed %d times, want it computed again", v, computed)
+	}
+}
+
+func TestIndexRacyFile(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+	file := filepath.Join(t.TempDir(), "solution.go")
+	writeFile(t, file, "code")
+
+	computed := 0
+	compute := func() (string, error) {
+		computed++
+		return "hash", nil
+	}
+	idx := LoadIndex()
+	_, _ = idx.Value(file, compute)
+	idx.Save()
+	// The file was modified right before it was indexed, it may be modified again unnoticed.
+	_, _ = LoadIndex().Value(file, compute)
+	if computed != 2 {
+		t.Errorf("racily indexed file computed %d times, want 2", computed)
+	}
+}
+
+func TestIndexMissingFile(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+	file := filepath.Join(t.TempDir(), "solution.go")
+	idx := LoadIndex()
+	_, err := idx.Value(file, func() (string, error) { return "", errors.New("should not be called") })
+	if !os.IsNotExist(err) {
+		t.Errorf("Value() of a missing file = %v, want not exist", err)
+	}
+	idx.Save()
+	if _, err = os.Stat(Get().IndexFile()); !os.IsNotExist(err) {
+		t.Errorf("unchanged index should not be written: %v", err)
+	}
+}
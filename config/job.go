@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/utils"
+)
+
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending"
+	JobDone    JobStatus = "done"
+	JobError   JobStatus = "error"
+	// JobSkipped items could not be processed for now, e.g. paid only questions without Premium, they're retried on resume.
+	JobSkipped JobStatus = "skipped"
+)
+
+type JobItem struct {
+	Status JobStatus `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// jobSaveInterval bounds how often the jobs file is rewritten while items are done.
+const jobSaveInterval = time.Second
+
+// Job records the progress of a bulk operation on items, e.g. slugs of questions to generate.
+// It's saved as items are done, at most once per jobSaveInterval, and when finished. So an interrupted operation
+// can be resumed with `--resume`, items done shortly before the interruption may be processed again.
+// The methods of a nil Job do nothing, for operations not tracked as jobs.
+type Job struct {
+	name      string
+	savedAt   time.Time
+	Items     []string           `json:"items"`
+	Status    map[string]JobItem `json:"status"`
+	StartedAt time.Time          `json:"started_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// jobs are unfinished jobs of all projects, keyed by project root and then the job name.
+type jobs map[string]map[string]*Job
+
+func loadJobs() jobs {
+	js := make(jobs)
+	data, err := os.ReadFile(Get().JobsFile())
+	if err != nil {
+		return js
+	}
+	err = json.Unmarshal(data, &js)
+	if err != nil {
+		log.Debug("failed to load jobs", "err", err)
+		return make(jobs)
+	}
+	return js
+}
+
+// saveJob records the job of the operation in this project, nil removes it.
+// The jobs file is read again under a lock, so that jobs saved by other processes in the meantime are kept.
+func saveJob(name string, job *Job) {
+	unlock, err := utils.LockFile(Get().JobsFile())
+	if err != nil {
+		log.Error("failed to lock jobs", "err", err)
+		return
+	}
+	defer unlock()
+
+	js := loadJobs()
+	root := Get().ProjectRoot()
+	if job == nil {
+		delete(js[root], name)
+		if len(js[root]) == 0 {
+			delete(js, root)
+		}
+	} else {
+		if js[root] == nil {
+			js[root] = make(map[string]*Job)
+		}
+		js[root][name] = job
+	}
+	data, err := json.Marshal(js)
+	if err != nil {
+		log.Error("failed to encode jobs", "err", err)
+		return
+	}
+	err = utils.WriteFile(Get().JobsFile(), data)
+	if err != nil {
+		log.Error("failed to save jobs", "err", err)
+	}
+}
+
+// LoadJob returns the unfinished job of the operation in this project, nil if there is none.
+func LoadJob(name string) *Job {
+	job := loadJobs()[Get().ProjectRoot()][name]
+	if job != nil {
+		job.name = name
+	}
+	return job
+}
+
+// StartJob starts a job of the operation over the items, replacing the unfinished one.
+// With resume, the unfinished job is continued instead: items not in it are added, and items done are kept.
+func StartJob(name string, items []string, resume bool) *Job {
+	var job *Job
+	if resume {
+		job = LoadJob(name)
+	}
+	if job == nil {
+		job = &Job{name: name, Status: make(map[string]JobItem), StartedAt: time.Now()}
+	}
+	for _, item := range items {
+		if _, ok := job.Status[item]; !ok {
+			job.Items = append(job.Items, item)
+			job.Status[item] = JobItem{Status: JobPending}
+		}
+	}
+	job.save(true)
+	return job
+}
+
+// Pending returns the items not done yet in order, including failed and skipped ones.
+func (j *Job) Pending() []string {
+	if j == nil {
+		return nil
+	}
+	var pending []string
+	for _, item := range j.Items {
+		if j.Status[item].Status != JobDone {
+			pending = append(pending, item)
+		}
+	}
+	return pending
+}
+
+func (j *Job) Done(item string) {
+	if j == nil {
+		return
+	}
+	j.Status[item] = JobItem{Status: JobDone}
+	j.save(false)
+}
+
+func (j *Job) Fail(item string, err error) {
+	if j == nil {
+		return
+	}
+	j.Status[item] = JobItem{Status: JobError, Error: err.Error()}
+	j.save(false)
+}
+
+func (j *Job) Skip(item string, reason string) {
+	if j == nil {
+		return
+	}
+	j.Status[item] = JobItem{Status: JobSkipped, Error: reason}
+	j.save(false)
+}
+
+// Finish removes the job if all items are done, otherwise it's saved to be resumed.
+func (j *Job) Finish() {
+	if j == nil {
+		return
+	}
+	pending := len(j.Pending())
+	if pending == 0 {
+		saveJob(j.name, nil)
+		return
+	}
+	j.save(true)
+	log.Info("run again with --resume to retry the rest", "pending", pending)
+}
+
+// save writes the job to the jobs file, unless it was written within jobSaveInterval and force is false.
+func (j *Job) save(force bool) {
+	now := time.Now()
+	j.UpdatedAt = now
+	if !force && now.Sub(j.savedAt) < jobSaveInterval {
+		return
+	}
+	j.savedAt = now
+	saveJob(j.name, j)
+}
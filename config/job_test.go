@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestJobResume(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+
+	job := StartJob("pick", []string{"a", "b", "c"}, false)
+	job.Done("a")
+	job.Fail("b", errors.New("failed"))
+	job.Skip("c", "paid only")
+	job.Finish()
+
+	loaded := LoadJob("pick")
+	if loaded == nil {
+		t.Fatal("unfinished job should be saved")
+	}
+	if got := loaded.Pending(); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Pending() = %v, want [b c]", got)
+	}
+	if got := loaded.Status["b"]; got.Status != JobError || got.Error != "failed" {
+		t.Errorf("status of b = %+v", got)
+	}
+	if got := loaded.Status["c"]; got.Status != JobSkipped || got.Error != "paid only" {
+		t.Errorf("status of c = %+v", got)
+	}
+
+	job = StartJob("pick", []string{"c", "d"}, true)
+	if got := job.Pending(); !slices.Equal(got, []string{"b", "c", "d"}) {
+		t.Errorf("Pending() after resume = %v, want [b c d]", got)
+	}
+	for _, item := range job.Pending() {
+		job.Done(item)
+	}
+	job.Finish()
+	if LoadJob("pick") != nil {
+		t.Errorf("finished job should be removed")
+	}
+
+	// Without resume, the unfinished job is replaced.
+	StartJob("pick", []string{"a"}, false).Finish()
+	job = StartJob("pick", []string{"x"}, false)
+	if got := job.Pending(); !slices.Equal(got, []string{"x"}) {
+		t.Errorf("Pending() of a new job = %v, want [x]", got)
+	}
+}
+
+func TestJobSaveInterval(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+
+	job := StartJob("migrate-files", []string{"a", "b"}, false)
+	other := StartJob("pick", []string{"x"}, false)
+	job.Done("a")
+	// Items done within the interval are not written until the job is finished.
+	if got := LoadJob("migrate-files").Pending(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Pending() before finished = %v, want [a b]", got)
+	}
+	job.Finish()
+	if got := LoadJob("migrate-files").Pending(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Pending() after finished = %v, want [b]", got)
+	}
+	// Jobs saved in the meantime are kept.
+	other.Done("x")
+	other.Finish()
+	if LoadJob("pick") != nil {
+		t.Errorf("finished job should be removed")
+	}
+	if got := LoadJob("migrate-files").Pending(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Pending() of the other job = %v, want [b]", got)
+	}
+}
+
+func TestNilJob(t *testing.T) {
+	var job *Job
+	job.Done("a")
+	job.Fail("a", errors.New("failed"))
+	job.Skip("a", "paid only")
+	job.Finish()
+	if job.Pending() != nil {
+		t.Errorf("Pending() of nil job should be nil")
+	}
+}
+
+func TestConcurrentJobs(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+
+	// Jobs saved at the same time, as by concurrent commands, don't overwrite each other.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			StartJob(fmt.Sprintf("job%d", i), []string{"a"}, false)
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 8; i++ {
+		if LoadJob(fmt.Sprintf("job%d", i)) == nil {
+			t.Errorf("job%d is lost", i)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QuestionConfigFilename is the per-question config file in the question directory.
+const QuestionConfigFilename = "question.yaml"
+
+// QuestionConfigFile returns the config file of the question in dir. Questions of flat layouts share
+// the directory, so their config files are prefixed with the slug, e.g. two-sum.question.yaml.
+func QuestionConfigFile(dir, slug string, flat bool) string {
+	if flat {
+		return filepath.Join(dir, slug+"."+QuestionConfigFilename)
+	}
+	return filepath.Join(dir, QuestionConfigFilename)
+}
+
+// QuestionConfig holds settings of a single question, it is optional.
+type QuestionConfig struct {
+	Compare CompareOptions `yaml:"compare"`
+}
+
+// CompareOptions relaxes how local runners compare the actual output with the expected one,
+// mostly needed by string, shell and SQL questions.
+type CompareOptions struct {
+	TrimTrailingWhitespace bool `yaml:"trim_trailing_whitespace"`
+	IgnoreCase             bool `yaml:"ignore_case"`
+	NormalizeNewlines      bool `yaml:"normalize_newlines"`
+}
+
+func (o CompareOptions) IsZero() bool {
+	return o == CompareOptions{}
+}
+
+// LoadQuestionConfig reads the question config file, a missing file results in the zero config.
+func LoadQuestionConfig(file string) (QuestionConfig, error) {
+	var qc QuestionConfig
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return qc, nil
+		}
+		return qc, err
+	}
+	err = yaml.Unmarshal(data, &qc)
+	if err != nil {
+		return qc, fmt.Errorf("invalid %s: %w", file, err)
+	}
+	return qc, nil
+}
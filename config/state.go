@@ -1,7 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/goccy/go-json"
@@ -11,64 +17,574 @@ import (
 
 // Small project state management.
 
+// LastQuestion is identified by site and slug, FrontendID is for display only.
 type LastQuestion struct {
+	Site       string `json:"site"`
 	FrontendID string `json:"frontend_id"`
 	Slug       string `json:"slug"`
 	Gen        string `json:"gen"`
 }
 
+// PlanProgress records questions generated from a study plan.
+type PlanProgress struct {
+	Picked []string `json:"picked"`
+}
+
+// LadderProgress records the progress of a rating ladder.
+type LadderProgress struct {
+	Start   int      `json:"start"`
+	Step    int      `json:"step"`
+	Rung    int      `json:"rung"`
+	Current string   `json:"current"`
+	Climbed []string `json:"climbed,omitempty"`
+}
+
+// QuestionState records the local progress of a question.
+// FrontendID is for display only, as it may differ between sites.
+type QuestionState struct {
+	FrontendID  string    `json:"frontend_id"`
+	Langs       []string  `json:"langs,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+	AcceptedAt  time.Time `json:"accepted_at"`
+	// AcceptedHashes are hashes of the last accepted code of each language, to find solutions changed since.
+	AcceptedHashes map[string]string `json:"accepted_hashes,omitempty"`
+	// Runs are the accepted submissions of each language, oldest first.
+	Runs map[string][]RunRecord `json:"runs,omitempty"`
+	// TimeLog records when the question was worked on, oldest first.
+	TimeLog []TimeEvent `json:"time_log,omitempty"`
+	// SolveTime is the time from the question was generated or opened to its first acceptance, zero if unknown.
+	SolveTime time.Duration `json:"solve_time,omitempty"`
+}
+
+func (q QuestionState) Accepted() bool {
+	return !q.AcceptedAt.IsZero()
+}
+
+// maxRunRecords limits the number of accepted submissions kept for each question and language.
+const maxRunRecords = 30
+
+// RunRecord records the runtime and memory reported for an accepted submission.
+type RunRecord struct {
+	SubmissionID string    `json:"submission_id,omitempty"`
+	AcceptedAt   time.Time `json:"accepted_at"`
+	Hash         string    `json:"hash,omitempty"`
+	// RuntimeMs is -1 if the runtime is not reported.
+	RuntimeMs         int     `json:"runtime_ms"`
+	RuntimePercentile float64 `json:"runtime_percentile"`
+	// Memory is in bytes.
+	Memory           int     `json:"memory"`
+	MemoryPercentile float64 `json:"memory_percentile"`
+}
+
+// Events in the time log of a question.
+const (
+	TimeGenerated = "generated"
+	TimeOpened    = "opened"
+	TimeTested    = "tested"
+	TimeSubmitted = "submitted"
+	TimeAccepted  = "accepted"
+	// TimeStarted and TimeStopped delimit a session timed by `leetgo time start`, it has no breaks in between.
+	TimeStarted = "started"
+	TimeStopped = "stopped"
+)
+
+// maxTimeEvents limits the number of events kept in the time log of each question.
+const maxTimeEvents = 200
+
+// SessionBreak is the longest pause between events counted as time spent, a longer one ends a session.
+const SessionBreak = time.Hour
+
+// TimeEvent is an event in the time log of a question.
+type TimeEvent struct {
+	At    time.Time `json:"at"`
+	Event string    `json:"event"`
+}
+
+// Session is a period of working on a question without a break longer than SessionBreak,
+// or a timed session from a started to a stopped event.
+type Session struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Events []string  `json:"events"`
+}
+
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Sessions splits the time log into sessions.
+// Pauses are breaks only outside timed sessions, coding without running commands leaves no events.
+func (q QuestionState) Sessions() []Session {
+	var sessions []Session
+	timed, stopped := false, false
+	for _, e := range q.TimeLog {
+		n := len(sessions)
+		newSession := n == 0 || stopped ||
+			!timed && (e.Event == TimeStarted || e.At.Sub(sessions[n-1].End) > SessionBreak)
+		if newSession {
+			sessions = append(sessions, Session{Start: e.At, End: e.At, Events: []string{e.Event}})
+		} else {
+			sessions[n-1].End = e.At
+			sessions[n-1].Events = append(sessions[n-1].Events, e.Event)
+		}
+		stopped = false
+		switch e.Event {
+		case TimeStarted:
+			timed = true
+		case TimeStopped:
+			stopped, timed = timed, false
+		}
+	}
+	return sessions
+}
+
+// Timing reports whether a timed session of the question is running.
+func (q QuestionState) Timing() bool {
+	for i := len(q.TimeLog) - 1; i >= 0; i-- {
+		switch q.TimeLog[i].Event {
+		case TimeStarted:
+			return true
+		case TimeStopped:
+			return false
+		}
+	}
+	return false
+}
+
+// TimeSpent returns the time spent on the question, the sum of the durations of its sessions.
+func (q QuestionState) TimeSpent() time.Duration {
+	var total time.Duration
+	for _, s := range q.Sessions() {
+		total += s.Duration()
+	}
+	return total
+}
+
+// MaxSolveTime caps the solve time, a question accepted longer after it was generated or opened has been put aside,
+// its solve time is unknown.
+const MaxSolveTime = 4 * time.Hour
+
+// solveTime returns the time from the question was first generated, opened or timed to acceptedAt,
+// editing in between leaves no events, so the time is not summed from sessions. It's zero if unknown.
+func (q QuestionState) solveTime(acceptedAt time.Time) time.Duration {
+	for _, e := range q.TimeLog {
+		if e.Event != TimeGenerated && e.Event != TimeOpened && e.Event != TimeStarted {
+			continue
+		}
+		d := acceptedAt.Sub(e.At)
+		if d <= 0 || d > MaxSolveTime {
+			return 0
+		}
+		return d
+	}
+	return 0
+}
+
+// maxSubmissionRecords limits the number of detached submissions kept in state.
+const maxSubmissionRecords = 50
+
+// SubmissionRecord records a submission that was not waited for.
+type SubmissionRecord struct {
+	ID          string    `json:"id"`
+	Site        string    `json:"site"`
+	Slug        string    `json:"slug"`
+	FrontendID  string    `json:"frontend_id"`
+	Lang        string    `json:"lang"`
+	Hash        string    `json:"hash,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Result is the status message of the finished submission, empty if it is still pending.
+	Result string `json:"result,omitempty"`
+}
+
+func (r SubmissionRecord) Pending() bool {
+	return r.Result == ""
+}
+
+// VirtualContest records a past contest being simulated locally, timed from StartedAt.
+type VirtualContest struct {
+	Site      string        `json:"site"`
+	Slug      string        `json:"slug"`
+	Title     string        `json:"title"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	// Questions are slugs of the questions of the contest.
+	Questions []string `json:"questions"`
+	// Results are the judged submissions made within the duration, keyed by question slug.
+	Results map[string]VirtualResult `json:"results,omitempty"`
+}
+
+// VirtualResult records the submissions of a question in a virtual contest.
+type VirtualResult struct {
+	// AcceptedAt is zero if the question has not been accepted.
+	AcceptedAt time.Time `json:"accepted_at"`
+	// FailCount is the number of wrong submissions before the accepted one.
+	FailCount int `json:"fail_count"`
+}
+
+func (v *VirtualContest) EndsAt() time.Time {
+	return v.StartedAt.Add(v.Duration)
+}
+
+func (v *VirtualContest) HasFinished() bool {
+	return !time.Now().Before(v.EndsAt())
+}
+
+// Record records a judged submission of a question, submissions after the end or after the question
+// was accepted are ignored. It returns whether the submission is recorded.
+func (v *VirtualContest) Record(slug string, accepted bool) bool {
+	if !slices.Contains(v.Questions, slug) || v.HasFinished() {
+		return false
+	}
+	if v.Results == nil {
+		v.Results = make(map[string]VirtualResult)
+	}
+	r := v.Results[slug]
+	if !r.AcceptedAt.IsZero() {
+		return false
+	}
+	if accepted {
+		r.AcceptedAt = time.Now()
+	} else {
+		r.FailCount++
+	}
+	v.Results[slug] = r
+	return true
+}
+
+// stateVersion is bumped when the layout of State changes, older states are migrated on load.
+const stateVersion = 1
+
 type State struct {
-	LastQuestion LastQuestion `json:"last_question"`
-	LastContest  string       `json:"last_contest"`
+	Version      int                      `json:"version"`
+	LastQuestion LastQuestion             `json:"last_question"`
+	LastContest  string                   `json:"last_contest"`
+	Plans        map[string]PlanProgress  `json:"plans,omitempty"`
+	Questions    map[string]QuestionState `json:"questions,omitempty"`
+	Ladder       *LadderProgress          `json:"ladder,omitempty"`
+	Submissions  []SubmissionRecord       `json:"submissions,omitempty"`
+	Virtual      *VirtualContest          `json:"virtual,omitempty"`
 }
 
-type States map[string]State
+// questionKey returns the key of a question in State.Questions, questions are keyed by site and slug.
+func questionKey(slug string) string {
+	return Get().LeetCode.Site.Short() + "/" + slug
+}
 
-func loadStates() States {
-	s := make(States)
+// Question returns the local progress of the question on the configured site.
+func (s *State) Question(slug string) (QuestionState, bool) {
+	qs, ok := s.Questions[questionKey(slug)]
+	return qs, ok
+}
 
+// MarkGenerated records that code of the question has been generated in the given language.
+func (s *State) MarkGenerated(slug, frontendID, lang string) {
+	if s.Questions == nil {
+		s.Questions = make(map[string]QuestionState)
+	}
+	key := questionKey(slug)
+	qs := s.Questions[key]
+	qs.FrontendID = frontendID
+	if !slices.Contains(qs.Langs, lang) {
+		qs.Langs = append(qs.Langs, lang)
+	}
+	if qs.GeneratedAt.IsZero() {
+		qs.GeneratedAt = time.Now()
+	}
+	s.Questions[key] = qs
+}
+
+// MarkAccepted records that a solution of the question has been accepted, hash is the hash of the accepted code.
+func (s *State) MarkAccepted(slug, frontendID, lang, hash string) {
+	if s.Questions == nil {
+		s.Questions = make(map[string]QuestionState)
+	}
+	key := questionKey(slug)
+	qs := s.Questions[key]
+	qs.FrontendID = frontendID
+	firstAccepted := !qs.Accepted()
+	qs.AcceptedAt = time.Now()
+	qs.logTime(TimeAccepted)
+	if firstAccepted {
+		qs.SolveTime = qs.solveTime(qs.AcceptedAt)
+	}
+	if hash != "" {
+		if qs.AcceptedHashes == nil {
+			qs.AcceptedHashes = make(map[string]string)
+		}
+		qs.AcceptedHashes[lang] = hash
+	}
+	s.Questions[key] = qs
+}
+
+func (q *QuestionState) logTime(event string) {
+	q.TimeLog = append(q.TimeLog, TimeEvent{At: time.Now(), Event: event})
+	if len(q.TimeLog) > maxTimeEvents {
+		q.TimeLog = q.TimeLog[len(q.TimeLog)-maxTimeEvents:]
+	}
+}
+
+// LogTime records the event in the time log of the question, the oldest events are dropped if there are too many.
+func (s *State) LogTime(slug, event string) {
+	if s.Questions == nil {
+		s.Questions = make(map[string]QuestionState)
+	}
+	key := questionKey(slug)
+	qs := s.Questions[key]
+	qs.logTime(event)
+	s.Questions[key] = qs
+}
+
+// AddRun records an accepted submission of the question in the language, the oldest records are dropped if there are too many.
+func (s *State) AddRun(slug, lang string, r RunRecord) {
+	if s.Questions == nil {
+		s.Questions = make(map[string]QuestionState)
+	}
+	key := questionKey(slug)
+	qs := s.Questions[key]
+	if qs.Runs == nil {
+		qs.Runs = make(map[string][]RunRecord)
+	}
+	runs := append(qs.Runs[lang], r)
+	if len(runs) > maxRunRecords {
+		runs = runs[len(runs)-maxRunRecords:]
+	}
+	qs.Runs[lang] = runs
+	s.Questions[key] = qs
+}
+
+// AcceptedHashes returns hashes of the accepted code in the language, keyed by slugs of questions on the configured site.
+func (s *State) AcceptedHashes(lang string) map[string]string {
+	prefix := questionKey("")
+	hashes := make(map[string]string)
+	for key, qs := range s.Questions {
+		if slug, ok := strings.CutPrefix(key, prefix); ok && qs.AcceptedHashes[lang] != "" {
+			hashes[slug] = qs.AcceptedHashes[lang]
+		}
+	}
+	return hashes
+}
+
+// GeneratedSlugs returns slugs of questions generated in the language on the configured site, in order.
+func (s *State) GeneratedSlugs(lang string) []string {
+	prefix := questionKey("")
+	var slugs []string
+	for key, qs := range s.Questions {
+		if slug, ok := strings.CutPrefix(key, prefix); ok && slices.Contains(qs.Langs, lang) {
+			slugs = append(slugs, slug)
+		}
+	}
+	slices.Sort(slugs)
+	return slugs
+}
+
+// SolveTimes returns the known solve times of questions on the configured site, keyed by slug.
+func (s *State) SolveTimes() map[string]time.Duration {
+	prefix := questionKey("")
+	times := make(map[string]time.Duration)
+	for key, qs := range s.Questions {
+		if slug, ok := strings.CutPrefix(key, prefix); ok && qs.SolveTime > 0 {
+			times[slug] = qs.SolveTime
+		}
+	}
+	return times
+}
+
+// ForgetGenerated removes the language from the generated ones of the question, after its files are removed.
+// The question is forgotten if it has no generated language and has never been accepted.
+func (s *State) ForgetGenerated(slug, lang string) {
+	key := questionKey(slug)
+	qs, ok := s.Questions[key]
+	if !ok {
+		return
+	}
+	qs.Langs = slices.DeleteFunc(qs.Langs, func(l string) bool { return l == lang })
+	if len(qs.Langs) == 0 && !qs.Accepted() {
+		delete(s.Questions, key)
+		return
+	}
+	s.Questions[key] = qs
+}
+
+// Submitted reports whether a detached submission of the question on the configured site is recorded.
+func (s *State) Submitted(slug string) bool {
+	site := Get().LeetCode.Site.Short()
+	return slices.ContainsFunc(s.Submissions, func(r SubmissionRecord) bool { return r.Site == site && r.Slug == slug })
+}
+
+// AddSubmission records a detached submission, the oldest records are dropped if there are too many.
+func (s *State) AddSubmission(r SubmissionRecord) {
+	s.Submissions = append(s.Submissions, r)
+	if len(s.Submissions) > maxSubmissionRecords {
+		s.Submissions = s.Submissions[len(s.Submissions)-maxSubmissionRecords:]
+	}
+}
+
+// rawStates holds the states of all projects, keyed by project root.
+// Only the state of the current project is decoded, states of other projects are kept as is.
+type rawStates map[string]json.RawMessage
+
+// statesCache keeps the last read or written state file in memory, it is invalidated when the file is modified.
+// A file modified within racyStateWindow before it was cached may be modified again without changing its
+// modification time and size, it's read again, like git does for racily clean files.
+var statesCache struct {
+	sync.Mutex
+	file     string
+	modTime  time.Time
+	size     int64
+	cachedAt time.Time
+	states   rawStates
+}
+
+// racyStateWindow covers the coarsest modification time granularity of common file systems.
+const racyStateWindow = 2 * time.Second
+
+func loadStates() rawStates {
 	file := Get().StateFile()
-	f, err := os.Open(file)
+	stat, err := os.Stat(file)
+	if err != nil {
+		log.Debug("failed to stat state file", "err", err)
+		return make(rawStates)
+	}
+
+	statesCache.Lock()
+	defer statesCache.Unlock()
+	if statesCache.states != nil && statesCache.file == file &&
+		statesCache.modTime.Equal(stat.ModTime()) && statesCache.size == stat.Size() &&
+		statesCache.cachedAt.Sub(stat.ModTime()) > racyStateWindow {
+		return maps.Clone(statesCache.states)
+	}
+
+	s := make(rawStates)
+	done := utils.Track(utils.ProfileDisk)
+	data, err := os.ReadFile(file)
+	done()
 	if err != nil {
 		log.Debug("failed to open state file", "err", err)
 		return s
 	}
-	defer func() { _ = f.Close() }()
-
-	dec := json.NewDecoder(f)
-	err = dec.Decode(&s)
+	err = json.Unmarshal(data, &s)
 	if err != nil {
 		log.Debug("failed to load state", "err", err)
+		return make(rawStates)
 	}
+	cacheStates(file, stat, s)
+	return maps.Clone(s)
+}
 
-	return s
+// cacheStates records the states read from or written to the file, statesCache must be locked.
+func cacheStates(file string, stat os.FileInfo, states rawStates) {
+	statesCache.file = file
+	statesCache.modTime = stat.ModTime()
+	statesCache.size = stat.Size()
+	statesCache.cachedAt = time.Now()
+	statesCache.states = maps.Clone(states)
+}
+
+// migrate upgrades a state saved by an older version.
+// Older states were keyed by slug only, they are assumed to belong to the configured site.
+func (s *State) migrate() {
+	if s.Version >= stateVersion {
+		return
+	}
+	site := Get().LeetCode.Site.Short()
+	if len(s.Questions) > 0 {
+		questions := make(map[string]QuestionState, len(s.Questions))
+		for slug, qs := range s.Questions {
+			if !strings.Contains(slug, "/") {
+				slug = site + "/" + slug
+			}
+			questions[slug] = qs
+		}
+		s.Questions = questions
+	}
+	if s.LastQuestion.Slug != "" && s.LastQuestion.Site == "" {
+		s.LastQuestion.Site = site
+	}
+	for i := range s.Submissions {
+		if s.Submissions[i].Site == "" {
+			s.Submissions[i].Site = site
+		}
+	}
+	s.Version = stateVersion
 }
 
 func LoadState() State {
-	s := loadStates()
-	projectRoot := Get().ProjectRoot()
-	return s[projectRoot]
+	defer func(start time.Time) {
+		log.Debug("state loaded", "elapsed", time.Since(start))
+	}(time.Now())
+
+	var state State
+	raw, ok := loadStates()[Get().ProjectRoot()]
+	if ok {
+		err := json.Unmarshal(raw, &state)
+		if err != nil {
+			log.Debug("failed to decode state", "err", err)
+			state = State{}
+		}
+	}
+	state.migrate()
+	return state
 }
 
 func SaveState(s State) {
-	projectRoot := Get().ProjectRoot()
-	file := Get().StateFile()
+	raw, err := json.Marshal(s)
+	if err != nil {
+		log.Error("failed to encode state", "err", err)
+		return
+	}
 	states := loadStates()
-	states[projectRoot] = s
+	states[Get().ProjectRoot()] = raw
+	writeStates(states)
+}
+
+// InitState records an empty state for a new project, the existing state is kept.
+func InitState(projectRoot string) {
+	states := loadStates()
+	if _, ok := states[projectRoot]; ok {
+		return
+	}
+	raw, err := json.Marshal(State{Version: stateVersion})
+	if err != nil {
+		log.Error("failed to encode state", "err", err)
+		return
+	}
+	states[projectRoot] = raw
+	writeStates(states)
+}
 
+// stateDirError tells how to fix err if the state directory is not writable.
+func stateDirError(err error) error {
+	if !utils.IsPermissionError(err) {
+		return err
+	}
+	return fmt.Errorf("state directory %s is not writable: %w, set LEETGO_STATE_DIR to a writable directory", Get().StateDir(), err)
+}
+
+func writeStates(states rawStates) {
+	file := Get().StateFile()
 	err := utils.CreateIfNotExists(file, false)
 	if err != nil {
-		log.Error("failed to create state file", "err", err)
+		log.Error("failed to create state file", "err", stateDirError(err))
 		return
 	}
 	f, err := os.OpenFile(file, os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
-		log.Error("failed to open state file", "err", err)
+		log.Error("failed to open state file", "err", stateDirError(err))
 		return
 	}
+	defer func() { _ = f.Close() }()
+
+	statesCache.Lock()
+	defer statesCache.Unlock()
+	// Drop the cache first, in case the file is changed but not recorded below.
+	statesCache.states = nil
 	enc := json.NewEncoder(f)
 	err = enc.Encode(states)
 	if err != nil {
 		log.Error("failed to save state", "err", err)
+		return
+	}
+	if stat, err := f.Stat(); err == nil {
+		cacheStates(file, stat, states)
 	}
 }
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForgetGenerated(t *testing.T) {
+	var s State
+	s.MarkGenerated("two-sum", "1", "go")
+	s.MarkGenerated("two-sum", "1", "python3")
+	s.MarkGenerated("add-two-numbers", "2", "go")
+	s.MarkAccepted("add-two-numbers", "2", "go", "hash")
+
+	s.ForgetGenerated("two-sum", "go")
+	if qs, ok := s.Question("two-sum"); !ok || len(qs.Langs) != 1 || qs.Langs[0] != "python3" {
+		t.Errorf("two-sum = %+v, %v, want only python3 left", qs, ok)
+	}
+	s.ForgetGenerated("two-sum", "python3")
+	if _, ok := s.Question("two-sum"); ok {
+		t.Errorf("two-sum without generated languages should be forgotten")
+	}
+	// Accepted questions are kept for the statistics.
+	s.ForgetGenerated("add-two-numbers", "go")
+	if qs, ok := s.Question("add-two-numbers"); !ok || !qs.Accepted() || len(qs.Langs) != 0 {
+		t.Errorf("add-two-numbers = %+v, %v, want it kept as accepted", qs, ok)
+	}
+	s.ForgetGenerated("unknown", "go")
+}
+
+func TestSaveStateRefreshesCache(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+
+	// Same-size states saved within the same modification time tick.
+	for _, last := range []string{"aaa", "bbb", "ccc"} {
+		SaveState(State{LastContest: last})
+		if got := LoadState().LastContest; got != last {
+			t.Errorf("LoadState().LastContest = %q, want %q", got, last)
+		}
+	}
+}
+
+func TestLoadStateRewrittenFile(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+	SaveState(State{LastContest: "aaa"})
+	file := Get().StateFile()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat, _ := os.Stat(file)
+
+	// Rewritten by another process with the same size and modification time, right after it's cached.
+	_ = LoadState()
+	writeFile(t, file, strings.Replace(string(data), "aaa", "bbb", 1))
+	if err := os.Chtimes(file, stat.ModTime(), stat.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadState().LastContest; got != "bbb" {
+		t.Errorf("LoadState().LastContest = %q, want the rewritten one", got)
+	}
+
+	// Cached long after the last modification, then modified again.
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	_ = LoadState()
+	writeFile(t, file, strings.Replace(string(data), "aaa", "ccc", 1))
+	if got := LoadState().LastContest; got != "ccc" {
+		t.Errorf("LoadState().LastContest = %q, want the modified one", got)
+	}
+}
+
+func TestTimeLog(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration, event string) TimeEvent { return TimeEvent{At: start.Add(d), Event: event} }
+	cases := []struct {
+		name      string
+		log       []TimeEvent
+		sessions  int
+		spent     time.Duration
+		solveTime time.Duration
+	}{
+		{"empty", nil, 0, 0, 0},
+		{
+			"one session",
+			[]TimeEvent{at(0, TimeGenerated), at(10*time.Minute, TimeTested), at(20*time.Minute, TimeSubmitted)},
+			1, 20 * time.Minute, 20 * time.Minute,
+		},
+		{
+			"edited without events",
+			[]TimeEvent{at(0, TimeGenerated), at(time.Second, TimeOpened), at(2*time.Hour, TimeSubmitted)},
+			2, time.Second, 2 * time.Hour,
+		},
+		{
+			"put aside for days",
+			[]TimeEvent{at(0, TimeGenerated), at(72*time.Hour, TimeOpened), at(72*time.Hour+time.Minute, TimeSubmitted)},
+			2, time.Minute, 0,
+		},
+		{
+			"timed session without events",
+			[]TimeEvent{at(0, TimeStarted), at(90*time.Minute, TimeStopped), at(91*time.Minute, TimeTested)},
+			2, 90 * time.Minute, 91 * time.Minute,
+		},
+		{
+			"timed session after events",
+			[]TimeEvent{at(0, TimeGenerated), at(time.Minute, TimeStarted), at(2*time.Hour, TimeAccepted)},
+			2, 2*time.Hour - time.Minute, 2 * time.Hour,
+		},
+		{
+			"stopped without start",
+			[]TimeEvent{at(0, TimeTested), at(time.Minute, TimeStopped), at(2*time.Minute, TimeSubmitted)},
+			1, 2 * time.Minute, 0,
+		},
+		{
+			"never generated or opened",
+			[]TimeEvent{at(0, TimeTested), at(time.Minute, TimeSubmitted)},
+			1, time.Minute, 0,
+		},
+	}
+	for _, c := range cases {
+		q := QuestionState{TimeLog: c.log}
+		if got := len(q.Sessions()); got != c.sessions {
+			t.Errorf("%s: %d sessions, want %d", c.name, got, c.sessions)
+		}
+		if got := q.TimeSpent(); got != c.spent {
+			t.Errorf("%s: time spent %s, want %s", c.name, got, c.spent)
+		}
+		acceptedAt := start
+		if len(c.log) > 0 {
+			acceptedAt = c.log[len(c.log)-1].At
+		}
+		if got := q.solveTime(acceptedAt); got != c.solveTime {
+			t.Errorf("%s: solve time %s, want %s", c.name, got, c.solveTime)
+		}
+	}
+}
+
+func TestTiming(t *testing.T) {
+	cases := []struct {
+		events []string
+		want   bool
+	}{
+		{nil, false},
+		{[]string{TimeGenerated, TimeStarted, TimeTested}, true},
+		{[]string{TimeStarted, TimeStopped, TimeTested}, false},
+		{[]string{TimeStopped, TimeStarted}, true},
+	}
+	for _, c := range cases {
+		var q QuestionState
+		for _, e := range c.events {
+			q.TimeLog = append(q.TimeLog, TimeEvent{At: time.Now(), Event: e})
+		}
+		if got := q.Timing(); got != c.want {
+			t.Errorf("Timing() of %q = %v, want %v", c.events, got, c.want)
+		}
+	}
+}
+
+func TestSolveTimes(t *testing.T) {
+	var s State
+	s.MarkGenerated("two-sum", "1", "go")
+	qs := s.Questions[questionKey("two-sum")]
+	qs.TimeLog = []TimeEvent{{At: time.Now().Add(-time.Minute), Event: TimeGenerated}}
+	s.Questions[questionKey("two-sum")] = qs
+	s.MarkAccepted("two-sum", "1", "go", "")
+	s.MarkGenerated("add-two-numbers", "2", "go")
+
+	times := s.SolveTimes()
+	if _, ok := times["two-sum"]; !ok || len(times) != 1 {
+		t.Errorf("SolveTimes() = %v, want only two-sum", times)
+	}
+	// Solve time is only recorded for the first acceptance.
+	qs, _ = s.Question("two-sum")
+	first := qs.SolveTime
+	s.MarkAccepted("two-sum", "1", "go", "")
+	if qs, _ = s.Question("two-sum"); qs.SolveTime != first {
+		t.Errorf("solve time changed to %s on the second acceptance", qs.SolveTime)
+	}
+}
+
+func TestVirtualContestRecord(t *testing.T) {
+	v := &VirtualContest{StartedAt: time.Now().Add(-time.Minute), Duration: time.Hour, Questions: []string{"a", "b"}}
+	cases := []struct {
+		slug     string
+		accepted bool
+		want     bool
+	}{
+		{"a", false, true},
+		{"a", true, true},
+		// Submissions after the question is accepted don't count.
+		{"a", false, false},
+		{"b", false, true},
+		{"c", true, false},
+	}
+	for _, c := range cases {
+		if got := v.Record(c.slug, c.accepted); got != c.want {
+			t.Errorf("Record(%q, %v) = %v, want %v", c.slug, c.accepted, got, c.want)
+		}
+	}
+	if r := v.Results["a"]; r.AcceptedAt.IsZero() || r.FailCount != 1 {
+		t.Errorf("result of a = %+v, want accepted after 1 failure", r)
+	}
+	if r := v.Results["b"]; !r.AcceptedAt.IsZero() || r.FailCount != 1 {
+		t.Errorf("result of b = %+v, want 1 failure", r)
+	}
+
+	v.StartedAt = time.Now().Add(-2 * time.Hour)
+	if v.Record("b", true) {
+		t.Errorf("Record() after the contest ended should be ignored")
+	}
+}
+
+func TestStateDirError(t *testing.T) {
+	setDirs(t, t.TempDir(), t.TempDir())
+	err := stateDirError(&fs.PathError{Op: "open", Path: Get().StateFile(), Err: fs.ErrPermission})
+	if !errors.Is(err, fs.ErrPermission) || !strings.Contains(err.Error(), "set LEETGO_STATE_DIR") {
+		t.Errorf("stateDirError() = %v, want a hint", err)
+	}
+	other := errors.New("disk full")
+	if err := stateDirError(other); err != other {
+		t.Errorf("stateDirError() = %v, want other errors as is", err)
+	}
+}
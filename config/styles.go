@@ -9,3 +9,27 @@ var (
 	FailedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff6600"))
 	StdoutStyle  = lipgloss.NewStyle().Faint(true)
 )
+
+// PassedMark returns the mark of a passed verdict, spelled out in plain output mode.
+func PassedMark() string {
+	if Get().UsePlainOutput() {
+		return "PASSED:"
+	}
+	return "√"
+}
+
+// FailedMark returns the mark of a failed verdict, spelled out in plain output mode.
+func FailedMark() string {
+	if Get().UsePlainOutput() {
+		return "FAILED:"
+	}
+	return "×"
+}
+
+// NewlineMark returns the separator used to show multi-line text in a single line.
+func NewlineMark() string {
+	if Get().UsePlainOutput() {
+		return " | "
+	}
+	return "↩ "
+}
@@ -9,9 +9,19 @@ var (
 const (
 	CmdName               = "leetgo"
 	ConfigFilename        = "leetgo.yaml"
+	GlobalConfigFilename  = "config.yaml"
 	QuestionCacheBaseName = "leetcode-questions"
 	StateFilename         = "state.json"
+	CredentialsFilename   = "credentials.enc"
+	SessionFilename       = ".leetgo/session.json"
 	DepVersionFilename    = "deps.json"
+	RatingsFilename       = "ratings.json"
+	SnapshotFilename      = "questions-snapshot.json"
+	JobsFilename          = "jobs.json"
+	IndexFilename         = "index.json"
+	ReleaseCheckFilename  = "latest-release.json"
+	PluginsCacheFilename  = "plugins.json"
+	LogFilename           = "leetgo.log"
 	CodeBeginMarker       = "@lc code=begin"
 	CodeEndMarker         = "@lc code=end"
 	ProjectURL            = "https://github.com/j178/leetgo"
@@ -22,17 +22,30 @@ type Opener interface {
 
 const specialAllFiles = "{{.Files}}"
 
+// Strategies to open multiple files, see editor.open_strategy.
+const (
+	strategyTabs   = "tabs"
+	strategyVSplit = "vsplit"
+	strategySplit  = "split"
+	strategyNone   = "none"
+)
+
 var knownEditors = map[string]Opener{
 	"none": &noneEditor{},
 	"vim": &editor{
 		command: "vim",
-		args:    []string{"-p", fmt.Sprintf("+/%s", constants.CodeBeginMarker), specialAllFiles},
+		args:    []string{specialAllFiles},
+		layout:  vimLayoutArgs,
+		attach:  attachVim,
 	},
 	"neovim": &editor{
 		command: "nvim",
-		args:    []string{"-p", fmt.Sprintf("+/%s", constants.CodeBeginMarker), specialAllFiles},
+		args:    []string{specialAllFiles},
+		layout:  vimLayoutArgs,
+		attach:  attachNeovim,
 	},
-	"vscode": &editor{command: "code", args: []string{specialAllFiles}},
+	// --reuse-window opens files in the last active window, an already open file is focused instead of opened again.
+	"vscode": &editor{command: "code", args: []string{"--reuse-window", specialAllFiles}},
 }
 
 type noneEditor struct{}
@@ -45,10 +58,97 @@ func (e *noneEditor) Open(result *lang.GenerateResult) error {
 type editor struct {
 	command string
 	args    []string
+	// layout returns the arguments to open files with the strategy, put before args.
+	// focusCode is true if the focused file is the code file.
+	layout func(strategy string, focusCode bool) []string
+	// attach returns the command to open files in a running instance of the editor, the first file is focused.
+	// ok is false if there is no running instance to attach to.
+	attach func(files []string, strategy string) (command string, args []string, ok bool)
+}
+
+// vimLayoutArgs opens files in tabs or windows, and moves the cursor to the code if the code file is focused.
+func vimLayoutArgs(strategy string, focusCode bool) []string {
+	var args []string
+	switch strategy {
+	case strategyTabs:
+		args = append(args, "-p")
+	case strategyVSplit:
+		args = append(args, "-O")
+	case strategySplit:
+		args = append(args, "-o")
+	}
+	if focusCode {
+		args = append(args, fmt.Sprintf("+/%s", constants.CodeBeginMarker))
+	}
+	return args
+}
+
+// vimOpenCmds returns Vim script string literals of the commands to open the files with the strategy and focus the first one.
+// :drop and :tab drop jump to the window of a file if it's already open, rather than opening it again.
+func vimOpenCmds(files []string, strategy string) []string {
+	open, focus := "tab drop", "tab drop"
+	switch strategy {
+	case strategyVSplit:
+		open, focus = "vertical split", "drop"
+	case strategySplit:
+		open, focus = "split", "drop"
+	case strategyNone:
+		open, focus = "badd", "drop"
+	}
+	cmds := make([]string, 0, len(files)+1)
+	for _, f := range files {
+		cmds = append(cmds, exCmd(open, f))
+	}
+	return append(cmds, exCmd(focus, files[0]))
+}
+
+// exCmd returns a Vim script string literal of the Ex command with the file as its argument.
+func exCmd(cmd string, file string) string {
+	return fmt.Sprintf("'%s ' .. fnameescape('%s')", cmd, strings.ReplaceAll(file, "'", "''"))
+}
+
+// attachNeovim opens files in the Neovim instance leetgo is running in, e.g. from its terminal.
+func attachNeovim(files []string, strategy string) (string, []string, bool) {
+	server := os.Getenv("NVIM")
+	if server == "" {
+		server = os.Getenv("NVIM_LISTEN_ADDRESS")
+	}
+	if server == "" {
+		return "", nil, false
+	}
+	expr := fmt.Sprintf("execute([%s])", strings.Join(vimOpenCmds(files, strategy), ", "))
+	return "nvim", []string{"--server", server, "--remote-expr", expr}, true
+}
+
+// attachVim opens files in the Vim instance leetgo is running in, which must be started with a server name.
+func attachVim(files []string, strategy string) (string, []string, bool) {
+	server := os.Getenv("VIM_SERVERNAME")
+	if server == "" {
+		return "", nil, false
+	}
+	expr := fmt.Sprintf("execute([%s])", strings.Join(vimOpenCmds(files, strategy), ", "))
+	return "vim", []string{"--servername", server, "--remote-expr", expr}, true
+}
+
+// filesToOpen returns the files to open in the order of editor.open_files, the first one is focused.
+// All files are opened in the generated order if it's empty or none of the files matches.
+func filesToOpen(result *lang.GenerateResult, types []string) []lang.FileOutput {
+	var files []lang.FileOutput
+	for _, t := range types {
+		for _, f := range result.Files {
+			if f.Type.String() == t {
+				files = append(files, f)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return result.Files
+	}
+	return files
 }
 
 // substituteArgs substitutes the special arguments with the actual values.
-func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error) {
+func (ed *editor) substituteArgs(result *lang.GenerateResult, files []lang.FileOutput) ([]string, error) {
 	getPath := func(fileType lang.FileType) string {
 		f := result.GetFile(fileType)
 		if f == nil {
@@ -92,14 +192,14 @@ func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error)
 		args[i] = s.String()
 	}
 
-	// replace the special marker with all files
+	// replace the special marker with the files to open
 	for i, arg := range args {
 		if arg == specialAllFiles {
-			allFiles := make([]string, len(result.Files))
-			for j, f := range result.Files {
-				allFiles[j] = f.GetPath()
+			paths := make([]string, len(files))
+			for j, f := range files {
+				paths[j] = f.GetPath()
 			}
-			args = slices.Replace(args, i, i+1, allFiles...)
+			args = slices.Replace(args, i, i+1, paths...)
 			break
 		}
 	}
@@ -108,10 +208,26 @@ func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error)
 }
 
 func (ed *editor) Open(result *lang.GenerateResult) error {
-	args, err := ed.substituteArgs(result)
+	cfg := config.Get().Editor
+	files := filesToOpen(result, cfg.OpenFiles)
+	if ed.attach != nil && len(files) > 0 {
+		paths := make([]string, len(files))
+		for i, f := range files {
+			paths[i] = f.GetPath()
+		}
+		if command, args, ok := ed.attach(paths, cfg.OpenStrategy); ok {
+			log.Debug("attaching to running editor", "command", command)
+			return runCmd(command, args, result.OutDir)
+		}
+	}
+	args, err := ed.substituteArgs(result, files)
 	if err != nil {
 		return fmt.Errorf("invalid editor command: %w", err)
 	}
+	if ed.layout != nil {
+		focusCode := len(files) > 0 && files[0].Type&lang.CodeFile != 0
+		args = append(ed.layout(cfg.OpenStrategy, focusCode), args...)
+	}
 	return runCmd(ed.command, args, result.OutDir)
 }
 
@@ -129,6 +245,10 @@ func Get(ed config.Editor) Opener {
 
 // Open opens the files in the given result with the configured editor.
 func Open(result *lang.GenerateResult) error {
+	if config.SafeMode() {
+		log.Debug("editor is disabled in safe mode")
+		return nil
+	}
 	cfg := config.Get()
 	ed := Get(cfg.Editor)
 	if ed == nil {
@@ -137,6 +257,11 @@ func Open(result *lang.GenerateResult) error {
 			cfg.Editor.Use,
 		)
 	}
+	if _, ok := ed.(*noneEditor); !ok && result.Question != nil {
+		state := config.LoadState()
+		state.LogTime(result.Question.TitleSlug, config.TimeOpened)
+		config.SaveState(state)
+	}
 	return ed.Open(result)
 }
 
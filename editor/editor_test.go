@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/j178/leetgo/constants"
+)
+
+func TestVimLayoutArgs(t *testing.T) {
+	focus := "+/" + constants.CodeBeginMarker
+	cases := []struct {
+		strategy  string
+		focusCode bool
+		want      []string
+	}{
+		{strategyTabs, false, []string{"-p"}},
+		{strategyVSplit, false, []string{"-O"}},
+		{strategySplit, false, []string{"-o"}},
+		{strategyNone, false, nil},
+		{strategyTabs, true, []string{"-p", focus}},
+		{strategyNone, true, []string{focus}},
+	}
+	for _, c := range cases {
+		if got := vimLayoutArgs(c.strategy, c.focusCode); !slices.Equal(got, c.want) {
+			t.Errorf("vimLayoutArgs(%q, %v) = %q, want %q", c.strategy, c.focusCode, got, c.want)
+		}
+	}
+}
+
+func TestVimOpenCmds(t *testing.T) {
+	files := []string{"solution.go", "it's.md"}
+	cases := []struct {
+		strategy string
+		want     []string
+	}{
+		{
+			strategyTabs, []string{
+				"'tab drop ' .. fnameescape('solution.go')",
+				"'tab drop ' .. fnameescape('it''s.md')",
+				"'tab drop ' .. fnameescape('solution.go')",
+			},
+		},
+		{
+			strategyVSplit, []string{
+				"'vertical split ' .. fnameescape('solution.go')",
+				"'vertical split ' .. fnameescape('it''s.md')",
+				"'drop ' .. fnameescape('solution.go')",
+			},
+		},
+		{
+			strategySplit, []string{
+				"'split ' .. fnameescape('solution.go')",
+				"'split ' .. fnameescape('it''s.md')",
+				"'drop ' .. fnameescape('solution.go')",
+			},
+		},
+		{
+			strategyNone, []string{
+				"'badd ' .. fnameescape('solution.go')",
+				"'badd ' .. fnameescape('it''s.md')",
+				"'drop ' .. fnameescape('solution.go')",
+			},
+		},
+	}
+	for _, c := range cases {
+		if got := vimOpenCmds(files, c.strategy); !slices.Equal(got, c.want) {
+			t.Errorf("vimOpenCmds(%q) = %q, want %q", c.strategy, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Package i18n translates prompts, judge verdicts, notices, command help and log messages
+// to the language configured by `language`, or detected from the locale.
+// Log messages are only translated in text logs, JSON logs and returned errors are kept in English,
+// so that they can be searched for.
+// Messages are written in English in the code and used as keys of the catalogs,
+// untranslated messages are shown as is.
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/j178/leetgo/config"
+)
+
+var catalogs = map[config.Language]map[string]string{
+	config.ZH: zhMessages,
+}
+
+func translate(lang config.Language, msg string) string {
+	if t, ok := catalogs[lang][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// T translates the message to the language of leetgo messages.
+func T(msg string) string {
+	return translate(config.Get().MessageLanguage(), msg)
+}
+
+// Sprintf translates the format and formats it with the arguments.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
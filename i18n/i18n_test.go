@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+)
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestTranslate(t *testing.T) {
+	cases := []struct {
+		lang config.Language
+		msg  string
+		want string
+	}{
+		{config.ZH, "Wrong Answer", "解答错误"},
+		{config.EN, "Wrong Answer", "Wrong Answer"},
+		{config.ZH, "Not translated", "Not translated"},
+	}
+	for _, c := range cases {
+		if got := translate(c.lang, c.msg); got != c.want {
+			t.Errorf("translate(%s, %q) = %q, want %q", c.lang, c.msg, got, c.want)
+		}
+	}
+}
+
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, translated := range catalog {
+			want, got := verbRe.FindAllString(msg, -1), verbRe.FindAllString(translated, -1)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s translation of %q has verbs %v, want %v", lang, msg, got, want)
+			}
+		}
+	}
+}
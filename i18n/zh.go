@@ -0,0 +1,345 @@
+package i18n
+
+// zhMessages are the Simplified Chinese translations, the format verbs must be kept in the same order.
+var zhMessages = map[string]string{
+	// Judge verdicts
+	"Accepted":              "通过",
+	"Wrong Answer":          "解答错误",
+	"Memory Limit Exceeded": "超出内存限制",
+	"Output Limit Exceeded": "超出输出限制",
+	"Time Limit Exceeded":   "超出时间限制",
+	"Runtime Error":         "执行出错",
+	"Internal Error":        "内部出错",
+	"Unknown Error":         "未知错误",
+	"Compile Error":         "编译出错",
+	"Timeout":               "超时",
+
+	// Judge results
+	"Passed cases":           "通过用例",
+	"Runtime":                "执行用时",
+	"Memory":                 "内存消耗",
+	"Last case":              "最后执行的输入",
+	"Input":                  "输入",
+	"Output":                 "输出",
+	"Expected":               "预期结果",
+	"Stdout":                 "标准输出",
+	"%s, better than %.0f%%": "%s，击败 %.0f%% 的用户",
+	"case %d passed":         "用例 %d 通过",
+	"case %d failed":         "用例 %d 失败",
+	"Case %d diff:":          "用例 %d 差异：",
+
+	// Judge states
+	"Pending":  "排队中",
+	"Judging":  "判题中",
+	"Finished": "已完成",
+
+	// Progress
+	"Running tests...":            "正在运行测试...",
+	"Submitting solution...":      "正在提交代码...",
+	"Waiting for result...":       "正在等待结果...",
+	"Waiting for OpenAI...":       "正在等待 OpenAI...",
+	"%s begins in %s, waiting...": "%s 将在 %s 后开始，等待中...",
+
+	// Prompts
+	"Select a contest:":                               "选择竞赛：",
+	"Not all items are checked, submit anyway?":       "还有未勾选的检查项，仍然提交吗？",
+	"Select a solution:":                              "选择题解：",
+	"Do you want to accept the fix?":                  "接受这个修复吗？",
+	"Update signatures and keep the function bodies?": "更新函数签名并保留函数体吗？",
+	"Select a difficulty level":                       "选择难度",
+	"Select question status":                          "选择题目状态",
+	"Select tags":                                     "选择标签",
+
+	// Clean
+	"Delete files of %d unsolved questions?":        "删除 %d 道未解决题目的文件吗？",
+	"Archive files of %d unsolved questions to %s?": "将 %d 道未解决题目的文件归档到 %s 吗？",
+
+	// Upgrade
+	"Upgrade leetgo from %s to %s?": "将 leetgo 从 %s 升级到 %s 吗？",
+	"A new version of leetgo is available: %s → %s, run `leetgo upgrade` to upgrade.": "leetgo 有新版本可用：%s → %s，运行 `leetgo upgrade` 升级。",
+
+	// Command help
+	"Add, commit and push your solution to remote repository":                         "添加、提交并推送代码到远程仓库",
+	"Browse top voted community solutions of a question":                              "浏览题目的高赞社区题解",
+	"Check results of detached submissions":                                           "查看后台提交的结果",
+	"Climb a rating ladder built from community difficulty ratings":                   "按社区难度分攀登题目阶梯",
+	"Compare runtime and memory of accepted submissions of a question":                "比较题目通过提交的执行用时和内存消耗",
+	"Compare the solution against a brute-force solution on random inputs":            "用随机输入对比代码与暴力解法",
+	"Copy solution code to clipboard":                                                 "复制代码到剪贴板",
+	"Delete saved cookies of the current site":                                        "删除当前站点保存的 cookies",
+	"Download community ratings from zerotrac/leetcode_problem_rating":                "从 zerotrac/leetcode_problem_rating 下载社区难度分",
+	"Download the note of a question from LeetCode":                                   "从 LeetCode 下载题目笔记",
+	"Estimate relative difficulty of contest questions from the standings":            "根据排名估计竞赛题目的相对难度",
+	"Export accepted solutions as an Anki deck":                                       "将通过的代码导出为 Anki 卡组",
+	"Export solved questions to other tools":                                          "导出已解决的题目到其他工具",
+	"Generate a new question":                                                         "生成新题目",
+	"Generate contest questions":                                                      "生成竞赛题目",
+	"Import a ratings dataset that maps question ids or slugs to ratings":             "导入题目 ID 或 slug 到难度分的数据集",
+	"Init a leetcode workspace":                                                       "初始化 leetcode 工作区",
+	"List notes of questions":                                                         "列出题目笔记",
+	"List questions in the local cache":                                               "列出本地缓存中的题目",
+	"List, archive or delete generated files of questions never solved":               "列出、归档或删除从未解决的题目的生成文件",
+	"Manage LeetCode credentials saved in the OS keychain":                            "管理保存在系统钥匙串中的 LeetCode 凭证",
+	"Manage local questions cache":                                                    "管理本地题目缓存",
+	"Manage notes of questions":                                                       "管理题目笔记",
+	"Manage question difficulty ratings":                                              "管理题目难度分",
+	"Manage the configuration":                                                        "管理配置",
+	"Move cache and state files of older versions to their current locations":         "将旧版本的缓存和状态文件移动到当前位置",
+	"Open one or multiple question pages in a browser":                                "在浏览器中打开一个或多个题目页面",
+	"Open solution in editor":                                                         "在编辑器中打开代码",
+	"Open the note of a question in editor, it's created if not exists":               "在编辑器中打开题目笔记，不存在则创建",
+	"Pick a random unsolved question":                                                 "随机选择一道未解决的题目",
+	"Run question test cases":                                                         "运行题目测试用例",
+	"Save cookies of the current site to the OS keychain":                             "将当前站点的 cookies 保存到系统钥匙串",
+	"Search notes of questions, the pattern is a case-insensitive regular expression": "搜索题目笔记，模式为不区分大小写的正则表达式",
+	"Serve JSON-RPC requests from editor plugins":                                     "为编辑器插件提供 JSON-RPC 服务",
+	"Show debug info":    "显示调试信息",
+	"Show question info": "显示题目信息",
+	"Show questions added, removed or re-rated since the last cache update":            "显示自上次更新缓存以来新增、删除或难度变化的题目",
+	"Show study plan progress and pick questions from it":                              "显示学习计划进度并从中选题",
+	"Show the effective configuration":                                                 "显示生效的配置",
+	"Show time spent on a question, or average solve time by difficulty":               "显示题目用时，或按难度统计的平均解题用时",
+	"Show your rank and the ranks of friends in a contest":                             "显示你和好友在竞赛中的排名",
+	"Simulate a past contest with a local timer":                                       "用本地计时器模拟往届竞赛",
+	"Start a timed session of a question":                                              "开始题目计时",
+	"Stop the timed session of a question":                                             "停止题目计时",
+	"Submit solution":                                                                  "提交代码",
+	"Unregister from contest":                                                          "取消报名竞赛",
+	"Update local questions cache":                                                     "更新本地题目缓存",
+	"Upgrade files generated by older versions to the current template":                "将旧版本生成的文件升级到当前模板",
+	"Upgrade leetgo to the latest release":                                             "将 leetgo 升级到最新版本",
+	"Upload the note of a question to LeetCode, replacing the note there":              "将题目笔记上传到 LeetCode，替换已有笔记",
+	"Use ChatGPT API to fix your solution code (just for fun)":                         "使用 ChatGPT API 修复代码（仅供娱乐）",
+	"Validate filename templates and preview the generated files of a sample question": "校验文件名模板并预览示例题目的生成文件",
+
+	// Flag help
+	"Skip opening the editor":                                                                "不打开编辑器",
+	"answer yes to all prompts":                                                              "对所有提示回答是",
+	"ask for the configuration interactively":                                                "交互式询问配置",
+	"auto submit if all tests passed":                                                        "测试全部通过后自动提交",
+	"continue the last interrupted check, skipping submissions checked":                      "继续上次中断的检查，跳过已检查的提交",
+	"continue the last interrupted migration, skipping questions done":                       "继续上次中断的迁移，跳过已完成的题目",
+	"continue the last interrupted pick of multiple questions, skipping questions generated": "继续上次中断的多题选择，跳过已生成的题目",
+	"create the brute-force solution from the current solution":                              "从当前代码创建暴力解法",
+	"csv or apkg, defaults to the extension of --output, or csv":                             "csv 或 apkg，默认取 --output 的扩展名，否则为 csv",
+	"delete the files": "删除文件",
+	"difficulty of questions: easy, medium or hard":                                              "题目难度：easy、medium 或 hard",
+	"difficulty of the question: easy, medium or hard":                                           "题目难度：easy、medium 或 hard",
+	"discard the current ladder progress and start over":                                         "放弃当前阶梯进度并重新开始",
+	"do not wait for the judge result, check it later with `leetgo submissions --pending`":       "不等待判题结果，稍后用 `leetgo submissions --pending` 查看",
+	"end the running virtual contest before the time is up":                                      "提前结束进行中的模拟竞赛",
+	"exclude paid only questions":                                                                "排除会员题目",
+	"file to write, defaults to leetgo-<lang>.<format> in the project root":                      "输出文件，默认为项目根目录下的 leetgo-<lang>.<format>",
+	"format of logs on stderr: auto, text or json, auto uses json when stderr is not a terminal": "stderr 日志格式：auto、text 或 json，auto 在 stderr 不是终端时使用 json",
+	"format of the dataset: csv, tsv or json, guessed from the file extension by default":        "数据集格式：csv、tsv 或 json，默认根据扩展名推断",
+	"generate from a saved question data or GraphQL response file, without fetching":             "从保存的题目数据或 GraphQL 响应文件生成，不联网获取",
+	"generate the next unsolved question of the plan":                                            "生成计划中下一道未解决的题目",
+	"handle of a friend to watch, in addition to contest.friends":                                "要关注的好友用户名，补充 contest.friends",
+	"include questions submitted but not accepted":                                               "包括提交过但未通过的题目",
+	"interval to refresh the standings":                                                          "刷新排名的间隔",
+	"keep existing files without asking, even with --yes":                                        "保留已存在的文件且不询问，即使指定了 --yes",
+	"keyword in the id, slug or title":                                                           "ID、slug 或标题中的关键字",
+	"language of code to generate: cpp, go, python ...":                                          "生成代码的语言：cpp、go、python ...",
+	"leetcode site: cn, us":                                                                      "leetcode 站点：cn、us",
+	"list at most n questions, 0 means no limit":                                                 "最多列出 n 道题目，0 表示不限",
+	"listen on a unix socket instead of stdio":                                                   "监听 unix socket 而不是 stdio",
+	"max absolute value of generated numbers":                                                    "生成数字的最大绝对值",
+	"max length of generated arrays and strings":                                                 "生成数组和字符串的最大长度",
+	"maximum difficulty rating of questions":                                                     "题目的最高难度分",
+	"maximum difficulty rating of the question":                                                  "题目的最高难度分",
+	"merge with existing ratings instead of replacing them":                                      "与已有难度分合并而不是替换",
+	"minimum difficulty rating of questions":                                                     "题目的最低难度分",
+	"minimum difficulty rating of the question":                                                  "题目的最低难度分",
+	"move the files to the archive directory in the project root":                                "将文件移动到项目根目录的归档目录",
+	"name of the deck": "卡组名称",
+	"never spawn external processes or prompt, print generated files as JSON":                        "从不启动外部进程或提示，以 JSON 输出生成的文件",
+	"number of random inputs to try":                                                                 "尝试的随机输入数量",
+	"number of solutions to list":                                                                    "列出的题解数量",
+	"number of standing pages to sample, 25 users per page":                                          "采样的排名页数，每页 25 名用户",
+	"number of standing pages to search for friends, 25 users per page":                              "搜索好友的排名页数，每页 25 名用户",
+	"only export questions having the tag slugs, e.g. array, dynamic-programming":                    "只导出带有这些标签的题目，例如 array、dynamic-programming",
+	"only export questions of the difficulty: easy, medium or hard":                                  "只导出该难度的题目：easy、medium 或 hard",
+	"only list the solutions":                                                                        "只列出题解",
+	"only print the current and the latest versions":                                                 "只输出当前版本和最新版本",
+	"only questions generated longer ago than this, e.g. 30d, 2w or 12h":                             "只包括早于此时间生成的题目，例如 30d、2w 或 12h",
+	"only run the specified test case, e.g. 1, 1-3, -1, 1-":                                          "只运行指定的测试用例，例如 1、1-3、-1、1-",
+	"only show submissions that are still waiting for results":                                       "只显示仍在等待结果的提交",
+	"open question page in browser":                                                                  "在浏览器中打开题目页面",
+	"open the question page in browser to paste the code":                                            "在浏览器中打开题目页面以粘贴代码",
+	"output format: table, tsv or json":                                                              "输出格式：table、tsv 或 json",
+	"overwrite existing files without asking":                                                        "覆盖已存在的文件且不询问",
+	"period the company asked the question in: 30d, 3m, 6m or all":                                   "公司出题的时间段：30d、3m、6m 或 all",
+	"period the company asked the questions in: 30d, 3m, 6m or all":                                  "公司出题的时间段：30d、3m、6m 或 all",
+	"pick a random unsolved question, see also `leetgo random`":                                      "随机选择一道未解决的题目，另见 `leetgo random`",
+	"pick deterministically from the seed, 'daily' for the UTC date of today, to share with a group": "根据种子确定性地选题，'daily' 表示今天的 UTC 日期，便于与他人共享",
+	"pick deterministically from the seed, 'daily' for the date of today, implies --random":          "根据种子确定性地选题，'daily' 表示今天的日期，隐含 --random",
+	"plain output without colors, spinners and box-drawing characters":                               "纯文本输出，不带颜色、加载动画和制表符",
+	"print structured JSON on stdout, logs and other output go to stderr":                            "在 stdout 输出结构化 JSON，日志和其他输出写到 stderr",
+	"print the files to generate and their diffs against existing files, without writing":            "输出要生成的文件及其与已有文件的差异，不写入",
+	"rating increment between rungs":                                                                 "阶梯之间的难度分增量",
+	"rating of the first rung":                                                                       "第一级阶梯的难度分",
+	"read cookies from: browser, cookies or password":                                                "cookies 来源：browser、cookies 或 password",
+	"refresh the standings periodically until the contest finishes":                                  "定期刷新排名直到竞赛结束",
+	"release channel: stable or nightly, defaults to upgrade.channel in the config":                  "发布渠道：stable 或 nightly，默认为配置中的 upgrade.channel",
+	"report where time was spent: network, disk, subprocesses and templates":                         "报告时间花费：网络、磁盘、子进程和模板",
+	"rerun local test whenever the solution or test cases file is saved":                             "每当代码或测试用例文件保存时重新运行本地测试",
+	"reveal the first N hints":                                                                       "显示前 N 条提示",
+	"run test both locally and remotely":                                                             "同时在本地和远程运行测试",
+	"run test locally":                                                                               "在本地运行测试",
+	"save the solution as solution.md next to your code":                                             "将题解保存为代码旁的 solution.md",
+	"seed of the random inputs, a random one is used if not set":                                     "随机输入的种子，未设置时随机选取",
+	"show debug logs, including requests to LeetCode":                                                "显示调试日志，包括对 LeetCode 的请求",
+	"show full question info":                                                                        "显示完整的题目信息",
+	"show only warnings and errors":                                                                  "只显示警告和错误",
+	"show question info in specific format (json)":                                                   "以指定格式（json）显示题目信息",
+	"show the official solution instead of community solutions":                                      "显示官方题解而不是社区题解",
+	"show the solution at this position of the list (1-based) without prompting":                     "不经提示直接显示列表中此位置（从 1 开始）的题解",
+	"show where each value comes from":                                                               "显示每个值的来源",
+	"skip paid only questions when picking the next question":                                        "选择下一道题时跳过会员题目",
+	"slug of a company that asked the question, e.g. amazon, requires LeetCode Premium":              "出过此题的公司 slug，例如 amazon，需要 LeetCode 会员",
+	"slug of a company that asked the questions, e.g. amazon, requires LeetCode Premium":             "出过这些题的公司 slug，例如 amazon，需要 LeetCode 会员",
+	"sort by id, acceptance, frequency or rating, defaults to the order of the cache":                "按 id、acceptance、frequency 或 rating 排序，默认为缓存中的顺序",
+	"sort questions by imported difficulty ratings":                                                  "按导入的难度分排序题目",
+	"status of questions: accepted, attempted or new":                                                "题目状态：accepted、attempted 或 new",
+	"stop at the first failed test case":                                                             "在第一个失败的测试用例处停止",
+	"submit all solutions changed since they were last accepted":                                     "提交所有自上次通过后修改过的代码",
+	"submit even if local test failed":                                                               "即使本地测试失败也提交",
+	"tag slugs the question must have, e.g. array, dynamic-programming":                              "题目必须带有的标签，例如 array、dynamic-programming",
+	"tag slugs the questions must have, e.g. array, dynamic-programming":                             "题目必须带有的标签，例如 array、dynamic-programming",
+	"template to use, cn or us":                                                                      "使用的模板，cn 或 us",
+	"wait for the duration, e.g. 25m, then stop the session and notify":                              "等待指定时长（例如 25m）后停止计时并通知",
+	"work from the local cache without network, commands requiring network fail at once":             "不联网使用本地缓存，需要网络的命令立即失败",
+	"write a CPU profile in pprof format to the file":                                                "将 pprof 格式的 CPU profile 写入文件",
+
+	// Log messages, shown translated in text logs only
+	"LEETCODE_SESSION, LEETCODE_CSRFTOKEN and LEETCODE_CFCLEARANCE can be removed from .env now": "现在可以从 .env 中删除 LEETCODE_SESSION、LEETCODE_CSRFTOKEN 和 LEETCODE_CFCLEARANCE",
+	"accepted in virtual contest":                                     "模拟竞赛中通过",
+	"add credentials failed, continue requesting without credentials": "添加凭证失败，继续不带凭证请求",
+	"added failed case to testcases.txt":                              "已将失败用例添加到 testcases.txt",
+	"all questions already generated":                                 "所有题目均已生成",
+	"all questions already upgraded":                                  "所有题目均已升级",
+	"archived":                                                        "已归档",
+	"brute-force solution created":                                    "已创建暴力解法",
+	"building":                                                        "正在构建",
+	"cache is too old, try updating with `leetgo cache update`":       "缓存太旧，请运行 `leetgo cache update` 更新",
+	"cache updated":                                                   "缓存已更新",
+	"checking python version":                                         "正在检查 python 版本",
+	"cleaned unsolved questions":                                      "已清理未解决的题目",
+	"config dir created":                                              "已创建配置目录",
+	"config file created":                                             "已创建配置文件",
+	"connection closed":                                               "连接已关闭",
+	"copied to clipboard":                                             "已复制到剪贴板",
+	"creating venv":                                                   "正在创建 venv",
+	"credentials deleted":                                             "凭证已删除",
+	"credentials saved":                                               "凭证已保存",
+	"deleted":                                                         "已删除",
+	"diverging input added to test cases":                             "已将结果不一致的输入添加到测试用例",
+	"downloaded":                                                      "已下载",
+	"downloading ratings":                                             "正在下载难度分",
+	"downloading":                                                     "正在下载",
+	"dropped from Cargo.toml":                                         "已从 Cargo.toml 移除",
+	"dropped from go.work":                                            "已从 go.work 移除",
+	"exported":                                                        "已导出",
+	"failed to add test case":                                         "添加测试用例失败",
+	"failed to check submission":                                      "查看提交失败",
+	"failed to clean":                                                 "清理失败",
+	"failed to create state file":                                     "创建状态文件失败",
+	"failed to download attachment":                                   "下载附件失败",
+	"failed to encode jobs":                                           "编码任务失败",
+	"failed to encode state":                                          "编码状态失败",
+	"failed to generate some questions":                               "部分题目生成失败",
+	"failed to get generated files":                                   "获取生成文件失败",
+	"failed to get question data":                                     "获取题目数据失败",
+	"failed to get question":                                          "获取题目失败",
+	"failed to get standings":                                         "获取排名失败",
+	"failed to load cache, try updating with `leetgo cache update`": "加载缓存失败，请运行 `leetgo cache update` 更新",
+	"failed to load plugin":                               "加载插件失败",
+	"failed to lock jobs":                                 "锁定任务失败",
+	"failed to log checklist answers":                     "记录检查清单回答失败",
+	"failed to open log file":                             "打开日志文件失败",
+	"failed to open state file":                           "打开状态文件失败",
+	"failed to read plugins directory":                    "读取插件目录失败",
+	"failed to run test locally":                          "本地测试运行失败",
+	"failed to run test remotely":                         "远程测试运行失败",
+	"failed to save jobs":                                 "保存任务失败",
+	"failed to save questions snapshot":                   "保存题目快照失败",
+	"failed to save state":                                "保存状态失败",
+	"failed to submit solution":                           "提交代码失败",
+	"failed to update signatures":                         "更新函数签名失败",
+	"failed to update the workspace":                      "更新工作区失败",
+	"failed to upgrade":                                   "升级失败",
+	"failed to write file":                                "写入文件失败",
+	"failed to write session file":                        "写入会话文件失败",
+	"file already exists, skipped in safe mode":           "文件已存在，安全模式下跳过",
+	"file already exists, skipped":                        "文件已存在，已跳过",
+	"friend not found in the searched pages":              "在搜索的页面中未找到好友",
+	"function missing in generated code":                  "生成的代码中缺少函数",
+	"generated":                                           "已生成",
+	"ignore question ID part in qid":                      "忽略 qid 中的题目 ID 部分",
+	"invalid modifier, ignored":                           "无效的修改器，已忽略",
+	"leetgo is up to date":                                "leetgo 已是最新版本",
+	"leetgo upgraded":                                     "leetgo 已升级",
+	"logging in with username and password":               "正在使用用户名和密码登录",
+	"low bandwidth mode, skipped downloading attachments": "低带宽模式，已跳过下载附件",
+	"low bandwidth mode, skipped downloading the question list, run `leetgo cache update` when needed": "低带宽模式，已跳过下载题目列表，需要时请运行 `leetgo cache update`",
+	"network is unavailable, continuing in offline mode":                                               "网络不可用，以离线模式继续",
+	"no accepted submissions recorded":                                                                 "没有通过的提交记录",
+	"no available question in rung, skip":                                                              "该级阶梯没有可用的题目，跳过",
+	"no changes since last cache update":                                                               "自上次更新缓存以来没有变化",
+	"no detached submissions":                                                                          "没有后台提交",
+	"no matches found":                                                                                 "没有找到匹配项",
+	"no note on LeetCode":                                                                              "LeetCode 上没有笔记",
+	"no notes found":                                                                                   "没有找到笔记",
+	"no pending submissions":                                                                           "没有等待中的提交",
+	"no solution changed since accepted":                                                               "没有自通过后修改过的代码",
+	"no solve time recorded yet, it's recorded when a question generated by leetgo is accepted": "还没有解题用时记录，由 leetgo 生成的题目通过时会记录",
+	"no time recorded":                          "没有用时记录",
+	"no unsolved questions to clean":            "没有需要清理的未解决题目",
+	"none editor is used, skip opening files":   "使用 none 编辑器，跳过打开文件",
+	"not signed in, your own rank is not shown": "未登录，不显示你的排名",
+	"note created":                              "笔记已创建",
+	"note pulled":                               "笔记已下载",
+	"note pushed":                               "笔记已上传",
+	"notes of older versions are not shown, run 'leetgo config migrate' to move them": "不显示旧版本的笔记，运行 'leetgo config migrate' 移动它们",
+	"offline mode, skipped downloading attachments":                                   "离线模式，已跳过下载附件",
+	"opening files":          "正在打开文件",
+	"picked":                 "已选择",
+	"pomodoro finished":      "番茄钟已结束",
+	"pomodoro stopped early": "番茄钟已提前停止",
+	"ratings imported":       "难度分已导入",
+	"ratings updated":        "难度分已更新",
+	"read LeetCode cookies":  "已读取 LeetCode cookies",
+	"registered":             "已报名",
+	"request panicked":       "请求处理崩溃",
+	"retry":                  "重试",
+	"run again with --resume to retry the rest": "使用 --resume 再次运行以重试剩余部分",
+	"running stress test":                       "正在运行对拍测试",
+	"running test locally":                      "正在本地运行测试",
+	"running test remotely":                     "正在远程运行测试",
+	"serving on stdio":                          "正在 stdio 上提供服务",
+	"serving":                                   "正在提供服务",
+	"session started":                           "计时已开始",
+	"session stopped":                           "计时已停止",
+	"set leetcode.credentials.from to keyring to use the saved credentials": "将 leetcode.credentials.from 设为 keyring 以使用保存的凭证",
+	"signature changed":                          "函数签名已变化",
+	"skip initializing workspace in safe mode":   "安全模式下跳过初始化工作区",
+	"skip updating go.work in safe mode":         "安全模式下跳过更新 go.work",
+	"skipped paid only question":                 "已跳过会员题目",
+	"skipped question":                           "已跳过题目",
+	"solution saved":                             "题解已保存",
+	"submission detached":                        "提交已转入后台",
+	"submitting solution":                        "正在提交代码",
+	"testcases.txt updated":                      "testcases.txt 已更新",
+	"unregistered":                               "已取消报名",
+	"updated":                                    "已更新",
+	"upgraded":                                   "已升级",
+	"virtual contest started":                    "模拟竞赛已开始",
+	"watch error":                                "监听出错",
+	"watching for changes, press Ctrl-C to exit": "正在监听变化，按 Ctrl-C 退出",
+	"would edit note":                            "将编辑笔记",
+	"would export":                               "将导出",
+	"would push note":                            "将上传笔记",
+	"would save solution":                        "将保存题解",
+	"would start virtual contest":                "将开始模拟竞赛",
+	"would write note":                           "将写入笔记",
+}
@@ -1,15 +1,18 @@
 package lang
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/dop251/goja"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
@@ -68,6 +71,21 @@ const (
 	OtherFile
 )
 
+func (t FileType) String() string {
+	switch t {
+	case CodeFile, CodeFile | TestFile:
+		return "code"
+	case TestFile:
+		return "test"
+	case TestCasesFile:
+		return "testcases"
+	case DocFile:
+		return "doc"
+	default:
+		return "other"
+	}
+}
+
 func (r *GenerateResult) AddFile(f FileOutput) *GenerateResult {
 	if r.mask&int(f.Type) != 0 {
 		panic(fmt.Sprintf("file type %d already exists", f.Type))
@@ -91,10 +109,81 @@ func (r *GenerateResult) SetOutDir(dir string) {
 	r.OutDir = dir
 }
 
+// bruteDir is the sub directory of a question to put the brute-force solution.
+const bruteDir = "brute"
+
+// Brute returns the paths of the brute-force solution, which lives in the brute/ sub directory of the question.
+func (r *GenerateResult) Brute() *GenerateResult {
+	brute := &GenerateResult{
+		Question: r.Question,
+		Lang:     r.Lang,
+		OutDir:   r.OutDir,
+		SubDir:   filepath.Join(r.SubDir, bruteDir),
+	}
+	for _, f := range r.Files {
+		if f.Type&(CodeFile|TestFile) != 0 {
+			brute.AddFile(FileOutput{Filename: f.Filename, Type: f.Type})
+		}
+	}
+	return brute
+}
+
+func (r *GenerateResult) IsBrute() bool {
+	return filepath.Base(r.SubDir) == bruteDir
+}
+
 func (r *GenerateResult) TargetDir() string {
 	return filepath.Join(r.OutDir, r.SubDir)
 }
 
+// QuestionConfigFile returns the path of the question config, named after the question if the
+// question has no directory of its own.
+func (r *GenerateResult) QuestionConfigFile() string {
+	return config.QuestionConfigFile(r.TargetDir(), r.Question.TitleSlug, r.SubDir == "")
+}
+
+// WorkspaceDir returns the directory of the workspace shared by questions of the language, which is OutDir or
+// one of its parents if out_dir is a template, see workspaceTemplate.
+func (r *GenerateResult) WorkspaceDir() string {
+	if r.Question == nil || r.Lang == nil {
+		return r.OutDir
+	}
+	dir, err := getWorkspaceDir(r.Question, r.Lang)
+	if err != nil {
+		return r.OutDir
+	}
+	// OutDir may be set to somewhere else, e.g. by SetOutDir.
+	rel, err := filepath.Rel(dir, r.OutDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return r.OutDir
+	}
+	return dir
+}
+
+// workspaceRelDir returns the directory of the question relative to WorkspaceDir, with forward slashes.
+func (r *GenerateResult) workspaceRelDir() string {
+	rel, err := filepath.Rel(r.WorkspaceDir(), r.TargetDir())
+	if err != nil {
+		return filepath.ToSlash(r.SubDir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// DropFromWorkspace removes the question from the workspace file that lists every question,
+// go.work of Go or Cargo.toml of Rust, before its files are removed or moved. It does nothing for other languages.
+func DropFromWorkspace(result *GenerateResult) error {
+	if result.SubDir == "" || result.Lang == nil {
+		return nil
+	}
+	switch result.Lang.Slug() {
+	case golangGen.Slug():
+		return dropGoModule(result)
+	case rustGen.Slug():
+		return dropBinSection(result)
+	}
+	return nil
+}
+
 // Lang is a basic generator for a language.
 type Lang interface {
 	// Name returns the full name of the language. e.g. "C++", "JavaScript", "Python"
@@ -111,10 +200,24 @@ type Lang interface {
 	GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error)
 }
 
+// TestOptions controls how local test cases are run.
+type TestOptions struct {
+	// TargetCase selects the cases to run, e.g. 1, 1-3, -1, 1-
+	TargetCase string
+	// FailFast stops running the remaining cases after the first failed one.
+	FailFast bool
+}
+
+// Buildable is an interface for languages whose code file is a standalone program.
+type Buildable interface {
+	// BuildProgram builds the code file of genResult, and returns the command to run it.
+	BuildProgram(q *leetcode.QuestionData, genResult *GenerateResult) ([]string, error)
+}
+
 // LocalTestable is an interface for languages that can run local test.
 type LocalTestable interface {
 	// RunLocalTest runs local test for the question.
-	RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error)
+	RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error)
 }
 
 func getCodeStringConfig(lang Lang, key string) string {
@@ -136,27 +239,104 @@ func getFilenameTemplate(q *leetcode.QuestionData, gen Lang) string {
 	return config.Get().Code.FilenameTemplate
 }
 
+// getOutDirTemplate returns the template of the output directory for the question.
+func getOutDirTemplate(q *leetcode.QuestionData, lang Lang) string {
+	return selectOutDirTemplate(config.Get(), q.IsContest(), getCodeStringConfig(lang, "out_dir"), lang.Slug())
+}
+
+// selectOutDirTemplate returns contest.out_dir for contest questions, otherwise `code.out_dir_template` takes
+// precedence over `out_dir` of the language, which defaults to the language slug.
+func selectOutDirTemplate(cfg *config.Config, isContest bool, langOutDir string, langSlug string) string {
+	if isContest {
+		return cfg.Contest.OutDir
+	}
+	if cfg.Code.OutDirTemplate != "" {
+		return cfg.Code.OutDirTemplate
+	}
+	if langOutDir != "" {
+		return langOutDir
+	}
+	return langSlug
+}
+
+// langOnlyAction matches template actions that render the same for all questions of a language, e.g. {{ .Lang }}.
+var langOnlyAction = regexp.MustCompile(`\{\{-?\s*\.Lang\s*(\|\s*\w+\s*)*-?}}`)
+
+// workspaceTemplate returns the leading segments of the out_dir template that render the same for all questions
+// of a language. The workspace of the language (go.mod, Cargo.toml, .venv...) is initialized there once,
+// rather than in every directory rendered for difficulties or tags.
+func workspaceTemplate(outDirTmpl string) string {
+	segments := strings.Split(filepath.ToSlash(outDirTmpl), "/")
+	n := 0
+	for _, seg := range segments {
+		if strings.Contains(langOnlyAction.ReplaceAllString(seg, ""), "{{") {
+			break
+		}
+		n++
+	}
+	return strings.Join(segments[:n], "/")
+}
+
 // getOutDir returns the absolute path of the output directory for the question.
-func getOutDir(q *leetcode.QuestionData, lang Lang) string {
-	cfg := config.Get()
-	if q.IsContest() {
-		return filepath.Join(cfg.ProjectRoot(), cfg.Contest.OutDir)
+// The `out_dir` config is a template that accepts the same attributes and functions as `filename_template`,
+// relative paths are resolved against the project root.
+func getOutDir(q *leetcode.QuestionData, lang Lang) (string, error) {
+	outDirTmpl := getOutDirTemplate(q, lang)
+	outDir, err := renderOutDir(q, lang, outDirTmpl, config.Get().ProjectRoot())
+	if err != nil {
+		return "", err
+	}
+	if outDir == "" {
+		return "", fmt.Errorf("out_dir template %q renders to an empty path", outDirTmpl)
+	}
+	return outDir, nil
+}
+
+// getWorkspaceDir returns the absolute path of the workspace directory of the question, see workspaceTemplate.
+// It's the project root if the out_dir template starts with question attributes.
+func getWorkspaceDir(q *leetcode.QuestionData, lang Lang) (string, error) {
+	projectRoot := config.Get().ProjectRoot()
+	dir, err := renderOutDir(q, lang, workspaceTemplate(getOutDirTemplate(q, lang)), projectRoot)
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		return filepath.Clean(projectRoot), nil
+	}
+	return dir, nil
+}
+
+// renderOutDir renders the out_dir template, ~ is expanded and relative paths are resolved against projectRoot.
+// It returns an empty path if the template renders to an empty string.
+func renderOutDir(q *leetcode.QuestionData, lang Lang, outDirTmpl string, projectRoot string) (string, error) {
+	// FirstTag is filled from the topic tags, which are queried with every question, no need to fetch more fields.
+	outDir, err := q.GetFormattedFilename(lang.Slug(), outDirTmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid out_dir template %q: %w", outDirTmpl, err)
 	}
-	outDir := getCodeStringConfig(lang, "out_dir")
-	// If outDir is not set, use the language slug as the outDir.
+	outDir = strings.TrimSpace(outDir)
 	if outDir == "" {
-		outDir = lang.Slug()
+		return "", nil
+	}
+	outDir, err = homedir.Expand(outDir)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(projectRoot, outDir)
 	}
-	outDir = filepath.Join(cfg.ProjectRoot(), outDir)
-	return outDir
+	return filepath.Clean(outDir), nil
 }
 
-func getTempBinFile(q *leetcode.QuestionData, lang Lang) (string, error) {
+func getTempBinFile(q *leetcode.QuestionData, lang Lang, genResult *GenerateResult) (string, error) {
 	tmpDir := config.Get().TempDir()
 	if err := utils.CreateIfNotExists(tmpDir, true); err != nil {
 		return "", err
 	}
 	filename := fmt.Sprintf("%s-%s.exec", q.TitleSlug, lang.Slug())
+	if genResult.IsBrute() {
+		filename = fmt.Sprintf("%s-%s-brute.exec", q.TitleSlug, lang.Slug())
+	}
 	return filepath.Join(tmpDir, filename), nil
 }
 
@@ -183,11 +363,21 @@ const codeContentTemplate = `
 {{ if not .SeparateDescriptionFile }}
 {{ block "description" . -}}
 {{ .BlockCommentStart }}
-{{ block "title" . }}{{ .Question.QuestionFrontendId }}. {{ .Question.GetTitle }} ({{ .Question.Difficulty }}){{ end }}
-{{ .Question.GetFormattedContent }}
+{{ block "title" . }}{{ .Question.QuestionFrontendId }}. {{ .Question.GetCommentTitle }} ({{ .Question.Difficulty }}){{ end }}
+{{ .Question.GetCommentContent }}
+{{- with .Question.GetCompanyTags }}
+Companies: {{ . }}
+{{ end }}
 {{ .BlockCommentEnd }}
 {{ end }}
 {{ end }}
+{{ if .Schema }}
+{{ block "schema" . -}}
+{{ .BlockCommentStart }}
+{{ range .Schema }}{{ . }}
+{{ end }}{{ .BlockCommentEnd }}
+{{ end }}
+{{ end }}
 {{ block "beforeBeforeMarker" . }}{{ end }}
 {{ block "beforeMarker" . }}{{ end }}
 {{ .LineComment }} {{ .CodeBeginMarker }}
@@ -212,6 +402,15 @@ type codeContentData struct {
 	SeparateDescriptionFile bool
 	NeedsDefinition         bool
 	Version                 string
+	// Sections of the question description as plain text, for comments in blocks.
+	Constraints []string
+	Hints       []string
+	FollowUp    string
+	// Schema is the statements creating the tables of database questions.
+	Schema []string
+	// NoteFile is the path of the note of the question relative to the project root, Note is its content if it exists.
+	NoteFile string
+	Note     string
 }
 
 const (
@@ -219,10 +418,23 @@ const (
 	afterAfterMarker   = "afterAfterMarker"
 )
 
+// questionNote returns the path of the note of the question relative to the project root, and its content.
+func questionNote(q *leetcode.QuestionData) (string, string) {
+	cfg := config.Get()
+	file := cfg.NoteFile(q.TitleSlug)
+	rel, err := filepath.Rel(cfg.ProjectRoot(), file)
+	if err != nil {
+		rel = file
+	}
+	content, _ := os.ReadFile(file)
+	return filepath.ToSlash(rel), strings.TrimSpace(string(content))
+}
+
 var validBlocks = map[string]bool{
 	"header":       true,
 	"description":  true,
 	"title":        true,
+	"schema":       true,
 	"beforeMarker": true,
 	"beforeCode":   true,
 	"code":         true,
@@ -239,6 +451,14 @@ var builtinModifiers = map[string]ModifierFunc{
 
 type ModifierFunc = func(string, *leetcode.QuestionData) string
 
+// argsModifiers are builtin modifiers that take arguments from the `args` field of the modifier config.
+var argsModifiers = map[string]func(lang Lang, args []string) (ModifierFunc, error){
+	"removeComments": newRemoveComments,
+	"renameClass":    newRenameClass,
+	"addImports":     newAddImports,
+	"replace":        newReplace,
+}
+
 func getBlocks(lang Lang) (ans []config.Block) {
 	blocks := viper.Get("code." + lang.Slug() + ".blocks")
 	if blocks == nil || len(blocks.([]any)) == 0 {
@@ -284,6 +504,20 @@ func getModifiers(lang Lang, modifiersMap map[string]ModifierFunc) ([]ModifierFu
 				funcs = append(funcs, f)
 				continue
 			}
+			if newFn, ok := argsModifiers[name]; ok {
+				var args []string
+				if rawArgs, ok := m["args"].([]any); ok {
+					for _, a := range rawArgs {
+						args = append(args, fmt.Sprint(a))
+					}
+				}
+				f, err := newFn(lang, args)
+				if err != nil {
+					return nil, fmt.Errorf("modifier %s: %w", name, err)
+				}
+				funcs = append(funcs, f)
+				continue
+			}
 		}
 		if m["script"] != nil {
 			script = m["script"].(string)
@@ -341,6 +575,152 @@ func removeUselessComments(code string, q *leetcode.QuestionData) string {
 	return strings.Join(newLines, "\n")
 }
 
+// commenter is implemented by languages built on baseLang.
+type commenter interface {
+	commentSyntax() (line, blockStart, blockEnd string)
+}
+
+// newRemoveComments removes all comment lines, including the ones containing useful definitions.
+func newRemoveComments(lang Lang, _ []string) (ModifierFunc, error) {
+	c, ok := lang.(commenter)
+	if !ok {
+		return nil, fmt.Errorf("not supported for %s", lang.Slug())
+	}
+	lineComment, blockStart, blockEnd := c.commentSyntax()
+	return func(code string, _ *leetcode.QuestionData) string {
+		return removeComments(code, lineComment, blockStart, blockEnd)
+	}, nil
+}
+
+// removeComments removes line comments and block comments at the beginning of lines.
+// Code that follows a block comment on the same line is kept.
+func removeComments(code, lineComment, blockStart, blockEnd string) string {
+	var newLines []string
+	inBlock := false
+	for _, line := range strings.Split(code, "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		rest := line
+		if inBlock {
+			i := strings.Index(rest, blockEnd)
+			if i < 0 {
+				continue
+			}
+			inBlock = false
+			indent = ""
+			rest = rest[i+len(blockEnd):]
+		}
+		rest = strings.TrimSpace(rest)
+		for blockStart != "" && strings.HasPrefix(rest, blockStart) {
+			i := strings.Index(rest[len(blockStart):], blockEnd)
+			if i < 0 {
+				inBlock = true
+				rest = ""
+				break
+			}
+			rest = strings.TrimSpace(rest[len(blockStart)+i+len(blockEnd):])
+		}
+		if lineComment != "" && strings.HasPrefix(rest, lineComment) {
+			rest = ""
+		}
+		switch {
+		case rest != "":
+			newLines = append(newLines, indent+rest)
+		case strings.TrimSpace(line) == "":
+			newLines = append(newLines, line)
+		}
+	}
+	return strings.Join(newLines, "\n")
+}
+
+// newRenameClass renames the Solution class, args are [NewName] or [OldName, NewName].
+// The local test harness still refers to the original class name, so an alias of it is
+// appended for languages that support local testing.
+func newRenameClass(lang Lang, args []string) (ModifierFunc, error) {
+	oldName, newName := "Solution", ""
+	switch len(args) {
+	case 1:
+		newName = args[0]
+	case 2:
+		oldName, newName = args[0], args[1]
+	default:
+		return nil, errors.New("expected args [NewName] or [OldName, NewName]")
+	}
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(oldName) + `\b`)
+	return func(code string, q *leetcode.QuestionData) string {
+		code = re.ReplaceAllLiteralString(code, newName)
+		if alias := classAlias(lang, q, oldName, newName); alias != "" {
+			code = strings.TrimRight(code, "\n") + "\n\n" + alias + "\n"
+		}
+		return code
+	}, nil
+}
+
+// classAlias returns the statement that keeps oldName usable by the test harness after renaming it to newName.
+func classAlias(lang Lang, q *leetcode.QuestionData, oldName, newName string) string {
+	switch lang.Slug() {
+	case cppGen.Slug():
+		return fmt.Sprintf("using %s = %s;", oldName, newName)
+	case python3Gen.Slug():
+		return fmt.Sprintf("%s = %s", oldName, newName)
+	case rustGen.Slug():
+		// The Solution struct is declared by the code header, outside the snippet.
+		if oldName == "Solution" && !q.MetaData.SystemDesign {
+			return fmt.Sprintf("type %s = %s;", newName, oldName)
+		}
+		return fmt.Sprintf("type %s = %s;", oldName, newName)
+	}
+	return ""
+}
+
+// newAddImports prepends import statements of the packages in args.
+func newAddImports(lang Lang, args []string) (ModifierFunc, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no packages to import")
+	}
+	var format string
+	switch lang.Slug() {
+	case "golang":
+		format = `import "%s"`
+	case "python3", "kotlin", "scala", "swift":
+		format = "import %s"
+	case "java":
+		format = "import %s;"
+	case "cpp", "c":
+		format = "#include <%s>"
+	case "rust", "php":
+		format = "use %s;"
+	case "csharp":
+		format = "using %s;"
+	case "javascript", "typescript":
+		format = `const %[1]s = require("%[1]s");`
+	default:
+		return nil, fmt.Errorf("not supported for %s", lang.Slug())
+	}
+	var imports strings.Builder
+	for _, pkg := range args {
+		imports.WriteString(fmt.Sprintf(format, pkg))
+		imports.WriteString("\n")
+	}
+	return func(code string, _ *leetcode.QuestionData) string {
+		return imports.String() + "\n" + code
+	}, nil
+}
+
+// newReplace replaces all matches of a regular expression, args are [pattern, replacement].
+// The replacement can refer to submatches with $1, ${name}.
+func newReplace(_ Lang, args []string) (ModifierFunc, error) {
+	if len(args) != 2 {
+		return nil, errors.New("expected args [pattern, replacement]")
+	}
+	re, err := regexp.Compile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return func(code string, _ *leetcode.QuestionData) string {
+		return re.ReplaceAllString(code, args[1])
+	}, nil
+}
+
 type baseLang struct {
 	name              string
 	slug              string
@@ -351,6 +731,14 @@ type baseLang struct {
 	blockCommentEnd   string
 }
 
+func (l baseLang) commentSyntax() (string, string, string) {
+	return l.lineComment, l.blockCommentStart, l.blockCommentEnd
+}
+
+func (l baseLang) codeExtension() string {
+	return l.extension
+}
+
 func (l baseLang) Name() string {
 	return l.name
 }
@@ -385,6 +773,7 @@ func (l baseLang) generateCodeContent(
 			},
 		},
 	)
+	defer utils.Track(utils.ProfileTemplate)()
 	_, err := tmpl.Parse(codeContentTemplate)
 	if err != nil {
 		return "", err
@@ -413,7 +802,12 @@ func (l baseLang) generateCodeContent(
 		SeparateDescriptionFile: separateDescriptionFile,
 		NeedsDefinition:         needsDefinition(code),
 		Version:                 fmt.Sprintf("%s: %s", constants.CmdName, constants.Version),
+		Constraints:             q.GetConstraints(),
+		Hints:                   q.GetHints(),
+		FollowUp:                q.GetFollowUp(),
+		Schema:                  q.GetSchema(l.slug),
 	}
+	data.NoteFile, data.Note = questionNote(q)
 	var buf strings.Builder
 	err = tmpl.Execute(&buf, data)
 	if err != nil {
@@ -0,0 +1,237 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestSelectOutDirTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		tmpl       string
+		isContest  bool
+		langOutDir string
+		want       string
+	}{
+		{"default", "", false, "", "golang"},
+		{"language out_dir", "", false, "go", "go"},
+		{"out_dir_template", "{{ .Lang }}/{{ .Difficulty }}", false, "go", "{{ .Lang }}/{{ .Difficulty }}"},
+		{"contest", "{{ .Lang }}/{{ .Difficulty }}", true, "go", "contest"},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{
+			Code:    config.CodeConfig{OutDirTemplate: tt.tmpl},
+			Contest: config.ContestConfig{OutDir: "contest"},
+		}
+		if got := selectOutDirTemplate(cfg, tt.isContest, tt.langOutDir, "golang"); got != tt.want {
+			t.Errorf("%s: selectOutDirTemplate() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWorkspaceTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"go", "go"},
+		{"go/{{ .Difficulty }}", "go"},
+		{"solutions/{{ .Lang }}/{{ .FirstTag }}", "solutions/{{ .Lang }}"},
+		{"{{ .Lang | upper }}/{{ .Difficulty }}", "{{ .Lang | upper }}"},
+		{"{{ .Difficulty }}/go", ""},
+		{"go-{{ .Difficulty }}", ""},
+		{"~/leetcode/go", "~/leetcode/go"},
+		{"/abs/go/{{ .Difficulty }}", "/abs/go"},
+	}
+	for _, tt := range tests {
+		if got := workspaceTemplate(tt.tmpl); got != tt.want {
+			t.Errorf("workspaceTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestRenderOutDir(t *testing.T) {
+	root := t.TempDir()
+	home, err := homedir.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs := filepath.Join(t.TempDir(), "solutions")
+	q := &leetcode.QuestionData{TitleSlug: "two-sum", Difficulty: "Easy"}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"go", filepath.Join(root, "go")},
+		{"{{ .Lang }}/{{ lower .Difficulty }}", filepath.Join(root, "golang", "easy")},
+		{"{{ .Lang }}/{{ .FirstTag }}/", filepath.Join(root, "golang", "others")},
+		{" ../go ", filepath.Join(filepath.Dir(root), "go")},
+		{abs + "/{{ .Difficulty }}", filepath.Join(abs, "Easy")},
+		{"~/leetcode", filepath.Join(home, "leetcode")},
+		{"{{ if .IsContest }}contest{{ end }}", ""},
+	}
+	for _, tt := range tests {
+		got, err := renderOutDir(q, golangGen, tt.tmpl, root)
+		if err != nil || got != tt.want {
+			t.Errorf("renderOutDir(%q) = %q, %v, want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+
+	if _, err := renderOutDir(q, golangGen, "{{ .Lang ", root); err == nil {
+		t.Errorf("renderOutDir() with an invalid template should fail")
+	}
+}
+
+func TestWorkspaceDir(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("code.golang.out_dir", filepath.ToSlash(dir)+"/go/{{ lower .Difficulty }}")
+	t.Cleanup(func() { viper.Set("code.golang.out_dir", "") })
+
+	q := &leetcode.QuestionData{TitleSlug: "two-sum", Difficulty: "Easy"}
+	outDir, err := getOutDir(q, golangGen)
+	if err != nil || outDir != filepath.Join(dir, "go", "easy") {
+		t.Fatalf("getOutDir() = %q, %v", outDir, err)
+	}
+	result := &GenerateResult{Question: q, Lang: golangGen, OutDir: outDir, SubDir: "0001.two-sum"}
+	if got := result.WorkspaceDir(); got != filepath.Join(dir, "go") {
+		t.Errorf("WorkspaceDir() = %q, want %q", got, filepath.Join(dir, "go"))
+	}
+	if got := result.workspaceRelDir(); got != "easy/0001.two-sum" {
+		t.Errorf("workspaceRelDir() = %q, want %q", got, "easy/0001.two-sum")
+	}
+
+	// Questions of all difficulties are dropped from the single go.work of the workspace.
+	goWork := filepath.Join(dir, "go", "go.work")
+	content := "go 1.21\n\nuse (\n\t.\n\t./easy/0001.two-sum\n\t./medium/0002.add-two-numbers\n)\n"
+	if err := os.MkdirAll(filepath.Dir(goWork), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(goWork, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := DropFromWorkspace(result); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(goWork)
+	if got := string(data); strings.Contains(got, "two-sum") || !strings.Contains(got, "./medium/0002.add-two-numbers") {
+		t.Errorf("go.work after drop:\n%s", got)
+	}
+
+	// OutDir set outside the workspace is its own workspace.
+	result.OutDir = t.TempDir()
+	if got := result.WorkspaceDir(); got != result.OutDir {
+		t.Errorf("WorkspaceDir() = %q, want OutDir %q", got, result.OutDir)
+	}
+}
+
+func TestQuestionNote(t *testing.T) {
+	wd, _ := os.Getwd()
+	root := t.TempDir()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	q := &leetcode.QuestionData{TitleSlug: "two-sum"}
+	file, note := questionNote(q)
+	if file != "notes/cn/two-sum.md" && file != "notes/us/two-sum.md" {
+		t.Errorf("questionNote() file = %q, want it in the notes of the site", file)
+	}
+	if note != "" {
+		t.Errorf("questionNote() of a missing note = %q", note)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, filepath.Dir(file)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, file), []byte("\nuse a map\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, note = questionNote(q); note != "use a map" {
+		t.Errorf("questionNote() = %q, want the trimmed note", note)
+	}
+}
+
+func TestRemoveComments(t *testing.T) {
+	cases := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"line comments", "// doc\nint a; // keep\n  // indented\nint b;", "int a; // keep\nint b;"},
+		{"block comment", "/**\n * Definition\n */\nclass Solution {};", "class Solution {};"},
+		{"code after block comment", "  /* a */ int a;\n/* b\n c */ int b;", "  int a;\nint b;"},
+		{"several block comments", "/* a */ /* b */ int a;", "int a;"},
+		{"blank lines kept", "int a;\n\n/* a */\nint b;", "int a;\n\nint b;"},
+	}
+	for _, c := range cases {
+		if got := removeComments(c.code, "//", "/*", "*/"); got != c.want {
+			t.Errorf("%s: removeComments() = %q, want %q", c.name, got, c.want)
+		}
+	}
+	code := "class Solution:\n    \"\"\"doc\"\"\" \n    def f(self):\n        # todo\n        pass"
+	if got, want := removeComments(code, "#", `"""`, `"""`), "class Solution:\n    def f(self):\n        pass"; got != want {
+		t.Errorf("removeComments() of python = %q, want %q", got, want)
+	}
+}
+
+func TestRenameClass(t *testing.T) {
+	q := &leetcode.QuestionData{}
+	cases := []struct {
+		lang Lang
+		args []string
+		code string
+		want string
+	}{
+		{cppGen, []string{"Sol"}, "class Solution {\n};\n", "class Sol {\n};\n\nusing Solution = Sol;\n"},
+		{python3Gen, []string{"Sol"}, "class Solution:\n    pass", "class Sol:\n    pass\n\nSolution = Sol\n"},
+		{rustGen, []string{"Sol"}, "impl Solution {}", "impl Sol {}\n\ntype Sol = Solution;\n"},
+		{rustGen, []string{"LRUCache", "Cache"}, "struct LRUCache {}", "struct Cache {}\n\ntype LRUCache = Cache;\n"},
+		// Languages without local testing need no alias.
+		{javaGen, []string{"Sol"}, "class Solution {} // SolutionX", "class Sol {} // SolutionX"},
+	}
+	for _, c := range cases {
+		f, err := newRenameClass(c.lang, c.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f(c.code, q); got != c.want {
+			t.Errorf("renameClass(%v) for %s = %q, want %q", c.args, c.lang.Slug(), got, c.want)
+		}
+	}
+	if _, err := newRenameClass(cppGen, nil); err == nil {
+		t.Errorf("newRenameClass() without args should fail")
+	}
+}
+
+func TestAddImportsAndReplace(t *testing.T) {
+	f, err := newAddImports(python3Gen, []string{"bisect", "heapq"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f("class Solution: pass", nil), "import bisect\nimport heapq\n\nclass Solution: pass"; got != want {
+		t.Errorf("addImports() = %q, want %q", got, want)
+	}
+	if _, err := newAddImports(bashGen, []string{"x"}); err == nil {
+		t.Errorf("newAddImports() for bash should fail")
+	}
+
+	f, err = newReplace(python3Gen, []string{`List\[(\w+)\]`, "list[$1]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f("def f(a: List[int]) -> List[str]:", nil), "def f(a: list[int]) -> list[str]:"; got != want {
+		t.Errorf("replace() = %q, want %q", got, want)
+	}
+	if _, err := newReplace(python3Gen, []string{"(", "x"}); err == nil {
+		t.Errorf("newReplace() with an invalid pattern should fail")
+	}
+}
@@ -317,20 +317,15 @@ using namespace std;
 	}, nil
 }
 
-func (c cpp) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
-	genResult, err := c.GeneratePaths(q)
-	if err != nil {
-		return false, fmt.Errorf("generate paths failed: %w", err)
-	}
-	genResult.SetOutDir(outDir)
-
+func (c cpp) BuildProgram(q *leetcode.QuestionData, genResult *GenerateResult) ([]string, error) {
+	outDir := genResult.WorkspaceDir()
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
-	execFile, err := getTempBinFile(q, c)
+	execFile, err := getTempBinFile(q, c, genResult)
 	if err != nil {
-		return false, fmt.Errorf("generate temporary binary file path failed: %w", err)
+		return nil, fmt.Errorf("generate temporary binary file path failed: %w", err)
 	}
 
 	cfg := config.Get()
@@ -341,10 +336,23 @@ func (c cpp) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase st
 
 	err = buildTest(q, genResult, args)
 	if err != nil {
-		return false, fmt.Errorf("compilation failed: %w", err)
+		return nil, fmt.Errorf("compilation failed: %w", err)
+	}
+	return []string{execFile}, nil
+}
+
+func (c cpp) RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error) {
+	genResult, err := c.GeneratePaths(q)
+	if err != nil {
+		return false, fmt.Errorf("generate paths failed: %w", err)
 	}
+	genResult.SetOutDir(outDir)
 
-	return runTest(q, genResult, []string{execFile}, targetCase)
+	args, err := c.BuildProgram(q, genResult)
+	if err != nil {
+		return false, err
+	}
+	return runTest(q, genResult, args, opts)
 }
 
 func (c cpp) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
@@ -0,0 +1,110 @@
+package lang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Code markers are matched tolerating edits of spacing and case, e.g. "@lc code = Begin".
+// A marker is a line of its own, with only comment punctuation around it, so that markers in strings don't count.
+// Older versions began code with "@lc code=start".
+var (
+	codeBeginRe       = regexp.MustCompile(`(?i)^\s*[^\w\s]*\s*@lc\s+code\s*=\s*begin\s*[^\w\s]*\s*$`)
+	legacyCodeBeginRe = regexp.MustCompile(`(?i)^\s*[^\w\s]*\s*@lc\s+code\s*=\s*start\s*[^\w\s]*\s*$`)
+	codeEndRe         = regexp.MustCompile(`(?i)^\s*[^\w\s]*\s*@lc\s+code\s*=\s*end\s*[^\w\s]*\s*$`)
+)
+
+func isCodeBegin(line string) bool {
+	return codeBeginRe.MatchString(line) || legacyCodeBeginRe.MatchString(line)
+}
+
+// ExtractCode extracts the solution code from a code file. The code is the lines between the code markers,
+// code of multiple marked blocks is joined, and a block missing the end marker extends to the end of the file.
+// If there is no begin marker, the code is the whole file without the header comment.
+func ExtractCode(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	code, ok := extractCodeBlocks(string(content))
+	if !ok {
+		code = stripHeaderComment(string(content), commentSyntaxOf(path))
+	}
+	if strings.TrimSpace(code) == "" {
+		return "", fmt.Errorf("no code found in %s", path)
+	}
+	return code, nil
+}
+
+// extractCodeBlocks returns the lines of all marked code blocks, it reports whether any begin marker is found.
+func extractCodeBlocks(content string) (string, bool) {
+	var (
+		blocks  []string
+		block   []string
+		inBlock bool
+		found   bool
+	)
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case isCodeBegin(line):
+			// A repeated begin marker is ignored, the block goes on.
+			inBlock, found = true, true
+		case codeEndRe.MatchString(line):
+			// An end marker without begin marker is ignored.
+			if inBlock {
+				blocks = append(blocks, strings.Join(block, "\n"))
+				block, inBlock = nil, false
+			}
+		case inBlock:
+			block = append(block, line)
+		}
+	}
+	if inBlock {
+		blocks = append(blocks, strings.TrimRight(strings.Join(block, "\n"), "\n"))
+	}
+	return strings.Join(blocks, "\n\n"), found
+}
+
+type commentSyntax struct {
+	line, blockStart, blockEnd string
+}
+
+// commentSyntaxOf returns the comment syntax of the language of the file by its extension,
+// the zero value if the language is unknown.
+func commentSyntaxOf(path string) commentSyntax {
+	ext := filepath.Ext(path)
+	for _, l := range AllLangs() {
+		c, ok := l.(interface {
+			commenter
+			codeExtension() string
+		})
+		if ok && c.codeExtension() == ext {
+			line, blockStart, blockEnd := c.commentSyntax()
+			return commentSyntax{line, blockStart, blockEnd}
+		}
+	}
+	return commentSyntax{}
+}
+
+// stripHeaderComment removes the blank and comment lines at the beginning of the content.
+func stripHeaderComment(content string, syntax commentSyntax) string {
+	lines := strings.Split(content, "\n")
+	inBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			inBlock = !strings.HasSuffix(trimmed, syntax.blockEnd)
+		case trimmed == "":
+		case syntax.blockStart != "" && strings.HasPrefix(trimmed, syntax.blockStart):
+			inBlock = !strings.HasSuffix(trimmed[len(syntax.blockStart):], syntax.blockEnd)
+		case syntax.line != "" && strings.HasPrefix(trimmed, syntax.line):
+		default:
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+	return ""
+}
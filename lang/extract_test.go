@@ -0,0 +1,85 @@
+package lang
+
+import "testing"
+
+func TestExtractCodeBlocks(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+		found   bool
+	}{
+		{
+			name:    "marked",
+			content: "// header\n// @lc code=begin\nfunc a() {}\n// @lc code=end\n",
+			want:    "func a() {}",
+			found:   true,
+		},
+		{
+			name:    "edited markers",
+			content: "#  @LC code = Begin\ndef a(): pass\n# @lc  code=END",
+			want:    "def a(): pass",
+			found:   true,
+		},
+		{
+			name:    "multiple blocks",
+			content: "// @lc code=begin\ntype T int\n// @lc code=end\n\nfunc main() {}\n// @lc code=begin\nfunc a() {}\n// @lc code=end\n",
+			want:    "type T int\n\nfunc a() {}",
+			found:   true,
+		},
+		{
+			name:    "missing end marker",
+			content: "// @lc code=end\n// @lc code=begin\nfunc a() {}\n\n",
+			want:    "func a() {}",
+			found:   true,
+		},
+		{
+			name:    "markers in code",
+			content: "s := \"// @lc code=begin\"\n// @lc code=begin\nfunc a() string { return \"@lc code=end\" }\n// @lc code=end\n",
+			want:    "func a() string { return \"@lc code=end\" }",
+			found:   true,
+		},
+		{
+			name:    "block comment markers",
+			content: "/* @lc code=begin */\nfunc a() {}\n<!-- @lc code=end -->\n",
+			want:    "func a() {}",
+			found:   true,
+		},
+		{
+			name:    "legacy markers",
+			content: "// @lc code=start\nfunc a() {}\n// @lc code=end\n",
+			want:    "func a() {}",
+			found:   true,
+		},
+		{
+			name:    "no markers",
+			content: "func a() {}\n",
+			found:   false,
+		},
+	}
+	for _, c := range cases {
+		got, found := extractCodeBlocks(c.content)
+		if got != c.want || found != c.found {
+			t.Errorf("%s: got %q, %v, want %q, %v", c.name, got, found, c.want, c.found)
+		}
+	}
+}
+
+func TestStripHeaderComment(t *testing.T) {
+	content := "// Created by me\n\n/*\n1. Two Sum\n*/\n\nfunc a() {}\n// done\n"
+	want := "func a() {}\n// done\n"
+	if got := stripHeaderComment(content, commentSyntaxOf("solution.go")); got != want {
+		t.Errorf("go: got %q, want %q", got, want)
+	}
+
+	content = "# Created by me\n\"\"\"\n1. Two Sum\n\"\"\"\nclass Solution: pass\n"
+	want = "class Solution: pass\n"
+	if got := stripHeaderComment(content, commentSyntaxOf("solution.py")); got != want {
+		t.Errorf("python: got %q, want %q", got, want)
+	}
+
+	content = "# not a comment\n"
+	if got := stripHeaderComment(content, commentSyntaxOf("solution.unknown")); got != content {
+		t.Errorf("unknown: got %q, want %q", got, content)
+	}
+}
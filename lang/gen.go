@@ -4,34 +4,37 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/charmbracelet/log"
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
 	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
-	"github.com/j178/leetgo/constants"
 	"github.com/j178/leetgo/leetcode"
 	"github.com/j178/leetgo/utils"
 )
 
 // GetGenerator returns the generator for the given language. If the language is not supported, an error will be returned.
-// The language can be specified by slug, short name or prefix of full name.
+// The language can be specified by slug, short name or prefix of full name, languages of plugins are included.
 func GetGenerator(lang string) (Lang, error) {
 	lang = strings.ToLower(lang)
-	for _, l := range SupportedLangs {
+	langs := AllLangs()
+	for _, l := range langs {
 		if l.Slug() == lang {
 			return l, nil
 		}
 	}
-	for _, l := range SupportedLangs {
+	for _, l := range langs {
 		if l.ShortName() == lang {
 			return l, nil
 		}
 	}
-	for _, l := range SupportedLangs {
+	for _, l := range langs {
 		if strings.HasPrefix(strings.ToLower(l.Name()), lang) {
 			return l, nil
 		}
@@ -39,20 +42,68 @@ func GetGenerator(lang string) (Lang, error) {
 	return nil, fmt.Errorf("language %s is not supported yet, welcome to send a PR", lang)
 }
 
-func generate(q *leetcode.QuestionData) (Lang, *GenerateResult, error) {
+// databaseLangs are the languages database questions can be solved in.
+var databaseLangs = []string{mysqlGen.slug, mssqlGen.slug, oraclesqlGen.slug, pythondataGen.slug}
+
+// GeneratorFor returns the generator to use for the question instead of gen.
+// Database and shell questions have no code snippets in most languages, they are generated in
+// mysql (or pythondata if gen is python) and bash instead.
+func GeneratorFor(q *leetcode.QuestionData, gen Lang) Lang {
+	switch q.CategoryTitle {
+	case leetcode.CategoryDatabase:
+		if slices.Contains(databaseLangs, gen.Slug()) {
+			return gen
+		}
+		if gen.Slug() == python3Gen.slug {
+			return pythondataGen
+		}
+		return mysqlGen
+	case leetcode.CategoryShell:
+		return bashGen
+	}
+	return gen
+}
+
+// paidOnlyError explains why a question cannot be generated, LeetCode returns the content of
+// paid only questions to signed-in Premium subscribers only.
+func paidOnlyError(q *leetcode.QuestionData) error {
+	return fmt.Errorf("cannot generate %q: %w", q.TitleSlug, leetcode.ErrPaidOnlyQuestion)
+}
+
+// ExistingFiles decides what to do with generated files that already exist.
+type ExistingFiles int
+
+const (
+	// ExistingFilesFromFlags follows the --force, --skip-existing and --yes flags, and asks otherwise.
+	ExistingFilesFromFlags ExistingFiles = iota
+	// ExistingFilesOverwrite overwrites existing files.
+	ExistingFilesOverwrite
+	// ExistingFilesSkip keeps existing files.
+	ExistingFilesSkip
+)
+
+func generate(q *leetcode.QuestionData, existing ExistingFiles) (Lang, *GenerateResult, error) {
 	cfg := config.Get()
 	gen, err := GetGenerator(cfg.Code.Lang)
 	if err != nil {
 		return nil, nil, err
 	}
+	gen = GeneratorFor(q, gen)
 
 	err = q.Fulfill()
+	if errors.Is(err, leetcode.ErrPaidOnlyQuestion) {
+		return nil, nil, paidOnlyError(q)
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get question data: %w", err)
 	}
 
 	codeSnippet := q.GetCodeSnippet(gen.Slug())
-	if codeSnippet == "" {
+	if codeSnippet == "" && q.IsPaidOnly && len(q.CodeSnippets) == 0 {
+		return nil, nil, paidOnlyError(q)
+	}
+	// Plugins may support languages LeetCode does not, they generate code from snippets of other languages.
+	if codeSnippet == "" && !isPlugin(gen) {
 		if len(q.CodeSnippets) <= 3 {
 			langs := make([]string, 0, len(q.CodeSnippets))
 			for _, snippet := range q.CodeSnippets {
@@ -68,13 +119,31 @@ func generate(q *leetcode.QuestionData) (Lang, *GenerateResult, error) {
 		return nil, nil, fmt.Errorf(`question %q doesn't support language %q`, q.TitleSlug, cfg.Code.Lang)
 	}
 
-	outDir := getOutDir(q, gen)
-	err = utils.CreateIfNotExists(outDir, true)
+	outDir, err := getOutDir(q, gen)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = gen.InitWorkspace(outDir)
+	if DryRun() {
+		result, err := gen.Generate(q)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SetOutDir(outDir)
+		printDryRun(result)
+		return gen, result, nil
+	}
+
+	err = utils.MakeDir(outDir)
+	if err != nil {
+		return nil, nil, outDirError(outDir, err)
+	}
+
+	workspaceDir, err := getWorkspaceDir(q, gen)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = gen.InitWorkspace(workspaceDir)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -95,33 +164,127 @@ func generate(q *leetcode.QuestionData) (Lang, *GenerateResult, error) {
 
 	// Write files
 	for i, file := range result.Files {
-		written, err := tryWrite(file.GetPath(), file.Content)
+		written, err := tryWrite(file.GetPath(), file.Content, existing)
 		if errors.Is(err, terminal.InterruptErr) {
 			return nil, nil, err
 		}
+		// Other files can't be written either.
+		if utils.IsPermissionError(err) {
+			return nil, nil, outDirError(outDir, err)
+		}
 		if err != nil {
 			log.Error("failed to write file", "path", utils.RelToCwd(file.GetPath()), "err", err)
 			continue
 		}
 		result.Files[i].Written = written
+		if written {
+			config.EmitEvent(
+				config.Event{
+					Type:       config.EventFileCreated,
+					Slug:       q.TitleSlug,
+					FrontendID: q.QuestionFrontendId,
+					Lang:       gen.Slug(),
+					File:       file.GetPath(),
+				},
+			)
+		}
 	}
+
+	downloadAttachments(q, result)
 	return gen, result, nil
 }
 
+// outDirError tells how to fix err if the output directory is not writable.
+func outDirError(outDir string, err error) error {
+	if !utils.IsPermissionError(err) {
+		return err
+	}
+	return fmt.Errorf(
+		"output directory %s is not writable: %w, set code.out_dir or code.out_dir_template to a writable directory",
+		outDir,
+		err,
+	)
+}
+
+// downloadAttachments saves the data files linked in the question into the question directory,
+// so that they are available to local tests.
+// Nothing is downloaded in low bandwidth or offline mode.
+func downloadAttachments(q *leetcode.QuestionData, result *GenerateResult) {
+	attachments := q.Attachments()
+	if len(attachments) > 0 && config.Get().LowBandwidth {
+		log.Info("low bandwidth mode, skipped downloading attachments", "count", len(attachments))
+		return
+	}
+	if len(attachments) > 0 && config.Offline() {
+		log.Info("offline mode, skipped downloading attachments", "count", len(attachments))
+		return
+	}
+	for _, a := range attachments {
+		file, err := a.Download(result.TargetDir())
+		if err != nil {
+			log.Warn("failed to download attachment", "url", a.URL, "err", err)
+			continue
+		}
+		log.Info("downloaded", "file", utils.RelToCwd(file))
+	}
+}
+
+// DryRun reports whether generation should only print the files, without writing anything.
+func DryRun() bool {
+	return viper.GetBool("dry-run")
+}
+
+// printDryRun prints the paths of the would-be files, with a unified diff against the existing ones.
+func printDryRun(result *GenerateResult) {
+	for _, f := range result.Files {
+		printFileDiff(f.GetPath(), f.Content)
+	}
+}
+
+// printFileDiff prints how writing content to path would change the file.
+func printFileDiff(path string, content string) {
+	relPath := utils.RelToCwd(path)
+	old, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(config.HumanOutput(), "%s %s\n", config.PassedStyle.Render("create"), relPath)
+		return
+	}
+	if string(old) == content {
+		fmt.Fprintf(config.HumanOutput(), "%s %s\n", config.SkippedStyle.Render("unchanged"), relPath)
+		return
+	}
+	fmt.Fprintf(config.HumanOutput(), "%s %s\n", config.FailedStyle.Render("overwrite"), relPath)
+	edits := myers.ComputeEdits("", string(old), content)
+	fmt.Fprint(config.HumanOutput(), gotextdiff.ToUnified("a/"+relPath, "b/"+relPath, string(old), edits))
+}
+
 // Generate generates the code for the given question.
 func Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
-	gen, result, err := generate(q)
+	return GenerateWith(q, ExistingFilesFromFlags)
+}
+
+// GenerateWith generates the code for the given question, existing files are handled as told
+// rather than by the global flags.
+func GenerateWith(q *leetcode.QuestionData, existing ExistingFiles) (*GenerateResult, error) {
+	gen, result, err := generate(q, existing)
 	if err != nil {
 		return nil, err
 	}
+	if DryRun() {
+		return result, nil
+	}
 
 	state := config.LoadState()
 	state.LastQuestion = config.LastQuestion{
+		Site:       config.Get().LeetCode.Site.Short(),
 		Slug:       q.TitleSlug,
 		FrontendID: q.QuestionFrontendId,
 		Gen:        gen.Slug(),
 	}
+	state.MarkGenerated(q.TitleSlug, q.QuestionFrontendId, gen.Slug())
+	state.LogTime(q.TitleSlug, config.TimeGenerated)
 	config.SaveState(state)
+	writeSession(result)
 
 	return result, nil
 }
@@ -133,17 +296,15 @@ func GenerateContest(ct *leetcode.Contest) ([]*GenerateResult, error) {
 		return nil, err
 	}
 
-	var results []*GenerateResult
-	for _, q := range qs {
-		_, result, err := generate(q)
-		if err != nil {
-			log.Error("failed to generate", "question", q.TitleSlug, "err", err)
-			continue
-		}
-		results = append(results, result)
-	}
+	results, err := GenerateAll(qs, nil)
 	if len(results) == 0 {
-		return nil, fmt.Errorf("no question generated")
+		return nil, errors.Join(errors.New("no question generated"), err)
+	}
+	if err != nil {
+		log.Error("failed to generate some questions", "err", err)
+	}
+	if DryRun() {
+		return results, nil
 	}
 
 	state := config.LoadState()
@@ -153,13 +314,69 @@ func GenerateContest(ct *leetcode.Contest) ([]*GenerateResult, error) {
 	return results, nil
 }
 
-func tryWrite(file string, content string) (bool, error) {
+// GenerateAll fetches the questions concurrently, then generates them one by one. Paid only questions are skipped,
+// failures of other questions don't stop the rest, they are joined in the returned error.
+// Progress of each question is recorded in the job, which may be nil.
+func GenerateAll(qs []*leetcode.QuestionData, job *config.Job) ([]*GenerateResult, error) {
+	fetchErrs := leetcode.FetchQuestions(qs)
+
+	var (
+		results []*GenerateResult
+		errs    []error
+	)
+	state := config.LoadState()
+	for i, q := range qs {
+		var (
+			gen    Lang
+			result *GenerateResult
+		)
+		err := fetchErrs[i]
+		if err == nil {
+			gen, result, err = generate(q, ExistingFilesFromFlags)
+		}
+		if errors.Is(err, leetcode.ErrPaidOnlyQuestion) {
+			log.Warn("skipped paid only question", "question", q.TitleSlug)
+			// Not done, so that --resume retries it after logging in with Premium.
+			job.Skip(q.TitleSlug, err.Error())
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", q.TitleSlug, err))
+			job.Fail(q.TitleSlug, err)
+			continue
+		}
+		results = append(results, result)
+		if !DryRun() {
+			// Save the state of every question, to be consistent with the job if interrupted.
+			state.MarkGenerated(q.TitleSlug, q.QuestionFrontendId, gen.Slug())
+			state.LogTime(q.TitleSlug, config.TimeGenerated)
+			config.SaveState(state)
+		}
+		job.Done(q.TitleSlug)
+	}
+	return results, errors.Join(errs...)
+}
+
+func tryWrite(file string, content string, existing ExistingFiles) (bool, error) {
 	write := true
 	relPath := utils.RelToCwd(file)
 	if utils.IsExist(file) {
-		if !viper.GetBool("yes") {
+		switch {
+		case existing == ExistingFilesOverwrite:
+		case existing == ExistingFilesSkip:
+			log.Info("file already exists, skipped", "file", relPath)
+			write = false
+		case viper.GetBool("force"):
+		case viper.GetBool("skip-existing"):
+			log.Info("file already exists, skipped", "file", relPath)
+			write = false
+		case viper.GetBool("yes"):
+		case config.SafeMode():
+			log.Warn("file already exists, skipped in safe mode", "file", relPath)
+			write = false
+		default:
 			prompt := &survey.Confirm{Message: fmt.Sprintf("File \"%s\" already exists, overwrite?", relPath)}
-			err := survey.AskOne(prompt, &write)
+			err := config.AskOne(prompt, &write)
 			if err != nil {
 				return false, err
 			}
@@ -185,13 +402,17 @@ func GeneratePathsOnly(q *leetcode.QuestionData) (*GenerateResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	gen = GeneratorFor(q, gen)
 
 	result, err := gen.GeneratePaths(q)
 	if err != nil {
 		return nil, err
 	}
 
-	outDir := getOutDir(q, gen)
+	outDir, err := getOutDir(q, gen)
+	if err != nil {
+		return nil, err
+	}
 	result.SetOutDir(outDir)
 	return result, nil
 }
@@ -202,37 +423,7 @@ func GetSolutionCode(q *leetcode.QuestionData) (string, error) {
 	if err != nil {
 		return "", errors.New("code file not found")
 	}
-	code, err := codeFile.GetContent()
-	if err != nil {
-		return "", err
-	}
-	codeLines := strings.Split(code, "\n")
-	var codeLinesToKeep []string
-	inCode := false
-	for _, line := range codeLines {
-		if !inCode && strings.Contains(line, constants.CodeBeginMarker) {
-			inCode = true
-			continue
-		}
-		if inCode && strings.Contains(line, constants.CodeEndMarker) {
-			break
-		}
-		if inCode {
-			codeLinesToKeep = append(codeLinesToKeep, line)
-		}
-	}
-
-	nonEmptyLines := 0
-	for _, line := range codeLinesToKeep {
-		if strings.TrimSpace(line) != "" {
-			nonEmptyLines++
-		}
-	}
-	if nonEmptyLines == 0 {
-		return "", fmt.Errorf("no code found in %s", codeFile.GetPath())
-	}
-
-	return strings.Join(codeLinesToKeep, "\n"), nil
+	return ExtractCode(codeFile.GetPath())
 }
 
 // UpdateSolutionCode updates the solution code in the generated code file.
@@ -249,11 +440,11 @@ func UpdateSolutionCode(q *leetcode.QuestionData, newCode string) error {
 	var newLines []string
 	skip := false
 	for _, line := range lines {
-		if strings.Contains(line, constants.CodeBeginMarker) {
+		if !skip && codeBeginRe.MatchString(line) {
 			newLines = append(newLines, line+"\n")
 			newLines = append(newLines, newCode)
 			skip = true
-		} else if strings.Contains(line, constants.CodeEndMarker) {
+		} else if skip && codeEndRe.MatchString(line) {
 			newLines = append(newLines, line)
 			skip = false
 		} else if !skip {
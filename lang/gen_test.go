@@ -0,0 +1,102 @@
+package lang
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+func TestTryWriteExistingFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "solution.go")
+	if err := os.WriteFile(file, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Explicit handling of existing files wins over the global flags.
+	viper.Set("force", true)
+	t.Cleanup(func() { viper.Set("force", false) })
+	written, err := tryWrite(file, "new", ExistingFilesSkip)
+	if data, _ := os.ReadFile(file); err != nil || written || string(data) != "old" {
+		t.Errorf("tryWrite() with ExistingFilesSkip = %v, %v, content %q", written, err, data)
+	}
+	viper.Set("force", false)
+	viper.Set("skip-existing", true)
+	t.Cleanup(func() { viper.Set("skip-existing", false) })
+	written, err = tryWrite(file, "new", ExistingFilesOverwrite)
+	if data, _ := os.ReadFile(file); err != nil || !written || string(data) != "new" {
+		t.Errorf("tryWrite() with ExistingFilesOverwrite = %v, %v, content %q", written, err, data)
+	}
+	written, err = tryWrite(file, "newer", ExistingFilesFromFlags)
+	if data, _ := os.ReadFile(file); err != nil || written || string(data) != "new" {
+		t.Errorf("tryWrite() with --skip-existing = %v, %v, content %q", written, err, data)
+	}
+	// --skip-existing wins over --yes.
+	viper.Set("yes", true)
+	t.Cleanup(func() { viper.Set("yes", false) })
+	written, err = tryWrite(file, "newer", ExistingFilesFromFlags)
+	if data, _ := os.ReadFile(file); err != nil || written || string(data) != "new" {
+		t.Errorf("tryWrite() with --skip-existing and --yes = %v, %v, content %q", written, err, data)
+	}
+}
+
+func TestGenerateAllSkipsPaidOnly(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	t.Setenv("LEETGO_STATE_DIR", t.TempDir())
+	q := &leetcode.QuestionData{TitleSlug: "paid-only", IsPaidOnly: true}
+	job := config.StartJob("pick", []string{q.TitleSlug}, false)
+
+	results, err := GenerateAll([]*leetcode.QuestionData{q}, job)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("GenerateAll() = %v, %v, want the paid only question skipped", results, err)
+	}
+	job.Finish()
+	// The question is retried on resume, e.g. after logging in with Premium.
+	if got := config.LoadJob("pick").Pending(); !slices.Equal(got, []string{q.TitleSlug}) {
+		t.Errorf("Pending() = %v, want the paid only question", got)
+	}
+}
+
+func TestOutDirError(t *testing.T) {
+	err := outDirError("go/0001.two-sum", &fs.PathError{Op: "open", Path: "go/0001.two-sum/solution.go", Err: syscall.EROFS})
+	if !utils.IsPermissionError(err) || !strings.Contains(err.Error(), "set code.out_dir") {
+		t.Errorf("outDirError() = %v, want a hint", err)
+	}
+	other := errors.New("disk full")
+	if err := outDirError("go", other); err != other {
+		t.Errorf("outDirError() = %v, want other errors as is", err)
+	}
+}
+
+func TestGeneratorFor(t *testing.T) {
+	cases := []struct {
+		category leetcode.CategoryTitle
+		gen      Lang
+		want     Lang
+	}{
+		{leetcode.CategoryAlgorithms, golangGen, golangGen},
+		{leetcode.CategoryDatabase, golangGen, mysqlGen},
+		{leetcode.CategoryDatabase, python3Gen, pythondataGen},
+		{leetcode.CategoryDatabase, mssqlGen, mssqlGen},
+		{leetcode.CategoryDatabase, pythondataGen, pythondataGen},
+		{leetcode.CategoryShell, cppGen, bashGen},
+		{leetcode.CategoryShell, python3Gen, bashGen},
+	}
+	for _, c := range cases {
+		q := &leetcode.QuestionData{CategoryTitle: c.category}
+		if got := GeneratorFor(q, c.gen); got.Slug() != c.want.Slug() {
+			t.Errorf("GeneratorFor() of %s question in %s = %s, want %s", c.category, c.gen.Slug(), got.Slug(), c.want.Slug())
+		}
+	}
+}
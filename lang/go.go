@@ -6,9 +6,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/log"
+	"golang.org/x/mod/modfile"
 
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
@@ -17,6 +19,11 @@ import (
 
 const leetgoGo = "github.com/j178/leetgo/testutils/go"
 
+const (
+	goModPath = "leetcode-solutions"
+	goVersion = "1.21"
+)
+
 var goDeps = []string{
 	leetgoGo + "@v0.2.1",
 }
@@ -125,6 +132,11 @@ func (g golang) InitWorkspace(outDir string) error {
 	if should, err := g.shouldInit(outDir); err != nil || !should {
 		return err
 	}
+	if config.SafeMode() {
+		log.Warn("skip initializing workspace in safe mode", "dir", outDir)
+		return nil
+	}
+	defer utils.Track(utils.ProfileSubprocess)()
 
 	err := utils.RemoveIfExist(filepath.Join(outDir, "go.mod"))
 	if err != nil {
@@ -132,12 +144,11 @@ func (g golang) InitWorkspace(outDir string) error {
 	}
 	_ = utils.RemoveIfExist(filepath.Join(outDir, "go.sum"))
 
-	const modPath = "leetcode-solutions"
 	var stderr strings.Builder
-	cmd := exec.Command("go", "mod", "init", modPath)
+	cmd := exec.Command("go", "mod", "init", goModPath)
 	log.Info("go mod init", "cmd", cmd.String())
 	cmd.Dir = outDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 	err = cmd.Run()
 	if err != nil && !strings.Contains(stderr.String(), "go.mod already exists") {
@@ -148,7 +159,7 @@ func (g golang) InitWorkspace(outDir string) error {
 	cmd.Args = append(cmd.Args, goDeps...)
 	log.Info("go get", "cmd", cmd.String())
 	cmd.Dir = outDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = os.Stderr
 	err = cmd.Run()
 	if err != nil {
@@ -159,28 +170,35 @@ func (g golang) InitWorkspace(outDir string) error {
 	return err
 }
 
-func (g golang) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
-	genResult, err := g.GeneratePaths(q)
-	if err != nil {
-		return false, fmt.Errorf("generate paths failed: %w", err)
-	}
-	genResult.SetOutDir(outDir)
-
+func (g golang) BuildProgram(q *leetcode.QuestionData, genResult *GenerateResult) ([]string, error) {
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
-	execFile, err := getTempBinFile(q, g)
+	execFile, err := getTempBinFile(q, g, genResult)
 	if err != nil {
-		return false, fmt.Errorf("get temp bin file failed: %w", err)
+		return nil, fmt.Errorf("get temp bin file failed: %w", err)
 	}
 
 	err = buildTest(q, genResult, []string{"go", "build", "-o", execFile, testFile})
 	if err != nil {
-		return false, fmt.Errorf("build failed: %w", err)
+		return nil, fmt.Errorf("build failed: %w", err)
 	}
+	return []string{execFile}, nil
+}
 
-	return runTest(q, genResult, []string{execFile}, targetCase)
+func (g golang) RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error) {
+	genResult, err := g.GeneratePaths(q)
+	if err != nil {
+		return false, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	args, err := g.BuildProgram(q, genResult)
+	if err != nil {
+		return false, err
+	}
+	return runTest(q, genResult, args, opts)
 }
 
 // toGoType converts LeetCode type name to Go type name.
@@ -223,30 +241,29 @@ func (g golang) generateNormalTestCode(q *leetcode.QuestionData) (string, error)
 }
 `
 	code := ""
-	paramNames := make([]string, 0, len(q.MetaData.Params))
-	for _, param := range q.MetaData.Params {
+	fn := q.MetaData.Function()
+	for _, param := range fn.Params {
 		code += fmt.Sprintf(
 			"\t%s := Deserialize[%s](ReadLine(stdin))\n",
 			param.Name,
-			toGoType(param.Type),
+			toGoType(param.Type.String()),
 		)
-		paramNames = append(paramNames, param.Name)
 	}
-	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+	paramNames := fn.ParamNames()
+	if !fn.IsVoid() {
 		code += fmt.Sprintf(
 			"\tans := %s(%s)\n",
-			q.MetaData.Name,
+			fn.Name,
 			strings.Join(paramNames, ", "),
 		)
 	} else {
 		code += fmt.Sprintf(
 			"\t%s(%s)\n",
-			q.MetaData.Name,
+			fn.Name,
 			strings.Join(paramNames, ", "),
 		)
-		if q.MetaData.Output != nil {
-			ansName := paramNames[q.MetaData.Output.ParamIndex]
-			code += fmt.Sprintf("\tans := %s\n", ansName)
+		if !fn.ResultType().IsVoid() {
+			code += fmt.Sprintf("\tans := %s\n", paramNames[fn.OutputParam])
 		} else {
 			code += "\tans := \"\"\n"
 		}
@@ -273,6 +290,8 @@ func (g golang) generateSystemDesignTestCode(q *leetcode.QuestionData) (string,
 	for i := 1; i < len(ops); i++ {
 		switch ops[i] {
 %s
+		default:
+			panic("unknown method: " + ops[i])
 		}
 	}
 	fmt.Println("\n%s", JoinArray(output))
@@ -357,8 +376,13 @@ func (g golang) generateCodeFile(
 	FileOutput,
 	error,
 ) {
+	// Solutions of the flat layout share a directory, the ignore constraint keeps each of them a standalone file.
+	buildConstraint := ""
+	if config.Get().Code.Go.Layout == config.GoLayoutFlat {
+		buildConstraint = "//go:build ignore\n\n"
+	}
 	codeHeader := fmt.Sprintf(
-		`package main
+		`%spackage main
 
 import (
 	"bufio"
@@ -366,7 +390,7 @@ import (
 	"os"
 
 	. "%s"
-)`, leetgoGo,
+)`, buildConstraint, leetgoGo,
 	)
 	testContent, err := g.generateTestContent(q)
 	if err != nil {
@@ -401,33 +425,43 @@ import (
 	}, nil
 }
 
+// goFilenames returns the sub directory and the filenames of code, test cases and description files
+// according to the layout.
+func goFilenames(layout, baseFilename string) (subDir, code, testcases, doc string) {
+	if layout == config.GoLayoutFlat {
+		return "", baseFilename + ".go", baseFilename + ".testcases.txt", baseFilename + ".md"
+	}
+	return baseFilename, "solution.go", "testcases.txt", "question.md"
+}
+
 func (g golang) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
 	filenameTmpl := getFilenameTemplate(q, g)
 	baseFilename, err := q.GetFormattedFilename(g.slug, filenameTmpl)
 	if err != nil {
 		return nil, err
 	}
+	subDir, codeFilename, testcasesFilename, docFilename := goFilenames(config.Get().Code.Go.Layout, baseFilename)
 	genResult := &GenerateResult{
-		SubDir:   baseFilename,
+		SubDir:   subDir,
 		Question: q,
 		Lang:     g,
 	}
 	genResult.AddFile(
 		FileOutput{
-			Filename: "solution.go",
+			Filename: codeFilename,
 			Type:     CodeFile | TestFile,
 		},
 	)
 	genResult.AddFile(
 		FileOutput{
-			Filename: "testcases.txt",
+			Filename: testcasesFilename,
 			Type:     TestCasesFile,
 		},
 	)
 	if separateDescriptionFile(g) {
 		genResult.AddFile(
 			FileOutput{
-				Filename: "question.md",
+				Filename: docFilename,
 				Type:     DocFile,
 			},
 		)
@@ -448,10 +482,11 @@ func (g golang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	subDir, codeFilename, testcasesFilename, docFilename := goFilenames(config.Get().Code.Go.Layout, baseFilename)
 	genResult := &GenerateResult{
 		Question: q,
 		Lang:     g,
-		SubDir:   baseFilename,
+		SubDir:   subDir,
 	}
 
 	separateDescriptionFile := separateDescriptionFile(g)
@@ -460,11 +495,11 @@ func (g golang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
 	if err != nil {
 		return nil, err
 	}
-	codeFile, err := g.generateCodeFile(q, "solution.go", blocks, modifiers, separateDescriptionFile)
+	codeFile, err := g.generateCodeFile(q, codeFilename, blocks, modifiers, separateDescriptionFile)
 	if err != nil {
 		return nil, err
 	}
-	testcaseFile, err := g.generateTestCasesFile(q, "testcases.txt")
+	testcaseFile, err := g.generateTestCasesFile(q, testcasesFilename)
 	if err != nil {
 		return nil, err
 	}
@@ -472,12 +507,93 @@ func (g golang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
 	genResult.AddFile(testcaseFile)
 
 	if separateDescriptionFile {
-		docFile, err := g.generateDescriptionFile(q, "question.md")
+		docFile, err := g.generateDescriptionFile(q, docFilename)
 		if err != nil {
 			return nil, err
 		}
 		genResult.AddFile(docFile)
 	}
 
+	if config.Get().Code.Go.Layout == config.GoLayoutModulePerQuestion {
+		genResult.ResultHooks = append(genResult.ResultHooks, addGoModule)
+	}
+
 	return genResult, nil
 }
+
+// addGoModule makes the question directory a standalone module, and adds it to the go.work of the workspace.
+func addGoModule(result *GenerateResult) error {
+	dir := result.TargetDir()
+	workspace, relDir := result.WorkspaceDir(), result.workspaceRelDir()
+	goMod := filepath.Join(dir, "go.mod")
+	if !utils.IsExist(goMod) {
+		modPath := goModPath + "/" + relDir
+		content := fmt.Sprintf("module %s\n\ngo %s\n\nrequire %s\n", modPath, goVersion, strings.Replace(goDeps[0], "@", " ", 1))
+		if err := utils.WriteFile(goMod, []byte(content)); err != nil {
+			return err
+		}
+		log.Info("generated", "file", utils.RelToCwd(goMod))
+		// Reuse checksums of the workspace module, so that the question module builds without `go mod tidy`.
+		if sum, err := os.ReadFile(filepath.Join(workspace, "go.sum")); err == nil {
+			if err := utils.WriteFile(filepath.Join(dir, "go.sum"), sum); err != nil {
+				return err
+			}
+		}
+	}
+
+	if config.SafeMode() {
+		log.Warn("skip updating go.work in safe mode", "dir", workspace)
+		return nil
+	}
+	defer utils.Track(utils.ProfileSubprocess)()
+	if !utils.IsExist(filepath.Join(workspace, "go.work")) {
+		cmd := exec.Command("go", "work", "init", ".")
+		cmd.Dir = workspace
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("go work init failed: %w", err)
+		}
+	}
+	cmd := exec.Command("go", "work", "use", "./"+relDir)
+	log.Info("go work use", "cmd", cmd.String())
+	cmd.Dir = workspace
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go work use failed: %w", err)
+	}
+	return nil
+}
+
+// dropGoModule removes the question directory from the go.work of the workspace, before the directory is removed or
+// moved, otherwise every go command in the workspace fails on the missing module. It does nothing for layouts without go.work.
+func dropGoModule(result *GenerateResult) error {
+	if result.SubDir == "" {
+		return nil
+	}
+	goWork := filepath.Join(result.WorkspaceDir(), "go.work")
+	data, err := os.ReadFile(goWork)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	work, err := modfile.ParseWork(goWork, data, nil)
+	if err != nil {
+		return err
+	}
+	dir := "./" + result.workspaceRelDir()
+	if !slices.ContainsFunc(work.Use, func(u *modfile.Use) bool { return u.Path == dir }) {
+		return nil
+	}
+	if err = work.DropUse(dir); err != nil {
+		return err
+	}
+	work.Cleanup()
+	err = utils.WriteFile(goWork, modfile.Format(work.Syntax))
+	if err != nil {
+		return err
+	}
+	log.Info("dropped from go.work", "dir", dir)
+	return nil
+}
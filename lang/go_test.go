@@ -0,0 +1,64 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+)
+
+func TestDropGoModule(t *testing.T) {
+	dir := t.TempDir()
+	goWork := filepath.Join(dir, "go.work")
+	content := "go 1.21\n\nuse (\n\t.\n\t./0001.two-sum\n\t./0002.add-two-numbers\n)\n"
+	if err := os.WriteFile(goWork, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := DropFromWorkspace(&GenerateResult{Lang: golangGen, OutDir: dir, SubDir: "0001.two-sum"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(goWork)
+	got := string(data)
+	if strings.Contains(got, "./0001.two-sum") || !strings.Contains(got, "./0002.add-two-numbers") {
+		t.Errorf("go.work after drop:\n%s", got)
+	}
+
+	// Modules not in go.work and questions without their own directory are left alone.
+	for _, r := range []*GenerateResult{
+		{Lang: golangGen, OutDir: dir, SubDir: "0003.x"},
+		{Lang: golangGen, OutDir: dir},
+		{Lang: rustGen, OutDir: dir, SubDir: "0002.add-two-numbers"},
+	} {
+		if err := DropFromWorkspace(r); err != nil {
+			t.Errorf("DropFromWorkspace(%q) = %v", r.SubDir, err)
+		}
+	}
+	if data, _ := os.ReadFile(goWork); string(data) != got {
+		t.Errorf("go.work changed:\n%s", data)
+	}
+}
+
+func TestGoFilenames(t *testing.T) {
+	cases := []struct {
+		layout                       string
+		subDir, code, testcases, doc string
+	}{
+		// Questions of the flat layout share the directory, files are named after the question.
+		{config.GoLayoutFlat, "", "0001.two-sum.go", "0001.two-sum.testcases.txt", "0001.two-sum.md"},
+		{config.GoLayoutPackagePerQuestion, "0001.two-sum", "solution.go", "testcases.txt", "question.md"},
+		{config.GoLayoutModulePerQuestion, "0001.two-sum", "solution.go", "testcases.txt", "question.md"},
+	}
+	for _, c := range cases {
+		subDir, code, testcases, doc := goFilenames(c.layout, "0001.two-sum")
+		if subDir != c.subDir || code != c.code || testcases != c.testcases || doc != c.doc {
+			t.Errorf(
+				"goFilenames() of %s = %q, %q, %q, %q, want %q, %q, %q, %q", c.layout,
+				subDir, code, testcases, doc, c.subDir, c.code, c.testcases, c.doc,
+			)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/goccy/go-json"
 	strip "github.com/grokify/html-strip-tags-go"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
 	goutils "github.com/j178/leetgo/testutils/go"
 )
@@ -181,6 +182,56 @@ func (s systemDesignJudger) Judge(input []string, output, actualOutput string) J
 	return accepted()
 }
 
+// compareJudger normalizes both outputs according to the compare options before judging.
+type compareJudger struct {
+	opts      config.CompareOptions
+	subJudger Judger
+}
+
+func (j compareJudger) normalize(s string) string {
+	// Compare the content of string outputs, rather than their quoted form.
+	var str string
+	if strings.HasPrefix(s, `"`) && json.Unmarshal([]byte(s), &str) == nil {
+		s = str
+	}
+	if j.opts.NormalizeNewlines {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+		s = strings.TrimRight(s, "\n")
+	}
+	if j.opts.TrimTrailingWhitespace {
+		lines := strings.Split(s, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t\r")
+		}
+		s = strings.Join(lines, "\n")
+	}
+	if j.opts.IgnoreCase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+func (j compareJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	if j.normalize(output) == j.normalize(actualOutput) {
+		return accepted()
+	}
+	return j.subJudger.Judge(input, output, actualOutput)
+}
+
+// GetQuestionJudger returns the judger of the question, respecting the compare options
+// in the question config of the generated result.
+func GetQuestionJudger(q *leetcode.QuestionData, result *GenerateResult) (Judger, error) {
+	judger := GetJudger(q)
+	qc, err := config.LoadQuestionConfig(result.QuestionConfigFile())
+	if err != nil {
+		return nil, err
+	}
+	if !qc.Compare.IsZero() {
+		judger = compareJudger{opts: qc.Compare, subJudger: judger}
+	}
+	return judger, nil
+}
+
 func GetJudger(q *leetcode.QuestionData) Judger {
 	if q.MetaData.SystemDesign {
 		return newSystemDesignJudger(q)
@@ -0,0 +1,79 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestCompareJudger(t *testing.T) {
+	cases := []struct {
+		opts     config.CompareOptions
+		output   string
+		actual   string
+		accepted bool
+	}{
+		{config.CompareOptions{}, `"abc"`, `"abc "`, false},
+		{config.CompareOptions{TrimTrailingWhitespace: true}, `"abc"`, `"abc "`, true},
+		{config.CompareOptions{TrimTrailingWhitespace: true}, "a\nb", "a  \nb\t", true},
+		{config.CompareOptions{IgnoreCase: true}, `"Hello"`, `"hELLO"`, true},
+		{config.CompareOptions{NormalizeNewlines: true}, "a\nb", "a\r\nb\n", true},
+		{config.CompareOptions{NormalizeNewlines: true}, "a\nb", "a\n\nb", false},
+	}
+	for _, c := range cases {
+		j := compareJudger{opts: c.opts, subJudger: stringJudger{}}
+		if r := j.Judge(nil, c.output, c.actual); r.IsAccepted() != c.accepted {
+			t.Errorf("judge %q vs %q with %+v: accepted = %v, want %v", c.output, c.actual, c.opts, r.IsAccepted(), c.accepted)
+		}
+	}
+}
+
+func TestGetQuestionJudger(t *testing.T) {
+	dir := t.TempDir()
+	twoSum := &leetcode.QuestionData{TitleSlug: "two-sum"}
+	addTwoNumbers := &leetcode.QuestionData{TitleSlug: "add-two-numbers"}
+	write := func(file string) {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("compare:\n  ignore_case: true\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	relaxed := func(q *leetcode.QuestionData, result *GenerateResult) bool {
+		j, err := GetQuestionJudger(q, result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, ok := j.(compareJudger)
+		return ok
+	}
+
+	// Questions of a flat layout share the directory, but not the config.
+	flat := &GenerateResult{Question: twoSum, OutDir: dir}
+	write(flat.QuestionConfigFile())
+	if filepath.Base(flat.QuestionConfigFile()) != "two-sum.question.yaml" {
+		t.Errorf("QuestionConfigFile() = %s, want it named after the question", flat.QuestionConfigFile())
+	}
+	if !relaxed(twoSum, flat) {
+		t.Errorf("two-sum should use its question config")
+	}
+	if relaxed(addTwoNumbers, &GenerateResult{Question: addTwoNumbers, OutDir: dir}) {
+		t.Errorf("add-two-numbers should not use the question config of two-sum")
+	}
+
+	nested := &GenerateResult{Question: twoSum, OutDir: dir, SubDir: "0001.two-sum"}
+	if want := filepath.Join(dir, "0001.two-sum", config.QuestionConfigFilename); nested.QuestionConfigFile() != want {
+		t.Errorf("QuestionConfigFile() = %s, want %s", nested.QuestionConfigFile(), want)
+	}
+	if relaxed(twoSum, nested) {
+		t.Errorf("question config should not be used before it is written")
+	}
+	write(nested.QuestionConfigFile())
+	if !relaxed(twoSum, nested) {
+		t.Errorf("two-sum should use the question config in its directory")
+	}
+}
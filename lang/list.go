@@ -153,6 +153,15 @@ var (
 		blockCommentStart: "/*",
 		blockCommentEnd:   "*/",
 	}
+	pythondataGen = baseLang{
+		name:              "Pandas",
+		slug:              "pythondata",
+		shortName:         "pandas",
+		extension:         ".py",
+		lineComment:       "#",
+		blockCommentStart: `"""`,
+		blockCommentEnd:   `"""`,
+	}
 	bashGen = baseLang{
 		name:              "Bash",
 		slug:              "bash",
@@ -227,6 +236,7 @@ var (
 		mysqlGen,
 		mssqlGen,
 		oraclesqlGen,
+		pythondataGen,
 		erlangGen,
 		racketGen,
 		scalaGen,
@@ -0,0 +1,128 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/mod/semver"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// versionRe matches the version line in the header of code files, e.g. `// leetgo: 1.4.2`.
+var versionRe = regexp.MustCompile(regexp.QuoteMeta(constants.CmdName) + `: (v?\d+\.\d+\.\d+\S*)`)
+
+// fileVersion returns the version of leetgo that generated the code file, "" if the header has no version line.
+func fileVersion(content string) string {
+	m := versionRe.FindStringSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimPrefix(m[1], "v")
+}
+
+// isLegacyFile reports whether the code file was generated by an older version: it uses legacy code markers,
+// or the version in its header is older than the running one.
+// Files without a version line, e.g. with a customized header, are only legacy if they use legacy markers.
+func isLegacyFile(content string) bool {
+	if !slices.ContainsFunc(strings.Split(content, "\n"), codeBeginRe.MatchString) {
+		return true
+	}
+	version, current := "v"+fileVersion(content), "v"+constants.Version
+	if !semver.IsValid(version) || !semver.IsValid(current) {
+		return false
+	}
+	return semver.Compare(version, current) < 0
+}
+
+// replaceCode replaces the lines between the current code markers with code.
+func replaceCode(content string, code string) string {
+	var lines []string
+	skip := false
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case !skip && codeBeginRe.MatchString(line):
+			lines = append(lines, line, code)
+			skip = true
+		case skip && codeEndRe.MatchString(line):
+			lines = append(lines, line)
+			skip = false
+		case !skip:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MigrateFiles rewrites the code file of the question generated by an older version to the current template,
+// keeping the solution code between the code markers. Generated test files are rewritten as well.
+// It reports whether the files are outdated, nothing is written in dry-run mode.
+func MigrateFiles(q *leetcode.QuestionData) (bool, error) {
+	gen, err := GetGenerator(config.Get().Code.Lang)
+	if err != nil {
+		return false, err
+	}
+	gen = GeneratorFor(q, gen)
+	paths, err := GeneratePathsOnly(q)
+	if err != nil {
+		return false, err
+	}
+	codeFile := paths.GetFile(CodeFile)
+	if codeFile == nil {
+		return false, errors.New("code file not found")
+	}
+	old, err := os.ReadFile(codeFile.GetPath())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !isLegacyFile(string(old)) {
+		return false, nil
+	}
+	// Files of any version are read by the same extractor, it knows the legacy markers.
+	code, ok := extractCodeBlocks(string(old))
+	if !ok {
+		return false, fmt.Errorf("no code markers found in %s", utils.RelToCwd(codeFile.GetPath()))
+	}
+
+	err = q.Fulfill()
+	if err != nil {
+		return false, fmt.Errorf("failed to get question data: %w", err)
+	}
+	result, err := gen.Generate(q)
+	if err != nil {
+		return false, err
+	}
+	result.SetOutDir(paths.OutDir)
+
+	var files []FileOutput
+	if f := result.GetFile(CodeFile); f != nil {
+		f.Content = replaceCode(f.Content, code)
+		files = append(files, *f)
+	}
+	if f := result.GetFile(TestFile); f != nil && f.Type&CodeFile == 0 && utils.IsExist(f.GetPath()) {
+		files = append(files, *f)
+	}
+	for _, f := range files {
+		if DryRun() {
+			printFileDiff(f.GetPath(), f.Content)
+			continue
+		}
+		err = utils.WriteFile(f.GetPath(), []byte(f.Content))
+		if err != nil {
+			return false, err
+		}
+		log.Info("upgraded", "file", utils.RelToCwd(f.GetPath()))
+	}
+	return true, nil
+}
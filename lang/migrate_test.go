@@ -0,0 +1,85 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/j178/leetgo/constants"
+)
+
+func TestMigrateCode(t *testing.T) {
+	legacy := "// Created by me\n\n// @lc code=start\nfunc twoSum() {}\n// @lc code=end\n"
+	current := "// Created by me\n// leetgo: dev\n\n// @lc code=begin\nfunc twoSum() {\n}\n// @lc code=end\n"
+
+	if !isLegacyFile(legacy) {
+		t.Errorf("file with legacy markers should be legacy")
+	}
+	if isLegacyFile(current) {
+		t.Errorf("file with current markers and version should not be legacy")
+	}
+
+	code, ok := extractCodeBlocks(legacy)
+	if !ok || code != "func twoSum() {}" {
+		t.Fatalf("extract legacy code: got %q, %v", code, ok)
+	}
+	want := "// Created by me\n// leetgo: dev\n\n// @lc code=begin\nfunc twoSum() {}\n// @lc code=end\n"
+	if got := replaceCode(current, code); got != want {
+		t.Errorf("replace code: got %q, want %q", got, want)
+	}
+
+	// Markers edited by hand are recognized by migration as well.
+	code, ok = extractCodeBlocks("//  @LC code = Start\nfunc twoSum() {}\n// @lc code=END\n")
+	if !ok || code != "func twoSum() {}" {
+		t.Errorf("extract code with edited markers: got %q, %v", code, ok)
+	}
+
+	if _, ok := extractCodeBlocks("func twoSum() {}"); ok {
+		t.Errorf("file without markers should have no code")
+	}
+}
+
+func TestFileVersion(t *testing.T) {
+	cases := []struct {
+		content string
+		want    string
+	}{
+		{"// Created by me\n// leetgo: 1.4.2\n", "1.4.2"},
+		{"# leetgo: v1.5.0-nightly.20240301\n", "1.5.0-nightly.20240301"},
+		{"// leetgo: dev\n", ""},
+		{"// Created by me\n", ""},
+	}
+	for _, c := range cases {
+		if got := fileVersion(c.content); got != c.want {
+			t.Errorf("fileVersion(%q) = %q, want %q", c.content, got, c.want)
+		}
+	}
+}
+
+func TestIsLegacyFile(t *testing.T) {
+	defer func(v string) { constants.Version = v }(constants.Version)
+	constants.Version = "1.4.2"
+
+	code := "\n// @lc code=begin\nfunc twoSum() {}\n// @lc code=end\n"
+	cases := []struct {
+		content string
+		want    bool
+	}{
+		{"// leetgo: 1.3.0" + code, true},
+		{"// leetgo: 1.4.2" + code, false},
+		{"// leetgo: 1.5.0" + code, false},
+		{"// leetgo: 1.4.2-nightly.20240301" + code, true},
+		{"// leetgo: dev" + code, false},
+		// Customized header without the version line.
+		{"// My solution" + code, false},
+		{"// leetgo: 1.4.2\n// @lc code=start\nfunc twoSum() {}\n// @lc code=end\n", true},
+	}
+	for _, c := range cases {
+		if got := isLegacyFile(c.content); got != c.want {
+			t.Errorf("isLegacyFile(%q) = %v, want %v", c.content, got, c.want)
+		}
+	}
+
+	constants.Version = "dev"
+	if isLegacyFile("// leetgo: 1.3.0" + code) {
+		t.Errorf("files should not be migrated by a development build")
+	}
+}
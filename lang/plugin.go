@@ -0,0 +1,367 @@
+package lang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// Language plugins are executables named leetgo-lang-<name> in the plugins directory.
+// leetgo runs `leetgo-lang-<name> <method>` with a JSON request on stdin, and reads a JSON response from stdout.
+// A response with a non-empty "error" field fails the method. Stderr is passed through to the user.
+const (
+	pluginPrefix     = "leetgo-lang-"
+	pluginAPIVersion = 1
+)
+
+// Methods of the plugin protocol.
+const (
+	pluginMethodInfo     = "info"
+	pluginMethodInit     = "init"
+	pluginMethodGenerate = "generate"
+	pluginMethodPaths    = "paths"
+	pluginMethodTest     = "test"
+)
+
+// pluginInfo is the response of the info method, describing the language.
+type pluginInfo struct {
+	APIVersion        int    `json:"api_version"`
+	Name              string `json:"name"`
+	Slug              string `json:"slug"`
+	ShortName         string `json:"short_name"`
+	Extension         string `json:"extension"`
+	LineComment       string `json:"line_comment"`
+	BlockCommentStart string `json:"block_comment_start"`
+	BlockCommentEnd   string `json:"block_comment_end"`
+	LocalTest         bool   `json:"local_test"`
+}
+
+// pluginGenerateRequest is the request of the generate and paths methods.
+type pluginGenerateRequest struct {
+	Question *leetcode.QuestionData `json:"question"`
+	// BaseFilename is the filename without extension formatted from filename_template.
+	BaseFilename            string         `json:"base_filename"`
+	SeparateDescriptionFile bool           `json:"separate_description_file"`
+	Blocks                  []config.Block `json:"blocks,omitempty"`
+}
+
+// pluginFile is a file generated by the plugin, its type is one of code, test, testcases, doc and other.
+// The content is empty in the response of the paths method.
+type pluginFile struct {
+	Filename string `json:"filename"`
+	Type     string `json:"type"`
+	Content  string `json:"content,omitempty"`
+}
+
+type pluginGenerateResponse struct {
+	Files []pluginFile `json:"files"`
+}
+
+type pluginTestRequest struct {
+	Question   *leetcode.QuestionData `json:"question"`
+	OutDir     string                 `json:"out_dir"`
+	TargetCase string                 `json:"target_case,omitempty"`
+	FailFast   bool                   `json:"fail_fast,omitempty"`
+}
+
+type pluginTestResponse struct {
+	Passed bool `json:"passed"`
+}
+
+// pluginLang is a language provided by a plugin.
+type pluginLang struct {
+	baseLang
+	path string
+}
+
+// testablePluginLang is a language provided by a plugin that supports local test.
+type testablePluginLang struct {
+	pluginLang
+}
+
+func isPlugin(l Lang) bool {
+	switch l.(type) {
+	case pluginLang, testablePluginLang:
+		return true
+	}
+	return false
+}
+
+// call runs the method of the plugin, encoding req to its stdin and decoding its stdout into resp.
+func (l pluginLang) call(method string, req any, resp any) error {
+	return callPlugin(l.path, method, req, resp)
+}
+
+func callPlugin(path string, method string, req any, resp any) error {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var stdout bytes.Buffer
+	cmd := exec.Command(path, method)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	done := utils.Track(utils.ProfileSubprocess)
+	err = cmd.Run()
+	done()
+	if err != nil {
+		return fmt.Errorf("plugin %s %s: %w", filepath.Base(path), method, err)
+	}
+
+	var result struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return fmt.Errorf("plugin %s %s: invalid response: %w", filepath.Base(path), method, err)
+	}
+	if result.Error != "" {
+		return fmt.Errorf("plugin %s %s: %s", filepath.Base(path), method, result.Error)
+	}
+	if resp == nil {
+		return nil
+	}
+	return json.Unmarshal(stdout.Bytes(), resp)
+}
+
+func parseFileType(s string) (FileType, error) {
+	for _, t := range []FileType{CodeFile, TestFile, TestCasesFile, DocFile, OtherFile} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid file type %q", s)
+}
+
+func (l pluginLang) InitWorkspace(dir string) error {
+	return l.call(pluginMethodInit, map[string]string{"dir": dir}, nil)
+}
+
+// generate generates files of the question by the plugin, gen is the Lang recorded in the result.
+func (l pluginLang) generate(method string, q *leetcode.QuestionData, gen Lang) (*GenerateResult, error) {
+	baseFilename, err := q.GetFormattedFilename(l.slug, getFilenameTemplate(q, l))
+	if err != nil {
+		return nil, err
+	}
+	req := pluginGenerateRequest{
+		Question:                q,
+		BaseFilename:            baseFilename,
+		SeparateDescriptionFile: separateDescriptionFile(l),
+		Blocks:                  getBlocks(l),
+	}
+	var resp pluginGenerateResponse
+	err = l.call(method, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     gen,
+	}
+	for _, f := range resp.Files {
+		typ, err := parseFileType(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", filepath.Base(l.path), err)
+		}
+		if f.Filename == "" || filepath.IsAbs(f.Filename) || strings.HasPrefix(filepath.Clean(f.Filename), "..") {
+			return nil, fmt.Errorf("plugin %s: invalid filename %q", filepath.Base(l.path), f.Filename)
+		}
+		genResult.AddFile(FileOutput{Filename: f.Filename, Type: typ, Content: f.Content})
+	}
+	if genResult.GetFile(CodeFile) == nil {
+		return nil, fmt.Errorf("plugin %s: no code file generated", filepath.Base(l.path))
+	}
+	return genResult, nil
+}
+
+func (l pluginLang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	return l.generate(pluginMethodGenerate, q, l)
+}
+
+func (l pluginLang) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	return l.generate(pluginMethodPaths, q, l)
+}
+
+func (l testablePluginLang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	return l.generate(pluginMethodGenerate, q, l)
+}
+
+func (l testablePluginLang) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	return l.generate(pluginMethodPaths, q, l)
+}
+
+func (l testablePluginLang) RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error) {
+	req := pluginTestRequest{
+		Question:   q,
+		OutDir:     outDir,
+		TargetCase: opts.TargetCase,
+		FailFast:   opts.FailFast,
+	}
+	var resp pluginTestResponse
+	err := l.call(pluginMethodTest, req, &resp)
+	return resp.Passed, err
+}
+
+// loadPlugin asks the plugin for the language it provides.
+func loadPlugin(path string) (Lang, error) {
+	var info pluginInfo
+	err := callPlugin(path, pluginMethodInfo, struct{}{}, &info)
+	if err != nil {
+		return nil, err
+	}
+	return newPluginLang(path, info)
+}
+
+func newPluginLang(path string, info pluginInfo) (Lang, error) {
+	if info.APIVersion != pluginAPIVersion {
+		return nil, fmt.Errorf("plugin %s: unsupported api version %d, expected %d", filepath.Base(path), info.APIVersion, pluginAPIVersion)
+	}
+	if info.Slug == "" || info.Extension == "" {
+		return nil, fmt.Errorf("plugin %s: slug and extension are required", filepath.Base(path))
+	}
+	l := pluginLang{
+		baseLang: baseLang{
+			name:              info.Name,
+			slug:              strings.ToLower(info.Slug),
+			shortName:         strings.ToLower(info.ShortName),
+			extension:         info.Extension,
+			lineComment:       info.LineComment,
+			blockCommentStart: info.BlockCommentStart,
+			blockCommentEnd:   info.BlockCommentEnd,
+		},
+		path: path,
+	}
+	if l.name == "" {
+		l.name = info.Slug
+	}
+	if l.shortName == "" {
+		l.shortName = l.slug
+	}
+	if info.LocalTest {
+		return testablePluginLang{l}, nil
+	}
+	return l, nil
+}
+
+// cachedPluginInfo is the info of a plugin, valid as long as the plugin file is not changed.
+type cachedPluginInfo struct {
+	ModTime time.Time  `json:"mod_time"`
+	Size    int64      `json:"size"`
+	Info    pluginInfo `json:"info"`
+}
+
+func (c cachedPluginInfo) sameFile(modTime time.Time, size int64) bool {
+	return c.ModTime.Equal(modTime) && c.Size == size
+}
+
+func readPluginsCache() map[string]cachedPluginInfo {
+	cache := map[string]cachedPluginInfo{}
+	data, err := os.ReadFile(config.Get().PluginsCacheFile())
+	if err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+func writePluginsCache(cache map[string]cachedPluginInfo) {
+	data, err := json.Marshal(cache)
+	if err == nil {
+		err = utils.WriteFile(config.Get().PluginsCacheFile(), data)
+	}
+	if err != nil {
+		log.Debug("failed to save plugins cache", "err", err)
+	}
+}
+
+var (
+	plugins     []Lang
+	pluginsOnce sync.Once
+)
+
+// Plugins returns the languages provided by plugins in the plugins directory.
+// Plugins are not loaded in safe mode, as they are external processes.
+func Plugins() []Lang {
+	pluginsOnce.Do(
+		func() {
+			if !config.SafeMode() {
+				plugins = loadPlugins(config.Get().PluginsDir())
+			}
+		},
+	)
+	return plugins
+}
+
+// loadPlugins loads the plugins in dir. The info of a plugin is cached until the plugin file changes,
+// so that commands, shell completion included, don't run every plugin.
+func loadPlugins(dir string) []Lang {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn("failed to read plugins directory", "dir", dir, "err", err)
+		}
+		return nil
+	}
+	var (
+		langs   []Lang
+		cache   = readPluginsCache()
+		updated = make(map[string]cachedPluginInfo, len(cache))
+	)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), pluginPrefix) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		stat, err := e.Info()
+		if err != nil {
+			log.Warn("failed to load plugin", "plugin", e.Name(), "err", err)
+			continue
+		}
+		cached, ok := cache[path]
+		if !ok || !cached.sameFile(stat.ModTime(), stat.Size()) {
+			cached = cachedPluginInfo{ModTime: stat.ModTime(), Size: stat.Size()}
+			if err := callPlugin(path, pluginMethodInfo, struct{}{}, &cached.Info); err != nil {
+				log.Warn("failed to load plugin", "plugin", e.Name(), "err", err)
+				continue
+			}
+		}
+		l, err := newPluginLang(path, cached.Info)
+		if err != nil {
+			log.Warn("failed to load plugin", "plugin", e.Name(), "err", err)
+			continue
+		}
+		updated[path] = cached
+		log.Debug("plugin loaded", "plugin", e.Name(), "lang", l.Slug())
+		langs = append(langs, l)
+	}
+	unchanged := maps.EqualFunc(
+		cache, updated, func(a, b cachedPluginInfo) bool {
+			return a.Info == b.Info && a.sameFile(b.ModTime, b.Size)
+		},
+	)
+	if !unchanged {
+		writePluginsCache(updated)
+	}
+	return langs
+}
+
+// AllLangs returns the languages provided by plugins and the builtin ones.
+// Plugins come first, so that a plugin can replace the builtin generator of a language.
+func AllLangs() []Lang {
+	return append(append([]Lang{}, Plugins()...), SupportedLangs...)
+}
@@ -0,0 +1,140 @@
+package lang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+// testPlugin counts its info calls in the calls file next to it, and responds to generate with the files.
+const testPlugin = `#!/bin/sh
+case "$1" in
+info)
+	echo info >> "$(dirname "$0")/calls"
+	echo '{"api_version": 1, "name": "Zig", "slug": "zig", "extension": ".zig", "line_comment": "//", "local_test": true}' ;;
+generate) echo '{"files": %s}' ;;
+paths) echo '{"files": [{"filename": "1/solution.zig", "type": "code"}]}' ;;
+test) echo '{"passed": true}' ;;
+*) echo '{"error": "unknown method"}' ;;
+esac
+`
+
+func writePlugin(t *testing.T, dir string, files string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	path := filepath.Join(dir, pluginPrefix+"zig")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(testPlugin, files)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPlugin(t *testing.T) {
+	path := writePlugin(t, t.TempDir(), "[]")
+
+	l, err := loadPlugin(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Slug() != "zig" || l.ShortName() != "zig" || l.Name() != "Zig" {
+		t.Errorf("unexpected language: %s, %s, %s", l.Slug(), l.ShortName(), l.Name())
+	}
+	if line, _, _ := l.(commenter).commentSyntax(); line != "//" {
+		t.Errorf("unexpected line comment %q", line)
+	}
+	tester, ok := l.(LocalTestable)
+	if !ok {
+		t.Fatal("plugin with local_test should be LocalTestable")
+	}
+	passed, err := tester.RunLocalTest(nil, t.TempDir(), TestOptions{})
+	if err != nil || !passed {
+		t.Errorf("RunLocalTest() = %v, %v", passed, err)
+	}
+	if err := l.InitWorkspace(t.TempDir()); err == nil {
+		t.Errorf("error response should fail the method")
+	}
+}
+
+func TestPluginGenerate(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	q := &leetcode.QuestionData{TitleSlug: "two-sum", QuestionFrontendId: "1"}
+	files := `[{"filename": "1/solution.zig", "type": "code", "content": "fn main() {}"},
+		{"filename": "1/cases.txt", "type": "testcases", "content": "[1]"}]`
+	l, err := loadPlugin(writePlugin(t, t.TempDir(), files))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := l.Generate(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, cases := result.GetFile(CodeFile), result.GetFile(TestCasesFile)
+	if code == nil || code.Filename != "1/solution.zig" || code.Content != "fn main() {}" || cases == nil || cases.Content != "[1]" {
+		t.Errorf("Generate() = %+v", result.Files)
+	}
+	if result.Lang.Slug() != "zig" {
+		t.Errorf("Generate() recorded lang %s", result.Lang.Slug())
+	}
+	result, err = l.GeneratePaths(q)
+	if err != nil || len(result.Files) != 1 || result.Files[0].Filename != "1/solution.zig" || result.Files[0].Content != "" {
+		t.Errorf("GeneratePaths() = %+v, %v", result, err)
+	}
+
+	for _, files := range []string{
+		`[{"filename": "../solution.zig", "type": "code"}]`,
+		`[{"filename": "/tmp/solution.zig", "type": "code"}]`,
+		`[{"filename": "", "type": "code"}]`,
+		`[{"filename": "solution.zig", "type": "binary"}]`,
+		`[{"filename": "README.md", "type": "doc"}]`,
+	} {
+		l, err := loadPlugin(writePlugin(t, t.TempDir(), files))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result, err := l.Generate(q); err == nil {
+			t.Errorf("Generate() with files %s = %+v, want error", files, result.Files)
+		}
+	}
+}
+
+func TestLoadPluginsCachesInfo(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	dir := t.TempDir()
+	path := writePlugin(t, dir, "[]")
+	calls := func() int {
+		data, _ := os.ReadFile(filepath.Join(dir, "calls"))
+		return strings.Count(string(data), "info")
+	}
+
+	for range 2 {
+		langs := loadPlugins(dir)
+		if len(langs) != 1 || langs[0].Slug() != "zig" {
+			t.Fatalf("loadPlugins() = %v", langs)
+		}
+	}
+	if n := calls(); n != 1 {
+		t.Errorf("info is called %d times, want once", n)
+	}
+
+	// A changed plugin is asked again.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if langs := loadPlugins(dir); len(langs) != 1 || !isPlugin(langs[0]) {
+		t.Fatalf("loadPlugins() = %v", langs)
+	}
+	if n := calls(); n != 2 {
+		t.Errorf("info is called %d times after the plugin changed, want twice", n)
+	}
+}
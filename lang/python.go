@@ -31,6 +31,11 @@ func (p python) InitWorkspace(outDir string) error {
 	if should, err := p.shouldInit(outDir); err != nil || !should {
 		return err
 	}
+	if config.SafeMode() {
+		log.Warn("skip initializing workspace in safe mode", "dir", outDir)
+		return nil
+	}
+	defer utils.Track(utils.ProfileSubprocess)()
 
 	pythonExe := config.Get().Code.Python.Executable
 	cmd := exec.Command(pythonExe, "--version")
@@ -57,7 +62,7 @@ func (p python) InitWorkspace(outDir string) error {
 	cmd = exec.Command(pythonExe, "-m", "venv", ".venv")
 	log.Info("creating venv", "cmd", cmd.String())
 	cmd.Dir = outDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = os.Stderr
 	if err = cmd.Run(); err != nil {
 		return err
@@ -76,7 +81,7 @@ func (p python) InitWorkspace(outDir string) error {
 	)
 	log.Info("pip install", "cmd", cmd.String())
 	cmd.Dir = outDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = os.Stderr
 	err = cmd.Run()
 	if err != nil {
@@ -98,19 +103,26 @@ func (p python) shouldInit(outDir string) (bool, error) {
 	return !update, nil
 }
 
-func (p python) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+func (p python) BuildProgram(q *leetcode.QuestionData, genResult *GenerateResult) ([]string, error) {
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	return []string{path.Join(genResult.WorkspaceDir(), ".venv", constants.VenvPython), testFile}, nil
+}
+
+func (p python) RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error) {
 	genResult, err := p.GeneratePaths(q)
 	if err != nil {
 		return false, err
 	}
 	genResult.SetOutDir(outDir)
 
-	testFile := genResult.GetFile(TestFile).GetPath()
-	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	args, err := p.BuildProgram(q, genResult)
+	if err != nil {
+		return false, err
 	}
-	cmd := []string{path.Join(outDir, ".venv", constants.VenvPython), testFile}
-	return runTest(q, genResult, cmd, targetCase)
+	return runTest(q, genResult, args, opts)
 }
 
 func toPythonType(typeName string) string {
@@ -144,40 +156,36 @@ func toPythonType(typeName string) string {
 func (p python) generateNormalTestCode(q *leetcode.QuestionData) (string, error) {
 	code := "if __name__ == \"__main__\":\n"
 
-	paramNames := make([]string, 0, len(q.MetaData.Params))
-	paramTypes := make([]string, 0, len(q.MetaData.Params))
-	for _, param := range q.MetaData.Params {
-		varName := param.Name
-		varType := toPythonType(param.Type)
+	fn := q.MetaData.Function()
+	for _, param := range fn.Params {
+		varType := toPythonType(param.Type.String())
 		code += fmt.Sprintf(
 			"\t%s: %s = deserialize(\"%s\", read_line())\n",
-			varName,
+			param.Name,
 			varType,
 			varType,
 		)
-		paramNames = append(paramNames, param.Name)
-		paramTypes = append(paramTypes, varType)
 	}
-	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+	paramNames := fn.ParamNames()
+	if !fn.IsVoid() {
 		code += fmt.Sprintf(
 			"\tans = Solution().%s(%s)\n",
-			q.MetaData.Name,
+			fn.Name,
 			strings.Join(paramNames, ", "),
 		)
-		code += fmt.Sprintf("\tprint(\"\\n%s\", serialize(ans, \"%s\"))\n", testCaseOutputMark, q.MetaData.Return.Type)
+		code += fmt.Sprintf("\tprint(\"\\n%s\", serialize(ans, \"%s\"))\n", testCaseOutputMark, fn.Return)
 	} else {
 		code += fmt.Sprintf(
 			"\t%s(%s)\n",
-			q.MetaData.Name,
+			fn.Name,
 			strings.Join(paramNames, ", "),
 		)
-		if q.MetaData.Output != nil {
-			ansName := paramNames[q.MetaData.Output.ParamIndex]
-			code += fmt.Sprintf("\tans = %s\n", ansName)
+		if resultType := fn.ResultType(); !resultType.IsVoid() {
+			code += fmt.Sprintf("\tans = %s\n", paramNames[fn.OutputParam])
 			code += fmt.Sprintf(
 				"\tprint(\"\\n%s\", serialize(ans, \"%s\"))\n",
 				testCaseOutputMark,
-				paramTypes[q.MetaData.Output.ParamIndex],
+				toPythonType(resultType.String()),
 			)
 		} else {
 			code += "\tans = None\n"
@@ -202,6 +210,8 @@ func (p python) generateSystemDesignTestCode(q *leetcode.QuestionData) (string,
 	for i in range(1, len(ops)):
 		match ops[i]:
 %s
+			case _:
+				raise ValueError(f"unknown method: {ops[i]}")
 
 	print("\n%s", join_array(output))
 `
@@ -256,7 +266,7 @@ func (p python) generateSystemDesignTestCode(q *leetcode.QuestionData) (string,
 		} else {
 			methodCall += fmt.Sprintf(
 				"\t\t\t\tobj.%s(%s)\n",
-				toPythonType(method.Name),
+				method.Name,
 				strings.Join(methodParamNames, ", "),
 			)
 			methodCall += "\t\t\t\toutput.append(\"null\")\n"
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -44,6 +46,11 @@ func (r rust) InitWorkspace(outDir string) error {
 	if should, err := r.shouldInit(outDir); err != nil || !should {
 		return err
 	}
+	if config.SafeMode() {
+		log.Warn("skip initializing workspace in safe mode", "dir", outDir)
+		return nil
+	}
+	defer utils.Track(utils.ProfileSubprocess)()
 
 	err := utils.RemoveIfExist(filepath.Join(outDir, "Cargo.toml"))
 	if err != nil {
@@ -54,7 +61,7 @@ func (r rust) InitWorkspace(outDir string) error {
 	const packageName = "leetcode-solutions"
 	cmd := exec.Command("cargo", "init", "--bin", "--name", packageName, outDir)
 	log.Info("cargo init", "cmd", cmd.String())
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = os.Stderr
 	cmd.Dir = outDir
 	err = cmd.Run()
@@ -64,7 +71,7 @@ func (r rust) InitWorkspace(outDir string) error {
 	cmd = exec.Command("cargo", "add")
 	cmd.Args = append(cmd.Args, rustDeps...)
 	log.Info("cargo add", "cmd", cmd.String())
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = config.HumanOutput()
 	cmd.Stderr = os.Stderr
 	cmd.Dir = outDir
 	err = cmd.Run()
@@ -76,7 +83,7 @@ func (r rust) InitWorkspace(outDir string) error {
 	return err
 }
 
-func (r rust) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+func (r rust) RunLocalTest(q *leetcode.QuestionData, outDir string, opts TestOptions) (bool, error) {
 	genResult, err := r.GeneratePaths(q)
 	if err != nil {
 		return false, fmt.Errorf("generate paths failed: %w", err)
@@ -94,7 +101,7 @@ func (r rust) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase s
 		return false, fmt.Errorf("build failed: %w", err)
 	}
 
-	return runTest(q, genResult, []string{"cargo", "run", "--quiet", "--bin", q.TitleSlug}, targetCase)
+	return runTest(q, genResult, []string{"cargo", "run", "--quiet", "--bin", q.TitleSlug}, opts)
 }
 
 func toRustType(typeName string) string {
@@ -392,7 +399,7 @@ func (r rust) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
 
 func addBinSection(result *GenerateResult) error {
 	q := result.Question
-	cargoTomlPath := filepath.Join(result.OutDir, "Cargo.toml")
+	cargoTomlPath := filepath.Join(result.WorkspaceDir(), "Cargo.toml")
 	data, err := os.ReadFile(cargoTomlPath)
 	if err != nil {
 		return err
@@ -417,7 +424,7 @@ func addBinSection(result *GenerateResult) error {
 		if binName == q.TitleSlug {
 			bins[i] = map[string]any{
 				"name": q.TitleSlug,
-				"path": filepath.ToSlash(filepath.Join(result.SubDir, "solution.rs")),
+				"path": path.Join(result.workspaceRelDir(), "solution.rs"),
 			}
 			exists = true
 			break
@@ -427,7 +434,7 @@ func addBinSection(result *GenerateResult) error {
 		bins = append(
 			bins, map[string]any{
 				"name": q.TitleSlug,
-				"path": filepath.ToSlash(filepath.Join(result.SubDir, "solution.rs")),
+				"path": path.Join(result.workspaceRelDir(), "solution.rs"),
 			},
 		)
 	}
@@ -446,6 +453,51 @@ func addBinSection(result *GenerateResult) error {
 	return os.WriteFile(cargoTomlPath, data, 0o644)
 }
 
+// dropBinSection removes the [[bin]] section of the question from the Cargo.toml of the workspace, before its directory
+// is removed or moved, otherwise every cargo command in the workspace fails on the missing file.
+func dropBinSection(result *GenerateResult) error {
+	cargoTomlPath := filepath.Join(result.WorkspaceDir(), "Cargo.toml")
+	data, err := os.ReadFile(cargoTomlPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var cargo map[string]any
+	err = toml.Unmarshal(data, &cargo)
+	if err != nil {
+		return err
+	}
+	bins, _ := cargo["bin"].([]any)
+	binPath := path.Join(result.workspaceRelDir(), "solution.rs")
+	kept := slices.DeleteFunc(
+		slices.Clone(bins), func(bin any) bool {
+			b, _ := bin.(map[string]any)
+			return b["path"] == binPath
+		},
+	)
+	if len(kept) == len(bins) {
+		return nil
+	}
+	if len(kept) == 0 {
+		delete(cargo, "bin")
+	} else {
+		cargo["bin"] = kept
+	}
+	data, err = toml.Marshal(cargo)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(cargoTomlPath, data, 0o644)
+	if err != nil {
+		return err
+	}
+	log.Info("dropped from Cargo.toml", "path", binPath)
+	return nil
+}
+
 func (r rust) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
 	filenameTmpl := getFilenameTemplate(q, r)
 	baseFilename, err := q.GetFormattedFilename(r.slug, filenameTmpl)
@@ -1,6 +1,11 @@
 package lang
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestToRustVarName(t *testing.T) {
 	tests := []struct {
@@ -16,3 +21,52 @@ func TestToRustVarName(t *testing.T) {
 		}
 	}
 }
+
+func TestDropBinSection(t *testing.T) {
+	dir := t.TempDir()
+	cargoToml := filepath.Join(dir, "Cargo.toml")
+	content := `[package]
+name = "leetcode-solutions"
+
+[[bin]]
+name = "two-sum"
+path = "src/0001.two-sum/solution.rs"
+
+[[bin]]
+name = "add-two-numbers"
+path = "src/0002.add-two-numbers/solution.rs"
+`
+	if err := os.WriteFile(cargoToml, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := DropFromWorkspace(&GenerateResult{Lang: rustGen, OutDir: dir, SubDir: filepath.Join("src", "0001.two-sum")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(cargoToml)
+	got := string(data)
+	if strings.Contains(got, "two-sum/") || !strings.Contains(got, "src/0002.add-two-numbers/solution.rs") ||
+		!strings.Contains(got, "leetcode-solutions") {
+		t.Errorf("Cargo.toml after drop:\n%s", got)
+	}
+
+	// Questions not in Cargo.toml are left alone.
+	err = DropFromWorkspace(&GenerateResult{Lang: rustGen, OutDir: dir, SubDir: filepath.Join("src", "0003.x")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(cargoToml); string(data) != got {
+		t.Errorf("Cargo.toml changed:\n%s", data)
+	}
+
+	err = DropFromWorkspace(
+		&GenerateResult{Lang: rustGen, OutDir: dir, SubDir: filepath.Join("src", "0002.add-two-numbers")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(cargoToml); strings.Contains(string(data), "[[bin]]") {
+		t.Errorf("Cargo.toml without bins:\n%s", data)
+	}
+}
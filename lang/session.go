@@ -0,0 +1,118 @@
+package lang
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/utils"
+)
+
+// sessionVersion is bumped when the layout of Session changes incompatibly.
+const sessionVersion = 1
+
+// Session describes the last generated question. It is written to `.leetgo/session.json` in the project root,
+// editor plugins can watch it to offer actions on the question without parsing the CLI output.
+type Session struct {
+	Version     int              `json:"version"`
+	Site        string           `json:"site"`
+	Slug        string           `json:"slug"`
+	FrontendID  string           `json:"frontend_id"`
+	Title       string           `json:"title"`
+	Lang        string           `json:"lang"`
+	ProjectRoot string           `json:"project_root"`
+	Dir         string           `json:"dir"`
+	Files       []SessionFile    `json:"files"`
+	CodeMarks   *SessionCodeMark `json:"code_marks,omitempty"`
+	Actions     []SessionAction  `json:"actions"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+type SessionFile struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// SessionCodeMark is the 1-based lines of the code begin and end markers in the code file.
+type SessionCodeMark struct {
+	File      string `json:"file"`
+	BeginLine int    `json:"begin_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// SessionAction is a command that can be run in the project root to act on the question.
+type SessionAction struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+}
+
+func newSession(result *GenerateResult) Session {
+	q := result.Question
+	cfg := config.Get()
+	s := Session{
+		Version:     sessionVersion,
+		Site:        cfg.LeetCode.Site.Short(),
+		Slug:        q.TitleSlug,
+		FrontendID:  q.QuestionFrontendId,
+		Title:       q.GetTitle(),
+		Lang:        result.Lang.Slug(),
+		ProjectRoot: cfg.ProjectRoot(),
+		Dir:         result.TargetDir(),
+		GeneratedAt: time.Now(),
+	}
+	for _, f := range result.Files {
+		s.Files = append(s.Files, SessionFile{Type: f.Type.String(), Path: f.GetPath()})
+	}
+	if f := result.GetFile(CodeFile); f != nil {
+		// The existing file may be kept instead of overwritten, so the markers are looked up in the file on disk.
+		content := f.Content
+		if data, err := os.ReadFile(f.GetPath()); err == nil {
+			content = string(data)
+		}
+		s.CodeMarks = findCodeMarks(f.GetPath(), content)
+	}
+
+	qid := q.TitleSlug
+	s.Actions = []SessionAction{
+		{Name: "test", Command: []string{constants.CmdName, "test", qid, "-L"}},
+		{Name: "test_remote", Command: []string{constants.CmdName, "test", qid, "-R"}},
+		{Name: "submit", Command: []string{constants.CmdName, "submit", qid}},
+		{Name: "test_and_submit", Command: []string{constants.CmdName, "test", qid, "-L", "-s"}},
+		{Name: "info", Command: []string{constants.CmdName, "info", qid}},
+		{Name: "open", Command: []string{constants.CmdName, "open", qid}},
+	}
+	return s
+}
+
+func findCodeMarks(file, content string) *SessionCodeMark {
+	marks := &SessionCodeMark{File: file}
+	for i, line := range strings.Split(content, "\n") {
+		switch {
+		case marks.BeginLine == 0 && isCodeBegin(line):
+			marks.BeginLine = i + 1
+		case marks.BeginLine != 0 && codeEndRe.MatchString(line):
+			marks.EndLine = i + 1
+			return marks
+		}
+	}
+	return nil
+}
+
+// writeSession writes the session file of the generated question, failures are only logged.
+func writeSession(result *GenerateResult) {
+	data, err := json.MarshalIndent(newSession(result), "", "  ")
+	if err != nil {
+		log.Debug("failed to encode session", "err", err)
+		return
+	}
+	file := config.Get().SessionFile()
+	err = utils.WriteFile(file, data)
+	if err != nil {
+		log.Warn("failed to write session file", "file", file, "err", err)
+	}
+}
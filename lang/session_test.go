@@ -0,0 +1,43 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestFindCodeMarks(t *testing.T) {
+	content := "// the marker @lc code=begin is quoted here\n" +
+		"// @lc code=begin\n" +
+		"func twoSum() {}\n" +
+		"// @lc code=end\r\n"
+	marks := findCodeMarks("solution.go", content)
+	if marks == nil || marks.BeginLine != 2 || marks.EndLine != 4 {
+		t.Errorf("findCodeMarks() = %+v, want lines 2 to 4", marks)
+	}
+	if marks := findCodeMarks("solution.go", "// @lc code=begin\nfunc twoSum() {}\n"); marks != nil {
+		t.Errorf("findCodeMarks() without end marker = %+v", marks)
+	}
+}
+
+func TestNewSessionKeptFile(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CONFIG_DIR", t.TempDir())
+	dir := t.TempDir()
+	q := &leetcode.QuestionData{TitleSlug: "two-sum", QuestionFrontendId: "1"}
+	q.SetClient(leetcode.NewClient(leetcode.NonAuth()))
+	result := &GenerateResult{Question: q, Lang: golangGen, OutDir: dir}
+	result.AddFile(FileOutput{Filename: "solution.go", Type: CodeFile, Content: "// @lc code=begin\n\n// @lc code=end\n"})
+
+	// The user's file has notes above the code, it was kept rather than overwritten.
+	kept := "package main\n\n// notes\n// @lc code=begin\nfunc twoSum() {\n}\n// @lc code=end\n"
+	if err := os.WriteFile(filepath.Join(dir, "solution.go"), []byte(kept), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	marks := newSession(result).CodeMarks
+	if marks == nil || marks.BeginLine != 4 || marks.EndLine != 7 {
+		t.Errorf("code marks = %+v, want lines 4 to 7 of the file on disk", marks)
+	}
+}
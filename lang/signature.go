@@ -0,0 +1,177 @@
+package lang
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// SignatureChange is a function signature of the official snippet that differs from the generated code.
+type SignatureChange struct {
+	// Old is the signature in the generated code, empty if the function is not found.
+	Old string
+	// New is the signature in the official snippet, after running the configured modifiers.
+	New string
+}
+
+var (
+	// cDeclaration matches C-like declarations with a return type, e.g. `vector<int> twoSum(vector<int>& nums) {`.
+	// Parameters can't contain parentheses, so that calls taking lambdas are not matched.
+	cDeclaration = regexp.MustCompile(`^(?:[\w:<>\[\],.?]+[*&]*\s+)+[*&]*(\w+)\s*\([^()]*\)\s*(?:const\s*)?\{$`)
+	// ctorDeclaration matches constructors and methods without a return type, e.g. `LRUCache(int capacity) {`.
+	ctorDeclaration = regexp.MustCompile(`^(\w+)\s*\([^()]*\)\s*(?::[^{}=]+)?\{$`)
+
+	declarationPatterns = map[string][]*regexp.Regexp{
+		"golang":  {regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?(\w+)\s*[\[(].*\{$`)},
+		"python3": {regexp.MustCompile(`^def\s+(\w+)\s*\(.*:$`)},
+		"rust":    {regexp.MustCompile(`^(?:pub(?:\([^)]*\))?\s+)?fn\s+(\w+)\s*[<(].*\{$`)},
+		"kotlin":  {regexp.MustCompile(`^(?:\w+\s+)*fun\s+(?:<[^>]*>\s*)?(\w+)\s*\(.*\{$`)},
+		"swift": {
+			regexp.MustCompile(`^(?:\w+\s+)*func\s+(\w+)\s*[<(].*\{$`),
+			regexp.MustCompile(`^(?:\w+\s+)*(init)\s*\(.*\{$`),
+		},
+		"scala":  {regexp.MustCompile(`^(?:\w+\s+)*def\s+(\w+)\s*[\[(].*[={]$`)},
+		"php":    {regexp.MustCompile(`^(?:\w+\s+)*function\s+(\w+)\s*\(.*\{$`)},
+		"ruby":   {regexp.MustCompile(`^def\s+(\w+[?!]?)\s*(?:\(.*\))?$`)},
+		"elixir": {regexp.MustCompile(`^defp?\s+(\w+[?!]?)\s*\(.*\)\s*do$`)},
+		"javascript": {
+			regexp.MustCompile(`^(?:var|let|const)\s+(\w+)\s*=\s*function\s*\(.*\{$`),
+			regexp.MustCompile(`^function\s+(\w+)\s*\(.*\{$`),
+			ctorDeclaration,
+		},
+		"typescript": {
+			regexp.MustCompile(`^function\s+(\w+)\s*[<(].*\{$`),
+			ctorDeclaration,
+		},
+		"cpp":    {cDeclaration, ctorDeclaration},
+		"c":      {cDeclaration},
+		"java":   {cDeclaration},
+		"csharp": {cDeclaration},
+		"dart":   {cDeclaration, ctorDeclaration},
+	}
+
+	// notDeclarations are keywords that look like function names or return types in control statements.
+	notDeclarations = []string{
+		"if", "else", "for", "foreach", "while", "switch", "catch", "return", "new", "do", "using", "lock", "fixed",
+		"synchronized", "throw", "await", "yield", "case", "when", "with",
+	}
+)
+
+// signatureName returns the function name if the trimmed line is a function declaration in the syntax of the language.
+func signatureName(lang Lang, line string) string {
+	for _, p := range declarationPatterns[lang.Slug()] {
+		m := p.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		first, _, _ := strings.Cut(line, " ")
+		if slices.Contains(notDeclarations, m[1]) || slices.Contains(notDeclarations, first) {
+			continue
+		}
+		return m[1]
+	}
+	return ""
+}
+
+// extractSignatures returns the trimmed lines of function declarations in the code.
+func extractSignatures(lang Lang, code string) []string {
+	var sigs []string
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		if signatureName(lang, line) != "" {
+			sigs = append(sigs, line)
+		}
+	}
+	return sigs
+}
+
+// CheckSignatures compares the function signatures of the official code snippet with the generated code,
+// it detects parameters added or renamed by LeetCode after the code was generated.
+func CheckSignatures(q *leetcode.QuestionData) ([]SignatureChange, error) {
+	gen, err := GetGenerator(config.Get().Code.Lang)
+	if err != nil {
+		return nil, err
+	}
+	gen = GeneratorFor(q, gen)
+	err = q.FulfillFields(leetcode.FieldsSnippet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question data: %w", err)
+	}
+	snippet := q.GetCodeSnippet(gen.Slug())
+	if snippet == "" {
+		return nil, nil
+	}
+	modifiersMap := builtinModifiers
+	if gen.Slug() == golangGen.Slug() {
+		modifiersMap = goBuiltinModifiers
+	}
+	modifiers, err := getModifiers(gen, modifiersMap)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range modifiers {
+		snippet = m(snippet, q)
+	}
+
+	code, err := GetSolutionCode(q)
+	if err != nil {
+		return nil, err
+	}
+	return diffSignatures(gen, snippet, code), nil
+}
+
+// diffSignatures returns the signatures of the snippet that are not found in the code.
+func diffSignatures(gen Lang, snippet string, code string) []SignatureChange {
+	current := extractSignatures(gen, code)
+	var changes []SignatureChange
+	for _, sig := range extractSignatures(gen, snippet) {
+		found := false
+		old := ""
+		for _, s := range current {
+			if s == sig {
+				found = true
+				break
+			}
+			if old == "" && signatureName(gen, s) == signatureName(gen, sig) {
+				old = s
+			}
+		}
+		if !found {
+			changes = append(changes, SignatureChange{Old: old, New: sig})
+		}
+	}
+	return changes
+}
+
+// UpdateSignatures replaces the changed signatures in the generated code, function bodies are kept.
+// Functions not found in the generated code are skipped.
+func UpdateSignatures(q *leetcode.QuestionData, changes []SignatureChange) error {
+	code, err := GetSolutionCode(q)
+	if err != nil {
+		return err
+	}
+	// UpdateSolutionCode puts a blank line after the begin marker, which the extracted code starts with already.
+	return UpdateSolutionCode(q, strings.TrimPrefix(replaceSignatures(code, changes), "\n"))
+}
+
+// replaceSignatures replaces the old signature lines with the new ones, keeping their indentation.
+func replaceSignatures(code string, changes []SignatureChange) string {
+	lines := strings.Split(code, "\n")
+	for _, c := range changes {
+		if c.Old == "" {
+			continue
+		}
+		for i, line := range lines {
+			if strings.TrimSpace(line) == c.Old {
+				indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+				lines[i] = indent + c.New
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
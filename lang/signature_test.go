@@ -0,0 +1,164 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+func TestExtractSignatures(t *testing.T) {
+	cases := []struct {
+		lang Lang
+		code string
+		sigs []string
+		name string
+	}{
+		{golangGen, "func twoSum(nums []int, target int) []int {\n\n}", []string{"func twoSum(nums []int, target int) []int {"}, "twoSum"},
+		{golangGen, "func (c *LRUCache) Get(key int) int {\n}", []string{"func (c *LRUCache) Get(key int) int {"}, "Get"},
+		{
+			python3Gen,
+			"class Solution:\n    def twoSum(self, nums: List[int], target: int) -> List[int]:\n        ",
+			[]string{"def twoSum(self, nums: List[int], target: int) -> List[int]:"},
+			"twoSum",
+		},
+		{
+			cppGen,
+			"// helper(a) {\nvector<int> twoSum(vector<int>& nums, int target) {",
+			[]string{"vector<int> twoSum(vector<int>& nums, int target) {"},
+			"twoSum",
+		},
+		{cppGen, "LRUCache(int capacity) {\n}", []string{"LRUCache(int capacity) {"}, "LRUCache"},
+		{javaGen, "public int[] twoSum(int[] nums, int target) {", []string{"public int[] twoSum(int[] nums, int target) {"}, "twoSum"},
+		{
+			rustGen,
+			"impl Solution {\n    pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {",
+			[]string{"pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {"},
+			"two_sum",
+		},
+		{
+			jsGen,
+			"var twoSum = function(nums, target) {\n};",
+			[]string{"var twoSum = function(nums, target) {"},
+			"twoSum",
+		},
+	}
+	for _, c := range cases {
+		sigs := extractSignatures(c.lang, c.code)
+		if !slices.Equal(sigs, c.sigs) {
+			t.Errorf("extract signatures of %q: got %v, want %v", c.code, sigs, c.sigs)
+			continue
+		}
+		if name := signatureName(c.lang, sigs[0]); name != c.name {
+			t.Errorf("signature name of %q: got %s, want %s", sigs[0], name, c.name)
+		}
+	}
+}
+
+func TestExtractSignaturesIgnoresStatements(t *testing.T) {
+	cases := []struct {
+		lang Lang
+		code string
+	}{
+		{golangGen, "if check(x) {\n} else if check(y) {\nfor i := range f(n) {\nsort.Slice(a, func(i, j int) bool {"},
+		{cppGen, "if (check(x)) {\nwhile (n) {\nelse if (x) {\nsort(a.begin(), a.end(), [](int x, int y) {"},
+		{cppGen, "for (int i = 0; i < n; i++) {\nswitch (x) {\nauto cmp = [](int a, int b) {"},
+		{javaGen, "} else if (x > 0) {\nnew Comparator<Integer>() {\nsynchronized (lock) {\ncatch (Exception e) {"},
+		{python3Gen, "if check(x):\nfor x in range(n):\nwhile helper(x):\nelif f(x):"},
+		{rustGen, "if check(x) {\nmatch f(x) {\nlet v = foo(x) {"},
+		{jsGen, "if (check(x)) {\nnums.forEach(function(x) {\nfor (const x of f(n)) {"},
+	}
+	for _, c := range cases {
+		if sigs := extractSignatures(c.lang, c.code); len(sigs) != 0 {
+			t.Errorf("extract signatures of %s %q: got %v, want none", c.lang.Slug(), c.code, sigs)
+		}
+	}
+}
+
+func TestCheckAndUpdateSignatures(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("code.golang.out_dir", filepath.ToSlash(dir))
+	t.Cleanup(func() { viper.Set("code.golang.out_dir", "") })
+
+	q := &leetcode.QuestionData{
+		TitleSlug:          "two-sum",
+		QuestionFrontendId: "1",
+		QuestionId:         "1",
+		Title:              "Two Sum",
+		Difficulty:         "Easy",
+		Content:            "<p>Given an array of integers.</p>",
+		SampleTestCase:     "[2,7,11,15]\n9",
+		ExampleTestcases:   "[2,7,11,15]\n9",
+		MetaData: leetcode.MetaData{
+			Name: "twoSum",
+			Params: []leetcode.MetaDataParam{
+				{Name: "nums", Type: "integer[]"},
+				{Name: "target", Type: "integer"},
+			},
+			Return: &leetcode.MetaDataReturn{Type: "integer[]"},
+		},
+		CodeSnippets: []leetcode.CodeSnippet{
+			{LangSlug: "golang", Code: "func twoSum(nums []int, target int) []int {\n    \n}"},
+		},
+	}
+	q.SetClient(leetcode.NewClient(leetcode.NonAuth()))
+	result, err := golangGen.Generate(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outDir, err := getOutDir(q, golangGen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.SetOutDir(outDir)
+	for _, f := range result.Files {
+		if err := utils.WriteFile(f.GetPath(), []byte(f.Content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	codeFile := result.GetFile(CodeFile).GetPath()
+
+	// The user's solution calls a helper in a statement that looks like a declaration.
+	err = UpdateSolutionCode(
+		q,
+		"func twoSum(nums []int, target int) []int {\n\tif found(nums) {\n\t\treturn nil\n\t}\n\treturn nums\n}\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := CheckSignatures(q)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("CheckSignatures() of an unchanged question = %v, %v", changes, err)
+	}
+
+	// LeetCode renamed a parameter.
+	q.CodeSnippets[0].Code = "func twoSum(numbers []int, target int) []int {\n    \n}"
+	changes, err = CheckSignatures(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SignatureChange{
+		{Old: "func twoSum(nums []int, target int) []int {", New: "func twoSum(numbers []int, target int) []int {"},
+	}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("CheckSignatures() = %v, want %v", changes, want)
+	}
+	before, _ := os.ReadFile(codeFile)
+	if err = UpdateSignatures(q, changes); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(codeFile)
+	wantContent := strings.Replace(string(before), want[0].Old, want[0].New, 1)
+	if string(after) != wantContent {
+		t.Errorf("UpdateSignatures() wrote:\n%s\nwant:\n%s", after, wantContent)
+	}
+	if changes, _ = CheckSignatures(q); len(changes) != 0 {
+		t.Errorf("CheckSignatures() after update = %v", changes)
+	}
+}
@@ -0,0 +1,206 @@
+package lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/list"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// StressOptions controls a stress test run.
+type StressOptions struct {
+	Count  int
+	Seed   uint64
+	Limits leetcode.InputLimits
+}
+
+func getStressPaths(q *leetcode.QuestionData) (Buildable, *GenerateResult, error) {
+	cfg := config.Get()
+	gen, err := GetGenerator(cfg.Code.Lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	builder, ok := gen.(Buildable)
+	if !ok {
+		return nil, nil, fmt.Errorf("language %s does not support stress test", gen.Slug())
+	}
+	err = q.Fulfill()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get question data: %w", err)
+	}
+	outDir, err := getOutDir(q, gen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !utils.IsExist(outDir) {
+		return nil, nil, fmt.Errorf("no code generated for %s in language %s", q.TitleSlug, gen.Slug())
+	}
+	genResult, err := gen.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+	return builder, genResult, nil
+}
+
+// InitBrute copies the solution into the brute/ sub directory, as a starting point of the brute-force solution.
+func InitBrute(q *leetcode.QuestionData) (*GenerateResult, error) {
+	_, genResult, err := getStressPaths(q)
+	if err != nil {
+		return nil, err
+	}
+	brute := genResult.Brute()
+	src := genResult.GetFile(CodeFile)
+	dst := brute.GetFile(CodeFile)
+	if utils.IsExist(dst.GetPath()) {
+		return nil, fmt.Errorf("file %s already exists", utils.RelToCwd(dst.GetPath()))
+	}
+	content, err := src.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	err = utils.WriteFile(dst.GetPath(), []byte(content))
+	if err != nil {
+		return nil, err
+	}
+	return brute, nil
+}
+
+// RunStressTest runs both the solution and the brute-force solution in brute/ on random inputs,
+// and returns the first input on which they disagree, with the output of the brute-force solution as expected.
+// It returns nil if they agree on all inputs.
+func RunStressTest(q *leetcode.QuestionData, opts StressOptions) (*TestCase, error) {
+	if config.SafeMode() {
+		return nil, fmt.Errorf("stress test: %w", config.ErrSafeMode)
+	}
+	builder, genResult, err := getStressPaths(q)
+	if err != nil {
+		return nil, err
+	}
+	brute := genResult.Brute()
+	if !utils.IsExist(brute.GetFile(CodeFile).GetPath()) {
+		return nil, fmt.Errorf(
+			"brute-force solution %s not found, run with --init to create it",
+			utils.RelToCwd(brute.GetFile(CodeFile).GetPath()),
+		)
+	}
+
+	gen, err := leetcode.NewInputGenerator(q, opts.Limits, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	args, err := builder.BuildProgram(q, genResult)
+	if err != nil {
+		return nil, err
+	}
+	bruteArgs, err := builder.BuildProgram(q, brute)
+	if err != nil {
+		return nil, err
+	}
+
+	judger, err := GetQuestionJudger(q, genResult)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("running stress test", "count", opts.Count, "seed", opts.Seed)
+	for i := 1; i <= opts.Count; i++ {
+		input, err := gen.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("generate input: %w", err)
+		}
+		c := TestCase{Question: q, No: i, Input: input}
+
+		expected, stdout, err := runProgram(genResult.TargetDir(), bruteArgs, c.InputString())
+		if err == nil {
+			err = checkOutput(q, input, expected)
+		}
+		if err != nil {
+			showStressFailure(q, c, "Brute-force solution failed", err.Error(), "", stdout)
+			return nil, fmt.Errorf("brute-force solution failed on random input %d", i)
+		}
+		c.Output = expected
+
+		actual, stdout, err := runProgram(genResult.TargetDir(), args, c.InputString())
+		if err == nil {
+			err = checkOutput(q, input, actual)
+		}
+		if err != nil {
+			showStressFailure(q, c, "Runtime error", err.Error(), actual, stdout)
+			return &c, nil
+		}
+		if r := judger.Judge(input, expected, actual); !r.IsAccepted() {
+			showStressFailure(q, c, "Wrong answer", r.GetInfo(), actual, stdout)
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+var errTimeLimitExceeded = errors.New("time limit exceeded")
+
+// runProgram runs a built solution on a single input, and returns its output and the other stdout lines.
+func runProgram(dir string, args []string, input string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	defer cancel()
+
+	outputBuf := new(strings.Builder)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = outputBuf
+	cmd.Stderr = outputBuf
+	done := utils.Track(utils.ProfileSubprocess)
+	err := cmd.Run()
+	done()
+	output, stdout := extractOutput(outputBuf.String())
+	if ctx.Err() != nil {
+		return output, stdout, errTimeLimitExceeded
+	}
+	return output, stdout, err
+}
+
+func showStressFailure(q *leetcode.QuestionData, c TestCase, title, reason, actual, stdout string) {
+	l := list.NewWriter()
+	if config.Get().UsePlainOutput() {
+		l.SetStyle(list.StyleDefault)
+	} else {
+		l.SetStyle(list.StyleBulletCircle)
+	}
+	l.AppendItem(fmt.Sprintf("Input %d:   %s", c.No, config.FailedStyle.Render(title)))
+	l.Indent()
+	l.AppendItem(fmt.Sprintf("Reason:     %s", reason))
+	l.AppendItem(
+		fmt.Sprintf(
+			"Input:      %s",
+			utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", config.NewlineMark()), 100),
+		),
+	)
+	if c.HasOutput() && actual != "" {
+		if diff := q.DiffOutputs(actual, c.Output); diff != nil {
+			l.AppendItem(fmt.Sprintf("Output:     %s", diff.Output))
+			l.AppendItem(fmt.Sprintf("Expected:   %s", diff.Expected))
+			for _, line := range diff.Tree {
+				l.AppendItem(fmt.Sprintf("Tree:       %s", line))
+			}
+		} else {
+			l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actual, 100)))
+			l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
+		}
+	} else if c.HasOutput() {
+		l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
+	}
+	if stdout != "" {
+		l.AppendItem(fmt.Sprintf("Stdout:     %s", config.StdoutStyle.Render(utils.TruncateString(stdout, 1000))))
+	}
+	l.UnIndent()
+	fmt.Fprintln(config.HumanOutput(), l.Render())
+}
@@ -18,12 +18,16 @@ import (
 	"github.com/j178/leetgo/utils"
 )
 
-func RunLocalTest(q *leetcode.QuestionData, targetCase string) (bool, error) {
+func RunLocalTest(q *leetcode.QuestionData, opts TestOptions) (bool, error) {
+	if config.SafeMode() {
+		return false, fmt.Errorf("local test: %w", config.ErrSafeMode)
+	}
 	cfg := config.Get()
 	gen, err := GetGenerator(cfg.Code.Lang)
 	if err != nil {
 		return false, err
 	}
+	gen = GeneratorFor(q, gen)
 	tester, ok := gen.(LocalTestable)
 	if !ok {
 		return false, fmt.Errorf("language %s does not support local test", gen.Slug())
@@ -32,12 +36,27 @@ func RunLocalTest(q *leetcode.QuestionData, targetCase string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("failed to get question data: %w", err)
 	}
-	outDir := getOutDir(q, gen)
+	outDir, err := getOutDir(q, gen)
+	if err != nil {
+		return false, err
+	}
 	if !utils.IsExist(outDir) {
 		return false, fmt.Errorf("no code generated for %s in language %s", q.TitleSlug, gen.Slug())
 	}
 
-	return tester.RunLocalTest(q, outDir, targetCase)
+	passed, err := tester.RunLocalTest(q, outDir, opts)
+	event := config.Event{
+		Type:       config.EventTestPassed,
+		Slug:       q.TitleSlug,
+		FrontendID: q.QuestionFrontendId,
+		Lang:       gen.Slug(),
+		Runner:     "local",
+	}
+	if !passed {
+		event.Type = config.EventTestFailed
+	}
+	config.EmitEvent(event)
+	return passed, err
 }
 
 // typeNameToType converts a Go type name to reflect.Type.
@@ -82,6 +101,11 @@ func deserialize(tpName string, raw string) (reflect.Value, error) {
 	return goutils.DeserializeValue(ty, raw)
 }
 
+// canDeserialize reports whether values of the type can be parsed by deserialize.
+func canDeserialize(tpName string) bool {
+	return typeNameToType(toGoType(tpName)) != nil
+}
+
 // extractOutput extracts the output from the stdout of the test program.
 func extractOutput(s string) (string, string) {
 	var output string
@@ -142,15 +166,17 @@ func buildTest(_ *leetcode.QuestionData, genResult *GenerateResult, args []strin
 	} else {
 		log.Info("building", "file", utils.RelToCwd(testFile))
 	}
+	done := utils.Track(utils.ProfileSubprocess)
 	err := cmd.Run()
+	done()
 	if err != nil {
-		fmt.Println(config.StdoutStyle.Render(strings.TrimSuffix(buf.String(), "\n")))
+		fmt.Fprintln(config.HumanOutput(), config.StdoutStyle.Render(strings.TrimSuffix(buf.String(), "\n")))
 		return err
 	}
 	return nil
 }
 
-func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string, targetCaseStr string) (bool, error) {
+func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string, opts TestOptions) (bool, error) {
 	testcaseFile := genResult.GetFile(TestCasesFile)
 	if testcaseFile == nil {
 		panic("no test cases file generated")
@@ -162,20 +188,30 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 	if len(tc.Cases) == 0 {
 		return false, fmt.Errorf("no test cases found")
 	}
-	caseRange, err := ParseRange(targetCaseStr, len(tc.Cases))
+	caseRange, err := ParseRange(opts.TargetCase, len(tc.Cases))
 	if err != nil {
 		return false, err
 	}
 
-	judger := GetJudger(q)
+	judger, err := GetQuestionJudger(q, genResult)
+	if err != nil {
+		return false, err
+	}
 
 	var ran, passed int
 	for _, c := range tc.Cases {
+		if opts.FailFast && passed < ran {
+			break
+		}
 		func() {
 			l := list.NewWriter()
-			l.SetStyle(list.StyleBulletCircle)
+			if config.Get().UsePlainOutput() {
+				l.SetStyle(list.StyleDefault)
+			} else {
+				l.SetStyle(list.StyleBulletCircle)
+			}
 			defer func() {
-				fmt.Println(l.Render())
+				fmt.Fprintln(config.HumanOutput(), l.Render())
 			}()
 			if !caseRange.Contains(c.No) {
 				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.SkippedStyle.Render("Skipped")))
@@ -197,10 +233,13 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 
 			outputBuf := new(strings.Builder)
 			cmd := exec.CommandContext(ctx, args[0], args[1:]...)
-			cmd.Dir = genResult.OutDir
+			// Run in the question directory, where the attachments are downloaded.
+			cmd.Dir = genResult.TargetDir()
 			cmd.Stdin = strings.NewReader(c.InputString())
 			cmd.Stdout = outputBuf
 			cmd.Stderr = outputBuf
+			done := utils.Track(utils.ProfileSubprocess)
+			defer done()
 			err = cmd.Start()
 			if err != nil {
 				l.AppendItem(
@@ -227,7 +266,7 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 				l.AppendItem(
 					fmt.Sprintf(
 						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
+						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", config.NewlineMark()), 100),
 					),
 				)
 				mayAppendStdout()
@@ -240,7 +279,7 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 				l.AppendItem(
 					fmt.Sprintf(
 						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
+						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", config.NewlineMark()), 100),
 					),
 				)
 				mayAppendStdout()
@@ -254,7 +293,7 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 				l.AppendItem(
 					fmt.Sprintf(
 						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
+						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", config.NewlineMark()), 100),
 					),
 				)
 				l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
@@ -273,11 +312,19 @@ func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string,
 				l.AppendItem(
 					fmt.Sprintf(
 						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
+						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", config.NewlineMark()), 100),
 					),
 				)
-				l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
-				l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
+				if diff := q.DiffOutputs(actualOutput, c.Output); diff != nil {
+					l.AppendItem(fmt.Sprintf("Output:     %s", diff.Output))
+					l.AppendItem(fmt.Sprintf("Expected:   %s", diff.Expected))
+					for _, line := range diff.Tree {
+						l.AppendItem(fmt.Sprintf("Tree:       %s", line))
+					}
+				} else {
+					l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
+					l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
+				}
 				mayAppendStdout()
 				l.UnIndent()
 			}
@@ -44,11 +44,14 @@ func (c *TestCase) Check() error {
 		}
 		l2, err := goutils.SplitArray(c.Input[1])
 		if err != nil {
-			return fmt.Errorf("%s is not a valid list", c.Input[0])
+			return fmt.Errorf("%s is not a valid list", c.Input[1])
 		}
 		if l1.Len() != len(l2) {
 			return fmt.Errorf("input[0] and input[1] should have the same length")
 		}
+		if err := checkOperations(q, l1.Interface().([]string), l2); err != nil {
+			return err
+		}
 		if c.HasOutput() {
 			l3, err := goutils.SplitArray(c.Output)
 			if err != nil {
@@ -80,6 +83,46 @@ func (c *TestCase) Check() error {
 	return nil
 }
 
+// checkOperations checks the operations of a system design test case against the constructor and
+// methods of the class, the first operation constructs the object and the rest call its methods.
+func checkOperations(q *leetcode.QuestionData, ops []string, args []string) error {
+	methods := make(map[string]leetcode.Signature, len(q.MetaData.Methods))
+	for _, m := range q.MetaData.MethodSignatures() {
+		methods[m.Name] = m
+	}
+	for i, op := range ops {
+		sig, ok := methods[op]
+		if i == 0 {
+			sig = q.MetaData.ConstructorSignature()
+			ok = sig.Name == "" || op == sig.Name
+		}
+		if !ok {
+			if i == 0 {
+				return fmt.Errorf("the first operation should be %s, got %s", q.MetaData.ClassName, op)
+			}
+			return fmt.Errorf("unknown method %s at index %d", op, i)
+		}
+		params, err := goutils.SplitArray(args[i])
+		if err != nil {
+			return fmt.Errorf("arguments of %s at index %d is not a valid list", op, i)
+		}
+		if len(params) != len(sig.Params) {
+			return fmt.Errorf("%s takes %d arguments, got %d at index %d", op, len(sig.Params), len(params), i)
+		}
+		for j, param := range params {
+			tp := sig.Params[j].Type.String()
+			// Class types such as NestedInteger and Node are not supported by deserialize, only their count is checked.
+			if !canDeserialize(tp) {
+				continue
+			}
+			if _, err := deserialize(tp, param); err != nil {
+				return fmt.Errorf("cannot parse %s as %s for %s at index %d", param, tp, op, i)
+			}
+		}
+	}
+	return nil
+}
+
 func (c *TestCase) InputString() string {
 	return utils.EnsureTrailingNewline(strings.Join(c.Input, "\n"))
 }
@@ -0,0 +1,77 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+	goutils "github.com/j178/leetgo/testutils/go"
+)
+
+func TestCheckOperations(t *testing.T) {
+	q := &leetcode.QuestionData{
+		MetaData: leetcode.MetaData{
+			SystemDesign: true,
+			ClassName:    "LRUCache",
+			Constructor: leetcode.MetaDataConstructor{
+				Params: []leetcode.MetaDataParam{{Name: "capacity", Type: "integer"}},
+			},
+			Methods: []leetcode.MetaDataMethod{
+				{
+					Name:   "get",
+					Params: []leetcode.MetaDataParam{{Name: "key", Type: "integer"}},
+					Return: leetcode.MetaDataReturn{Type: "integer"},
+				},
+				{
+					Name:   "put",
+					Params: []leetcode.MetaDataParam{{Name: "key", Type: "integer"}, {Name: "value", Type: "integer"}},
+					Return: leetcode.MetaDataReturn{Type: "void"},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		ops  []string
+		args string
+		ok   bool
+	}{
+		{[]string{"LRUCache", "put", "get"}, `[[2],[1,1],[1]]`, true},
+		{[]string{"Cache", "put", "get"}, `[[2],[1,1],[1]]`, false},
+		{[]string{"LRUCache", "delete"}, `[[2],[1]]`, false},
+		{[]string{"LRUCache", "put"}, `[[2],[1]]`, false},
+		{[]string{"LRUCache", "get"}, `[[2],["a"]]`, false},
+	}
+	for _, c := range cases {
+		args, err := goutils.SplitArray(c.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = checkOperations(q, c.ops, args)
+		if (err == nil) != c.ok {
+			t.Errorf("check %v %s: err = %v, want ok = %v", c.ops, c.args, err, c.ok)
+		}
+	}
+}
+
+func TestCheckOperationsClassType(t *testing.T) {
+	q := &leetcode.QuestionData{
+		MetaData: leetcode.MetaData{
+			SystemDesign: true,
+			ClassName:    "NestedIterator",
+			Constructor: leetcode.MetaDataConstructor{
+				Params: []leetcode.MetaDataParam{{Name: "nestedList", Type: "list<NestedInteger>"}},
+			},
+			Methods: []leetcode.MetaDataMethod{
+				{Name: "next", Return: leetcode.MetaDataReturn{Type: "integer"}},
+				{Name: "hasNext", Return: leetcode.MetaDataReturn{Type: "boolean"}},
+			},
+		},
+	}
+	args, err := goutils.SplitArray(`[[[[1,1],2,[1,1]]],[],[]]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkOperations(q, []string{"NestedIterator", "hasNext", "next"}, args)
+	if err != nil {
+		t.Errorf("class typed arguments should be accepted: %v", err)
+	}
+}
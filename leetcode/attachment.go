@@ -0,0 +1,131 @@
+package leetcode
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/log"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+// attachmentExts are extensions of data files that some questions link to.
+var attachmentExts = map[string]bool{
+	".txt":  true,
+	".csv":  true,
+	".tsv":  true,
+	".json": true,
+	".in":   true,
+	".out":  true,
+	".xml":  true,
+	".zip":  true,
+	".gz":   true,
+}
+
+// Attachment is a data file linked in the question content.
+type Attachment struct {
+	Name string
+	URL  string
+}
+
+// Attachments returns the data files linked in the question content, e.g. input files of some questions.
+func (q *QuestionData) Attachments() []Attachment {
+	var base *url.URL
+	if q.client != nil {
+		base, _ = url.Parse(q.client.BaseURI())
+	}
+
+	var attachments []Attachment
+	seen := make(map[string]bool)
+	for _, content := range []string{q.Content, q.TranslatedContent} {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+		if err != nil {
+			continue
+		}
+		doc.Find("a[href]").Each(
+			func(_ int, s *goquery.Selection) {
+				href, _ := s.Attr("href")
+				u, err := url.Parse(strings.TrimSpace(href))
+				if err != nil {
+					return
+				}
+				if !u.IsAbs() {
+					if base == nil {
+						return
+					}
+					u = base.ResolveReference(u)
+				}
+				if u.Scheme != "http" && u.Scheme != "https" {
+					return
+				}
+				name := path.Base(u.Path)
+				if !attachmentExts[strings.ToLower(path.Ext(name))] || seen[name] {
+					return
+				}
+				seen[name] = true
+				attachments = append(attachments, Attachment{Name: name, URL: u.String()})
+			},
+		)
+	}
+	return attachments
+}
+
+// attachmentClient downloads attachments, some of them are large but a stalled download should not hang forever.
+var attachmentClient = &http.Client{Timeout: 5 * time.Minute}
+
+// Download saves the attachment into dir, existing files are kept.
+// The file is written to a temporary file first, so that a failed download leaves nothing behind.
+func (a Attachment) Download(dir string) (string, error) {
+	file := filepath.Join(dir, a.Name)
+	if utils.IsExist(file) {
+		log.Debug("attachment already exists", "file", file)
+		return file, nil
+	}
+
+	if config.Offline() {
+		return "", ErrOffline
+	}
+	resp, err := attachmentClient.Get(a.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", a.URL, resp.Status)
+	}
+
+	err = utils.MakeDir(dir)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "."+a.Name+".*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	_, err = io.Copy(f, resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", a.URL, err)
+	}
+	err = f.Close()
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(f.Name(), file)
+	if err != nil {
+		return "", err
+	}
+	return file, nil
+}
@@ -0,0 +1,57 @@
+package leetcode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	q := &QuestionData{
+		Content: `<p>Read from <a href="https://assets.leetcode.com/data/input.txt">input.txt</a>,
+see <a href="https://leetcode.com/problems/two-sum/">two sum</a> and <a href="/data/local.csv">local</a>.</p>`,
+		TranslatedContent: `<p><a href="https://assets.leetcode.com/data/input.txt">input.txt</a></p>`,
+	}
+	got := q.Attachments()
+	if len(got) != 1 {
+		t.Fatalf("got %d attachments, want 1: %v", len(got), got)
+	}
+	if got[0].Name != "input.txt" || got[0].URL != "https://assets.leetcode.com/data/input.txt" {
+		t.Errorf("got %+v", got[0])
+	}
+}
+
+func TestAttachmentDownload(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/truncated.txt" {
+					// Promise more than sent, the client gets an unexpected EOF.
+					w.Header().Set("Content-Length", "100")
+				}
+				_, _ = w.Write([]byte("data"))
+			},
+		),
+	)
+	defer srv.Close()
+	dir := t.TempDir()
+
+	_, err := Attachment{Name: "truncated.txt", URL: srv.URL + "/truncated.txt"}.Download(dir)
+	if err == nil {
+		t.Errorf("truncated download should fail")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("failed download left files: %v", entries)
+	}
+
+	file, err := Attachment{Name: "input.txt", URL: srv.URL + "/input.txt"}.Download(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "input.txt"))
+	if file != filepath.Join(dir, "input.txt") || string(data) != "data" {
+		t.Errorf("Download() = %s with %q", file, data)
+	}
+}
@@ -1,8 +1,11 @@
 package leetcode
 
 import (
+	"os"
 	"sync"
 
+	"github.com/charmbracelet/log"
+
 	"github.com/j178/leetgo/config"
 )
 
@@ -29,3 +32,19 @@ var (
 	lazyCache QuestionsCache
 	once      sync.Once
 )
+
+// takeSnapshot keeps the question list of the cache before it is updated, for `leetgo cache diff`.
+func takeSnapshot(c QuestionsCache) {
+	if _, err := os.Stat(c.CacheFile()); err != nil {
+		return
+	}
+	qs := c.GetAllQuestions()
+	if len(qs) == 0 {
+		return
+	}
+	ratings, _ := LoadRatings()
+	err := SaveSnapshot(NewSnapshot(qs, ratings))
+	if err != nil {
+		log.Warn("failed to save questions snapshot", "err", err)
+	}
+}
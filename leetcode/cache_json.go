@@ -3,7 +3,10 @@
 package leetcode
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -11,6 +14,7 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/goccy/go-json"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/utils"
 )
 
@@ -39,10 +43,19 @@ func (c *jsonCache) doLoad() error {
 	if _, err := os.Stat(c.path); errors.Is(err, os.ErrNotExist) {
 		return err
 	}
+	done := utils.Track(utils.ProfileDisk)
 	s, err := os.ReadFile(c.path)
+	done()
 	if err != nil {
 		return err
 	}
+	// The cache is stored compressed in low bandwidth mode, read it regardless of the current mode.
+	if bytes.HasPrefix(s, gzipMagic) {
+		s, err = gunzip(s)
+		if err != nil {
+			return err
+		}
+	}
 
 	var records []*QuestionData
 	err = json.Unmarshal(s, &records)
@@ -94,13 +107,13 @@ func (c *jsonCache) Update() error {
 	if err != nil {
 		return err
 	}
+	takeSnapshot(c)
 	f, err := os.Create(c.path)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = f.Close() }()
-	enc := json.NewEncoder(f)
-	err = enc.Encode(all)
+	err = encodeQuestions(f, all, config.Get().LowBandwidth)
 	if err != nil {
 		return err
 	}
@@ -108,6 +121,34 @@ func (c *jsonCache) Update() error {
 	return nil
 }
 
+// encodeQuestions writes the questions as JSON, compressed with gzip if compress is set.
+func encodeQuestions(w io.Writer, qs []*QuestionData, compress bool) error {
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	err := json.NewEncoder(w).Encode(qs)
+	if err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
 func (c *jsonCache) GetBySlug(slug string) *QuestionData {
 	c.load()
 	return c.slugs[slug]
@@ -0,0 +1,75 @@
+//go:build !sqlite
+
+package leetcode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONCacheRoundTrip(t *testing.T) {
+	qs := []*QuestionData{
+		{TitleSlug: "two-sum", QuestionFrontendId: "1", Title: "Two Sum"},
+		{TitleSlug: "add-two-numbers", QuestionFrontendId: "2", Title: "Add Two Numbers"},
+	}
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := encodeQuestions(&buf, qs, compress); err != nil {
+			t.Fatal(err)
+		}
+		if bytes.HasPrefix(buf.Bytes(), gzipMagic) != compress {
+			t.Errorf("compress %v: cache starts with %q", compress, buf.Bytes()[:2])
+		}
+		path := filepath.Join(t.TempDir(), "leetcode-questions.json")
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		c := &jsonCache{path: path}
+		if err := c.doLoad(); err != nil {
+			t.Fatalf("compress %v: %v", compress, err)
+		}
+		q := c.slugs["two-sum"]
+		if q == nil || c.frontIds["2"] == nil || q.Title != "Two Sum" || q.partial != 1 {
+			t.Errorf("compress %v: loaded %+v", compress, c.slugs)
+		}
+	}
+}
+
+type fakeListClient struct {
+	Client
+	qs []*QuestionData
+}
+
+func (c *fakeListClient) GetAllQuestions() ([]*QuestionData, error) {
+	return c.qs, nil
+}
+
+func TestUpdateTakesSnapshot(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "leetcode-questions.json")
+	client := &fakeListClient{qs: []*QuestionData{{TitleSlug: "two-sum", QuestionFrontendId: "1"}}}
+
+	// Nothing to keep before the first update.
+	if err := (&jsonCache{path: path, client: client}).Update(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(); err != ErrSnapshotNotFound {
+		t.Fatalf("LoadSnapshot() after the first update = %v, want ErrSnapshotNotFound", err)
+	}
+
+	client.qs = append(client.qs, &QuestionData{TitleSlug: "add-two-numbers", QuestionFrontendId: "2"})
+	if err := (&jsonCache{path: path, client: client}).Update(); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Questions) != 1 || s.Questions["two-sum"].FrontendID != "1" {
+		t.Errorf("snapshot = %+v, want the question list before the update", s.Questions)
+	}
+}
@@ -337,6 +337,7 @@ func (c *sqliteCache) Update() error {
 	if err != nil {
 		return err
 	}
+	takeSnapshot(c)
 	placeholder := "(" + strings.Repeat("?,", 19) + "?)"
 	batch := 100
 	for len(all) > 0 {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"sort"
 	"strings"
@@ -56,11 +57,20 @@ type Client interface {
 	Login(username, password string) (*http.Response, error)
 	GetUserStatus() (*UserStatus, error)
 	GetQuestionData(slug string) (*QuestionData, error)
+	// GetQuestionsData queries the fields of questions in batches, questions not found are nil in the result.
+	GetQuestionsData(slugs []string, fields QuestionFields) ([]*QuestionData, error)
 	GetAllQuestions() ([]*QuestionData, error)
 	GetTodayQuestion() (*QuestionData, error)
 	GetQuestionOfDate(date time.Time) (*QuestionData, error)
 	GetQuestionsByFilter(f QuestionFilter, limit int, skip int) (QuestionList, error)
 	GetQuestionTags() ([]QuestionTag, error)
+	GetStudyPlan(slug string) (*StudyPlan, error)
+	GetCompanyQuestions(listSlug string) ([]CompanyQuestion, error)
+	GetSolutions(questionSlug string, langSlug string, limit int) ([]*Solution, error)
+	GetSolution(questionSlug string, id string) (*Solution, error)
+	GetEditorial(questionSlug string) (*Solution, error)
+	GetNote(q *QuestionData) (string, error)
+	UpdateNote(q *QuestionData, content string) error
 	RunCode(q *QuestionData, lang string, code string, dataInput string) (
 		*InterpretSolutionResult,
 		error,
@@ -72,6 +82,8 @@ type Client interface {
 	GetContestQuestionData(contestSlug string, questionSlug string) (*QuestionData, error)
 	RegisterContest(slug string) error
 	UnregisterContest(slug string) error
+	GetContestRanking(contestSlug string, page int) (*ContestRanking, error)
+	GetMyContestRanking(contestSlug string) (*MyContestRanking, error)
 }
 
 type cnClient struct {
@@ -107,10 +119,15 @@ func NewClient(cred CredentialsProvider) Client {
 	httpClient.Client(
 		&http.Client{
 			CheckRedirect: nonFollowRedirect,
-			Transport: &http.Transport{
-				// Disable http2
-				TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
-			},
+			Transport: utils.TrackTransport(
+				&http.Transport{
+					Proxy:               http.ProxyFromEnvironment,
+					DialContext:         (&net.Dialer{Timeout: connectTimeout}).DialContext,
+					TLSHandshakeTimeout: connectTimeout,
+					// Disable http2
+					TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+				},
+			),
 		},
 	)
 
@@ -178,11 +195,16 @@ const (
 	submitCodePath        = "/problems/%s/submit/"
 	checkResultPath       = "/submissions/detail/%s/check/"
 	contestRegisterPath   = "/contest/api/%s/register/"
+	contestRankingPath    = "/contest/api/ranking/%s/"
+	contestMyRankingPath  = "/contest/api/myranking/%s/"
 	problemsAllPath       = "/api/problems/all/"
 	problemsApiTagsPath   = "/problems/api/tags/"
 )
 
 func (c *cnClient) send(req *http.Request, authType authType, result any, failure any) (*http.Response, error) {
+	if config.Offline() {
+		return nil, ErrOffline
+	}
 	switch authType {
 	case withoutAuth:
 	case withAuth:
@@ -227,8 +249,8 @@ func (c *cnClient) send(req *http.Request, authType authType, result any, failur
 		},
 		retry.RetryIf(
 			func(err error) bool {
-				// Do not retry on 429
-				return !errors.Is(err, ErrTooManyRequests)
+				// Do not retry on 429, or when the network is unavailable.
+				return !errors.Is(err, ErrTooManyRequests) && !isNetworkError(err)
 			},
 		),
 		retry.Attempts(3),
@@ -240,7 +262,7 @@ func (c *cnClient) send(req *http.Request, authType authType, result any, failur
 		),
 	)
 
-	return nil, err
+	return nil, checkNetworkError(err)
 }
 
 //nolint:unused
@@ -416,7 +438,14 @@ query globalData {
 	return &userStatus, nil
 }
 
-func (c *cnClient) getQuestionData(slug string, query string, authType authType) (*QuestionData, error) {
+// getQuestionData fetches the question data, which is saved for offline mode.
+// In offline mode, the data saved when the question was fetched last time is returned.
+// getQuestionData fetches the question fully, full is the full fields of the site.
+func (c *cnClient) getQuestionData(slug string, full string) (*QuestionData, error) {
+	if config.Offline() {
+		return loadQuestionData(slug)
+	}
+	omitted := omittedContent()
 	var resp struct {
 		Data struct {
 			Question QuestionData `json:"question"`
@@ -424,62 +453,30 @@ func (c *cnClient) getQuestionData(slug string, query string, authType authType)
 	}
 	_, err := c.graphqlPost(
 		graphqlRequest{
-			query:         query,
+			query:         questionQuery(omitContent(full, omitted)),
 			operationName: "questionData",
 			variables:     map[string]any{"titleSlug": slug},
-			authType:      authType,
+			authType:      withAuth,
 		}, &resp, nil,
 	)
 	if err != nil {
+		// The network turned out to be unavailable.
+		if config.Offline() {
+			return loadQuestionData(slug)
+		}
 		return nil, err
 	}
 	q := resp.Data.Question
-	if q.TitleSlug == "" {
-		return nil, ErrQuestionNotFound
-	}
-	if q.IsPaidOnly && q.Content == "" {
-		return nil, ErrPaidOnlyQuestion
+	if err = checkQuestionData(&q); err != nil {
+		return nil, err
 	}
+	q.omittedContent = omitted
+	saveQuestionData(&q)
 	return &q, nil
 }
 
 func (c *cnClient) GetQuestionData(slug string) (*QuestionData, error) {
-	query := `
-	query questionData($titleSlug: String!) {
-		question(titleSlug: $titleSlug) {
-			questionId
-			questionFrontendId
-			categoryTitle
-			title
-			titleSlug
-			content
-			isPaidOnly
-			translatedTitle
-			translatedContent
-			difficulty
-			status
-			stats
-			hints
-			similarQuestions
-			sampleTestCase
-			exampleTestcases
-			exampleTestcaseList
-			jsonExampleTestcases
-			metaData
-			codeSnippets {
-				lang
-				langSlug
-				code
-			}
-			topicTags {
-				name
-				slug
-				translatedName
-			}
-			editorType
-		}
-	}`
-	q, err := c.getQuestionData(slug, query, withAuth)
+	q, err := c.getQuestionData(slug, cnQuestionFullFields)
 	if err != nil {
 		return q, err
 	}
@@ -487,6 +484,10 @@ func (c *cnClient) GetQuestionData(slug string) (*QuestionData, error) {
 	return q, nil
 }
 
+func (c *cnClient) GetQuestionsData(slugs []string, fields QuestionFields) ([]*QuestionData, error) {
+	return c.getQuestionsData(c, slugs, fields, cnQuestionFullFields)
+}
+
 func (c *cnClient) GetAllQuestions() ([]*QuestionData, error) {
 	query := `
 	query AllQuestionUrls {
@@ -508,6 +509,23 @@ func (c *cnClient) GetAllQuestions() ([]*QuestionData, error) {
 	url := resp.Get("data.allQuestionUrls.questionUrl").Str
 
 	log.Debug("request", "url", url)
+	var qs []*QuestionData
+	err = downloadQuestions(c.http.New().Get(url), &qs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range qs {
+		qs[i].client = c
+		qs[i].partial = 1
+	}
+	return qs, err
+}
+
+// downloadQuestions receives the question list of the request into v, showing a progress bar of bytes downloaded.
+func downloadQuestions(req *sling.Sling, v any) error {
+	if config.Offline() {
+		return ErrOffline
+	}
 	tracker := &progress.Tracker{
 		Message: "Downloading questions",
 		Total:   0,
@@ -520,21 +538,18 @@ func (c *cnClient) GetAllQuestions() ([]*QuestionData, error) {
 	pw.Style().Visibility.ETA = false
 	pw.Style().Visibility.ETAOverall = false
 
-	go pw.Render()
+	if !config.Get().UsePlainOutput() {
+		go pw.Render()
+	}
 
-	var qs []*QuestionData
 	dec := progressDecoder{smartDecoder{LogResponse: false}, tracker}
-	_, err = c.http.New().Get(url).ResponseDecoder(dec).ReceiveSuccess(&qs)
+	_, err := req.ResponseDecoder(dec).ReceiveSuccess(v)
 	if err != nil {
-		return nil, err
-	}
-	for i := range qs {
-		qs[i].client = c
-		qs[i].partial = 1
+		return err
 	}
 	// Sleep a while to make sure the progress bar is rendered.
 	time.Sleep(time.Millisecond * 100)
-	return qs, err
+	return nil
 }
 
 func (c *cnClient) GetTodayQuestion() (*QuestionData, error) {
@@ -918,11 +933,40 @@ func (c *cnClient) UnregisterContest(slug string) error {
 	return err
 }
 
+func (c *cnClient) GetContestRanking(contestSlug string, page int) (*ContestRanking, error) {
+	path := fmt.Sprintf(contestRankingPath, contestSlug)
+	query := struct {
+		Pagination int    `url:"pagination"`
+		Region     string `url:"region"`
+	}{page, "global"}
+	var ranking ContestRanking
+	_, err := c.jsonGet(path, query, withoutAuth, &ranking, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ranking, nil
+}
+
+func (c *cnClient) GetMyContestRanking(contestSlug string) (*MyContestRanking, error) {
+	path := fmt.Sprintf(contestMyRankingPath, contestSlug)
+	query := struct {
+		Region string `url:"region"`
+	}{"global"}
+	var ranking MyContestRanking
+	_, err := c.jsonGet(path, query, requireAuth, &ranking, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ranking, nil
+}
+
 type QuestionFilter struct {
 	Difficulty     string   `json:"difficulty,omitempty"`
 	Tags           []string `json:"tags,omitempty"`
 	Status         string   `json:"status,omitempty"`
 	SearchKeywords string   `json:"searchKeywords,omitempty"`
+	// PremiumOnly set to false excludes paid only questions.
+	PremiumOnly *bool `json:"premiumOnly,omitempty"`
 }
 
 func (c *cnClient) GetQuestionsByFilter(f QuestionFilter, limit int, skip int) (QuestionList, error) {
@@ -984,6 +1028,10 @@ query problemsetQuestionList($categorySlug: String, $limit: Int, $skip: Int, $fi
 	return result, err
 }
 
+func (c *cnClient) GetCompanyQuestions(listSlug string) ([]CompanyQuestion, error) {
+	return nil, ErrCompanyNotSupported
+}
+
 func (c *cnClient) GetQuestionTags() ([]QuestionTag, error) {
 	var resp gjson.Result
 	_, err := c.jsonGet(problemsApiTagsPath, nil, withAuth, &resp, nil)
@@ -1002,3 +1050,251 @@ func (c *cnClient) GetQuestionTags() ([]QuestionTag, error) {
 	}
 	return tags, nil
 }
+
+func (c *cnClient) GetStudyPlan(slug string) (*StudyPlan, error) {
+	query := `
+query studyPlanDetail($slug: String!) {
+  studyPlanV2Detail(planSlug: $slug) {
+    slug
+    name
+    description
+    planSubGroups {
+      slug
+      name
+      questions {
+        title
+        translatedTitle
+        titleSlug
+        questionFrontendId
+        difficulty
+        status
+        isPaidOnly: paidOnly
+        topicTags {
+          slug
+          name
+        }
+      }
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "studyPlanDetail",
+			variables:     map[string]any{"slug": slug},
+			authType:      withAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	detail := resp.Get("data.studyPlanV2Detail")
+	if !detail.Exists() || detail.Type == gjson.Null {
+		return nil, fmt.Errorf("study plan not found: %s", slug)
+	}
+	var plan StudyPlan
+	err = json.Unmarshal(utils.StringToBytes(detail.Raw), &plan)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range plan.Questions() {
+		q.client = c
+		q.partial = 1
+	}
+	return &plan, nil
+}
+
+func (c *cnClient) GetSolutions(questionSlug string, langSlug string, limit int) ([]*Solution, error) {
+	query := `
+query questionSolutionArticles($questionSlug: String!, $skip: Int, $first: Int, $orderBy: SolutionArticleOrderBy, $tagSlugs: [String!]) {
+  questionSolutionArticles(questionSlug: $questionSlug, skip: $skip, first: $first, orderBy: $orderBy, tagSlugs: $tagSlugs) {
+    edges {
+      node {
+        title
+        slug
+        upvoteCount
+        author {
+          username
+        }
+        tags {
+          slug
+        }
+      }
+    }
+  }
+}`
+	variables := map[string]any{
+		"questionSlug": questionSlug,
+		"skip":         0,
+		"first":        limit,
+		"orderBy":      "MOST_UPVOTE",
+	}
+	if langSlug != "" {
+		variables["tagSlugs"] = []string{langSlug}
+	}
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "questionSolutionArticles",
+			variables:     variables,
+			authType:      withoutAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var solutions []*Solution
+	for _, edge := range resp.Get("data.questionSolutionArticles.edges").Array() {
+		node := edge.Get("node")
+		s := &Solution{
+			ID:     node.Get("slug").Str,
+			Title:  node.Get("title").Str,
+			Author: node.Get("author.username").Str,
+			Votes:  int(node.Get("upvoteCount").Int()),
+		}
+		for _, t := range node.Get("tags.#.slug").Array() {
+			s.Tags = append(s.Tags, t.Str)
+		}
+		solutions = append(solutions, s)
+	}
+	return solutions, nil
+}
+
+func (c *cnClient) GetSolution(questionSlug string, id string) (*Solution, error) {
+	query := `
+query solutionDetailArticle($slug: String!) {
+  solutionArticle(slug: $slug, orderBy: DEFAULT) {
+    title
+    slug
+    content
+    upvoteCount
+    author {
+      username
+    }
+    tags {
+      slug
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "solutionDetailArticle",
+			variables:     map[string]any{"slug": id},
+			authType:      withoutAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	article := resp.Get("data.solutionArticle")
+	if !article.Exists() || article.Type == gjson.Null {
+		return nil, fmt.Errorf("solution not found: %s", id)
+	}
+	s := &Solution{
+		ID:      article.Get("slug").Str,
+		Title:   article.Get("title").Str,
+		Author:  article.Get("author.username").Str,
+		Votes:   int(article.Get("upvoteCount").Int()),
+		Content: article.Get("content").Str,
+	}
+	for _, t := range article.Get("tags.#.slug").Array() {
+		s.Tags = append(s.Tags, t.Str)
+	}
+	return s, nil
+}
+
+// getNote returns the id and content of the note of the question, id is empty if there is no note.
+func (c *cnClient) getNote(q *QuestionData) (string, string, error) {
+	query := `
+query noteOneTargetCommonNote($noteType: NoteCommonTypeEnum!, $targetId: String!) {
+  noteOneTargetCommonNote(noteType: $noteType, targetId: $targetId) {
+    userNotes {
+      id
+      content
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "noteOneTargetCommonNote",
+			variables:     map[string]any{"noteType": "COMMON_QUESTION", "targetId": q.QuestionId},
+			authType:      requireAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	note := resp.Get("data.noteOneTargetCommonNote.userNotes.0")
+	return note.Get("id").String(), note.Get("content").Str, nil
+}
+
+func (c *cnClient) GetNote(q *QuestionData) (string, error) {
+	_, content, err := c.getNote(q)
+	return content, err
+}
+
+func (c *cnClient) UpdateNote(q *QuestionData, content string) error {
+	id, _, err := c.getNote(q)
+	if err != nil {
+		return err
+	}
+	var req graphqlRequest
+	if id == "" {
+		req = graphqlRequest{
+			query: `
+mutation noteCreateCommonNote($content: String!, $noteType: NoteCommonTypeEnum!, $targetId: String!) {
+  noteCreateCommonNote(content: $content, noteType: $noteType, targetId: $targetId) {
+    ok
+  }
+}`,
+			operationName: "noteCreateCommonNote",
+			variables:     map[string]any{"content": content, "noteType": "COMMON_QUESTION", "targetId": q.QuestionId},
+			authType:      requireAuth,
+		}
+	} else {
+		req = graphqlRequest{
+			query: `
+mutation noteUpdateUserNote($noteId: ID!, $content: String!) {
+  noteUpdateUserNote(noteId: $noteId, content: $content) {
+    ok
+  }
+}`,
+			operationName: "noteUpdateUserNote",
+			variables:     map[string]any{"noteId": id, "content": content},
+			authType:      requireAuth,
+		}
+	}
+	var resp gjson.Result
+	_, err = c.graphqlPost(req, &resp, nil)
+	if err != nil {
+		return err
+	}
+	if !resp.Get("data.*.ok").Bool() {
+		return errors.New("failed to update note")
+	}
+	return nil
+}
+
+// officialSolutionAuthor is the account posting the official solutions on leetcode.cn.
+const officialSolutionAuthor = "LeetCode-Solution"
+
+// GetEditorial returns the official solution of the question, it's a solution article of the official account
+// on leetcode.cn.
+func (c *cnClient) GetEditorial(questionSlug string) (*Solution, error) {
+	solutions, err := c.GetSolutions(questionSlug, "", 50)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range solutions {
+		if s.Author == officialSolutionAuthor {
+			return c.GetSolution(questionSlug, s.ID)
+		}
+	}
+	return nil, ErrNoEditorial
+}
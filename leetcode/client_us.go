@@ -29,40 +29,7 @@ func (c *usClient) Login(username, password string) (*http.Response, error) {
 }
 
 func (c *usClient) GetQuestionData(slug string) (*QuestionData, error) {
-	query := `
-	query questionData($titleSlug: String!) {
-		question(titleSlug: $titleSlug) {
-			questionId
-			questionFrontendId
-			categoryTitle
-			title
-			titleSlug
-			content
-			isPaidOnly
-			translatedTitle
-			translatedContent
-			difficulty
-			status
-			stats
-			hints
-			similarQuestions
-			sampleTestCase
-			exampleTestcases
-			exampleTestcaseList
-			metaData
-			codeSnippets {
-				lang
-				langSlug
-				code
-			}
-			topicTags {
-				name
-				slug
-				translatedName
-			}
-		}
-	}`
-	q, err := c.getQuestionData(slug, query, withAuth)
+	q, err := c.getQuestionData(slug, usQuestionFullFields)
 	if err != nil {
 		return q, err
 	}
@@ -70,6 +37,10 @@ func (c *usClient) GetQuestionData(slug string) (*QuestionData, error) {
 	return q, nil
 }
 
+func (c *usClient) GetQuestionsData(slugs []string, fields QuestionFields) ([]*QuestionData, error) {
+	return c.getQuestionsData(c, slugs, fields, usQuestionFullFields)
+}
+
 func (c *usClient) GetAllQuestions() ([]*QuestionData, error) {
 	var resp struct {
 		UserName        string `json:"user_name"`
@@ -84,15 +55,18 @@ func (c *usClient) GetAllQuestions() ([]*QuestionData, error) {
 				QuestionFrontendID int    `json:"frontend_question_id"`
 				QuestionTitle      string `json:"question__title"`
 				QuestionTitleSlug  string `json:"question__title_slug"`
+				TotalAcs           int    `json:"total_acs"`
+				TotalSubmitted     int    `json:"total_submitted"`
 			} `json:"stat"`
 			Status     string `json:"status"`
 			Difficulty struct {
 				Level int `json:"level"`
 			} `json:"difficulty"`
-			PaidOnly bool `json:"paid_only"`
+			PaidOnly  bool    `json:"paid_only"`
+			Frequency float64 `json:"frequency"`
 		} `json:"stat_status_pairs"`
 	}
-	_, err := c.http.New().Get(problemsAllPath).ReceiveSuccess(&resp)
+	err := downloadQuestions(c.http.New().Get(problemsAllPath), &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +91,11 @@ func (c *usClient) GetAllQuestions() ([]*QuestionData, error) {
 			IsPaidOnly:         pair.PaidOnly,
 			Status:             pair.Status,
 			Difficulty:         difficulty,
+			Stats: Stats{
+				TotalAcceptedRaw:   pair.Stat.TotalAcs,
+				TotalSubmissionRaw: pair.Stat.TotalSubmitted,
+			},
+			Frequency: pair.Frequency,
 		}
 		qs = append(qs, q)
 	}
@@ -283,6 +262,50 @@ func (c *usClient) GetUpcomingContests() ([]*Contest, error) {
 	return contests, nil
 }
 
+// GetCompanyQuestions fetches the questions of a favorite list of a company, e.g. amazon-six-months.
+func (c *usClient) GetCompanyQuestions(listSlug string) ([]CompanyQuestion, error) {
+	query := `
+query favoriteQuestionList($favoriteSlug: String!, $skip: Int!, $limit: Int!) {
+  favoriteQuestionList(favoriteSlug: $favoriteSlug, skip: $skip, limit: $limit) {
+    questions {
+      titleSlug
+      frequency
+    }
+    hasMore
+  }
+}
+`
+	const limit = 100
+	var qs []CompanyQuestion
+	for skip := 0; ; skip += limit {
+		var resp gjson.Result
+		_, err := c.graphqlPost(
+			graphqlRequest{
+				query:         query,
+				operationName: "favoriteQuestionList",
+				variables:     map[string]any{"favoriteSlug": listSlug, "skip": skip, "limit": limit},
+				authType:      requireAuth,
+			}, &resp, nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list := resp.Get("data.favoriteQuestionList")
+		if !list.Exists() || list.Type == gjson.Null {
+			return nil, fmt.Errorf("%w: no question list %s", ErrCompanyNotSupported, listSlug)
+		}
+		var page []CompanyQuestion
+		err = json.Unmarshal(utils.StringToBytes(list.Get("questions").Raw), &page)
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, page...)
+		if !list.Get("hasMore").Bool() || len(page) == 0 {
+			return qs, nil
+		}
+	}
+}
+
 func (c *usClient) GetQuestionsByFilter(f QuestionFilter, limit int, skip int) (QuestionList, error) {
 	query := `
 query problemsetQuestionList($categorySlug: String, $limit: Int, $skip: Int, $filters: QuestionListFilterInput) {
@@ -360,3 +383,202 @@ func (c *usClient) GetQuestionTags() ([]QuestionTag, error) {
 	}
 	return tags, nil
 }
+
+func (c *usClient) GetSolutions(questionSlug string, langSlug string, limit int) ([]*Solution, error) {
+	query := `
+query communitySolutions($questionSlug: String!, $skip: Int!, $first: Int!, $orderBy: TopicSortingOption, $languageTags: [String!]) {
+  questionSolutions(
+    filters: {questionSlug: $questionSlug, skip: $skip, first: $first, orderBy: $orderBy, languageTags: $languageTags}
+  ) {
+    solutions {
+      id
+      title
+      solutionTags {
+        slug
+      }
+      post {
+        voteCount
+        author {
+          username
+        }
+      }
+    }
+  }
+}`
+	languageTags := []string{}
+	if langSlug != "" {
+		languageTags = append(languageTags, langSlug)
+	}
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "communitySolutions",
+			variables: map[string]any{
+				"questionSlug": questionSlug,
+				"skip":         0,
+				"first":        limit,
+				"orderBy":      "most_votes",
+				"languageTags": languageTags,
+			},
+			authType: withoutAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var solutions []*Solution
+	for _, node := range resp.Get("data.questionSolutions.solutions").Array() {
+		s := &Solution{
+			ID:     node.Get("id").String(),
+			Title:  node.Get("title").Str,
+			Author: node.Get("post.author.username").Str,
+			Votes:  int(node.Get("post.voteCount").Int()),
+		}
+		for _, t := range node.Get("solutionTags.#.slug").Array() {
+			s.Tags = append(s.Tags, t.Str)
+		}
+		solutions = append(solutions, s)
+	}
+	return solutions, nil
+}
+
+func (c *usClient) GetSolution(questionSlug string, id string) (*Solution, error) {
+	topicId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid solution id: %s", id)
+	}
+	query := `
+query communitySolution($topicId: Int!) {
+  topic(id: $topicId) {
+    id
+    title
+    solutionTags {
+      slug
+    }
+    post {
+      voteCount
+      content
+      author {
+        username
+      }
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err = c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "communitySolution",
+			variables:     map[string]any{"topicId": topicId},
+			authType:      withoutAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	topic := resp.Get("data.topic")
+	if !topic.Exists() || topic.Type == gjson.Null {
+		return nil, fmt.Errorf("solution not found: %s", id)
+	}
+	s := &Solution{
+		ID:     topic.Get("id").String(),
+		Title:  topic.Get("title").Str,
+		Author: topic.Get("post.author.username").Str,
+		Votes:  int(topic.Get("post.voteCount").Int()),
+		// Contents of leetcode.com posts are escaped.
+		Content: decodePostContent(topic.Get("post.content").Str),
+	}
+	for _, t := range topic.Get("solutionTags.#.slug").Array() {
+		s.Tags = append(s.Tags, t.Str)
+	}
+	return s, nil
+}
+
+func (c *usClient) GetEditorial(questionSlug string) (*Solution, error) {
+	query := `
+query officialSolution($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    solution {
+      id
+      title
+      content
+      paidOnly
+      canSeeDetail
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "officialSolution",
+			variables:     map[string]any{"titleSlug": questionSlug},
+			authType:      withAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	solution := resp.Get("data.question.solution")
+	if !solution.Exists() || solution.Type == gjson.Null {
+		return nil, ErrNoEditorial
+	}
+	if !solution.Get("canSeeDetail").Bool() {
+		return nil, ErrPaidOnlyEditorial
+	}
+	return &Solution{
+		ID:      solution.Get("id").String(),
+		Title:   solution.Get("title").Str,
+		Author:  "LeetCode",
+		Content: solution.Get("content").Str,
+	}, nil
+}
+
+func (c *usClient) GetNote(q *QuestionData) (string, error) {
+	query := `
+query questionNote($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    note
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "questionNote",
+			variables:     map[string]any{"titleSlug": q.TitleSlug},
+			authType:      requireAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	return resp.Get("data.question.note").Str, nil
+}
+
+func (c *usClient) UpdateNote(q *QuestionData, content string) error {
+	query := `
+mutation updateNote($titleSlug: String!, $content: String!) {
+  updateNote(titleSlug: $titleSlug, content: $content) {
+    ok
+    error
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "updateNote",
+			variables:     map[string]any{"titleSlug": q.TitleSlug, "content": content},
+			authType:      requireAuth,
+		}, &resp, nil,
+	)
+	if err != nil {
+		return err
+	}
+	if !resp.Get("data.updateNote.ok").Bool() {
+		return fmt.Errorf("failed to update note: %s", resp.Get("data.updateNote.error").Str)
+	}
+	return nil
+}
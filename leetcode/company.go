@@ -0,0 +1,167 @@
+package leetcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+// ErrCompanyNotSupported is returned for company questions on leetcode.cn.
+var ErrCompanyNotSupported = errors.New("company tags are only available on leetcode.com with LeetCode Premium")
+
+// CompanyPeriods are the recent periods of company questions, and the suffixes of their favorite lists on leetcode.com.
+var CompanyPeriods = []struct {
+	Name   string
+	Suffix string
+}{
+	{"30d", "thirty-days"},
+	{"3m", "three-months"},
+	{"6m", "six-months"},
+	{"all", "all"},
+}
+
+// companyListSlug returns the slug of the favorite list of questions asked by the company in the period.
+func companyListSlug(company string, period string) (string, error) {
+	for _, p := range CompanyPeriods {
+		if p.Name == period {
+			return strings.ToLower(company) + "-" + p.Suffix, nil
+		}
+	}
+	return "", fmt.Errorf("invalid period %q, only 30d, 3m, 6m or all is supported", period)
+}
+
+// CompanyQuestion is a question asked by a company, with how frequently it is asked in the period.
+type CompanyQuestion struct {
+	TitleSlug string  `json:"titleSlug"`
+	Frequency float64 `json:"frequency"`
+}
+
+// CompanyQuestions are the questions asked by a company in a recent period.
+type CompanyQuestions struct {
+	Company   string            `json:"company"`
+	Period    string            `json:"period"`
+	FetchedAt time.Time         `json:"fetched_at"`
+	Questions []CompanyQuestion `json:"questions"`
+
+	// frequencies indexes Questions by slug, built on the first lookup.
+	frequencies map[string]float64
+}
+
+// Frequency returns the frequency of the question in the period, false if the company did not ask it.
+func (cq *CompanyQuestions) Frequency(slug string) (float64, bool) {
+	if cq.frequencies == nil {
+		cq.frequencies = make(map[string]float64, len(cq.Questions))
+		for _, q := range cq.Questions {
+			cq.frequencies[q.TitleSlug] = q.Frequency
+		}
+	}
+	f, ok := cq.frequencies[slug]
+	return f, ok
+}
+
+// companyCacheTTL is how long fetched company questions are used before fetching again.
+const companyCacheTTL = 7 * 24 * time.Hour
+
+func companyQuestionsFile(listSlug string) string {
+	cfg := config.Get()
+	return filepath.Join(cfg.CacheDir(), "companies", cfg.LeetCode.Site.Short(), listSlug+".json")
+}
+
+// LoadOrFetchCompanyQuestions returns the questions asked by the company in the period ("30d", "3m", "6m" or "all"),
+// from the cache if fetched within a week or in offline mode.
+func LoadOrFetchCompanyQuestions(c Client, company string, period string) (*CompanyQuestions, error) {
+	if period == "" {
+		period = "all"
+	}
+	listSlug, err := companyListSlug(company, period)
+	if err != nil {
+		return nil, err
+	}
+	file := companyQuestionsFile(listSlug)
+
+	var cached CompanyQuestions
+	data, err := os.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(data, &cached)
+	}
+	if err == nil && (time.Since(cached.FetchedAt) < companyCacheTTL || config.Offline()) {
+		return &cached, nil
+	}
+
+	qs, err := c.GetCompanyQuestions(listSlug)
+	if err != nil {
+		return nil, err
+	}
+	cq := &CompanyQuestions{
+		Company:   company,
+		Period:    period,
+		FetchedAt: time.Now(),
+		Questions: qs,
+	}
+	data, err = json.Marshal(cq)
+	if err == nil {
+		err = utils.WriteFile(file, data)
+	}
+	if err != nil {
+		log.Debug("failed to save company questions", "company", company, "err", err)
+	}
+	return cq, nil
+}
+
+// CompanyTagStat is how many times a company asked a question.
+type CompanyTagStat struct {
+	Name             string `json:"name"`
+	Slug             string `json:"slug"`
+	TimesEncountered int    `json:"timesEncountered"`
+}
+
+// CompanyTagStats are the companies that asked a question, keyed by period:
+// "1" for the last 6 months, "2" for 6 months to 1 year, "3" for 1 to 2 years.
+type CompanyTagStats map[string][]CompanyTagStat
+
+type companyTagStatsNoMethods CompanyTagStats
+
+func (s *CompanyTagStats) UnmarshalJSON(data []byte) error {
+	unquoted, err := strconv.Unquote(utils.BytesToString(data))
+	if err != nil {
+		unquoted = utils.BytesToString(data)
+	}
+	if unquoted == "null" || unquoted == "" {
+		return nil
+	}
+	return json.Unmarshal(utils.StringToBytes(unquoted), (*companyTagStatsNoMethods)(s))
+}
+
+// Companies returns the names of the companies, the most frequently asked first.
+func (s CompanyTagStats) Companies() []string {
+	times := map[string]int{}
+	for _, stats := range s {
+		for _, stat := range stats {
+			times[stat.Name] += stat.TimesEncountered
+		}
+	}
+	names := make([]string, 0, len(times))
+	for name := range times {
+		names = append(names, name)
+	}
+	slices.SortFunc(
+		names, func(a, b string) int {
+			if times[a] != times[b] {
+				return times[b] - times[a]
+			}
+			return strings.Compare(a, b)
+		},
+	)
+	return names
+}
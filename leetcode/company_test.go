@@ -0,0 +1,55 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestCompanyTagStats(t *testing.T) {
+	data := `{"companyTagStats": "{\"1\": [{\"name\": \"Amazon\", \"slug\": \"amazon\", \"timesEncountered\": 3}], \"2\": [{\"name\": \"Google\", \"slug\": \"google\", \"timesEncountered\": 5}, {\"name\": \"Amazon\", \"slug\": \"amazon\", \"timesEncountered\": 4}]}"}`
+	var q QuestionData
+	if err := json.Unmarshal([]byte(data), &q); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := q.CompanyTagStats.Companies(), []string{"Amazon", "Google"}; !slices.Equal(got, want) {
+		t.Errorf("Companies() = %v, want %v", got, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"companyTagStats": null}`), &q); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompanyListSlug(t *testing.T) {
+	slug, err := companyListSlug("Amazon", "6m")
+	if err != nil || slug != "amazon-six-months" {
+		t.Errorf("companyListSlug() = %q, %v", slug, err)
+	}
+	if _, err := companyListSlug("amazon", "1y"); err == nil {
+		t.Errorf("invalid period should fail")
+	}
+}
+
+func TestCompanyQuestionsFrequency(t *testing.T) {
+	var cq CompanyQuestions
+	data := `{"company": "amazon", "questions": [{"titleSlug": "two-sum", "frequency": 87.5}, {"titleSlug": "lru-cache", "frequency": 0}]}`
+	if err := json.Unmarshal([]byte(data), &cq); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		slug string
+		want float64
+		ok   bool
+	}{
+		{"two-sum", 87.5, true},
+		{"lru-cache", 0, true},
+		{"add-two-numbers", 0, false},
+	}
+	for _, c := range cases {
+		if f, ok := cq.Frequency(c.slug); f != c.want || ok != c.ok {
+			t.Errorf("Frequency(%q) = %v, %v, want %v, %v", c.slug, f, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package leetcode
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/j178/leetgo/config"
+)
+
+// Constraint is a bound parsed from the "Constraints" section of a question, e.g. `1 <= nums.length <= 10^4`.
+// Bounds are kept as expressions, because they may refer to other values, e.g. `0 <= k < n`.
+type Constraint struct {
+	Name string
+	// Min and Max are inclusive bound expressions, empty if unbounded.
+	Min string
+	Max string
+}
+
+// Constraints are the constraints of a question, together with the raw text of each item.
+type Constraints struct {
+	Bounds  []Constraint
+	Aliases map[string]string
+	Text    []string
+}
+
+var (
+	rangeConstraintRe = regexp.MustCompile(`^(.+?)\s*(<=|<)\s*(.+?)\s*(<=|<)\s*(.+?)$`)
+	aliasConstraintRe = regexp.MustCompile(`^([\w.\[\]]+)\s*==\s*([\w.\[\]]+)$`)
+	nodesConstraintRe = regexp.MustCompile(`(?i)number of nodes.* in the range \[(.+?),\s*(.+?)\]`)
+	constraintNameRe  = regexp.MustCompile(`^[A-Za-z_][\w.\[\]]*$`)
+)
+
+// NodesConstraint is the name of the constraint on the number of nodes of a tree or linked list.
+const NodesConstraint = "nodes"
+
+// ParseConstraints parses the "Constraints" section of the English content of the question.
+func (q *QuestionData) ParseConstraints() *Constraints {
+	content, _ := q.markdownContentIn(config.EN)
+	cs := &Constraints{Aliases: make(map[string]string)}
+	for _, item := range constraintItems(content) {
+		text := normalizeConstraintText(cleanMarkdown(item))
+		if text != "" {
+			cs.Text = append(cs.Text, text)
+			cs.parseItem(text)
+		}
+	}
+	return cs
+}
+
+// superscriptPowers maps the superscripts of markdown content back to the characters of exponents.
+var superscriptPowers = map[rune]rune{
+	'⁰': '0', '¹': '1', '²': '2', '³': '3', '⁴': '4', '⁵': '5', '⁶': '6', '⁷': '7', '⁸': '8', '⁹': '9',
+	'ⁿ': 'n', 'ᵏ': 'k', 'ᵐ': 'm', '⁺': '+', '⁻': '-',
+}
+
+// normalizeConstraintText turns a constraint into an expression that EvalBound understands,
+// e.g. `1 ≤ n ≤ 10⁴` into `1 <= n <= 10^4`.
+func normalizeConstraintText(s string) string {
+	replacer := strings.NewReplacer(
+		"\u00A0", " ", "\u200B", "", "≤", "<=", "≥", ">=", "×", "*", "\n", " ",
+	)
+	s = strings.TrimSpace(replacer.Replace(s))
+	s = strings.TrimSuffix(s, ".")
+
+	var sb strings.Builder
+	var power []rune
+	flush := func() {
+		switch {
+		case len(power) == 0:
+		case len(power) == 1 || strings.Trim(string(power), "0123456789") == "":
+			sb.WriteString("^" + string(power))
+		default:
+			sb.WriteString("^(" + string(power) + ")")
+		}
+		power = power[:0]
+	}
+	for _, r := range s {
+		if p, ok := superscriptPowers[r]; ok {
+			power = append(power, p)
+			continue
+		}
+		flush()
+		sb.WriteRune(r)
+	}
+	flush()
+	return sb.String()
+}
+
+func (cs *Constraints) parseItem(text string) {
+	if m := nodesConstraintRe.FindStringSubmatch(text); m != nil {
+		cs.Bounds = append(cs.Bounds, Constraint{Name: NodesConstraint, Min: m[1], Max: m[2]})
+		return
+	}
+	if m := aliasConstraintRe.FindStringSubmatch(text); m != nil {
+		cs.Aliases[m[1]] = m[2]
+		cs.Aliases[m[2]] = m[1]
+		return
+	}
+	m := rangeConstraintRe.FindStringSubmatch(text)
+	if m == nil {
+		return
+	}
+	lo, hi := m[1], m[5]
+	if m[2] == "<" {
+		lo = "(" + lo + ")+1"
+	}
+	if m[4] == "<" {
+		hi = "(" + hi + ")-1"
+	}
+	for _, name := range strings.Split(m[3], ",") {
+		name = strings.TrimSpace(name)
+		if !constraintNameRe.MatchString(name) {
+			continue
+		}
+		cs.Bounds = append(cs.Bounds, Constraint{Name: name, Min: lo, Max: hi})
+	}
+}
+
+// Lookup finds the constraint of name, following aliases like `n == nums.length`.
+func (cs *Constraints) Lookup(name string) (Constraint, bool) {
+	for _, n := range []string{name, cs.Aliases[name]} {
+		if n == "" {
+			continue
+		}
+		for _, c := range cs.Bounds {
+			if c.Name == n {
+				return c, true
+			}
+		}
+	}
+	return Constraint{}, false
+}
+
+// EvalBound evaluates a bound expression like `2 * 10^4` or `n - 1`.
+// Identifiers are resolved from env, an error is returned if any of them is unknown.
+func EvalBound(expr string, env map[string]int64) (int64, error) {
+	p := &boundParser{s: expr, env: env}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return 0, fmt.Errorf("unexpected %q in %q", p.s[p.pos:], expr)
+	}
+	return v, nil
+}
+
+type boundParser struct {
+	s   string
+	pos int
+	env map[string]int64
+}
+
+func (p *boundParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *boundParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *boundParser) expr() (int64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			r, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v = saturatingAdd(v, r)
+		case '-':
+			p.pos++
+			r, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v = saturatingAdd(v, -r)
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *boundParser) term() (int64, error) {
+	v, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for p.peek() == '*' {
+		p.pos++
+		r, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+		v = saturatingMul(v, r)
+	}
+	return v, nil
+}
+
+func (p *boundParser) factor() (int64, error) {
+	if p.peek() == '-' {
+		p.pos++
+		v, err := p.factor()
+		return -v, err
+	}
+	base, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+	exp, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	v := int64(1)
+	// Any base other than 0, 1 and -1 saturates within 64 multiplications.
+	for i := int64(0); i < exp && i < 64; i++ {
+		v = saturatingMul(v, base)
+	}
+	return v, nil
+}
+
+func (p *boundParser) primary() (int64, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing ) in %q", p.s)
+		}
+		p.pos++
+		return v, nil
+	case c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.s) && (unicode.IsDigit(rune(p.s[p.pos])) || p.s[p.pos] == ',') {
+			p.pos++
+		}
+		return strconv.ParseInt(strings.ReplaceAll(p.s[start:p.pos], ",", ""), 10, 64)
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.s) && isBoundIdentChar(p.s[p.pos]) {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		v, ok := p.env[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown value %q", name)
+		}
+		return v, nil
+	}
+	return 0, fmt.Errorf("invalid expression %q", p.s)
+}
+
+func isBoundIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c == '[' || c == ']' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+func saturatingAdd(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+func saturatingMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	r := a * b
+	if r/b != a {
+		if (a > 0) == (b > 0) {
+			return math.MaxInt64
+		}
+		return math.MinInt64
+	}
+	return r
+}
@@ -0,0 +1,90 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEvalBound(t *testing.T) {
+	env := map[string]int64{"n": 5, "nums.length": 8}
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"10^4", 10000},
+		{"-10^9", -1000000000},
+		{"2 * 10^4", 20000},
+		{"2^31 - 1", 2147483647},
+		{"10^9 + 7", 1000000007},
+		{"(n)-1", 4},
+		{"nums.length", 8},
+		{"1,000", 1000},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.input, func(t *testing.T) {
+				actual, err := EvalBound(tc.input, env)
+				if err != nil {
+					t.Fatalf("EvalBound(%q) failed: %v", tc.input, err)
+				}
+				if actual != tc.expected {
+					t.Errorf("EvalBound(%q) = %d, expected %d", tc.input, actual, tc.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	q := &QuestionData{
+		EditorType: EditorTypeCKEditor,
+		Content: `<p>Given an array <code>nums</code> of length <code>n</code>, return either:</p>
+<ul>
+	<li><code>0 &lt;= x &lt;= 5</code> if the array is sorted, or</li>
+	<li><code>-1</code> otherwise.</li>
+</ul>
+<p><strong>Constraints:</strong></p>
+<ul>
+	<li><code>n == nums.length</code></li>
+	<li><code>1 &lt;= n &lt;= 10<sup>4</sup></code></li>
+	<li><code>-2<sup>31</sup> &lt;= nums[i] &lt; 2<sup>31</sup></code></li>
+	<li><code>0 &lt;= k &lt; n</code></li>
+	<li>The number of nodes in the tree is in the range <code>[0, 100]</code>.</li>
+</ul>
+<p><strong>Follow-up:</strong> Can you do it in <code>O(n)</code>?</p>
+<ul>
+	<li><code>1 &lt;= y &lt;= 2</code></li>
+</ul>`,
+	}
+	cs := q.ParseConstraints()
+	want := []Constraint{
+		{Name: "n", Min: "1", Max: "10^4"},
+		{Name: "nums[i]", Min: "-2^31", Max: "(2^31)-1"},
+		{Name: "k", Min: "0", Max: "(n)-1"},
+		{Name: NodesConstraint, Min: "0", Max: "100"},
+	}
+	if !slices.Equal(cs.Bounds, want) {
+		t.Errorf("Bounds = %+v, want %+v", cs.Bounds, want)
+	}
+	if len(cs.Text) != 5 || cs.Aliases["n"] != "nums.length" {
+		t.Errorf("Text = %q, Aliases = %v", cs.Text, cs.Aliases)
+	}
+	if c, ok := cs.Lookup("nums.length"); !ok || c.Max != "10^4" {
+		t.Errorf("Lookup(nums.length) = %+v, %v", c, ok)
+	}
+}
+
+func TestNormalizeConstraintText(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{"1 ≤ n ≤ 10⁴.", "1 <= n <= 10^4"},
+		{"2 × 10⁵", "2 * 10^5"},
+		{"1 <= k <= 2ⁿ⁻¹", "1 <= k <= 2^(n-1)"},
+	}
+	for _, c := range cases {
+		if got := normalizeConstraintText(c.input); got != c.want {
+			t.Errorf("normalizeConstraintText(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
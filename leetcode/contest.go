@@ -2,7 +2,13 @@ package leetcode
 
 import (
 	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/j178/leetgo/config"
 )
 
 type Contest struct {
@@ -91,3 +97,332 @@ func (ct *Contest) Refresh() error {
 	*ct = *contest
 	return nil
 }
+
+// rankingStart returns the start time of the contest the standings are of,
+// for a virtual contest it's the start time of the original one.
+func (ct *Contest) rankingStart() int64 {
+	if ct.IsVirtual && ct.OriginStartTime > 0 {
+		return ct.OriginStartTime
+	}
+	return ct.StartTime
+}
+
+// rankingPageSize is the number of users in a page of contest ranking.
+const rankingPageSize = 25
+
+// ContestRanking is a page of the contest standings.
+type ContestRanking struct {
+	UserNum   int `json:"user_num"`
+	Questions []struct {
+		QuestionId int    `json:"question_id"`
+		Credit     int    `json:"credit"`
+		TitleSlug  string `json:"title_slug"`
+	} `json:"questions"`
+	// TotalRank are the ranks of users in the page, in the same order as Submissions.
+	TotalRank []ContestRank `json:"total_rank"`
+	// Submissions are the accepted submissions of each user in the page, keyed by question id.
+	Submissions []map[string]ContestSubmission `json:"submissions"`
+}
+
+// ContestRank is the rank of a user in the contest standings.
+type ContestRank struct {
+	Username   string `json:"username"`
+	UserSlug   string `json:"user_slug"`
+	Rank       int    `json:"rank"`
+	Score      int    `json:"score"`
+	FinishTime int64  `json:"finish_time"`
+}
+
+// ContestSubmission is the accepted submission of a contest question, with the number of wrong submissions before it.
+type ContestSubmission struct {
+	Date      int64 `json:"date"`
+	FailCount int   `json:"fail_count"`
+}
+
+// MyContestRanking is the rank and submissions of the current user in a contest.
+type MyContestRanking struct {
+	Rank        ContestRank                  `json:"my_rank"`
+	Submissions map[string]ContestSubmission `json:"my_submission"`
+}
+
+// QuestionSolves is how many of the sampled users have solved a contest question.
+type QuestionSolves struct {
+	Question *QuestionData
+	Credit   int
+	Solved   int
+	Sampled  int
+	// AvgTime is the average time from the start of the contest to the accepted submission.
+	AvgTime time.Duration
+}
+
+// EstimateDifficulty samples pages spread over the standings, and orders the questions by solve count,
+// easiest first. In a running virtual contest, only solves made within the elapsed time are counted.
+func (ct *Contest) EstimateDifficulty(pages int) ([]QuestionSolves, error) {
+	first, err := ct.client.GetContestRanking(ct.TitleSlug, 1)
+	if err != nil {
+		return nil, err
+	}
+	rankings := []*ContestRanking{first}
+	totalPages := (first.UserNum + rankingPageSize - 1) / rankingPageSize
+	for _, page := range samplePages(totalPages, pages)[1:] {
+		ranking, err := ct.client.GetContestRanking(ct.TitleSlug, page)
+		if err != nil {
+			return nil, err
+		}
+		rankings = append(rankings, ranking)
+	}
+
+	start, cutoff := ct.rankingStart(), int64(math.MaxInt64)
+	if ct.IsVirtual && ct.OriginStartTime > 0 && !ct.HasFinished() {
+		cutoff = start + int64(time.Since(time.Unix(ct.StartTime, 0)).Seconds())
+	}
+	return countSolves(ct.Questions, rankings, start, cutoff), nil
+}
+
+// samplePages returns n page numbers evenly spread in [1, total], starting from the first page.
+func samplePages(total int, n int) []int {
+	total = max(total, 1)
+	n = min(max(n, 1), total)
+	if n == 1 {
+		return []int{1}
+	}
+	pages := make([]int, n)
+	for i := range pages {
+		pages[i] = 1 + i*(total-1)/(n-1)
+	}
+	return pages
+}
+
+func countSolves(questions []*QuestionData, rankings []*ContestRanking, start int64, cutoff int64) []QuestionSolves {
+	solves := make([]QuestionSolves, len(questions))
+	totalTime := make([]int64, len(questions))
+	for i, q := range questions {
+		solves[i].Question = q
+	}
+	for _, r := range rankings {
+		for _, rq := range r.Questions {
+			for i, q := range questions {
+				if strconv.Itoa(rq.QuestionId) == q.QuestionId {
+					solves[i].Credit = rq.Credit
+				}
+			}
+		}
+		for _, user := range r.Submissions {
+			for i, q := range questions {
+				solves[i].Sampled++
+				if sub, ok := user[q.QuestionId]; ok && sub.Date <= cutoff {
+					solves[i].Solved++
+					totalTime[i] += sub.Date - start
+				}
+			}
+		}
+	}
+	for i := range solves {
+		if solves[i].Solved > 0 {
+			solves[i].AvgTime = time.Duration(totalTime[i]/int64(solves[i].Solved)) * time.Second
+		}
+	}
+	sort.SliceStable(
+		solves, func(i, j int) bool {
+			if solves[i].Solved != solves[j].Solved {
+				return solves[i].Solved > solves[j].Solved
+			}
+			return solves[i].Credit < solves[j].Credit
+		},
+	)
+	return solves
+}
+
+// penaltyPerFailure is the time added to the finish time for each wrong submission of an accepted question.
+const penaltyPerFailure = 5 * time.Minute
+
+// UserStanding is the rank and accepted submissions of a user in the contest standings.
+type UserStanding struct {
+	ContestRank
+	// Handle is the handle the user is looked up by.
+	Handle string
+	// Found is false if the user is not in the searched pages of the standings.
+	Found       bool
+	Submissions map[string]ContestSubmission
+}
+
+// Penalty is the time added for wrong submissions of accepted questions.
+func (s UserStanding) Penalty() time.Duration {
+	fails := 0
+	for _, sub := range s.Submissions {
+		fails += sub.FailCount
+	}
+	return time.Duration(fails) * penaltyPerFailure
+}
+
+// ContestStandings are the standings of the current user and friends in a contest.
+type ContestStandings struct {
+	UserNum int
+	// Start is the start time of the contest the ranking is of, i.e. the original contest for virtual ones.
+	Start   int64
+	Me      *UserStanding
+	Friends []UserStanding
+}
+
+// Standings looks up the current user, and the friends by username or user slug in at most maxPages pages
+// from the top of the standings. The current user is skipped if not signed in.
+func (ct *Contest) Standings(friends []string, maxPages int) (*ContestStandings, error) {
+	standings := &ContestStandings{Start: ct.rankingStart()}
+
+	var rankings []*ContestRanking
+	for page := 1; page <= max(maxPages, 1); page++ {
+		ranking, err := ct.client.GetContestRanking(ct.TitleSlug, page)
+		if err != nil {
+			return nil, err
+		}
+		standings.UserNum = ranking.UserNum
+		rankings = append(rankings, ranking)
+		standings.Friends = findUsers(rankings, friends)
+		if allFound(standings.Friends) || page*rankingPageSize >= ranking.UserNum {
+			break
+		}
+	}
+
+	if status, err := ct.client.GetUserStatus(); err == nil && status.IsSignedIn {
+		mine, err := ct.client.GetMyContestRanking(ct.TitleSlug)
+		if err != nil {
+			return nil, err
+		}
+		standings.Me = &UserStanding{
+			ContestRank: mine.Rank,
+			Handle:      status.Username,
+			Found:       mine.Rank.Rank > 0,
+			Submissions: mine.Submissions,
+		}
+	}
+	return standings, nil
+}
+
+// findUsers finds the users by handles in the ranking pages, handles are matched case-insensitively.
+func findUsers(rankings []*ContestRanking, handles []string) []UserStanding {
+	users := make([]UserStanding, len(handles))
+	for i, handle := range handles {
+		users[i].Handle = handle
+	}
+	for _, r := range rankings {
+		for j, rank := range r.TotalRank {
+			for i, handle := range handles {
+				if users[i].Found {
+					continue
+				}
+				if !strings.EqualFold(rank.Username, handle) && !strings.EqualFold(rank.UserSlug, handle) {
+					continue
+				}
+				users[i].ContestRank = rank
+				users[i].Found = true
+				if j < len(r.Submissions) {
+					users[i].Submissions = r.Submissions[j]
+				}
+			}
+		}
+	}
+	return users
+}
+
+func allFound(users []UserStanding) bool {
+	for _, u := range users {
+		if !u.Found {
+			return false
+		}
+	}
+	return true
+}
+
+// Credits returns credits of the contest questions, keyed by question slug.
+func (r *ContestRanking) Credits() map[string]int {
+	credits := make(map[string]int, len(r.Questions))
+	for _, q := range r.Questions {
+		credits[q.TitleSlug] = q.Credit
+	}
+	return credits
+}
+
+// VirtualScore is the result of a contest simulated locally, ranked against the standings of the contest.
+type VirtualScore struct {
+	Score  int
+	Solved int
+	// Finish is the time of the last accepted submission from the start, including the penalty.
+	Finish  time.Duration
+	Penalty time.Duration
+	Rank    int
+	UserNum int
+}
+
+// ScoreVirtual scores the results of the virtual contest by the credits of the questions, and finds the rank
+// it would have got in the standings by binary searching the pages.
+// The same as the finish times in the standings, the finish time includes the penalty of wrong submissions.
+func (ct *Contest) ScoreVirtual(v *config.VirtualContest) (*VirtualScore, error) {
+	pages := make(map[int]*ContestRanking)
+	getPage := func(page int) (*ContestRanking, error) {
+		if r, ok := pages[page]; ok {
+			return r, nil
+		}
+		r, err := ct.client.GetContestRanking(ct.TitleSlug, page)
+		if err != nil {
+			return nil, err
+		}
+		pages[page] = r
+		return r, nil
+	}
+	first, err := getPage(1)
+	if err != nil {
+		return nil, err
+	}
+
+	score := &VirtualScore{UserNum: first.UserNum}
+	credits := first.Credits()
+	var last time.Time
+	for slug, r := range v.Results {
+		if r.AcceptedAt.IsZero() {
+			continue
+		}
+		score.Score += credits[slug]
+		score.Solved++
+		score.Penalty += time.Duration(r.FailCount) * penaltyPerFailure
+		if r.AcceptedAt.After(last) {
+			last = r.AcceptedAt
+		}
+	}
+	if score.Solved > 0 {
+		score.Finish = last.Sub(v.StartedAt).Round(time.Second) + score.Penalty
+	}
+	finishAt := ct.rankingStart() + int64(score.Finish.Seconds())
+
+	// Find the first page whose last user is not ranked before the virtual one.
+	lo, hi := 1, max((first.UserNum+rankingPageSize-1)/rankingPageSize, 1)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		r, err := getPage(mid)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(r.TotalRank); n > 0 && rankedBefore(r.TotalRank[n-1], score.Score, finishAt) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	r, err := getPage(lo)
+	if err != nil {
+		return nil, err
+	}
+	before := 0
+	for _, u := range r.TotalRank {
+		if rankedBefore(u, score.Score, finishAt) {
+			before++
+		}
+	}
+	score.Rank = (lo-1)*rankingPageSize + before + 1
+	return score, nil
+}
+
+// rankedBefore reports whether the user is ranked before the one with the score and finish time,
+// users with a higher score, or the same score and an earlier finish time are ranked before.
+func rankedBefore(u ContestRank, score int, finishAt int64) bool {
+	return u.Score > score || u.Score == score && u.FinishTime <= finishAt
+}
@@ -0,0 +1,206 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+)
+
+func TestSamplePages(t *testing.T) {
+	cases := []struct {
+		total, n int
+		want     []int
+	}{
+		{0, 5, []int{1}},
+		{3, 5, []int{1, 2, 3}},
+		{100, 1, []int{1}},
+		{100, 5, []int{1, 25, 50, 75, 100}},
+	}
+	for _, c := range cases {
+		if got := samplePages(c.total, c.n); !slices.Equal(got, c.want) {
+			t.Errorf("samplePages(%d, %d) = %v, want %v", c.total, c.n, got, c.want)
+		}
+	}
+}
+
+func TestCountSolves(t *testing.T) {
+	data := `{
+		"user_num": 3,
+		"questions": [
+			{"question_id": 1, "credit": 3, "title_slug": "a"},
+			{"question_id": 2, "credit": 4, "title_slug": "b"},
+			{"question_id": 3, "credit": 5, "title_slug": "c"}
+		],
+		"submissions": [
+			{"1": {"date": 1100, "fail_count": 0}, "2": {"date": 1500, "fail_count": 1}},
+			{"2": {"date": 1300, "fail_count": 0}},
+			{"1": {"date": 1200, "fail_count": 0}, "2": {"date": 1900, "fail_count": 0}}
+		]
+	}`
+	var ranking ContestRanking
+	if err := json.Unmarshal([]byte(data), &ranking); err != nil {
+		t.Fatal(err)
+	}
+	questions := []*QuestionData{{QuestionId: "1"}, {QuestionId: "2"}, {QuestionId: "3"}}
+
+	solves := countSolves(questions, []*ContestRanking{&ranking}, 1000, 1600)
+	var got []string
+	for _, s := range solves {
+		got = append(got, s.Question.QuestionId)
+		if s.Sampled != 3 {
+			t.Errorf("question %s: sampled %d, want 3", s.Question.QuestionId, s.Sampled)
+		}
+	}
+	if want := []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if solves[1].Solved != 2 || solves[1].AvgTime != 400*time.Second {
+		t.Errorf("question 2: solved %d in %s, want 2 in 6m40s", solves[1].Solved, solves[1].AvgTime)
+	}
+	if solves[2].Solved != 0 || solves[2].Credit != 5 {
+		t.Errorf("question 3: solved %d with credit %d, want 0 with 5", solves[2].Solved, solves[2].Credit)
+	}
+}
+
+func TestFindUsers(t *testing.T) {
+	data := `{
+		"user_num": 3,
+		"total_rank": [
+			{"username": "Alice", "user_slug": "alice", "rank": 1, "score": 12, "finish_time": 1900},
+			{"username": "bob_lc", "user_slug": "bob", "rank": 2, "score": 7, "finish_time": 1500}
+		],
+		"submissions": [
+			{"1": {"date": 1100, "fail_count": 0}, "2": {"date": 1900, "fail_count": 2}},
+			{"2": {"date": 1500, "fail_count": 1}}
+		]
+	}`
+	var ranking ContestRanking
+	if err := json.Unmarshal([]byte(data), &ranking); err != nil {
+		t.Fatal(err)
+	}
+
+	users := findUsers([]*ContestRanking{&ranking}, []string{"alice", "bob", "carol"})
+	if !users[0].Found || users[0].Rank != 1 || users[0].Penalty() != 10*time.Minute {
+		t.Errorf("alice: found %v at rank %d with penalty %s, want rank 1 with 10m0s", users[0].Found, users[0].Rank, users[0].Penalty())
+	}
+	if !users[1].Found || users[1].Username != "bob_lc" || len(users[1].Submissions) != 1 {
+		t.Errorf("bob: found %v as %q with %d submissions, want bob_lc with 1", users[1].Found, users[1].Username, len(users[1].Submissions))
+	}
+	if users[2].Found || users[2].Handle != "carol" {
+		t.Errorf("carol: found %v, want not found", users[2].Found)
+	}
+	if allFound(users) {
+		t.Error("allFound = true, want false")
+	}
+}
+
+func TestRankedBefore(t *testing.T) {
+	u := ContestRank{Score: 12, FinishTime: 2000}
+	cases := []struct {
+		score    int
+		finishAt int64
+		want     bool
+	}{
+		{11, 1000, true},
+		{12, 2000, true},
+		{12, 2500, true},
+		{12, 1500, false},
+		{13, 3000, false},
+	}
+	for _, c := range cases {
+		if got := rankedBefore(u, c.score, c.finishAt); got != c.want {
+			t.Errorf("rankedBefore(%v, %d, %d) = %v, want %v", u, c.score, c.finishAt, got, c.want)
+		}
+	}
+}
+
+type fakeRankingClient struct {
+	Client
+	pages []*ContestRanking
+}
+
+func (c *fakeRankingClient) GetContestRanking(_ string, page int) (*ContestRanking, error) {
+	return c.pages[page-1], nil
+}
+
+func TestScoreVirtual(t *testing.T) {
+	const start = int64(1700000000)
+	// 60 users: page 1 scored more, page 2 has the same score with 10 users finishing earlier, page 3 scored less.
+	var pages []*ContestRanking
+	for p := 0; p < 3; p++ {
+		page := &ContestRanking{UserNum: 60}
+		err := json.Unmarshal(
+			[]byte(`{"questions": [{"title_slug": "q1", "credit": 3}, {"title_slug": "q2", "credit": 4}, {"title_slug": "q3", "credit": 5}]}`),
+			page,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < rankingPageSize && p*rankingPageSize+i < 60; i++ {
+			u := ContestRank{Score: 12, FinishTime: start + 600}
+			switch p {
+			case 1:
+				u = ContestRank{Score: 7, FinishTime: start + 3000}
+				if i < 10 {
+					u.FinishTime = start + int64(i)*100
+				}
+			case 2:
+				u = ContestRank{Score: 3, FinishTime: start + 1000}
+			}
+			page.TotalRank = append(page.TotalRank, u)
+		}
+		pages = append(pages, page)
+	}
+	c := &fakeRankingClient{pages: pages}
+	ct := &Contest{client: c, TitleSlug: "weekly-contest-1", StartTime: start}
+
+	startedAt := time.Now().Add(-20 * time.Minute)
+	v := &config.VirtualContest{
+		Slug:      ct.TitleSlug,
+		StartedAt: startedAt,
+		Duration:  90 * time.Minute,
+		Questions: []string{"q1", "q2", "q3"},
+	}
+	// Two wrong attempts before q1 is accepted, q3 is never accepted.
+	for _, r := range []struct {
+		slug     string
+		accepted bool
+	}{{"q1", false}, {"q1", false}, {"q1", true}, {"q1", false}, {"q2", true}, {"q3", false}, {"q4", true}} {
+		v.Record(r.slug, r.accepted)
+	}
+	if _, ok := v.Results["q4"]; ok {
+		t.Errorf("submission of a question outside the contest should be ignored")
+	}
+	if got := v.Results["q1"].FailCount; got != 2 {
+		t.Fatalf("q1 fail count = %d, want 2, submissions after acceptance are ignored", got)
+	}
+
+	score, err := ct.ScoreVirtual(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := v.Results["q2"].AcceptedAt
+	if v.Results["q1"].AcceptedAt.After(last) {
+		last = v.Results["q1"].AcceptedAt
+	}
+	wantFinish := last.Sub(startedAt).Round(time.Second) + 10*time.Minute
+	if score.Score != 7 || score.Solved != 2 || score.Penalty != 10*time.Minute || score.Finish != wantFinish {
+		t.Errorf("ScoreVirtual() = %+v, want score 7, 2 solved, penalty 10m, finish %s", score, wantFinish)
+	}
+	if score.Rank != 36 || score.UserNum != 60 {
+		t.Errorf("rank = %d of %d, want 36 of 60", score.Rank, score.UserNum)
+	}
+
+	// Nothing accepted ranks after everyone.
+	score, err = ct.ScoreVirtual(&config.VirtualContest{StartedAt: startedAt, Questions: v.Questions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Score != 0 || score.Finish != 0 || score.Rank != 61 {
+		t.Errorf("ScoreVirtual() without accepted questions = %+v, want rank 61", score)
+	}
+}
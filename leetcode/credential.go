@@ -215,8 +215,13 @@ func (b *browserAuth) Reset() {
 }
 
 func ReadCredentials() CredentialsProvider {
+	return CredentialsFrom(config.Get().LeetCode.Credentials.From)
+}
+
+// CredentialsFrom returns the provider of the source, one of the values of leetcode.credentials.from.
+func CredentialsFrom(from string) CredentialsProvider {
 	cfg := config.Get()
-	switch cfg.LeetCode.Credentials.From {
+	switch from {
 	case "browser":
 		return NewBrowserAuth(cfg.LeetCode.Credentials.Browsers)
 	case "password":
@@ -228,6 +233,8 @@ func ReadCredentials() CredentialsProvider {
 		csrfToken := os.Getenv("LEETCODE_CSRFTOKEN")
 		cfClearance := os.Getenv("LEETCODE_CFCLEARANCE")
 		return NewCookiesAuth(session, csrfToken, cfClearance)
+	case "keyring":
+		return NewKeyringAuth(cfg.LeetCode.Site)
 	default:
 		return NonAuth()
 	}
@@ -0,0 +1,140 @@
+package leetcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/j178/leetgo/config"
+	goutils "github.com/j178/leetgo/testutils/go"
+)
+
+// OutputDiff is the structured difference between an actual output and the expected one.
+type OutputDiff struct {
+	// Output and Expected are the outputs with differing elements highlighted.
+	Output   string
+	Expected string
+	// Tree shows a level by level diff of binary trees, one line per level.
+	Tree []string
+}
+
+// DiffOutputs compares outputs structurally: arrays are compared element-wise,
+// binary trees are compared by their shapes. It returns nil if the outputs are not comparable.
+func (q *QuestionData) DiffOutputs(actual, expected string) *OutputDiff {
+	resultType := ""
+	m := q.MetaData
+	if !m.SystemDesign && ((m.Return != nil && m.Return.Type != "void") || (m.Output != nil && m.Output.ParamIndex < len(m.Params))) {
+		resultType = m.ResultType()
+	}
+	actual, expected = strings.TrimSpace(actual), strings.TrimSpace(expected)
+	if !strings.HasPrefix(actual, "[") || !strings.HasPrefix(expected, "[") {
+		return nil
+	}
+	a, err := goutils.SplitArray(actual)
+	if err != nil {
+		return nil
+	}
+	e, err := goutils.SplitArray(expected)
+	if err != nil {
+		return nil
+	}
+	diff := &OutputDiff{
+		Output:   highlightElements(a, e, config.FailedStyle.Render),
+		Expected: highlightElements(e, a, config.PassedStyle.Render),
+	}
+	if resultType == "TreeNode" {
+		diff.Tree = diffTrees(actual, expected)
+	}
+	return diff
+}
+
+func highlight(s string, render func(...string) string) string {
+	if config.Get().UsePlainOutput() {
+		return "*" + s + "*"
+	}
+	return render(s)
+}
+
+// highlightElements renders elements of a, highlights those different from b at the same index.
+func highlightElements(a, b []string, render func(...string) string) string {
+	parts := make([]string, len(a))
+	for i, s := range a {
+		if i >= len(b) || s != b[i] {
+			s = highlight(s, render)
+		}
+		parts[i] = s
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+type treePair struct {
+	actual, expected *goutils.TreeNode
+}
+
+// diffTrees walks two binary trees in level order side by side, nodes of the same position are compared.
+// Nodes only in actual are shown as +val, nodes only in expected are shown as -val.
+func diffTrees(actual, expected string) []string {
+	a, err := goutils.DeserializeTreeNode(actual)
+	if err != nil {
+		return nil
+	}
+	e, err := goutils.DeserializeTreeNode(expected)
+	if err != nil {
+		return nil
+	}
+
+	var lines []string
+	level := []treePair{{a, e}}
+	for depth := 0; len(level) > 0; depth++ {
+		var (
+			tokens []string
+			next   []treePair
+		)
+		for _, p := range level {
+			if p.actual == nil && p.expected == nil {
+				continue
+			}
+			switch {
+			case p.expected == nil:
+				tokens = append(tokens, highlight("+"+strconv.Itoa(p.actual.Val), config.FailedStyle.Render))
+			case p.actual == nil:
+				tokens = append(tokens, highlight("-"+strconv.Itoa(p.expected.Val), config.PassedStyle.Render))
+			case p.actual.Val != p.expected.Val:
+				tokens = append(
+					tokens,
+					highlight(fmt.Sprintf("%d(expected %d)", p.actual.Val, p.expected.Val), config.FailedStyle.Render),
+				)
+			default:
+				tokens = append(tokens, strconv.Itoa(p.actual.Val))
+			}
+			var al, ar, el, er *goutils.TreeNode
+			if p.actual != nil {
+				al, ar = p.actual.Left, p.actual.Right
+			}
+			if p.expected != nil {
+				el, er = p.expected.Left, p.expected.Right
+			}
+			next = append(next, treePair{al, el}, treePair{ar, er})
+		}
+		if len(tokens) == 0 {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("Level %d: %s", depth, strings.Join(tokens, " ")))
+		level = next
+	}
+	return lines
+}
+
+// Format renders the diff as lines prefixed with a newline, labels are padded to the given width.
+func (d *OutputDiff) Format(width int) string {
+	if d == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n%-*s%s", width, "Output:", d.Output))
+	sb.WriteString(fmt.Sprintf("\n%-*s%s", width, "Expected:", d.Expected))
+	for _, l := range d.Tree {
+		sb.WriteString(fmt.Sprintf("\n%-*s%s", width, "", l))
+	}
+	return sb.String()
+}
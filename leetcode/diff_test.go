@@ -0,0 +1,118 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func plainOutput(t *testing.T) {
+	viper.Set("plain", true)
+	t.Cleanup(func() { viper.Set("plain", false) })
+}
+
+func treeQuestion() *QuestionData {
+	return &QuestionData{
+		MetaData: MetaData{
+			Name:   "invertTree",
+			Params: []MetaDataParam{{Name: "root", Type: "TreeNode"}},
+			Return: &MetaDataReturn{Type: "TreeNode"},
+		},
+	}
+}
+
+func TestDiffOutputs(t *testing.T) {
+	plainOutput(t)
+	q := &QuestionData{
+		MetaData: MetaData{
+			Name:   "twoSum",
+			Params: []MetaDataParam{{Name: "nums", Type: "integer[]"}},
+			Return: &MetaDataReturn{Type: "integer[]"},
+		},
+	}
+	cases := []struct {
+		name             string
+		actual, expected string
+		output, want     string
+	}{
+		{"same", "[1,2]", "[1,2]", "[1,2]", "[1,2]"},
+		{"different element", "[1,3]", " [1,2]\n", "[1,*3*]", "[1,*2*]"},
+		{"longer output", "[1,2,3]", "[1,2]", "[1,2,*3*]", "[1,2]"},
+		{"shorter output", "[1]", "[1,2]", "[1]", "[1,*2*]"},
+		{"empty output", "[]", "[1]", "[]", "[*1*]"},
+		{"nested arrays", "[[1,2],[3]]", "[[1,2],[4],[5]]", "[[1,2],*[3]*]", "[[1,2],*[4]*,*[5]*]"},
+		{"strings", `["a","b"]`, `["a","c"]`, `["a",*"b"*]`, `["a",*"c"*]`},
+	}
+	for _, c := range cases {
+		diff := q.DiffOutputs(c.actual, c.expected)
+		if diff == nil {
+			t.Errorf("%s: DiffOutputs() = nil", c.name)
+			continue
+		}
+		if diff.Output != c.output || diff.Expected != c.want || diff.Tree != nil {
+			t.Errorf("%s: DiffOutputs() = %+v, want output %s, expected %s", c.name, diff, c.output, c.want)
+		}
+	}
+
+	for _, c := range [][2]string{{"1", "2"}, {"[1,2]", "3"}, {`"[1]"`, "[1]"}, {"[1,[2]", "[1]"}} {
+		if diff := q.DiffOutputs(c[0], c[1]); diff != nil {
+			t.Errorf("DiffOutputs(%q, %q) = %+v, want nil", c[0], c[1], diff)
+		}
+	}
+}
+
+func TestDiffTrees(t *testing.T) {
+	plainOutput(t)
+	cases := []struct {
+		name             string
+		actual, expected string
+		want             []string
+	}{
+		{"same", "[1,2,3]", "[1,2,3]", []string{"Level 0: 1", "Level 1: 2 3"}},
+		{
+			"different value",
+			"[4,2,7,1,3]", "[4,7,2,1,3]",
+			[]string{"Level 0: 4", "Level 1: *2(expected 7)* *7(expected 2)*", "Level 2: 1 3"},
+		},
+		{
+			"null nodes",
+			"[1,null,2]", "[1,2]",
+			[]string{"Level 0: 1", "Level 1: *-2* *+2*"},
+		},
+		{
+			"deeper output",
+			"[1,2,null,3]", "[1,2]",
+			[]string{"Level 0: 1", "Level 1: 2", "Level 2: *+3*"},
+		},
+		{
+			"empty output",
+			"[]", "[1,2]",
+			[]string{"Level 0: *-1*", "Level 1: *-2*"},
+		},
+	}
+	q := treeQuestion()
+	for _, c := range cases {
+		diff := q.DiffOutputs(c.actual, c.expected)
+		if diff == nil || !slices.Equal(diff.Tree, c.want) {
+			t.Errorf("%s: tree diff = %q, want %q", c.name, diff, c.want)
+		}
+	}
+}
+
+func TestOutputDiffFormat(t *testing.T) {
+	plainOutput(t)
+	diff := treeQuestion().DiffOutputs("[1,null,2]", "[1,2]")
+	want := "\nOutput:   [1,*null*,*2*]" +
+		"\nExpected: [1,*2*]" +
+		"\n          Level 0: 1" +
+		"\n          Level 1: *-2* *+2*"
+	if got := diff.Format(10); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	var nilDiff *OutputDiff
+	if got := nilDiff.Format(10); got != "" {
+		t.Errorf("Format() of nil diff = %q, want empty", got)
+	}
+}
@@ -0,0 +1,133 @@
+package leetcode
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+
+	"github.com/j178/leetgo/config"
+)
+
+// fetchWorkers bounds the number of concurrent requests of FetchQuestions, to stay below the rate limit of LeetCode.
+const fetchWorkers = 4
+
+// FetchQuestions fetches the full data of the questions concurrently, showing a progress bar with ETA.
+// Questions are queried in batches, so that a contest or a study plan takes a few requests only.
+// A failure doesn't stop other questions from being fetched, the returned errors are in the order of qs,
+// nil for questions fetched successfully.
+// The same question may appear more than once, e.g. from the cache by id and by slug, it's fetched only once.
+func FetchQuestions(qs []*QuestionData) []error {
+	var (
+		unique []*QuestionData
+		index  = make(map[*QuestionData]int, len(qs))
+	)
+	for _, q := range qs {
+		if _, ok := index[q]; !ok {
+			index[q] = len(unique)
+			unique = append(unique, q)
+		}
+	}
+	uniqueErrs := fetchQuestions(unique)
+	errs := make([]error, len(qs))
+	for i, q := range qs {
+		errs[i] = uniqueErrs[index[q]]
+	}
+	return errs
+}
+
+// fetchQuestions fetches the distinct questions concurrently.
+func fetchQuestions(qs []*QuestionData) []error {
+	errs := make([]error, len(qs))
+	if len(qs) == 0 {
+		return errs
+	}
+
+	tracker := &progress.Tracker{
+		Message: "Fetching questions",
+		Total:   int64(len(qs)),
+		Units:   progress.UnitsDefault,
+	}
+	pw := progress.NewWriter()
+	pw.SetAutoStop(true)
+	pw.AppendTracker(tracker)
+	pw.SetStyle(progress.StyleBlocks)
+	pw.SetUpdateFrequency(100 * time.Millisecond)
+	render := len(qs) > 1 && !config.Get().UsePlainOutput() && !config.JSONOutput()
+	if render {
+		go pw.Render()
+	}
+
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(fetchWorkers, len(qs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				if len(batch) == 1 {
+					errs[batch[0]] = qs[batch[0]].Fulfill()
+				} else {
+					fulfillBatch(qs, batch, errs)
+				}
+				tracker.Increment(int64(len(batch)))
+			}
+		}()
+	}
+	for _, batch := range fetchBatches(qs) {
+		jobs <- batch
+	}
+	close(jobs)
+	wg.Wait()
+	tracker.MarkAsDone()
+	if render {
+		// Wait for the progress bar to render the final state.
+		for pw.IsRenderInProgress() {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return errs
+}
+
+// fetchBatches groups the indexes of the partial questions into batches of questionBatchSize.
+// Questions of contests are fetched from their pages one by one, so are all questions in offline mode.
+func fetchBatches(qs []*QuestionData) [][]int {
+	var (
+		batches [][]int
+		batch   []int
+	)
+	for i, q := range qs {
+		if !q.isPartial() || q.IsContest() || config.Offline() {
+			batches = append(batches, []int{i})
+			continue
+		}
+		batch = append(batch, i)
+		if len(batch) == questionBatchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// fulfillBatch fetches the full data of the questions of a batch in one request.
+func fulfillBatch(qs []*QuestionData, batch []int, errs []error) {
+	slugs := make([]string, len(batch))
+	for i, idx := range batch {
+		slugs[i] = qs[idx].TitleSlug
+	}
+	nqs, err := qs[batch[0]].client.GetQuestionsData(slugs, FieldsFull)
+	for i, idx := range batch {
+		if err != nil {
+			errs[idx] = err
+			continue
+		}
+		errs[idx] = checkQuestionData(nqs[i])
+		if errs[idx] == nil {
+			qs[idx].fulfillWith(nqs[i], nil)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package leetcode
+
+import (
+	"sync"
+	"testing"
+)
+
+// fakeClient serves question data without network, counting the questions queried.
+type fakeClient struct {
+	Client
+	mu      sync.Mutex
+	queried map[string]int
+}
+
+func (c *fakeClient) query(slug string) *QuestionData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queried[slug]++
+	return &QuestionData{
+		client:       c,
+		TitleSlug:    slug,
+		Content:      "content of " + slug,
+		CodeSnippets: []CodeSnippet{{LangSlug: "golang", Code: "func " + slug}},
+	}
+}
+
+func (c *fakeClient) GetQuestionData(slug string) (*QuestionData, error) {
+	return c.query(slug), nil
+}
+
+func (c *fakeClient) GetQuestionsData(slugs []string, fields QuestionFields) ([]*QuestionData, error) {
+	qs := make([]*QuestionData, len(slugs))
+	for i, slug := range slugs {
+		qs[i] = c.query(slug)
+		if fields != FieldsFull {
+			qs[i].Content = ""
+			qs[i].partial = 1
+		}
+	}
+	return qs, nil
+}
+
+// Run with -race to catch concurrent writes to a question appearing more than once.
+func TestFetchQuestionsDuplicates(t *testing.T) {
+	c := &fakeClient{queried: map[string]int{}}
+	a := &QuestionData{client: c, partial: 1, TitleSlug: "two-sum"}
+	b := &QuestionData{client: c, partial: 1, TitleSlug: "add-two-numbers"}
+	qs := []*QuestionData{a, a, b, a}
+	for range questionBatchSize {
+		qs = append(qs, a, b)
+	}
+
+	errs := FetchQuestions(qs)
+	if len(errs) != len(qs) {
+		t.Fatalf("got %d errors for %d questions", len(errs), len(qs))
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("question %d: %v", i, err)
+		}
+	}
+	for _, q := range []*QuestionData{a, b} {
+		if q.Content != "content of "+q.TitleSlug {
+			t.Errorf("%s is not fetched: %+v", q.TitleSlug, q)
+		}
+		if n := c.queried[q.TitleSlug]; n != 1 {
+			t.Errorf("%s is queried %d times, want once", q.TitleSlug, n)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package leetcode
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// LocalFilter filters questions loaded from the local cache.
+type LocalFilter struct {
+	Difficulty string
+	Tags       []string
+	FreeOnly   bool
+	// Keyword matches the frontend id exactly, or the slug and titles case-insensitively.
+	Keyword string
+	// MinRating and MaxRating limit questions by their difficulty ratings, zero means no limit.
+	// Questions without a rating are excluded when any of them is set.
+	MinRating float64
+	MaxRating float64
+	Ratings   Ratings
+	// Company limits questions to the ones asked by a company, nil means no limit.
+	Company *CompanyQuestions
+	// Exclude reports whether a question should be excluded regardless of other conditions.
+	Exclude func(q *QuestionData) bool
+}
+
+// Match reports whether the question satisfies all conditions of the filter.
+func (f LocalFilter) Match(q *QuestionData) bool {
+	if f.Difficulty != "" && !strings.EqualFold(q.Difficulty, f.Difficulty) {
+		return false
+	}
+	if f.FreeOnly && q.IsPaidOnly {
+		return false
+	}
+	if f.Keyword != "" && !matchKeyword(q, f.Keyword) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tags := q.TagSlugs()
+		for _, t := range f.Tags {
+			if !slices.Contains(tags, t) {
+				return false
+			}
+		}
+	}
+	if f.MinRating > 0 || f.MaxRating > 0 {
+		rating, ok := f.Ratings.Get(q.TitleSlug)
+		if !ok || (f.MinRating > 0 && rating < f.MinRating) || (f.MaxRating > 0 && rating > f.MaxRating) {
+			return false
+		}
+	}
+	if f.Company != nil {
+		if _, ok := f.Company.Frequency(q.TitleSlug); !ok {
+			return false
+		}
+	}
+	if f.Exclude != nil && f.Exclude(q) {
+		return false
+	}
+	return true
+}
+
+func matchKeyword(q *QuestionData, keyword string) bool {
+	if q.QuestionFrontendId == keyword {
+		return true
+	}
+	keyword = strings.ToLower(keyword)
+	for _, s := range []string{q.TitleSlug, q.Title, q.TranslatedTitle} {
+		if strings.Contains(strings.ToLower(s), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterQuestions returns questions that match the filter.
+func FilterQuestions(qs []*QuestionData, f LocalFilter) []*QuestionData {
+	var result []*QuestionData
+	for _, q := range qs {
+		if f.Match(q) {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// PickSeeded picks the question whose slug hashes lowest together with the seed, nil if qs is empty.
+// The pick only depends on the seed and the questions, not on their order, and it stays the same when
+// other questions are added, so caches updated at different times agree on it.
+func PickSeeded(qs []*QuestionData, seed string) *QuestionData {
+	var (
+		picked *QuestionData
+		lowest uint64
+	)
+	for _, q := range qs {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(seed + "/" + q.TitleSlug))
+		if sum := h.Sum64(); picked == nil || sum < lowest {
+			picked, lowest = q, sum
+		}
+	}
+	return picked
+}
+
+// HasTags reports whether any of the questions carries tag information.
+// Some sites do not provide tags in the question list API.
+func HasTags(qs []*QuestionData) bool {
+	for _, q := range qs {
+		if len(q.TopicTags) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SortByRating sorts questions by their ratings in ascending order, questions without a rating go last.
+func SortByRating(qs []*QuestionData, ratings Ratings) {
+	slices.SortStableFunc(
+		qs, func(a, b *QuestionData) int {
+			ra, okA := ratings.Get(a.TitleSlug)
+			rb, okB := ratings.Get(b.TitleSlug)
+			switch {
+			case okA && okB:
+				return cmp.Compare(ra, rb)
+			case okA:
+				return -1
+			case okB:
+				return 1
+			}
+			return 0
+		},
+	)
+}
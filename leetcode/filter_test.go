@@ -0,0 +1,48 @@
+package leetcode
+
+import "testing"
+
+func TestPickSeeded(t *testing.T) {
+	qs := []*QuestionData{{TitleSlug: "two-sum"}, {TitleSlug: "add-two-numbers"}, {TitleSlug: "3sum"}, {TitleSlug: "lru-cache"}}
+	picked := PickSeeded(qs, "2024-01-01")
+	reversed := []*QuestionData{qs[3], qs[2], qs[1], qs[0]}
+	if got := PickSeeded(reversed, "2024-01-01"); got != picked {
+		t.Errorf("pick depends on order: %s vs %s", got.TitleSlug, picked.TitleSlug)
+	}
+	if got := PickSeeded(append(qs, &QuestionData{TitleSlug: "zzz-not-picked"}), "2024-01-01"); got.TitleSlug != picked.TitleSlug &&
+		got.TitleSlug != "zzz-not-picked" {
+		t.Errorf("adding a question changed the pick to %s", got.TitleSlug)
+	}
+
+	seen := map[string]bool{}
+	for _, seed := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		seen[PickSeeded(qs, seed).TitleSlug] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("different seeds should pick different questions")
+	}
+	if PickSeeded(nil, "a") != nil {
+		t.Errorf("empty questions should pick nothing")
+	}
+}
+
+func TestMatchKeyword(t *testing.T) {
+	q := &QuestionData{QuestionFrontendId: "1", TitleSlug: "two-sum", Title: "Two Sum", TranslatedTitle: "两数之和"}
+	cases := []struct {
+		keyword string
+		want    bool
+	}{
+		{"1", true},
+		{"two-sum", true},
+		{"two sum", true},
+		{"SUM", true},
+		{"两数", true},
+		{"11", false},
+		{"three", false},
+	}
+	for _, c := range cases {
+		if got := (LocalFilter{Keyword: c.keyword}).Match(q); got != c.want {
+			t.Errorf("Match(%q) = %v, want %v", c.keyword, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package leetcode
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrSystemDesignNotSupported = errors.New("system design questions are not supported")
+
+// InputLimits caps the size of randomly generated inputs, so that a brute-force solution can finish quickly.
+type InputLimits struct {
+	MaxLen   int
+	MaxValue int64
+}
+
+// InputGenerator generates random inputs for a question, respecting the constraints parsed from its statement.
+// It is best-effort: constraints that are not simple ranges (uniqueness, sortedness, ...) are ignored.
+type InputGenerator struct {
+	q      *QuestionData
+	cs     *Constraints
+	limits InputLimits
+	rnd    *rand.Rand
+	env    map[string]int64
+}
+
+func NewInputGenerator(q *QuestionData, limits InputLimits, seed uint64) (*InputGenerator, error) {
+	if q.MetaData.SystemDesign {
+		return nil, ErrSystemDesignNotSupported
+	}
+	return &InputGenerator{
+		q:      q,
+		cs:     q.ParseConstraints(),
+		limits: limits,
+		rnd:    rand.New(rand.NewPCG(seed, seed)),
+	}, nil
+}
+
+// Generate returns a random input, one line per parameter.
+func (g *InputGenerator) Generate() ([]string, error) {
+	g.env = make(map[string]int64)
+	lines := make([]string, 0, len(g.q.MetaData.Params))
+	for _, p := range g.q.MetaData.Params {
+		s, err := g.value(p.Name, p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+		lines = append(lines, s)
+	}
+	return lines, nil
+}
+
+func (g *InputGenerator) value(name, ty string) (string, error) {
+	switch ty {
+	case "integer", "long":
+		v := g.number(name)
+		if !strings.Contains(name, "[") {
+			g.env[name] = v
+		}
+		return strconv.FormatInt(v, 10), nil
+	case "double":
+		v := float64(g.number(name)) + g.rnd.Float64()
+		return strconv.FormatFloat(v, 'f', 5, 64), nil
+	case "boolean":
+		return strconv.FormatBool(g.rnd.IntN(2) == 1), nil
+	case "character":
+		return strconv.Quote(string(g.char(name))), nil
+	case "string":
+		n := g.length(name + ".length")
+		if !strings.Contains(name, "[") {
+			g.env[name+".length"] = int64(n)
+		}
+		buf := make([]byte, n)
+		for i := range buf {
+			buf[i] = g.char(name)
+		}
+		return strconv.Quote(string(buf)), nil
+	case "TreeNode", "ListNode":
+		n := g.length(NodesConstraint)
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = strconv.FormatInt(g.number("Node.val"), 10)
+		}
+		return "[" + strings.Join(vals, ",") + "]", nil
+	}
+	if elem, ok := strings.CutSuffix(ty, "[]"); ok {
+		n := g.length(name + ".length")
+		g.env[name+".length"] = int64(n)
+		elemName := name + "[" + string(rune('i'+strings.Count(name, "["))) + "]"
+		vals := make([]string, n)
+		for i := range vals {
+			s, err := g.value(elemName, elem)
+			if err != nil {
+				return "", err
+			}
+			vals[i] = s
+		}
+		return "[" + strings.Join(vals, ",") + "]", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", ty)
+}
+
+// bound returns the range of name, preferring values already generated in this input.
+func (g *InputGenerator) bound(name string) (int64, int64, bool) {
+	alias := g.cs.Aliases[name]
+	for _, n := range []string{name, alias} {
+		if v, ok := g.env[n]; ok {
+			return v, v, true
+		}
+	}
+	if v, err := strconv.ParseInt(alias, 10, 64); err == nil {
+		return v, v, true
+	}
+	c, ok := g.cs.Lookup(name)
+	if !ok {
+		return 0, 0, false
+	}
+	lo, err := EvalBound(c.Min, g.env)
+	if err != nil {
+		return 0, 0, false
+	}
+	hi, err := EvalBound(c.Max, g.env)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+func (g *InputGenerator) number(name string) int64 {
+	lo, hi, ok := g.bound(name)
+	if !ok {
+		lo, hi = 0, g.limits.MaxValue
+	}
+	lo, hi = clampRange(lo, hi, -g.limits.MaxValue, g.limits.MaxValue)
+	return g.between(lo, hi)
+}
+
+func (g *InputGenerator) length(name string) int {
+	lo, hi, ok := g.bound(name)
+	if !ok {
+		lo, hi = 1, int64(g.limits.MaxLen)
+	}
+	lo, hi = clampRange(lo, hi, 0, int64(g.limits.MaxLen))
+	return int(g.between(lo, hi))
+}
+
+func (g *InputGenerator) between(lo, hi int64) int64 {
+	if lo >= hi {
+		return lo
+	}
+	return lo + g.rnd.Int64N(hi-lo+1)
+}
+
+// clampRange narrows [lo, hi] into [min, max], or to the endpoint nearest to it if they do not overlap.
+func clampRange(lo, hi, minV, maxV int64) (int64, int64) {
+	if lo > maxV {
+		return lo, lo
+	}
+	if hi < minV {
+		return hi, hi
+	}
+	return max(lo, minV), min(hi, maxV)
+}
+
+const (
+	lowercaseLetters = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars       = "0123456789"
+	// Unconstrained strings use a small alphabet, which makes repeated characters (and bugs) more likely.
+	defaultCharset = "abc"
+)
+
+var quotedCharsRe = regexp.MustCompile(`'(.)'`)
+
+// char picks a random character for name, guessing the charset from sentences like "s consists of lowercase English letters".
+func (g *InputGenerator) char(name string) byte {
+	base, _, _ := strings.Cut(name, "[")
+	charset := ""
+	for _, text := range g.cs.Text {
+		if !strings.HasPrefix(text, base+" ") && !strings.HasPrefix(text, base+"[") {
+			continue
+		}
+		lower := strings.ToLower(text)
+		if strings.Contains(lower, "lowercase") || (strings.Contains(lower, "letters") && !strings.Contains(lower, "uppercase")) {
+			charset += lowercaseLetters
+		}
+		if strings.Contains(lower, "uppercase") {
+			charset += uppercaseLetters
+		}
+		if strings.Contains(lower, "digit") {
+			charset += digitChars
+		}
+		if charset == "" {
+			for _, m := range quotedCharsRe.FindAllStringSubmatch(text, -1) {
+				charset += m[1]
+			}
+		}
+		if charset != "" {
+			break
+		}
+	}
+	if charset == "" {
+		charset = defaultCharset
+	}
+	return charset[g.rnd.IntN(len(charset))]
+}
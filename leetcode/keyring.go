@@ -0,0 +1,242 @@
+package leetcode
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/utils"
+)
+
+const credentialsPassphrase = "LEETGO_CREDENTIALS_PASSPHRASE"
+
+// StoredCookies are the LeetCode cookies kept in the OS keychain or the encrypted credentials file.
+type StoredCookies struct {
+	LeetCodeSession string `json:"leetcode_session"`
+	CsrfToken       string `json:"csrftoken"`
+	CfClearance     string `json:"cf_clearance,omitempty"`
+}
+
+type keyringAuth struct {
+	cookiesAuth
+	mu     sync.Mutex
+	site   config.LeetcodeSite
+	loaded bool
+}
+
+// NewKeyringAuth reads cookies saved by `leetgo credentials save` from the OS keychain,
+// or from the encrypted credentials file if the keychain is not available.
+func NewKeyringAuth(site config.LeetcodeSite) CredentialsProvider {
+	return &keyringAuth{site: site}
+}
+
+func (k *keyringAuth) AddCredentials(req *http.Request) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.loaded {
+		k.loaded = true
+		cookies, err := LoadCookies(k.site)
+		if err != nil {
+			return err
+		}
+		k.LeetCodeSession = cookies.LeetCodeSession
+		k.CsrfToken = cookies.CsrfToken
+		k.CfClearance = cookies.CfClearance
+	}
+	return k.cookiesAuth.AddCredentials(req)
+}
+
+func (k *keyringAuth) Reset() {}
+
+// CookiesOf returns the cookies the provider adds to requests of the client.
+func CookiesOf(c Client, provider CredentialsProvider) (StoredCookies, error) {
+	req, _ := http.NewRequest(http.MethodGet, c.BaseURI(), nil)
+	err := provider.AddCredentials(req)
+	if err != nil {
+		return StoredCookies{}, err
+	}
+	var cookies StoredCookies
+	for _, cookie := range req.Cookies() {
+		switch cookie.Name {
+		case "LEETCODE_SESSION":
+			cookies.LeetCodeSession = cookie.Value
+		case "csrftoken":
+			cookies.CsrfToken = cookie.Value
+		case "cf_clearance":
+			cookies.CfClearance = cookie.Value
+		}
+	}
+	return cookies, nil
+}
+
+func keyringUser(site config.LeetcodeSite) string {
+	u, err := url.Parse(string(site))
+	if err != nil {
+		return string(site)
+	}
+	return u.Host
+}
+
+// SaveCookies saves cookies of the site to the OS keychain, or to the encrypted credentials file
+// if the keychain is not available. It returns where the cookies are saved.
+func SaveCookies(site config.LeetcodeSite, cookies StoredCookies) (string, error) {
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return "", err
+	}
+	err = keyring.Set(constants.CmdName, keyringUser(site), string(data))
+	if err == nil {
+		return "OS keychain", nil
+	}
+	log.Debug("failed to save to keychain, fallback to encrypted file", "err", err)
+
+	file := config.Get().CredentialsFile()
+	all, err := readCredentialsFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if all == nil {
+		all = make(map[string]StoredCookies)
+	}
+	all[keyringUser(site)] = cookies
+	err = writeCredentialsFile(file, all)
+	if err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// LoadCookies loads cookies of the site saved by SaveCookies.
+func LoadCookies(site config.LeetcodeSite) (StoredCookies, error) {
+	var cookies StoredCookies
+	data, err := keyring.Get(constants.CmdName, keyringUser(site))
+	if err == nil {
+		err = json.Unmarshal([]byte(data), &cookies)
+		return cookies, err
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		log.Debug("failed to read keychain, fallback to encrypted file", "err", err)
+	}
+
+	file := config.Get().CredentialsFile()
+	all, ferr := readCredentialsFile(file)
+	if os.IsNotExist(ferr) {
+		return cookies, errors.New("no credentials saved, run `leetgo credentials save` first")
+	}
+	if ferr != nil {
+		return cookies, ferr
+	}
+	cookies, ok := all[keyringUser(site)]
+	if !ok {
+		return cookies, fmt.Errorf("no credentials saved for %s, run `leetgo credentials save` first", site)
+	}
+	return cookies, nil
+}
+
+// DeleteCookies removes cookies of the site from both the OS keychain and the encrypted credentials file.
+func DeleteCookies(site config.LeetcodeSite) error {
+	err := keyring.Delete(constants.CmdName, keyringUser(site))
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		log.Debug("failed to delete from keychain", "err", err)
+	}
+
+	file := config.Get().CredentialsFile()
+	if !utils.IsExist(file) {
+		return nil
+	}
+	all, err := readCredentialsFile(file)
+	if err != nil {
+		return err
+	}
+	delete(all, keyringUser(site))
+	if len(all) == 0 {
+		return os.Remove(file)
+	}
+	return writeCredentialsFile(file, all)
+}
+
+// The credentials file is salt + nonce + AES-GCM sealed JSON, keyed by scrypt of the passphrase.
+const saltSize = 16
+
+func credentialsKey(salt []byte) ([]byte, error) {
+	passphrase := os.Getenv(credentialsPassphrase)
+	if passphrase == "" {
+		return nil, fmt.Errorf("OS keychain is not available, set %s to use the encrypted credentials file", credentialsPassphrase)
+	}
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+func credentialsCipher(salt []byte) (cipher.AEAD, error) {
+	key, err := credentialsKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func readCredentialsFile(file string) (map[string]StoredCookies, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < saltSize {
+		return nil, errors.New("invalid credentials file")
+	}
+	aead, err := credentialsCipher(data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid credentials file")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file, wrong passphrase? %w", err)
+	}
+	var all map[string]StoredCookies
+	err = json.Unmarshal(plain, &all)
+	return all, err
+}
+
+func writeCredentialsFile(file string, all map[string]StoredCookies) error {
+	plain, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, saltSize)
+	_, _ = rand.Read(salt)
+	aead, err := credentialsCipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+
+	data := append(salt, nonce...)
+	data = aead.Seal(data, nonce, plain, nil)
+	err = utils.MakeDir(filepath.Dir(file))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o600)
+}
@@ -0,0 +1,35 @@
+package leetcode
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentialsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials.enc")
+	want := map[string]StoredCookies{
+		"leetcode.cn": {LeetCodeSession: "session", CsrfToken: "token"},
+	}
+
+	t.Setenv(credentialsPassphrase, "")
+	if err := writeCredentialsFile(file, want); err == nil {
+		t.Fatal("expected error without passphrase")
+	}
+
+	t.Setenv(credentialsPassphrase, "secret")
+	if err := writeCredentialsFile(file, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readCredentialsFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["leetcode.cn"] != want["leetcode.cn"] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	t.Setenv(credentialsPassphrase, "wrong")
+	if _, err := readCredentialsFile(file); err == nil {
+		t.Error("expected error with wrong passphrase")
+	}
+}
@@ -0,0 +1,117 @@
+package leetcode
+
+import (
+	"strings"
+)
+
+// TypeInfo is a parsed metadata type name, e.g. "integer[][]" is "integer" with two array dimensions.
+type TypeInfo struct {
+	// Base is the element type name without array suffixes, e.g. "integer", "string", "TreeNode" or "void".
+	Base string
+	// Dim is the number of array dimensions, 0 for scalar types.
+	Dim int
+}
+
+// ParseType parses a metadata type name, it accepts both raw ("list<String>") and normalized ("string[]") names.
+func ParseType(name string) TypeInfo {
+	name = normalizeType(name)
+	t := TypeInfo{}
+	for strings.HasSuffix(name, "[]") {
+		name = name[:len(name)-2]
+		t.Dim++
+	}
+	t.Base = name
+	return t
+}
+
+func (t TypeInfo) IsVoid() bool {
+	return t.Dim == 0 && t.Base == "void"
+}
+
+func (t TypeInfo) IsArray() bool {
+	return t.Dim > 0
+}
+
+// Elem returns the element type of an array type, or the type itself if it's not an array.
+func (t TypeInfo) Elem() TypeInfo {
+	if t.Dim > 0 {
+		t.Dim--
+	}
+	return t
+}
+
+// String returns the normalized type name, e.g. "integer[][]".
+func (t TypeInfo) String() string {
+	return t.Base + strings.Repeat("[]", t.Dim)
+}
+
+type FuncParam struct {
+	Name string
+	Type TypeInfo
+}
+
+// Signature is the typed signature of the solution function, or of a constructor or method of a design class.
+type Signature struct {
+	Name   string
+	Params []FuncParam
+	Return TypeInfo
+	// OutputParam is the index of the param modified in place by a void function, -1 if there is none.
+	OutputParam int
+}
+
+func newSignature(name string, params []MetaDataParam, ret string) Signature {
+	s := Signature{Name: name, Return: ParseType(ret), OutputParam: -1}
+	for _, p := range params {
+		s.Params = append(s.Params, FuncParam{Name: p.Name, Type: ParseType(p.Type)})
+	}
+	return s
+}
+
+// IsVoid reports whether the function returns nothing.
+func (s Signature) IsVoid() bool {
+	return s.Return.IsVoid()
+}
+
+// ResultType returns the type of the result of the function: the return type, or the type of
+// the output param for in-place functions, or void if the function produces no checkable result.
+func (s Signature) ResultType() TypeInfo {
+	if !s.IsVoid() || s.OutputParam < 0 || s.OutputParam >= len(s.Params) {
+		return s.Return
+	}
+	return s.Params[s.OutputParam].Type
+}
+
+func (s Signature) ParamNames() []string {
+	names := make([]string, 0, len(s.Params))
+	for _, p := range s.Params {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Function returns the signature of the solution function of a normal problem.
+func (m *MetaData) Function() Signature {
+	ret := "void"
+	if m.Return != nil {
+		ret = m.Return.Type
+	}
+	s := newSignature(m.Name, m.Params, ret)
+	if m.Output != nil {
+		s.OutputParam = m.Output.ParamIndex
+	}
+	return s
+}
+
+// ConstructorSignature returns the signature of the constructor of a design problem, named after the class.
+func (m *MetaData) ConstructorSignature() Signature {
+	return newSignature(m.ClassName, m.Constructor.Params, "void")
+}
+
+// MethodSignatures returns the signatures of the methods of a design problem, in the order of the metadata.
+func (m *MetaData) MethodSignatures() []Signature {
+	methods := make([]Signature, 0, len(m.Methods))
+	for _, method := range m.Methods {
+		methods = append(methods, newSignature(method.Name, method.Params, method.Return.Type))
+	}
+	return methods
+}
@@ -3,9 +3,13 @@ package leetcode
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/mattn/go-runewidth"
+
 	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/i18n"
 	"github.com/j178/leetgo/utils"
 )
 
@@ -78,52 +82,72 @@ type SubmitCheckResult struct {
 	FullRuntimeError  string  `json:"full_runtime_error"`
 }
 
+// fieldWidth is the width of labels of judge results, to align the values.
+const fieldWidth = 15
+
+// field formats a line of judge results with the translated label.
+func field(label string, value string) string {
+	label = i18n.T(label) + ":"
+	padding := max(fieldWidth-runewidth.StringWidth(label), 1)
+	return "\n" + label + strings.Repeat(" ", padding) + value
+}
+
 func (r *SubmitCheckResult) Display(q *QuestionData) string {
 	stdout := ""
 	if len(r.StdOutput) > 0 {
-		stdout = "\nStdout:        " + utils.TruncateString(strings.ReplaceAll(r.StdOutput, "\n", "↩ "), 1000)
+		stdout = field("Stdout", utils.TruncateString(strings.ReplaceAll(r.StdOutput, "\n", config.NewlineMark()), 1000))
 	}
 	switch StatusCode(r.StatusCode) {
 	case Accepted:
 		return fmt.Sprintf(
 			"\n%s%s%s%s\n",
-			config.PassedStyle.Render(fmt.Sprintf(" √ %s\n", r.StatusMsg)),
-			fmt.Sprintf("\nPassed cases:  %d/%d", r.TotalCorrect, r.TotalTestcases),
-			fmt.Sprintf("\nRuntime:       %s, better than %.0f%%", r.StatusRuntime, r.RuntimePercentile),
-			fmt.Sprintf("\nMemory:        %s, better than %.0f%%", r.StatusMemory, r.MemoryPercentile),
+			config.PassedStyle.Render(fmt.Sprintf(" %s %s\n", config.PassedMark(), i18n.T(r.StatusMsg))),
+			field("Passed cases", fmt.Sprintf("%d/%d", r.TotalCorrect, r.TotalTestcases)),
+			field("Runtime", i18n.Sprintf("%s, better than %.0f%%", r.StatusRuntime, r.RuntimePercentile)),
+			field("Memory", i18n.Sprintf("%s, better than %.0f%%", r.StatusMemory, r.MemoryPercentile)),
 		)
 	case WrongAnswer:
+		if diff := q.DiffOutputs(r.CodeOutput, r.ExpectedOutput); diff != nil {
+			return fmt.Sprintf(
+				"\n%s%s%s%s%s\n",
+				config.FailedStyle.Render(" "+config.FailedMark()+" "+i18n.T("Wrong Answer")+"\n"),
+				field("Passed cases", fmt.Sprintf("%d/%d", r.TotalCorrect, r.TotalTestcases)),
+				field("Last case", utils.TruncateString(strings.ReplaceAll(r.LastTestcase, "\n", config.NewlineMark()), 100)),
+				stdout,
+				diff.Format(15),
+			)
+		}
 		return fmt.Sprintf(
 			"\n%s%s%s%s%s%s\n",
-			config.FailedStyle.Render(" × Wrong Answer\n"),
-			fmt.Sprintf("\nPassed cases:  %d/%d", r.TotalCorrect, r.TotalTestcases),
-			fmt.Sprintf("\nLast case:     %s", utils.TruncateString(strings.ReplaceAll(r.LastTestcase, "\n", "↩ "), 100)),
-			fmt.Sprintf("\nOutput:        %s", utils.TruncateString(strings.ReplaceAll(r.CodeOutput, "\n", "↩ "), 100)),
+			config.FailedStyle.Render(" "+config.FailedMark()+" "+i18n.T("Wrong Answer")+"\n"),
+			field("Passed cases", fmt.Sprintf("%d/%d", r.TotalCorrect, r.TotalTestcases)),
+			field("Last case", utils.TruncateString(strings.ReplaceAll(r.LastTestcase, "\n", config.NewlineMark()), 100)),
+			field("Output", utils.TruncateString(strings.ReplaceAll(r.CodeOutput, "\n", config.NewlineMark()), 100)),
 			stdout,
-			fmt.Sprintf("\nExpected:      %s", utils.TruncateString(strings.ReplaceAll(r.ExpectedOutput, "\n", "↩ "), 100)),
+			field("Expected", utils.TruncateString(strings.ReplaceAll(r.ExpectedOutput, "\n", config.NewlineMark()), 100)),
 		)
 	case MemoryLimitExceeded, TimeLimitExceeded, OutputLimitExceeded:
 		return fmt.Sprintf(
 			"\n%s%s%s\n",
-			config.ErrorStyle.Render(fmt.Sprintf(" × %s\n", r.StatusMsg)),
-			fmt.Sprintf("\nPassed cases:  %d/%d", r.TotalCorrect, r.TotalTestcases),
-			fmt.Sprintf("\nLast case:     %s", utils.TruncateString(r.LastTestcase, 100)),
+			config.ErrorStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg))),
+			field("Passed cases", fmt.Sprintf("%d/%d", r.TotalCorrect, r.TotalTestcases)),
+			field("Last case", utils.TruncateString(r.LastTestcase, 100)),
 		)
 	case RuntimeError:
 		return fmt.Sprintf(
 			"\n%s%s%s\n",
-			config.ErrorStyle.Render(fmt.Sprintf(" × %s\n", r.StatusMsg)),
-			fmt.Sprintf("\nPassed cases:   %s", formatCompare(r.CompareResult)),
+			config.ErrorStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg))),
+			field("Passed cases", formatCompare(r.CompareResult)),
 			"\n"+config.StdoutStyle.Render(r.FullRuntimeError),
 		)
 	case CompileError:
 		return fmt.Sprintf(
 			"\n%s%s\n",
-			config.ErrorStyle.Render(fmt.Sprintf(" × %s\n", r.StatusMsg)),
+			config.ErrorStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg))),
 			"\n"+config.StdoutStyle.Render(r.FullCompileError),
 		)
 	default:
-		return config.FailedStyle.Render(fmt.Sprintf("\n × %s\n", r.StatusMsg))
+		return config.FailedStyle.Render(fmt.Sprintf("\n %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg)))
 	}
 }
 
@@ -135,6 +159,12 @@ func (r *SubmitCheckResult) Accepted() bool {
 	return r.StatusCode == int(Accepted)
 }
 
+// RuntimeMs parses the runtime in StatusRuntime, e.g. "4 ms", false if the runtime is not reported.
+func (r *SubmitCheckResult) RuntimeMs() (int, bool) {
+	ms, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(r.StatusRuntime), "ms")))
+	return ms, err == nil
+}
+
 type RunCheckResult struct {
 	InputData              string
 	State                  string   `json:"state"` // STARTED, SUCCESS
@@ -179,6 +209,19 @@ type RunCheckResult struct {
 
 func formatCompare(s string) string {
 	var sb strings.Builder
+	if config.Get().UsePlainOutput() {
+		for i, c := range s {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if c == '1' {
+				sb.WriteString(i18n.Sprintf("case %d passed", i+1))
+			} else {
+				sb.WriteString(i18n.Sprintf("case %d failed", i+1))
+			}
+		}
+		return sb.String()
+	}
 	for _, c := range s {
 		if c == '1' {
 			sb.WriteString(config.PassedStyle.Render("√"))
@@ -189,51 +232,69 @@ func formatCompare(s string) string {
 	return sb.String()
 }
 
-func (r *RunCheckResult) Display(_ *QuestionData) string {
+// formatCaseDiffs shows structured diffs of the failed cases.
+func (r *RunCheckResult) formatCaseDiffs(q *QuestionData) string {
+	var sb strings.Builder
+	for i, c := range r.CompareResult {
+		if c == '1' || i >= len(r.CodeAnswer) || i >= len(r.ExpectedCodeAnswer) {
+			continue
+		}
+		diff := q.DiffOutputs(r.CodeAnswer[i], r.ExpectedCodeAnswer[i])
+		if diff == nil {
+			continue
+		}
+		sb.WriteString("\n\n" + i18n.Sprintf("Case %d diff:", i+1))
+		sb.WriteString(diff.Format(15))
+	}
+	return sb.String()
+}
+
+func (r *RunCheckResult) Display(q *QuestionData) string {
 	stdout := ""
 	if len(r.CodeOutput) > 0 {
-		stdout = "\nStdout:        " + utils.TruncateString(strings.Join(r.CodeOutput, "↩ "), 1000)
+		stdout = field("Stdout", utils.TruncateString(strings.Join(r.CodeOutput, config.NewlineMark()), 1000))
 	}
 	switch StatusCode(r.StatusCode) {
 	case Accepted:
 		if r.CorrectAnswer {
 			return fmt.Sprintf(
 				"\n%s%s%s%s%s%s\n",
-				config.PassedStyle.Render(fmt.Sprintf(" √ %s\n", r.StatusMsg)),
-				fmt.Sprintf("\nPassed cases:  %s", formatCompare(r.CompareResult)),
-				fmt.Sprintf("\nInput:         %s", utils.TruncateString(strings.ReplaceAll(r.InputData, "\n", "↩ "), 100)),
-				fmt.Sprintf("\nOutput:        %s", utils.TruncateString(strings.Join(r.CodeAnswer, "↩ "), 100)),
+				config.PassedStyle.Render(fmt.Sprintf(" %s %s\n", config.PassedMark(), i18n.T(r.StatusMsg))),
+				field("Passed cases", formatCompare(r.CompareResult)),
+				field("Input", utils.TruncateString(strings.ReplaceAll(r.InputData, "\n", config.NewlineMark()), 100)),
+				field("Output", utils.TruncateString(strings.Join(r.CodeAnswer, config.NewlineMark()), 100)),
 				stdout,
-				fmt.Sprintf("\nExpected:      %s", utils.TruncateString(strings.Join(r.ExpectedCodeAnswer, "↩ "), 100)),
+				field("Expected", utils.TruncateString(strings.Join(r.ExpectedCodeAnswer, config.NewlineMark()), 100)),
 			)
 		} else {
 			return fmt.Sprintf(
-				"\n%s%s%s%s%s%s\n",
-				config.ErrorStyle.Render("\n × Wrong Answer\n"),
-				fmt.Sprintf("\nPassed cases:  %s", formatCompare(r.CompareResult)),
-				fmt.Sprintf("\nInput:         %s", utils.TruncateString(strings.ReplaceAll(r.InputData, "\n", "↩ "), 100)),
-				fmt.Sprintf("\nOutput:        %s", utils.TruncateString(strings.Join(r.CodeAnswer, "↩ "), 100)),
+				"\n%s%s%s%s%s%s%s\n",
+				config.ErrorStyle.Render("\n "+config.FailedMark()+" "+i18n.T("Wrong Answer")+"\n"),
+				field("Passed cases", formatCompare(r.CompareResult)),
+				field("Input", utils.TruncateString(strings.ReplaceAll(r.InputData, "\n", config.NewlineMark()), 100)),
+				field("Output", utils.TruncateString(strings.Join(r.CodeAnswer, config.NewlineMark()), 100)),
 				stdout,
-				fmt.Sprintf("\nExpected:      %s", utils.TruncateString(strings.Join(r.ExpectedCodeAnswer, "↩ "), 100)),
+				field("Expected", utils.TruncateString(strings.Join(r.ExpectedCodeAnswer, config.NewlineMark()), 100)),
+				r.formatCaseDiffs(q),
 			)
 		}
 	case MemoryLimitExceeded, TimeLimitExceeded, OutputLimitExceeded:
-		return config.ErrorStyle.Render(fmt.Sprintf("\n × %s\n", r.StatusMsg))
+		return config.ErrorStyle.Render(fmt.Sprintf("\n %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg)))
 	case RuntimeError:
 		return fmt.Sprintf(
 			"\n%s%s%s\n",
-			config.ErrorStyle.Render(fmt.Sprintf(" × %s\n", r.StatusMsg)),
-			fmt.Sprintf("Passed cases:   %s", formatCompare(r.CompareResult)),
+			config.ErrorStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg))),
+			field("Passed cases", formatCompare(r.CompareResult)),
 			"\n"+config.StdoutStyle.Render(r.FullRuntimeError),
 		)
 	case CompileError:
 		return fmt.Sprintf(
 			"\n%s%s\n",
-			config.ErrorStyle.Render(fmt.Sprintf(" × %s\n", r.StatusMsg)),
+			config.ErrorStyle.Render(fmt.Sprintf(" %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg))),
 			"\n"+config.StdoutStyle.Render(r.FullCompileError),
 		)
 	default:
-		return config.FailedStyle.Render(fmt.Sprintf("\n × %s\n", r.StatusMsg))
+		return config.FailedStyle.Render(fmt.Sprintf("\n %s %s\n", config.FailedMark(), i18n.T(r.StatusMsg)))
 	}
 }
 
@@ -0,0 +1,24 @@
+package leetcode
+
+import "testing"
+
+func TestRuntimeMs(t *testing.T) {
+	cases := []struct {
+		runtime string
+		want    int
+		ok      bool
+	}{
+		{"4 ms", 4, true},
+		{"0 ms", 0, true},
+		{"1234ms", 1234, true},
+		{"N/A", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		r := &SubmitCheckResult{StatusRuntime: c.runtime}
+		got, ok := r.RuntimeMs()
+		if got != c.want || ok != c.ok {
+			t.Errorf("RuntimeMs(%q) = %d, %v, want %d, %v", c.runtime, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package leetcode
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+// ErrOffline is returned for requests made in offline mode.
+var ErrOffline = errors.New("requires network, not available in offline mode")
+
+// connectTimeout bounds connecting to servers, so that commands fail instead of hanging without network.
+const connectTimeout = 10 * time.Second
+
+// isNetworkError reports whether the request failed because the server can't be reached.
+func isNetworkError(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// checkNetworkError switches to offline mode if the request failed because the network is unavailable.
+func checkNetworkError(err error) error {
+	if err == nil || !isNetworkError(err) {
+		return err
+	}
+	config.SetOffline()
+	log.Warn("network is unavailable, continuing in offline mode")
+	return fmt.Errorf("network is unavailable: %w", err)
+}
+
+func questionDataFile(slug string) string {
+	cfg := config.Get()
+	return filepath.Join(cfg.CacheDir(), "questions", cfg.LeetCode.Site.Short(), slug+".json")
+}
+
+// saveQuestionData keeps the full data of the question, so that it can be generated in offline mode.
+func saveQuestionData(q *QuestionData) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		log.Debug("failed to encode question data", "question", q.TitleSlug, "err", err)
+		return
+	}
+	err = utils.WriteFile(questionDataFile(q.TitleSlug), data)
+	if err != nil {
+		log.Debug("failed to save question data", "question", q.TitleSlug, "err", err)
+	}
+}
+
+// loadQuestionData loads the question data saved when the question was fetched last time.
+func loadQuestionData(slug string) (*QuestionData, error) {
+	data, err := os.ReadFile(questionDataFile(slug))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w, and question %s has never been fetched", ErrOffline, slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var q QuestionData
+	err = json.Unmarshal(data, &q)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
@@ -0,0 +1,55 @@
+package leetcode
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestQuestionDataRoundTrip(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+
+	_, err := loadQuestionData("two-sum")
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("loadQuestionData() of a question never fetched = %v, want ErrOffline", err)
+	}
+
+	saveQuestionData(&QuestionData{TitleSlug: "two-sum", Content: "content", Difficulty: "Easy"})
+	c := &fakeClient{}
+	qs, err := loadQuestionsData(c, []string{"two-sum"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := qs[0]
+	if q.TitleSlug != "two-sum" || q.Content != "content" || q.Difficulty != "Easy" {
+		t.Errorf("loaded question = %+v", q)
+	}
+	if q.client != c {
+		t.Errorf("loaded question should use the client")
+	}
+
+	_, err = loadQuestionsData(c, []string{"two-sum", "add-two-numbers"})
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("loadQuestionsData() with a question never fetched = %v, want ErrOffline", err)
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&net.DNSError{Err: "no such host", Name: "leetcode.cn"}, true},
+		{fmt.Errorf("post: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{&net.OpError{Op: "read", Err: errors.New("connection reset")}, false},
+		{ErrTooManyRequests, false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := isNetworkError(c.err); got != c.want {
+			t.Errorf("isNetworkError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package leetcode
+
+import "errors"
+
+type StudyPlanGroup struct {
+	Slug      string          `json:"slug"`
+	Name      string          `json:"name"`
+	Questions []*QuestionData `json:"questions"`
+}
+
+type StudyPlan struct {
+	Slug        string           `json:"slug"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Groups      []StudyPlanGroup `json:"planSubGroups"`
+}
+
+// Questions returns all questions of the plan in order.
+func (p *StudyPlan) Questions() []*QuestionData {
+	var qs []*QuestionData
+	for _, g := range p.Groups {
+		qs = append(qs, g.Questions...)
+	}
+	return qs
+}
+
+// Progress returns the number of solved questions and the total number of questions.
+func (p *StudyPlan) Progress() (solved int, total int) {
+	for _, q := range p.Questions() {
+		total++
+		if q.IsSolved() {
+			solved++
+		}
+	}
+	return
+}
+
+// NextQuestion returns the first question that is neither solved nor in the skip list,
+// paid only questions are skipped as well if freeOnly is set.
+func (p *StudyPlan) NextQuestion(skip []string, freeOnly bool) (*QuestionData, error) {
+	skipped := make(map[string]bool, len(skip))
+	for _, s := range skip {
+		skipped[s] = true
+	}
+	for _, q := range p.Questions() {
+		if q.IsSolved() || skipped[q.TitleSlug] || (freeOnly && q.IsPaidOnly) {
+			continue
+		}
+		return q, nil
+	}
+	return nil, errors.New("all questions in this plan have been solved or picked")
+}
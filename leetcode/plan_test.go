@@ -0,0 +1,63 @@
+package leetcode
+
+import "testing"
+
+func TestStudyPlanNextQuestion(t *testing.T) {
+	plan := &StudyPlan{
+		Groups: []StudyPlanGroup{
+			{
+				Slug: "array",
+				Questions: []*QuestionData{
+					{TitleSlug: "two-sum", Status: "ac"},
+					{TitleSlug: "paid-only", IsPaidOnly: true},
+				},
+			},
+			{
+				Slug: "linked-list",
+				Questions: []*QuestionData{
+					{TitleSlug: "add-two-numbers"},
+					{TitleSlug: "merge-two-lists", Status: "SOLVED"},
+					{TitleSlug: "reverse-list"},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		name     string
+		skip     []string
+		freeOnly bool
+		want     string
+	}{
+		{"first unsolved", nil, false, "paid-only"},
+		{"free only", nil, true, "add-two-numbers"},
+		{"skipped", []string{"paid-only", "add-two-numbers"}, false, "reverse-list"},
+		{"all skipped", []string{"add-two-numbers", "reverse-list"}, true, ""},
+	}
+	for _, c := range cases {
+		q, err := plan.NextQuestion(c.skip, c.freeOnly)
+		switch {
+		case c.want == "" && err == nil:
+			t.Errorf("%s: NextQuestion() = %s, want an error", c.name, q.TitleSlug)
+		case c.want != "" && (err != nil || q.TitleSlug != c.want):
+			t.Errorf("%s: NextQuestion() = %v, %v, want %s", c.name, q, err, c.want)
+		}
+	}
+
+	if solved, total := plan.Progress(); solved != 2 || total != 5 {
+		t.Errorf("Progress() = %d/%d, want 2/5", solved, total)
+	}
+
+	// A completed plan has no next question.
+	for _, q := range plan.Questions() {
+		q.Status = "ac"
+	}
+	if q, err := plan.NextQuestion(nil, false); err == nil {
+		t.Errorf("NextQuestion() of a completed plan = %s, want an error", q.TitleSlug)
+	}
+	if solved, total := plan.Progress(); solved != 5 || total != 5 {
+		t.Errorf("Progress() of a completed plan = %d/%d, want 5/5", solved, total)
+	}
+	if solved, total := (&StudyPlan{}).Progress(); solved != 0 || total != 0 {
+		t.Errorf("Progress() of an empty plan = %d/%d", solved, total)
+	}
+}
@@ -3,6 +3,7 @@ package leetcode
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -41,12 +42,73 @@ func QuestionBySlug(slug string, c Client) (*QuestionData, error) {
 	return q, err
 }
 
+// QuestionsBySlugs loads questions from cache, the ones not cached are fetched in batches with the fields of
+// the question list only. Questions not found are nil in the result.
+func QuestionsBySlugs(slugs []string, c Client) ([]*QuestionData, error) {
+	qs := make([]*QuestionData, len(slugs))
+	var missing []int
+	for i, slug := range slugs {
+		q, err := QuestionFromCacheBySlug(slug, c)
+		if err != nil {
+			missing = append(missing, i)
+			continue
+		}
+		qs[i] = q
+	}
+	if len(missing) == 0 {
+		return qs, nil
+	}
+	missingSlugs := make([]string, len(missing))
+	for i, idx := range missing {
+		missingSlugs[i] = slugs[idx]
+	}
+	fetched, err := c.GetQuestionsData(missingSlugs, FieldsList)
+	if err != nil {
+		return qs, err
+	}
+	for i, idx := range missing {
+		qs[idx] = fetched[i]
+	}
+	return qs, nil
+}
+
+// normalizeQID converts a question or contest URL to the equivalent qid notation:
+//
+//	https://leetcode.com/problems/two-sum/description/ -> two-sum
+//	https://leetcode.com/contest/weekly-contest-330/problems/count-distinct-numbers-on-board/ -> weekly-contest-330/count-distinct-numbers-on-board
+//	https://leetcode.com/contest/weekly-contest-330/ -> weekly-contest-330/
+//
+// Other identifiers are returned unchanged.
+func normalizeQID(qid string) string {
+	if !strings.HasPrefix(qid, "http://") && !strings.HasPrefix(qid, "https://") {
+		return qid
+	}
+	u, err := url.Parse(qid)
+	if err != nil {
+		return qid
+	}
+	parts := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	switch {
+	case len(parts) >= 2 && parts[0] == "problems":
+		return parts[1]
+	case len(parts) >= 4 && parts[0] == "contest" && parts[2] == "problems":
+		return parts[1] + "/" + parts[3]
+	case len(parts) >= 2 && parts[0] == "contest":
+		return parts[1] + "/"
+	}
+	return qid
+}
+
+// ParseQID resolves a question identifier to questions. Supported notations:
+// frontend id, slug, question url, contest url, today, yesterday, today-N, last,
+// and contest qid like w330/1, weekly-contest-330/2, last/ or w330/.
 func ParseQID(qid string, c Client) ([]*QuestionData, error) {
 	var (
 		q   *QuestionData
 		qs  []*QuestionData
 		err error
 	)
+	qid = normalizeQID(qid)
 	switch {
 	case isNumber(qid):
 		q, err = QuestionFromCacheByID(qid, c)
@@ -98,6 +160,7 @@ func ParseQID(qid string, c Client) ([]*QuestionData, error) {
 }
 
 func ParseContestQID(qid string, c Client, withQuestions bool) (*Contest, []*QuestionData, error) {
+	qid = normalizeQID(qid)
 	if len(qid) < 3 {
 		return nil, nil, errors.New("invalid contest qid")
 	}
@@ -106,11 +169,12 @@ func ParseContestQID(qid string, c Client, withQuestions bool) (*Contest, []*Que
 	}
 
 	var (
-		contestSlug string
-		questionNum = -1
-		err         error
-		q           *QuestionData
-		qs          []*QuestionData
+		contestSlug  string
+		questionNum  = -1
+		questionSlug string
+		err          error
+		q            *QuestionData
+		qs           []*QuestionData
 	)
 	contestPat := regexp.MustCompile(`(?i)([wb])\D*(\d+)`)
 	parts := strings.SplitN(qid, "/", 2)
@@ -134,13 +198,20 @@ func ParseContestQID(qid string, c Client, withQuestions bool) (*Contest, []*Que
 	if len(parts[1]) > 0 {
 		questionNum, err = strconv.Atoi(parts[1])
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid contest qid: %s is not a number", parts[1])
+			// Question in contest can also be referenced by its slug.
+			questionSlug = parts[1]
 		}
 	}
 	contest, err := c.GetContest(contestSlug)
 	if err != nil {
 		return nil, nil, fmt.Errorf("contest not found %s: %w", contestSlug, err)
 	}
+	if withQuestions && questionSlug != "" {
+		questionNum, err = contest.GetQuestionNumber(questionSlug)
+		if err != nil {
+			return contest, nil, fmt.Errorf("get contest question %s failed: %w", questionSlug, err)
+		}
+	}
 
 	if withQuestions {
 		if questionNum > 0 {
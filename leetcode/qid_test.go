@@ -0,0 +1,33 @@
+package leetcode
+
+import "testing"
+
+func TestNormalizeQID(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"1", "1"},
+		{"two-sum", "two-sum"},
+		{"w330/1", "w330/1"},
+		{"https://leetcode.com/problems/two-sum/", "two-sum"},
+		{"https://leetcode.cn/problems/two-sum/description/", "two-sum"},
+		{"https://leetcode.com/problems/two-sum", "two-sum"},
+		{
+			"https://leetcode.com/contest/weekly-contest-330/problems/count-distinct-numbers-on-board/",
+			"weekly-contest-330/count-distinct-numbers-on-board",
+		},
+		{"https://leetcode.cn/contest/biweekly-contest-100/", "biweekly-contest-100/"},
+		{"https://leetcode.com/", "https://leetcode.com/"},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.input, func(t *testing.T) {
+				actual := normalizeQID(tc.input)
+				if actual != tc.expected {
+					t.Errorf("normalizeQID(%q) = %q, expected %q", tc.input, actual, tc.expected)
+				}
+			},
+		)
+	}
+}
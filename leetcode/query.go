@@ -0,0 +1,235 @@
+package leetcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/j178/leetgo/config"
+)
+
+// QuestionFields selects the fields of questions to query, fewer fields make the response smaller and faster.
+type QuestionFields int
+
+const (
+	// FieldsList are the fields shown in question lists: id, title, difficulty, status and tags.
+	FieldsList QuestionFields = iota
+	// FieldsSnippet are the list fields plus the code snippets and metadata, enough to check function signatures.
+	FieldsSnippet
+	// FieldsFull are all the fields needed to generate a question.
+	FieldsFull
+	// FieldsContent are the descriptions in both languages, see QuestionData.contentIn.
+	FieldsContent
+)
+
+// questionBatchSize bounds the number of questions queried in one GraphQL request,
+// LeetCode rejects queries that are too complex.
+const questionBatchSize = 10
+
+const questionListFields = `
+			questionId
+			questionFrontendId
+			categoryTitle
+			title
+			titleSlug
+			translatedTitle
+			difficulty
+			isPaidOnly
+			status
+			topicTags {
+				name
+				slug
+				translatedName
+			}`
+
+const questionSnippetFields = questionListFields + `
+			metaData
+			codeSnippets {
+				lang
+				langSlug
+				code
+			}`
+
+const questionContentFields = `
+			titleSlug
+			content
+			translatedContent`
+
+const cnQuestionFullFields = questionSnippetFields + `
+			content
+			translatedContent
+			stats
+			hints
+			similarQuestions
+			sampleTestCase
+			exampleTestcases
+			exampleTestcaseList
+			jsonExampleTestcases
+			mysqlSchemas
+			dataSchemas
+			editorType`
+
+const usQuestionFullFields = questionSnippetFields + `
+			content
+			translatedContent
+			stats
+			companyTagStats
+			hints
+			similarQuestions
+			sampleTestCase
+			exampleTestcases
+			exampleTestcaseList
+			mysqlSchemas
+			dataSchemas`
+
+// selectFields returns the fields to query, full is the full fields of the site.
+func selectFields(fields QuestionFields, full string) string {
+	switch fields {
+	case FieldsList:
+		return questionListFields
+	case FieldsSnippet:
+		return questionSnippetFields
+	case FieldsContent:
+		return questionContentFields
+	default:
+		return full
+	}
+}
+
+// omittedContent returns the language of the description left out when fetching questions fully.
+// In low bandwidth mode only the description shown is fetched, the other one is fetched on first use.
+func omittedContent() config.Language {
+	cfg := config.Get()
+	if !cfg.LowBandwidth {
+		return ""
+	}
+	if cfg.ContentLanguage() == config.ZH {
+		return config.EN
+	}
+	return config.ZH
+}
+
+// omitContent removes the description in lang from the fields.
+func omitContent(fields string, lang config.Language) string {
+	switch lang {
+	case config.EN:
+		return strings.Replace(fields, "\n\t\t\tcontent\n", "\n", 1)
+	case config.ZH:
+		return strings.Replace(fields, "\n\t\t\ttranslatedContent\n", "\n", 1)
+	}
+	return fields
+}
+
+// questionQuery returns the query of a single question.
+func questionQuery(fields string) string {
+	return `
+	query questionData($titleSlug: String!) {
+		question(titleSlug: $titleSlug) {` + fields + `
+		}
+	}`
+}
+
+// batchQuestionQuery returns the query of n questions in one request, each question is aliased as q0, q1, ...
+// with its slug in the variable s0, s1, ...
+func batchQuestionQuery(n int, fields string) string {
+	params := make([]string, n)
+	var sb strings.Builder
+	for i := range n {
+		params[i] = fmt.Sprintf("$s%d: String!", i)
+		fmt.Fprintf(&sb, "\n\t\tq%d: question(titleSlug: $s%d) {%s\n\t\t}", i, i, fields)
+	}
+	return fmt.Sprintf("\n\tquery questionsData(%s) {%s\n\t}", strings.Join(params, ", "), sb.String())
+}
+
+// batchQuestions returns the aliased questions of a batch query in the order of the slugs, nil for questions not found.
+func batchQuestions(data map[string]*QuestionData, n int) []*QuestionData {
+	qs := make([]*QuestionData, n)
+	for i := range n {
+		q := data[fmt.Sprintf("q%d", i)]
+		if q != nil && q.TitleSlug != "" {
+			qs[i] = q
+		}
+	}
+	return qs
+}
+
+// checkQuestionData returns the error of a fully queried question, nil means not found.
+func checkQuestionData(q *QuestionData) error {
+	if q == nil || q.TitleSlug == "" {
+		return ErrQuestionNotFound
+	}
+	if q.IsPaidOnly && q.Content == "" {
+		return ErrPaidOnlyQuestion
+	}
+	return nil
+}
+
+// getQuestionsData queries the questions in batches of questionBatchSize, one request per batch.
+// Questions fully queried are saved for offline mode, others are partial and fetched fully by Fulfill when needed.
+// In offline mode, the data saved when the questions were fetched last time is returned.
+func (c *cnClient) getQuestionsData(
+	client Client,
+	slugs []string,
+	fields QuestionFields,
+	full string,
+) ([]*QuestionData, error) {
+	if config.Offline() {
+		return loadQuestionsData(client, slugs)
+	}
+	query, omitted := selectFields(fields, full), config.Language("")
+	if fields == FieldsFull {
+		omitted = omittedContent()
+		query = omitContent(query, omitted)
+	}
+	qs := make([]*QuestionData, 0, len(slugs))
+	for start := 0; start < len(slugs); start += questionBatchSize {
+		batch := slugs[start:min(start+questionBatchSize, len(slugs))]
+		variables := make(map[string]any, len(batch))
+		for i, slug := range batch {
+			variables[fmt.Sprintf("s%d", i)] = slug
+		}
+		var resp struct {
+			Data map[string]*QuestionData `json:"data"`
+		}
+		_, err := c.graphqlPost(
+			graphqlRequest{
+				query:         batchQuestionQuery(len(batch), query),
+				operationName: "questionsData",
+				variables:     variables,
+				authType:      withAuth,
+			}, &resp, nil,
+		)
+		if err != nil {
+			// The network turned out to be unavailable.
+			if config.Offline() {
+				return loadQuestionsData(client, slugs)
+			}
+			return nil, err
+		}
+		for _, q := range batchQuestions(resp.Data, len(batch)) {
+			if q != nil {
+				q.client = client
+				if fields != FieldsFull {
+					q.partial = 1
+				} else if checkQuestionData(q) == nil {
+					q.omittedContent = omitted
+					saveQuestionData(q)
+				}
+			}
+			qs = append(qs, q)
+		}
+	}
+	return qs, nil
+}
+
+func loadQuestionsData(client Client, slugs []string) ([]*QuestionData, error) {
+	qs := make([]*QuestionData, len(slugs))
+	for i, slug := range slugs {
+		q, err := loadQuestionData(slug)
+		if err != nil {
+			return nil, err
+		}
+		q.client = client
+		qs[i] = q
+	}
+	return qs, nil
+}
@@ -0,0 +1,171 @@
+package leetcode
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dghubble/sling"
+	"github.com/goccy/go-json"
+	"github.com/spf13/viper"
+)
+
+func TestBatchQuestionQuery(t *testing.T) {
+	query := batchQuestionQuery(2, questionListFields)
+	for _, s := range []string{
+		"query questionsData($s0: String!, $s1: String!)",
+		"q0: question(titleSlug: $s0)",
+		"q1: question(titleSlug: $s1)",
+	} {
+		if !strings.Contains(query, s) {
+			t.Errorf("query does not contain %q:\n%s", s, query)
+		}
+	}
+	if strings.Contains(query, "content") {
+		t.Errorf("list query should not select the content:\n%s", query)
+	}
+}
+
+func TestBatchQuestions(t *testing.T) {
+	data := []byte(`{"q0": {"titleSlug": "two-sum"}, "q1": null, "q2": {"titleSlug": "add-two-numbers"}}`)
+	var resp map[string]*QuestionData
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	qs := batchQuestions(resp, 3)
+	if len(qs) != 3 || qs[0].TitleSlug != "two-sum" || qs[1] != nil || qs[2].TitleSlug != "add-two-numbers" {
+		t.Errorf("batchQuestions() = %v", qs)
+	}
+}
+
+func TestFetchBatches(t *testing.T) {
+	var qs []*QuestionData
+	for range questionBatchSize + 3 {
+		qs = append(qs, &QuestionData{partial: 1})
+	}
+	qs[1].partial = 0
+	qs[2].contest = &Contest{}
+	batches := fetchBatches(qs)
+	// The full and the contest questions are alone, the rest are batched.
+	want := []int{1, 1, questionBatchSize, 1}
+	if len(batches) != len(want) {
+		t.Fatalf("fetchBatches() = %v", batches)
+	}
+	for i, b := range batches {
+		if len(b) != want[i] {
+			t.Errorf("fetchBatches() = %v", batches)
+			break
+		}
+	}
+}
+
+func TestFulfillFields(t *testing.T) {
+	c := &fakeClient{queried: map[string]int{}}
+	q := &QuestionData{client: c, partial: 1, TitleSlug: "two-sum", Frequency: 42, Difficulty: "Easy"}
+
+	for range 2 {
+		if err := q.FulfillFields(FieldsSnippet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if q.GetCodeSnippet("golang") != "func two-sum" {
+		t.Errorf("snippets are not merged: %+v", q.CodeSnippets)
+	}
+	if q.Frequency != 42 || q.Difficulty != "Easy" || q.Content != "" || q.partial != 1 {
+		t.Errorf("other fields should be kept: %+v", q)
+	}
+	if n := c.queried["two-sum"]; n != 1 {
+		t.Errorf("snippets are queried %d times, want once", n)
+	}
+
+	if err := q.FulfillFields(FieldsFull); err != nil {
+		t.Fatal(err)
+	}
+	if q.Content != "content of two-sum" || q.partial != 0 {
+		t.Errorf("question is not fully fetched: %+v", q)
+	}
+}
+
+// graphqlServer answers batched question queries, slugs starting with "missing" are not found,
+// and batches with a slug starting with "limited" are rejected.
+func graphqlServer(t *testing.T, requests *int) *cnClient {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				*requests++
+				var body struct {
+					Variables map[string]string `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				data := make(map[string]any, len(body.Variables))
+				for i := range len(body.Variables) {
+					slug := body.Variables[fmt.Sprintf("s%d", i)]
+					switch {
+					case strings.HasPrefix(slug, "limited"):
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					case strings.HasPrefix(slug, "missing"):
+						data[fmt.Sprintf("q%d", i)] = nil
+					default:
+						data[fmt.Sprintf("q%d", i)] = map[string]string{"titleSlug": slug, "content": "content of " + slug}
+					}
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+			},
+		),
+	)
+	t.Cleanup(srv.Close)
+	return &cnClient{http: sling.New().Base(srv.URL), opt: Options{cred: NonAuth()}}
+}
+
+func TestGetQuestionsData(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	requests := 0
+	c := graphqlServer(t, &requests)
+
+	slugs := []string{"missing"}
+	for i := range questionBatchSize {
+		slugs = append(slugs, fmt.Sprintf("q-%d", i))
+	}
+	qs, err := c.GetQuestionsData(slugs, FieldsSnippet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(qs) != len(slugs) || qs[0] != nil {
+		t.Fatalf("GetQuestionsData() = %v in %d requests, want two batches with the first not found", qs, requests)
+	}
+	for i, q := range qs[1:] {
+		if q.TitleSlug != slugs[i+1] || q.partial != 1 || q.client != c {
+			t.Errorf("question %d = %+v, want partial %s", i+1, q, slugs[i+1])
+		}
+	}
+
+	// A failed batch fails the query, even if earlier batches succeeded.
+	_, err = c.GetQuestionsData(append(slugs, "limited"), FieldsFull)
+	if err == nil {
+		t.Errorf("GetQuestionsData() with a rejected batch should fail")
+	}
+
+	// Fully queried questions are saved, and served in offline mode without requests.
+	if _, err = c.GetQuestionsData([]string{"two-sum", "missing-2"}, FieldsFull); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("offline", true)
+	t.Cleanup(func() { viper.Set("offline", false) })
+	requests = 0
+	qs, err = c.GetQuestionsData([]string{"two-sum"}, FieldsSnippet)
+	if err != nil || requests != 0 {
+		t.Fatalf("offline GetQuestionsData() = %v, %v in %d requests", qs, err, requests)
+	}
+	if qs[0].Content != "content of two-sum" || qs[0].partial != 0 || qs[0].client != c {
+		t.Errorf("offline question = %+v, want the saved full data", qs[0])
+	}
+	if _, err = c.GetQuestionsData([]string{"two-sum", "missing-2"}, FieldsFull); err == nil {
+		t.Errorf("offline GetQuestionsData() of a question never fetched should fail")
+	}
+}
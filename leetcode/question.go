@@ -1,16 +1,18 @@
 package leetcode
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"text/template"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/JohannesKaufmann/html-to-markdown/plugin"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/log"
 	"github.com/goccy/go-json"
 	"github.com/k3a/html2text"
 	"github.com/muesli/reflow/wordwrap"
@@ -214,11 +216,7 @@ func (m *MetaData) NArg() int {
 }
 
 func (m *MetaData) ResultType() string {
-	if m.Return != nil && m.Return.Type != "void" {
-		return m.Return.Type
-	} else {
-		return m.Params[m.Output.ParamIndex].Type
-	}
+	return m.Function().ResultType().String()
 }
 
 type JsonExampleTestCases []string
@@ -244,6 +242,13 @@ type SimilarQuestion struct {
 	TranslatedTitle string `json:"translatedTitle"`
 }
 
+func (s SimilarQuestion) GetTitle() string {
+	if config.Get().ContentLanguage() == config.ZH && s.TranslatedTitle != "" {
+		return s.TranslatedTitle
+	}
+	return s.Title
+}
+
 type SimilarQuestions []SimilarQuestion
 
 type similarQuestionsNoMethods SimilarQuestions
@@ -279,22 +284,29 @@ const (
 )
 
 type QuestionData struct {
-	client               Client
-	contest              *Contest
-	partial              int32
-	TitleSlug            string               `json:"titleSlug"`
-	QuestionId           string               `json:"questionId"`
-	QuestionFrontendId   string               `json:"questionFrontendId"`
-	CategoryTitle        CategoryTitle        `json:"categoryTitle"`
-	Title                string               `json:"title"`
-	TranslatedTitle      string               `json:"translatedTitle"`
-	Difficulty           string               `json:"difficulty"`
-	TopicTags            []TopicTag           `json:"topicTags"`
-	IsPaidOnly           bool                 `json:"isPaidOnly"`
-	Content              string               `json:"content"`
-	TranslatedContent    string               `json:"translatedContent"`
-	Status               string               `json:"status"` // "ac", "notac", or null
-	Stats                Stats                `json:"stats"`
+	client  Client
+	contest *Contest
+	partial int32
+	// omittedContent is the language of the description not fetched yet, see omittedContent.
+	omittedContent     config.Language
+	TitleSlug          string        `json:"titleSlug"`
+	QuestionId         string        `json:"questionId"`
+	QuestionFrontendId string        `json:"questionFrontendId"`
+	CategoryTitle      CategoryTitle `json:"categoryTitle"`
+	Title              string        `json:"title"`
+	TranslatedTitle    string        `json:"translatedTitle"`
+	Difficulty         string        `json:"difficulty"`
+	TopicTags          []TopicTag    `json:"topicTags"`
+	IsPaidOnly         bool          `json:"isPaidOnly"`
+	Content            string        `json:"content"`
+	TranslatedContent  string        `json:"translatedContent"`
+	Status             string        `json:"status"` // "ac", "notac", or null
+	Stats              Stats         `json:"stats"`
+	// Frequency is how often the question is asked in interviews, in 0-100.
+	// Only the question list of leetcode.com provides it, and only to premium users.
+	Frequency float64 `json:"frequency,omitempty"`
+	// CompanyTagStats are only provided by leetcode.com to premium users.
+	CompanyTagStats      CompanyTagStats      `json:"companyTagStats,omitempty"`
 	Hints                []string             `json:"hints"`
 	SimilarQuestions     SimilarQuestions     `json:"similarQuestions"`
 	SampleTestCase       string               `json:"sampleTestCase"`
@@ -304,6 +316,9 @@ type QuestionData struct {
 	MetaData             MetaData             `json:"metaData"`
 	CodeSnippets         []CodeSnippet        `json:"codeSnippets"`
 	EditorType           EditorType           `json:"editorType"`
+	// MysqlSchemas and DataSchemas are the statements creating the tables of database questions.
+	MysqlSchemas []string `json:"mysqlSchemas"`
+	DataSchemas  []string `json:"dataSchemas"`
 }
 
 type questionDataNoMethods QuestionData
@@ -330,6 +345,35 @@ func (q *QuestionData) normalize() {
 	}
 }
 
+// QuestionFromJSON loads a question from a saved payload, either a QuestionData object
+// or a raw GraphQL response like {"data": {"question": {...}}}.
+func QuestionFromJSON(data []byte, c Client) (*QuestionData, error) {
+	var resp struct {
+		Data *struct {
+			Question *QuestionData `json:"question"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question json: %w", err)
+	}
+	var q *QuestionData
+	if resp.Data != nil {
+		q = resp.Data.Question
+	} else {
+		q = &QuestionData{}
+		err = json.Unmarshal(data, q)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question json: %w", err)
+		}
+	}
+	if q == nil || q.TitleSlug == "" {
+		return nil, errors.New("invalid question json: titleSlug not found")
+	}
+	q.client = c
+	return q, nil
+}
+
 func (q *QuestionData) SetClient(c Client) {
 	q.client = c
 }
@@ -351,7 +395,7 @@ func (q *QuestionData) Contest() *Contest {
 }
 
 func (q *QuestionData) Fulfill() (err error) {
-	if atomic.LoadInt32(&q.partial) == 0 {
+	if !q.isPartial() {
 		return
 	}
 
@@ -365,25 +409,127 @@ func (q *QuestionData) Fulfill() (err error) {
 	if err != nil {
 		return
 	}
+	q.fulfillWith(nq, contest)
+	return nil
+}
+
+// questionMu guards writing fetched data into questions, which may be shared by the cache.
+// The whole question is replaced when fetched, partial included, so it's read under the lock too.
+var questionMu sync.Mutex
+
+// isPartial reports whether the question has only some of its fields, e.g. from a question list.
+func (q *QuestionData) isPartial() bool {
+	questionMu.Lock()
+	defer questionMu.Unlock()
+	return q.partial != 0
+}
+
+// fulfillWith replaces the partial question with the full data nq, keeping the contest it belongs to.
+func (q *QuestionData) fulfillWith(nq *QuestionData, contest *Contest) {
+	questionMu.Lock()
+	defer questionMu.Unlock()
 	*q = *nq
 	q.contest = contest
-	atomic.StoreInt32(&q.partial, 0)
+	q.partial = 0
+}
+
+// FulfillFields fetches the fields of a partial question, a full question is returned as is.
+// The fetched fields are merged into the question, other fields are kept.
+// Partial questions come from question lists, so they have the list fields already.
+// Questions of contests are always fetched fully.
+func (q *QuestionData) FulfillFields(fields QuestionFields) error {
+	if fields == FieldsList || !q.isPartial() {
+		return nil
+	}
+	if fields == FieldsFull || q.IsContest() {
+		return q.Fulfill()
+	}
+	questionMu.Lock()
+	fetched := len(q.CodeSnippets) > 0
+	questionMu.Unlock()
+	if fetched {
+		return nil
+	}
+	qs, err := q.client.GetQuestionsData([]string{q.TitleSlug}, fields)
+	if err != nil {
+		return err
+	}
+	if qs[0] == nil {
+		return ErrQuestionNotFound
+	}
+	questionMu.Lock()
+	defer questionMu.Unlock()
+	if qs[0].partial == 0 {
+		// Full data loaded in offline mode.
+		contest := q.contest
+		*q = *qs[0]
+		q.contest = contest
+		return nil
+	}
+	q.MetaData = qs[0].MetaData
+	q.CodeSnippets = qs[0].CodeSnippets
 	return nil
 }
 
 func (q *QuestionData) GetTitle() string {
-	if config.Get().Language == config.ZH && q.TranslatedTitle != "" {
+	return q.titleIn(config.Get().ContentLanguage())
+}
+
+// GetCommentTitle returns the title in the language of code comments.
+func (q *QuestionData) GetCommentTitle() string {
+	return q.titleIn(config.Get().CommentLanguage())
+}
+
+func (q *QuestionData) titleIn(lang config.Language) string {
+	if lang == config.ZH && q.TranslatedTitle != "" {
 		return q.TranslatedTitle
 	}
 	return q.Title
 }
 
 func (q *QuestionData) GetPreferContent() (string, config.Language) {
-	cfg := config.Get()
-	if cfg.Language == config.ZH && q.TranslatedContent != "" {
+	return q.contentIn(config.Get().ContentLanguage())
+}
+
+// contentIn returns the content in the given language, falling back to the other one if not available.
+// contentIn returns the description in lang, or in the other language if it's not translated.
+// The description left out in low bandwidth mode is fetched when it's needed.
+func (q *QuestionData) contentIn(lang config.Language) (string, config.Language) {
+	content, got := q.fetchedContentIn(lang)
+	questionMu.Lock()
+	omitted := q.omittedContent
+	questionMu.Unlock()
+	if omitted != "" && (lang == omitted || got == omitted) {
+		q.fetchOmittedContent()
+		content, got = q.fetchedContentIn(lang)
+	}
+	return content, got
+}
+
+// fetchOmittedContent fetches the description left out in low bandwidth mode. It's tried once,
+// on failure the description in the other language is used.
+func (q *QuestionData) fetchOmittedContent() {
+	qs, err := q.client.GetQuestionsData([]string{q.TitleSlug}, FieldsContent)
+	if err == nil && qs[0] == nil {
+		err = ErrQuestionNotFound
+	}
+	questionMu.Lock()
+	defer questionMu.Unlock()
+	if err != nil {
+		log.Debug("failed to fetch question content", "question", q.TitleSlug, "err", err)
+	} else if q.omittedContent == config.EN {
+		q.Content = qs[0].Content
+	} else {
+		q.TranslatedContent = qs[0].TranslatedContent
+	}
+	q.omittedContent = ""
+}
+
+func (q *QuestionData) fetchedContentIn(lang config.Language) (string, config.Language) {
+	if lang == config.ZH && q.TranslatedContent != "" {
 		return q.TranslatedContent, config.ZH
 	}
-	if cfg.Language == config.EN && q.Content == "" {
+	if lang == config.EN && q.Content == "" {
 		return q.TranslatedContent, config.ZH
 	}
 	return q.Content, config.EN
@@ -429,12 +575,48 @@ func htmlToMarkdown(html string) string {
 	return content
 }
 
-func (q *QuestionData) GetFormattedContent() string {
-	content, lang := q.GetPreferContent()
+// GetMarkdownContent returns the preferred content converted to markdown, without wrapping.
+func (q *QuestionData) GetMarkdownContent() (string, config.Language) {
+	return q.markdownContentIn(config.Get().ContentLanguage())
+}
 
+func (q *QuestionData) markdownContentIn(lang config.Language) (string, config.Language) {
+	content, lang := q.contentIn(lang)
 	if q.EditorType == EditorTypeCKEditor {
 		content = htmlToMarkdown(content)
 	}
+	if config.Get().LowBandwidth {
+		content = linkImages(content)
+	}
+	return content, lang
+}
+
+var markdownImageRe = regexp.MustCompile(`!\[([^\]]*)]\(`)
+
+// linkImages turns markdown images into links, so that markdown previews don't download them.
+func linkImages(content string) string {
+	return markdownImageRe.ReplaceAllStringFunc(
+		content, func(s string) string {
+			alt := strings.TrimSpace(markdownImageRe.FindStringSubmatch(s)[1])
+			if alt == "" {
+				alt = "image"
+			}
+			return "[" + alt + "]("
+		},
+	)
+}
+
+func (q *QuestionData) GetFormattedContent() string {
+	return q.formattedContentIn(config.Get().ContentLanguage())
+}
+
+// GetCommentContent returns the formatted content in the language of code comments.
+func (q *QuestionData) GetCommentContent() string {
+	return q.formattedContentIn(config.Get().CommentLanguage())
+}
+
+func (q *QuestionData) formattedContentIn(lang config.Language) string {
+	content, lang := q.markdownContentIn(lang)
 
 	// Wrap and remove blank lines
 	if lang == config.EN {
@@ -504,6 +686,29 @@ func (q *QuestionData) ParseExampleOutputs() []string {
 	return result
 }
 
+// IsSolved reports whether the question is marked as solved by LeetCode.
+// Study plan API uses "SOLVED", while other APIs use "ac".
+func (q *QuestionData) IsSolved() bool {
+	return strings.EqualFold(q.Status, "SOLVED") || strings.EqualFold(q.Status, "ac")
+}
+
+// GetCompanyTags returns the companies that asked the question, the most frequently asked first.
+func (q *QuestionData) GetCompanyTags() string {
+	return strings.Join(q.CompanyTagStats.Companies(), ", ")
+}
+
+// AcceptanceRate returns the acceptance rate in percent, false if the stats are unknown.
+func (q *QuestionData) AcceptanceRate() (float64, bool) {
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(q.Stats.ACRate, "%"), 64)
+	if err == nil {
+		return rate, true
+	}
+	if q.Stats.TotalSubmissionRaw > 0 {
+		return float64(q.Stats.TotalAcceptedRaw) * 100 / float64(q.Stats.TotalSubmissionRaw), true
+	}
+	return 0, false
+}
+
 func (q *QuestionData) TagSlugs() []string {
 	slugs := make([]string, 0, len(q.TopicTags))
 	for _, tag := range q.TopicTags {
@@ -512,6 +717,17 @@ func (q *QuestionData) TagSlugs() []string {
 	return slugs
 }
 
+// GetSchema returns the statements creating the tables of a database question in the language.
+func (q *QuestionData) GetSchema(langSlug string) []string {
+	switch langSlug {
+	case "mysql":
+		return q.MysqlSchemas
+	case "pythondata":
+		return q.DataSchemas
+	}
+	return nil
+}
+
 func (q *QuestionData) GetCodeSnippet(slug string) string {
 	for _, snippet := range q.CodeSnippets {
 		if slug == snippet.LangSlug {
@@ -527,8 +743,10 @@ type filenameTemplateData struct {
 	Title            string
 	Difficulty       string
 	Lang             string
+	Site             string
 	SlugIsMeaningful bool
 	Category         string
+	FirstTag         string
 	IsContest        bool
 	ContestTitle     string
 	ContestShortSlug string
@@ -561,6 +779,71 @@ func contestShortSlug(contestSlug string) string {
 	return strings.Replace(contestSlug, "-contest-", "-", 1)
 }
 
+// filenameTemplateFuncs are the functions available in filename templates.
+var filenameTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"padWithZero": func(n int, s string) string {
+		return fmt.Sprintf("%0*s", n, s)
+	},
+	"toUnderscore": func(s string) string {
+		return strings.ReplaceAll(s, "-", "_")
+	},
+	// toCamel converts a slug to camel case, e.g. two-sum to twoSum.
+	"toCamel": func(s string) string {
+		parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == ' ' })
+		for i, p := range parts {
+			if i > 0 && p != "" {
+				parts[i] = strings.ToUpper(p[:1]) + p[1:]
+			}
+		}
+		return strings.Join(parts, "")
+	},
+	// substr returns s[start:end] in runes, out of range indices are clamped.
+	"substr": func(start, end int, s string) string {
+		rs := []rune(s)
+		start = max(0, min(start, len(rs)))
+		end = max(start, min(end, len(rs)))
+		return string(rs[start:end])
+	},
+	// categorySlug converts a category title to a slug, e.g. Algorithms to algorithms.
+	"categorySlug": func(category string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "-")
+	},
+	"group": func(size int, s string) string {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return "Others"
+		}
+		return fmt.Sprintf("%d-%d", (id-1)/size*size+1, (id-1)/size*size+size)
+	},
+}
+
+// SampleQuestion returns a question used to preview filename templates, it never touches the network.
+func SampleQuestion() *QuestionData {
+	return &QuestionData{
+		TitleSlug:          "two-sum",
+		QuestionId:         "1",
+		QuestionFrontendId: "1",
+		CategoryTitle:      CategoryAlgorithms,
+		Title:              "Two Sum",
+		TranslatedTitle:    "两数之和",
+		Difficulty:         "Easy",
+	}
+}
+
+// SampleContestQuestion returns a contest question used to preview contest filename templates.
+func SampleContestQuestion() *QuestionData {
+	q := SampleQuestion()
+	q.contest = &Contest{
+		TitleSlug: "weekly-contest-330",
+		Title:     "Weekly Contest 330",
+		Questions: []*QuestionData{q},
+	}
+	return q
+}
+
 func (q *QuestionData) GetFormattedFilename(lang string, filenameTemplate string) (string, error) {
 	id, slugValid := q.normalizeQuestionId()
 	data := &filenameTemplateData{
@@ -569,10 +852,15 @@ func (q *QuestionData) GetFormattedFilename(lang string, filenameTemplate string
 		Title:            q.GetTitle(),
 		Difficulty:       q.Difficulty,
 		Lang:             lang,
+		Site:             config.Get().LeetCode.Site.Short(),
 		SlugIsMeaningful: slugValid,
 		Category:         string(q.CategoryTitle),
+		FirstTag:         "others",
 		IsContest:        q.IsContest(),
 	}
+	if len(q.TopicTags) > 0 {
+		data.FirstTag = q.TopicTags[0].Slug
+	}
 	if q.IsContest() {
 		// Override id with contest question number
 		id, err := q.contest.GetQuestionNumber(q.TitleSlug)
@@ -584,27 +872,9 @@ func (q *QuestionData) GetFormattedFilename(lang string, filenameTemplate string
 		data.ContestTitle = q.contest.Title
 		data.ContestShortSlug = contestShortSlug(q.contest.TitleSlug)
 	}
+	defer utils.Track(utils.ProfileTemplate)()
 	tmpl := template.New("filename")
-	tmpl.Funcs(
-		template.FuncMap{
-			"lower": strings.ToLower,
-			"upper": strings.ToUpper,
-			"trim":  strings.TrimSpace,
-			"padWithZero": func(n int, s string) string {
-				return fmt.Sprintf("%0*s", n, s)
-			},
-			"toUnderscore": func(s string) string {
-				return strings.ReplaceAll(s, "-", "_")
-			},
-			"group": func(size int, s string) string {
-				id, err := strconv.Atoi(s)
-				if err != nil {
-					return "Others"
-				}
-				return fmt.Sprintf("%d-%d", (id-1)/size*size+1, (id-1)/size*size+size)
-			},
-		},
-	)
+	tmpl.Funcs(filenameTemplateFuncs)
 	tmpl, err := tmpl.Parse(filenameTemplate)
 	if err != nil {
 		return "", err
@@ -0,0 +1,220 @@
+package leetcode
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+)
+
+func TestGetFormattedFilename(t *testing.T) {
+	q := SampleQuestion()
+	cases := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{ .Id | padWithZero 4 }}.{{ .Slug }}`, "0001.two-sum"},
+		{`{{ .Slug | toUnderscore }}`, "two_sum"},
+		{`{{ .Slug | toCamel }}`, "twoSum"},
+		{`{{ .Slug | substr 0 3 }}`, "two"},
+		{`{{ .Slug | substr 4 100 }}`, "sum"},
+		{`{{ .Category | categorySlug }}/{{ .Difficulty | lower }}`, "algorithms/easy"},
+		{`{{ .Id | group 100 }}/{{ .Slug | upper }}`, "1-100/TWO-SUM"},
+	}
+	for _, c := range cases {
+		got, err := q.GetFormattedFilename("golang", c.tmpl)
+		if err != nil {
+			t.Errorf("format %s: %v", c.tmpl, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("format %s: got %q, want %q", c.tmpl, got, c.want)
+		}
+	}
+
+	if _, err := q.GetFormattedFilename("golang", `{{ .Slug | unknown }}`); err == nil {
+		t.Errorf("expected error for unknown function")
+	}
+}
+
+func TestQuestionFromJSON(t *testing.T) {
+	payloads := []string{
+		`{"titleSlug": "two-sum", "questionFrontendId": "1", "metaData": "{\"name\": \"twoSum\"}"}`,
+		`{"data": {"question": {"titleSlug": "two-sum", "questionFrontendId": "1", "metaData": "{\"name\": \"twoSum\"}"}}}`,
+	}
+	for _, p := range payloads {
+		q, err := QuestionFromJSON([]byte(p), nil)
+		if err != nil {
+			t.Errorf("load %s: %v", p, err)
+			continue
+		}
+		if q.TitleSlug != "two-sum" || q.QuestionFrontendId != "1" || q.MetaData.Name != "twoSum" {
+			t.Errorf("load %s: got %+v", p, q)
+		}
+	}
+
+	for _, p := range []string{`{}`, `{"data": {"question": null}}`, `[]`} {
+		if _, err := QuestionFromJSON([]byte(p), nil); err == nil {
+			t.Errorf("load %s: expected error", p)
+		}
+	}
+}
+
+func TestMetaDataSignature(t *testing.T) {
+	var m MetaData
+	err := m.UnmarshalJSON([]byte(`{"name": "rotate", "params": [{"name": "matrix", "type": "list<list<integer>>"}], "return": {"type": "void"}, "output": {"paramindex": 0}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := m.Function()
+	if !fn.IsVoid() || fn.Params[0].Type != (TypeInfo{Base: "integer", Dim: 2}) {
+		t.Errorf("unexpected signature: %+v", fn)
+	}
+	if got := m.ResultType(); got != "integer[][]" {
+		t.Errorf("result type: got %q, want %q", got, "integer[][]")
+	}
+
+	m = MetaData{Name: "sort", Return: &MetaDataReturn{Type: "void"}}
+	if got := m.ResultType(); got != "void" {
+		t.Errorf("result type without output: got %q, want void", got)
+	}
+
+	m = MetaData{
+		SystemDesign: true,
+		ClassName:    "LRUCache",
+		Constructor:  MetaDataConstructor{Params: []MetaDataParam{{Name: "capacity", Type: "integer"}}},
+		Methods: []MetaDataMethod{
+			{Name: "get", Params: []MetaDataParam{{Name: "key", Type: "integer"}}, Return: MetaDataReturn{Type: "integer"}},
+			{Name: "put", Params: []MetaDataParam{{Name: "key", Type: "integer"}, {Name: "value", Type: "integer"}}, Return: MetaDataReturn{Type: "void"}},
+		},
+	}
+	if c := m.ConstructorSignature(); c.Name != "LRUCache" || len(c.Params) != 1 || !c.IsVoid() {
+		t.Errorf("unexpected constructor: %+v", c)
+	}
+	methods := m.MethodSignatures()
+	if len(methods) != 2 || methods[0].IsVoid() || !methods[1].IsVoid() || methods[1].ResultType().String() != "void" {
+		t.Errorf("unexpected methods: %+v", methods)
+	}
+}
+
+func TestLinkImages(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"![tree](https://assets.leetcode.com/a.png)", "[tree](https://assets.leetcode.com/a.png)"},
+		{"see ![](a.png) and ![ b ](b.png)", "see [image](a.png) and [b](b.png)"},
+		{"[link](a.png) stays", "[link](a.png) stays"},
+	}
+	for _, c := range cases {
+		if got := linkImages(c.in); got != c.want {
+			t.Errorf("linkImages(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestAcceptanceRate(t *testing.T) {
+	cases := []struct {
+		stats Stats
+		want  float64
+		ok    bool
+	}{
+		{Stats{ACRate: "52.5%"}, 52.5, true},
+		{Stats{TotalAcceptedRaw: 1, TotalSubmissionRaw: 4}, 25, true},
+		{Stats{}, 0, false},
+	}
+	for _, c := range cases {
+		q := &QuestionData{Stats: c.stats}
+		got, ok := q.AcceptanceRate()
+		if got != c.want || ok != c.ok {
+			t.Errorf("AcceptanceRate(%+v) = %v, %v, want %v, %v", c.stats, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestOmitContent(t *testing.T) {
+	en := omitContent(cnQuestionFullFields, config.EN)
+	if strings.Contains(en, "\tcontent\n") || !strings.Contains(en, "\ttranslatedContent\n") || !strings.Contains(en, "editorType") {
+		t.Errorf("fields without the english content:\n%s", en)
+	}
+	zh := omitContent(usQuestionFullFields, config.ZH)
+	if strings.Contains(zh, "translatedContent") || !strings.Contains(zh, "\tcontent\n") {
+		t.Errorf("fields without the translated content:\n%s", zh)
+	}
+	if got := omitContent(usQuestionFullFields, ""); got != usQuestionFullFields {
+		t.Errorf("fields changed without an omitted language:\n%s", got)
+	}
+}
+
+// fakeContentClient serves the descriptions of questions, counting the requests.
+type fakeContentClient struct {
+	Client
+	requests int
+	err      error
+}
+
+func (c *fakeContentClient) GetQuestionsData(slugs []string, fields QuestionFields) ([]*QuestionData, error) {
+	c.requests++
+	if fields != FieldsContent {
+		return nil, errors.New("unexpected fields")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []*QuestionData{{TitleSlug: slugs[0], Content: "en", TranslatedContent: "zh"}}, nil
+}
+
+func TestContentInFetchesOmitted(t *testing.T) {
+	c := &fakeContentClient{}
+	q := &QuestionData{client: c, TitleSlug: "two-sum", TranslatedContent: "zh", omittedContent: config.EN}
+	if content, lang := q.contentIn(config.ZH); content != "zh" || lang != config.ZH || c.requests != 0 {
+		t.Errorf("contentIn(zh) = %q, %s with %d requests, want the fetched content", content, lang, c.requests)
+	}
+	for range 2 {
+		if content, lang := q.contentIn(config.EN); content != "en" || lang != config.EN {
+			t.Errorf("contentIn(en) = %q, %s, want the omitted content fetched", content, lang)
+		}
+	}
+	if c.requests != 1 || q.TranslatedContent != "zh" {
+		t.Errorf("omitted content fetched in %d requests, translated content %q", c.requests, q.TranslatedContent)
+	}
+
+	// Without a translation the english content is shown, so it's fetched even for zh.
+	q = &QuestionData{client: c, TitleSlug: "two-sum", omittedContent: config.EN}
+	if content, lang := q.contentIn(config.ZH); content != "en" || lang != config.EN {
+		t.Errorf("contentIn(zh) of an untranslated question = %q, %s", content, lang)
+	}
+
+	// On failure, the other language is used, and the request is not repeated.
+	c = &fakeContentClient{err: errors.New("offline")}
+	q = &QuestionData{client: c, TitleSlug: "two-sum", TranslatedContent: "zh", omittedContent: config.EN}
+	for range 2 {
+		if content, lang := q.contentIn(config.EN); content != "zh" || lang != config.ZH {
+			t.Errorf("contentIn(en) after a failed fetch = %q, %s", content, lang)
+		}
+	}
+	if c.requests != 1 {
+		t.Errorf("failed fetch is repeated %d times", c.requests)
+	}
+}
+
+func TestGetSchema(t *testing.T) {
+	q := &QuestionData{
+		MysqlSchemas: []string{"Create table Person (id int)"},
+		DataSchemas:  []string{"Person = pd.DataFrame()"},
+	}
+	if got := q.GetSchema("mysql"); !slices.Equal(got, q.MysqlSchemas) {
+		t.Errorf("GetSchema(mysql) = %q", got)
+	}
+	if got := q.GetSchema("pythondata"); !slices.Equal(got, q.DataSchemas) {
+		t.Errorf("GetSchema(pythondata) = %q", got)
+	}
+	// LeetCode provides schemas for mysql and pythondata only.
+	for _, lang := range []string{"mssql", "bash", "golang"} {
+		if got := q.GetSchema(lang); got != nil {
+			t.Errorf("GetSchema(%s) = %q, want nil", lang, got)
+		}
+	}
+}
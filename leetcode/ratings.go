@@ -0,0 +1,266 @@
+package leetcode
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+// ZerotracRatingsURL is the community maintained dataset of contest question ratings.
+const ZerotracRatingsURL = "https://raw.githubusercontent.com/zerotrac/leetcode_problem_rating/main/data.json"
+
+var ErrRatingsNotFound = errors.New("ratings not found")
+
+type Rating struct {
+	FrontendID string  `json:"frontend_id"`
+	Slug       string  `json:"slug"`
+	Rating     float64 `json:"rating"`
+}
+
+// Ratings maps question slug to its difficulty rating.
+type Ratings map[string]Rating
+
+func (r Ratings) Get(slug string) (float64, bool) {
+	rating, ok := r[slug]
+	return rating.Rating, ok
+}
+
+// Merge adds ratings from other, overriding existing ones.
+func (r Ratings) Merge(other Ratings) {
+	for slug, rating := range other {
+		r[slug] = rating
+	}
+}
+
+// LoadRatings loads ratings imported to the cache directory.
+func LoadRatings() (Ratings, error) {
+	data, err := os.ReadFile(config.Get().RatingsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrRatingsNotFound
+		}
+		return nil, err
+	}
+	var ratings Ratings
+	err = json.Unmarshal(data, &ratings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ratings file: %w", err)
+	}
+	return ratings, nil
+}
+
+// MergeWithSaved merges ratings into the imported ratings, the saved ones are overridden.
+func MergeWithSaved(ratings Ratings) (Ratings, error) {
+	saved, err := LoadRatings()
+	if errors.Is(err, ErrRatingsNotFound) {
+		return ratings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved.Merge(ratings)
+	return saved, nil
+}
+
+func SaveRatings(ratings Ratings) error {
+	data, err := json.Marshal(ratings)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(config.Get().RatingsFile(), data)
+}
+
+type zerotracRating struct {
+	Rating    float64 `json:"Rating"`
+	ID        int     `json:"ID"`
+	TitleSlug string  `json:"TitleSlug"`
+}
+
+// ratingsClient downloads the ratings dataset, a stalled server should not hang the commands using ratings.
+var ratingsClient = &http.Client{Timeout: time.Minute}
+
+// FetchZerotracRatings downloads the zerotrac ratings dataset.
+func FetchZerotracRatings() (Ratings, error) {
+	if config.Offline() {
+		return nil, ErrOffline
+	}
+	return fetchZerotracRatings(ZerotracRatingsURL)
+}
+
+func fetchZerotracRatings(url string) (Ratings, error) {
+	log.Info("downloading ratings", "url", url)
+	resp, err := ratingsClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download ratings: %s", resp.Status)
+	}
+
+	var data []zerotracRating
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ratings data: %w", err)
+	}
+	ratings := make(Ratings, len(data))
+	for _, r := range data {
+		ratings[r.TitleSlug] = Rating{
+			FrontendID: strconv.Itoa(r.ID),
+			Slug:       r.TitleSlug,
+			Rating:     r.Rating,
+		}
+	}
+	return ratings, nil
+}
+
+// LoadOrFetchRatings loads imported ratings, downloads the zerotrac dataset if there is none.
+func LoadOrFetchRatings() (Ratings, error) {
+	ratings, err := LoadRatings()
+	if !errors.Is(err, ErrRatingsNotFound) {
+		return ratings, err
+	}
+	ratings, err = FetchZerotracRatings()
+	if err != nil {
+		return nil, err
+	}
+	return ratings, SaveRatings(ratings)
+}
+
+// ImportRatings parses a ratings dataset in csv, tsv or json format.
+//
+// For csv and tsv, the first line is a header that must contain a "rating" column,
+// and a "slug" (or "title slug") or "id" (or "frontend id") column.
+// For json, it can be an array of objects with the same keys, or an object maps question id or slug to rating.
+// Questions referenced only by id are resolved to slugs by resolve, unresolved ones are ignored.
+func ImportRatings(r io.Reader, format string, resolve func(id string) string) (Ratings, error) {
+	var records []map[string]string
+	var err error
+	switch format {
+	case "csv":
+		records, err = readRatingRecords(r, ',')
+	case "tsv":
+		records, err = readRatingRecords(r, '\t')
+	case "json":
+		records, err = readRatingJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported ratings format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make(Ratings, len(records))
+	for i, rec := range records {
+		value, err := strconv.ParseFloat(rec["rating"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: invalid rating %q", i+1, rec["rating"])
+		}
+		id, slug := rec["id"], rec["slug"]
+		if slug == "" && id != "" {
+			slug = resolve(id)
+		}
+		if slug == "" {
+			continue
+		}
+		ratings[slug] = Rating{FrontendID: id, Slug: slug, Rating: value}
+	}
+	if len(ratings) == 0 {
+		return nil, errors.New("no ratings found in dataset")
+	}
+	return ratings, nil
+}
+
+// normalizeRatingKey maps dataset column names to "id", "slug" or "rating".
+func normalizeRatingKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(key)
+	switch key {
+	case "id", "frontendid", "questionid", "questionfrontendid":
+		return "id"
+	case "slug", "titleslug":
+		return "slug"
+	default:
+		return key
+	}
+}
+
+func readRatingRecords(r io.Reader, sep rune) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = sep
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid ratings dataset: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("no ratings found in dataset")
+	}
+	header := make([]string, len(rows[0]))
+	for i, h := range rows[0] {
+		header[i] = normalizeRatingKey(h)
+	}
+	if !slices.Contains(header, "rating") || (!slices.Contains(header, "id") && !slices.Contains(header, "slug")) {
+		return nil, errors.New(`ratings dataset header must contain "rating" and "id" or "slug" columns`)
+	}
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		rec := make(map[string]string, len(header))
+		for i, v := range row {
+			if i < len(header) {
+				rec[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func readRatingJSON(r io.Reader) ([]map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]any
+	if err = json.Unmarshal(data, &objects); err == nil {
+		records := make([]map[string]string, 0, len(objects))
+		for _, obj := range objects {
+			rec := make(map[string]string, len(obj))
+			for k, v := range obj {
+				rec[normalizeRatingKey(k)] = strings.TrimSpace(fmt.Sprint(v))
+			}
+			records = append(records, rec)
+		}
+		return records, nil
+	}
+
+	var mapping map[string]float64
+	if err = json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid ratings dataset: %w", err)
+	}
+	records := make([]map[string]string, 0, len(mapping))
+	for k, v := range mapping {
+		rec := map[string]string{"rating": strconv.FormatFloat(v, 'f', -1, 64)}
+		if isNumber(k) {
+			rec["id"] = k
+		} else {
+			rec["slug"] = k
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
@@ -0,0 +1,154 @@
+package leetcode
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/config"
+)
+
+func TestImportRatings(t *testing.T) {
+	resolve := func(id string) string {
+		if id == "1" {
+			return "two-sum"
+		}
+		return ""
+	}
+	cases := []struct {
+		name    string
+		format  string
+		dataset string
+		want    Ratings
+	}{
+		{
+			"csv", "csv",
+			"Title Slug,Rating\ntwo-sum, 1200.5\nadd-two-numbers,1500\n",
+			Ratings{
+				"two-sum":         {Slug: "two-sum", Rating: 1200.5},
+				"add-two-numbers": {Slug: "add-two-numbers", Rating: 1500},
+			},
+		},
+		{
+			"tsv by id", "tsv",
+			"frontend_id\trating\n1\t1200\n999\t2000\n",
+			Ratings{"two-sum": {FrontendID: "1", Slug: "two-sum", Rating: 1200}},
+		},
+		{
+			"json array", "json",
+			`[{"ID": 1, "Rating": 1200}, {"TitleSlug": "add-two-numbers", "Rating": 1500}]`,
+			Ratings{
+				"two-sum":         {FrontendID: "1", Slug: "two-sum", Rating: 1200},
+				"add-two-numbers": {Slug: "add-two-numbers", Rating: 1500},
+			},
+		},
+		{
+			"json object", "json",
+			`{"1": 1200, "add-two-numbers": 1500}`,
+			Ratings{
+				"two-sum":         {FrontendID: "1", Slug: "two-sum", Rating: 1200},
+				"add-two-numbers": {Slug: "add-two-numbers", Rating: 1500},
+			},
+		},
+	}
+	for _, c := range cases {
+		got, err := ImportRatings(strings.NewReader(c.dataset), c.format, resolve)
+		if err != nil {
+			t.Errorf("%s: ImportRatings() error = %v", c.name, err)
+			continue
+		}
+		if !maps.Equal(got, c.want) {
+			t.Errorf("%s: ImportRatings() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestImportRatingsInvalid(t *testing.T) {
+	cases := []struct {
+		format  string
+		dataset string
+	}{
+		{"csv", "slug,difficulty\ntwo-sum,easy\n"},
+		{"csv", "slug,rating\ntwo-sum,high\n"},
+		{"csv", "slug,rating\n"},
+		{"json", `{"999": 1200}`},
+		{"json", `not json`},
+		{"xml", `<ratings/>`},
+	}
+	for _, c := range cases {
+		_, err := ImportRatings(strings.NewReader(c.dataset), c.format, func(string) string { return "" })
+		if err == nil {
+			t.Errorf("ImportRatings(%q, %s) should fail", c.dataset, c.format)
+		}
+	}
+}
+
+func TestMergeWithSaved(t *testing.T) {
+	t.Setenv("LEETGO_HOME", "")
+	t.Setenv("LEETGO_CACHE_DIR", t.TempDir())
+	imported := Ratings{"two-sum": {Slug: "two-sum", Rating: 1300}}
+
+	got, err := MergeWithSaved(imported)
+	if err != nil || !maps.Equal(got, imported) {
+		t.Errorf("MergeWithSaved() without saved ratings = %v, %v", got, err)
+	}
+
+	err = SaveRatings(
+		Ratings{
+			"two-sum":         {Slug: "two-sum", Rating: 1200},
+			"add-two-numbers": {Slug: "add-two-numbers", Rating: 1500},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = MergeWithSaved(imported)
+	want := Ratings{
+		"two-sum":         {Slug: "two-sum", Rating: 1300},
+		"add-two-numbers": {Slug: "add-two-numbers", Rating: 1500},
+	}
+	if err != nil || !maps.Equal(got, want) {
+		t.Errorf("MergeWithSaved() = %v, %v, want %v", got, err, want)
+	}
+
+	// Saved ratings that can't be loaded are not silently replaced.
+	err = os.WriteFile(config.Get().RatingsFile(), []byte("corrupted"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = MergeWithSaved(imported); err == nil {
+		t.Errorf("MergeWithSaved() with invalid saved ratings should fail")
+	}
+}
+
+func TestFetchZerotracRatings(t *testing.T) {
+	stall := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stall" {
+			<-stall
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Rating": 1200.5, "ID": 1, "TitleSlug": "two-sum"}]`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(stall) })
+
+	ratings, err := fetchZerotracRatings(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := ratings["two-sum"]; r.FrontendID != "1" || r.Rating != 1200.5 {
+		t.Errorf("fetchZerotracRatings() = %+v", ratings)
+	}
+
+	timeout := ratingsClient.Timeout
+	ratingsClient.Timeout = 100 * time.Millisecond
+	t.Cleanup(func() { ratingsClient.Timeout = timeout })
+	if _, err := fetchZerotracRatings(srv.URL + "/stall"); err == nil {
+		t.Errorf("fetchZerotracRatings() of a stalled server should time out")
+	}
+}
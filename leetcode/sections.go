@@ -0,0 +1,80 @@
+package leetcode
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	constraintsHeadingRe = regexp.MustCompile(`(?i)^\**\s*(?:constraints|提示)\s*[:：]\s*\**$`)
+	followUpRe           = regexp.MustCompile(`(?i)^\**\s*(?:follow[- ]?up|进阶)\s*[:：]?\s*\**\s*(.*)$`)
+	listItemRe           = regexp.MustCompile(`^(?:[-*+]|\d+\.)\s+`)
+)
+
+// cleanMarkdown removes emphasis and code marks from a line of markdown, for use in plain text comments.
+func cleanMarkdown(s string) string {
+	s = strings.NewReplacer("**", "", "`", "", `\_`, "_", `\*`, "*", `\[`, "[", `\]`, "]").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// GetConstraints returns the items of the "Constraints" section of the preferred content, as plain text.
+func (q *QuestionData) GetConstraints() []string {
+	content, _ := q.GetMarkdownContent()
+	items := constraintItems(content)
+	for i, item := range items {
+		items[i] = cleanMarkdown(item)
+	}
+	return items
+}
+
+// constraintItems returns the list items following the "Constraints" heading of markdown content,
+// lists elsewhere in the content are not part of the section.
+func constraintItems(content string) []string {
+	var items []string
+	inSection := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !inSection {
+			inSection = constraintsHeadingRe.MatchString(line)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if !listItemRe.MatchString(line) {
+			break
+		}
+		items = append(items, listItemRe.ReplaceAllString(line, ""))
+	}
+	return items
+}
+
+// GetFollowUp returns the "Follow-up" section of the preferred content as plain text, empty if there is none.
+func (q *QuestionData) GetFollowUp() string {
+	content, _ := q.GetMarkdownContent()
+	var parts []string
+	inSection := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !inSection {
+			if m := followUpRe.FindStringSubmatch(line); m != nil {
+				inSection = true
+				parts = append(parts, m[1])
+			}
+			continue
+		}
+		parts = append(parts, line)
+	}
+	return cleanMarkdown(strings.Join(parts, " "))
+}
+
+// GetHints returns the hints of the question as plain text.
+func (q *QuestionData) GetHints() []string {
+	hints := make([]string, 0, len(q.Hints))
+	for _, h := range q.Hints {
+		if h = cleanMarkdown(htmlToMarkdown(h)); h != "" {
+			hints = append(hints, h)
+		}
+	}
+	return hints
+}
@@ -0,0 +1,86 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQuestionSections(t *testing.T) {
+	q := &QuestionData{
+		EditorType: EditorTypeCKEditor,
+		Content: `<p>Given an array of integers <code>nums</code>&nbsp;and an integer <code>target</code>.</p>
+<p>&nbsp;</p>
+<p><strong>Constraints:</strong></p>
+
+<ul>
+	<li><code>2 &lt;= nums.length &lt;= 10<sup>4</sup></code></li>
+	<li><code>-10<sup>9</sup> &lt;= nums[i] &lt;= 10<sup>9</sup></code></li>
+	<li><strong>Only one valid answer exists.</strong></li>
+</ul>
+
+<p>&nbsp;</p>
+<strong>Follow-up:&nbsp;</strong>Can you come up with an algorithm that is less than <code>O(n<sup>2</sup>)</code> time complexity?`,
+		Hints: []string{"A really brute force way would be to search for all possible pairs.", "<b>Try a hash map</b>"},
+	}
+
+	wantConstraints := []string{"2 <= nums.length <= 10⁴", "-10⁹ <= nums[i] <= 10⁹", "Only one valid answer exists."}
+	if got := q.GetConstraints(); !slices.Equal(got, wantConstraints) {
+		t.Errorf("constraints: got %q, want %q", got, wantConstraints)
+	}
+	if got, want := q.GetFollowUp(), "Can you come up with an algorithm that is less than O(n²) time complexity?"; got != want {
+		t.Errorf("follow-up: got %q, want %q", got, want)
+	}
+	wantHints := []string{"A really brute force way would be to search for all possible pairs.", "Try a hash map"}
+	if got := q.GetHints(); !slices.Equal(got, wantHints) {
+		t.Errorf("hints: got %q, want %q", got, wantHints)
+	}
+
+	// The translated content is preferred in a Chinese locale.
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "zh_CN.UTF-8")
+	q.TranslatedContent = `<p>给定一个整数数组 <code>nums</code>。</p>
+<p><strong>提示：</strong></p>
+<ul>
+	<li><code>2 &lt;= nums.length &lt;= 10<sup>4</sup></code></li>
+</ul>
+<p><strong>进阶：</strong>你可以想出一个时间复杂度小于 <code>O(n<sup>2</sup>)</code> 的算法吗？</p>`
+	if got, want := q.GetConstraints(), []string{"2 <= nums.length <= 10⁴"}; !slices.Equal(got, want) {
+		t.Errorf("zh constraints: got %q, want %q", got, want)
+	}
+	if got, want := q.GetFollowUp(), "你可以想出一个时间复杂度小于 O(n²) 的算法吗？"; got != want {
+		t.Errorf("zh follow-up: got %q, want %q", got, want)
+	}
+}
+
+func TestConstraintItems(t *testing.T) {
+	content := `Return either:
+
+- ` + "`0`" + ` if sorted
+- ` + "`-1`" + ` otherwise
+
+**Constraints:**
+
+- ` + "`1 <= n <= 10⁴`" + `
+* ` + "`n == nums.length`" + `
+
+**Follow-up:** Can you do it in O(n)?
+
+- not a constraint`
+	want := []string{"`1 <= n <= 10⁴`", "`n == nums.length`"}
+	if got := constraintItems(content); !slices.Equal(got, want) {
+		t.Errorf("constraintItems() = %q, want %q", got, want)
+	}
+	if got := constraintItems("- no constraints section"); got != nil {
+		t.Errorf("constraintItems() without a section = %q, want nil", got)
+	}
+
+	// Markdown questions share the extractor with HTML ones.
+	q := &QuestionData{EditorType: EditorTypeMarkdown, Content: content}
+	if got, want := q.GetConstraints(), []string{"1 <= n <= 10⁴", "n == nums.length"}; !slices.Equal(got, want) {
+		t.Errorf("GetConstraints() = %q, want %q", got, want)
+	}
+	if got := q.ParseConstraints().Bounds; len(got) != 1 || got[0].Max != "10^4" {
+		t.Errorf("ParseConstraints() = %+v", got)
+	}
+}
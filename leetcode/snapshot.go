@@ -0,0 +1,137 @@
+package leetcode
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+var ErrSnapshotNotFound = errors.New("no snapshot found, it is taken on the next `leetgo cache update`")
+
+// QuestionSnapshot is the brief of a question at the time a snapshot was taken.
+type QuestionSnapshot struct {
+	FrontendID string  `json:"frontend_id"`
+	Slug       string  `json:"slug"`
+	Title      string  `json:"title"`
+	Difficulty string  `json:"difficulty"`
+	Rating     float64 `json:"rating,omitempty"`
+}
+
+// Snapshot records the question list before a cache update, so that changes can be shown later.
+type Snapshot struct {
+	TakenAt   time.Time                   `json:"taken_at"`
+	Questions map[string]QuestionSnapshot `json:"questions"`
+}
+
+func NewSnapshot(qs []*QuestionData, ratings Ratings) Snapshot {
+	s := Snapshot{
+		TakenAt:   time.Now(),
+		Questions: make(map[string]QuestionSnapshot, len(qs)),
+	}
+	for _, q := range qs {
+		rating, _ := ratings.Get(q.TitleSlug)
+		s.Questions[q.TitleSlug] = QuestionSnapshot{
+			FrontendID: q.QuestionFrontendId,
+			Slug:       q.TitleSlug,
+			Title:      q.GetTitle(),
+			Difficulty: q.Difficulty,
+			Rating:     rating,
+		}
+	}
+	return s
+}
+
+func LoadSnapshot() (Snapshot, error) {
+	var s Snapshot
+	data, err := os.ReadFile(config.Get().SnapshotFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, ErrSnapshotNotFound
+		}
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return s, fmt.Errorf("invalid snapshot file: %w", err)
+	}
+	return s, nil
+}
+
+func SaveSnapshot(s Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(config.Get().SnapshotFile(), data)
+}
+
+// SnapshotChange is a question whose difficulty or rating has changed.
+type SnapshotChange struct {
+	Old QuestionSnapshot
+	New QuestionSnapshot
+}
+
+type SnapshotDiff struct {
+	Added   []QuestionSnapshot
+	Removed []QuestionSnapshot
+	Changed []SnapshotChange
+}
+
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSnapshots compares two snapshots, results are sorted by frontend id.
+func DiffSnapshots(old, new Snapshot) SnapshotDiff {
+	var d SnapshotDiff
+	for slug, n := range new.Questions {
+		o, ok := old.Questions[slug]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, n)
+		case o.Difficulty != n.Difficulty || o.Rating != n.Rating:
+			d.Changed = append(d.Changed, SnapshotChange{Old: o, New: n})
+		}
+	}
+	for slug, o := range old.Questions {
+		if _, ok := new.Questions[slug]; !ok {
+			d.Removed = append(d.Removed, o)
+		}
+	}
+
+	byID := func(a, b QuestionSnapshot) int {
+		return compareFrontendID(a.FrontendID, b.FrontendID)
+	}
+	slices.SortFunc(d.Added, byID)
+	slices.SortFunc(d.Removed, byID)
+	slices.SortFunc(
+		d.Changed, func(a, b SnapshotChange) int {
+			return byID(a.New, b.New)
+		},
+	)
+	return d
+}
+
+// compareFrontendID orders numeric ids numerically, and others lexically after them.
+func compareFrontendID(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return cmp.Compare(a, b)
+}
@@ -0,0 +1,59 @@
+package leetcode
+
+import "testing"
+
+func TestDiffSnapshots(t *testing.T) {
+	twoSum := QuestionSnapshot{FrontendID: "1", Slug: "two-sum", Difficulty: "Easy"}
+	addTwoNumbers := QuestionSnapshot{FrontendID: "2", Slug: "add-two-numbers", Difficulty: "Medium", Rating: 1500}
+	lcp := QuestionSnapshot{FrontendID: "LCP 01", Slug: "guess-numbers", Difficulty: "Easy"}
+	longest := QuestionSnapshot{FrontendID: "10", Slug: "regular-expression-matching", Difficulty: "Hard"}
+	snapshot := func(qs ...QuestionSnapshot) Snapshot {
+		s := Snapshot{Questions: map[string]QuestionSnapshot{}}
+		for _, q := range qs {
+			s.Questions[q.Slug] = q
+		}
+		return s
+	}
+
+	if d := DiffSnapshots(snapshot(twoSum, addTwoNumbers), snapshot(twoSum, addTwoNumbers)); !d.Empty() {
+		t.Errorf("DiffSnapshots() of the same snapshots = %+v, want empty", d)
+	}
+
+	reRated, harder := addTwoNumbers, twoSum
+	reRated.Rating = 1600
+	harder.Difficulty = "Medium"
+	d := DiffSnapshots(
+		snapshot(twoSum, addTwoNumbers, longest),
+		snapshot(harder, reRated, lcp),
+	)
+	if len(d.Added) != 1 || d.Added[0] != lcp {
+		t.Errorf("Added = %+v, want %s", d.Added, lcp.Slug)
+	}
+	if len(d.Removed) != 1 || d.Removed[0] != longest {
+		t.Errorf("Removed = %+v, want %s", d.Removed, longest.Slug)
+	}
+	// Sorted numerically by frontend id.
+	want := []SnapshotChange{{Old: twoSum, New: harder}, {Old: addTwoNumbers, New: reRated}}
+	if len(d.Changed) != len(want) || d.Changed[0] != want[0] || d.Changed[1] != want[1] {
+		t.Errorf("Changed = %+v, want %+v", d.Changed, want)
+	}
+}
+
+func TestCompareFrontendID(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"2", "10", -1},
+		{"10", "2", 1},
+		{"1", "1", 0},
+		{"100", "LCP 01", -1},
+		{"LCP 01", "1", 1},
+		{"LCP 02", "LCP 01", 1},
+	}
+	for _, c := range cases {
+		if got := compareFrontendID(c.a, c.b); got != c.want {
+			t.Errorf("compareFrontendID(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package leetcode
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	ErrNoEditorial       = errors.New("the question has no editorial")
+	ErrPaidOnlyEditorial = errors.New("the editorial is only available to Premium subscribers")
+)
+
+// Solution is a community solution or the editorial of a question.
+type Solution struct {
+	// ID identifies the solution to fetch its content: the topic id on leetcode.com, the article slug on leetcode.cn.
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Author string   `json:"author"`
+	Votes  int      `json:"votes"`
+	Tags   []string `json:"tags"`
+	// Content is the markdown content, only filled by GetSolution.
+	Content string `json:"content,omitempty"`
+}
+
+// Languages returns the tags of the solution that are programming languages.
+func (s *Solution) Languages() []string {
+	var langs []string
+	for _, t := range s.Tags {
+		if solutionLangTags[strings.ToLower(t)] {
+			langs = append(langs, t)
+		}
+	}
+	return langs
+}
+
+// solutionLangTags are the tag slugs of programming languages, solution tags also contain topics like "array".
+var solutionLangTags = map[string]bool{
+	"c": true, "cpp": true, "csharp": true, "java": true, "python": true, "python3": true, "golang": true, "go": true,
+	"rust": true, "javascript": true, "typescript": true, "kotlin": true, "swift": true, "php": true, "ruby": true,
+	"scala": true, "dart": true, "elixir": true, "erlang": true, "racket": true, "mysql": true, "bash": true,
+	"pandas": true, "oraclesql": true, "mssql": true, "postgresql": true,
+}
+
+// decodePostContent decodes the content of leetcode.com posts, which is escaped like a JavaScript string literal
+// without the quotes. Escapes in code blocks are decoded too, e.g. \\n is the two characters of "\n" in a
+// string literal of the code, not a newline. Invalid escapes are kept as is.
+func decodePostContent(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '"', '\'', '\\', '/':
+			sb.WriteByte(c)
+		case 'u':
+			r, n := decodeUnicodeEscape(s[i-1:])
+			if n == 0 {
+				sb.WriteString(`\u`)
+				continue
+			}
+			sb.WriteRune(r)
+			i += n - 2
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// decodeUnicodeEscape decodes the \uXXXX escape at the start of s, or a surrogate pair of two escapes.
+// It returns the number of bytes decoded, 0 if the escape is invalid.
+func decodeUnicodeEscape(s string) (rune, int) {
+	hex := func(s string) (rune, bool) {
+		if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+			return 0, false
+		}
+		v, err := strconv.ParseUint(s[2:6], 16, 16)
+		return rune(v), err == nil
+	}
+	r, ok := hex(s)
+	if !ok {
+		return 0, 0
+	}
+	if utf16.IsSurrogate(r) {
+		if r2, ok := hex(s[6:]); ok {
+			if d := utf16.DecodeRune(r, r2); d != '\uFFFD' {
+				return d, 12
+			}
+		}
+	}
+	return r, 6
+}
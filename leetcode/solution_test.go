@@ -0,0 +1,40 @@
+package leetcode
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSolutionLanguages(t *testing.T) {
+	s := &Solution{Tags: []string{"Array", "Go", "hash-table", "C++", "cpp", "Python3", "sorting"}}
+	if got, want := s.Languages(), []string{"Go", "cpp", "Python3"}; !slices.Equal(got, want) {
+		t.Errorf("Languages() = %q, want %q", got, want)
+	}
+	if got := (&Solution{Tags: []string{"array"}}).Languages(); got != nil {
+		t.Errorf("Languages() without language tags = %q, want nil", got)
+	}
+}
+
+func TestDecodePostContent(t *testing.T) {
+	cases := []struct {
+		content, want string
+	}{
+		{"no escapes", "no escapes"},
+		{`line\nnext\ttab`, "line\nnext\ttab"},
+		{`say \"hi\" it\'s a\/b`, `say "hi" it's a/b`},
+		// Escaped backslashes in code must stay as escapes of the code.
+		{"```go\\nfmt.Print(\\\"a\\\\n\\\")\\n```", "```go\nfmt.Print(\"a\\n\")\n```"},
+		{`\\\n`, "\\\n"},
+		{`\u4e2d\u6587`, "中文"},
+		{`\ud83d\ude00`, "\U0001F600"},
+		{`\ud83d alone`, "\uFFFD alone"},
+		{`\u12 short`, `\u12 short`},
+		{`unknown \x escape`, `unknown \x escape`},
+		{`trailing \`, `trailing \`},
+	}
+	for _, c := range cases {
+		if got := decodePostContent(c.content); got != c.want {
+			t.Errorf("decodePostContent(%q) = %q, want %q", c.content, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+	"golang.org/x/mod/semver"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/utils"
+)
+
+// Release channels.
+const (
+	ChannelStable  = "stable"
+	ChannelNightly = "nightly"
+)
+
+const (
+	releasesURL    = "https://api.github.com/repos/j178/leetgo/releases"
+	checksumsAsset = "checksums.txt"
+	// checkInterval is how often the latest release is checked for the new version notice.
+	checkInterval = 24 * time.Hour
+)
+
+var ErrNoAsset = fmt.Errorf("no release asset for %s/%s", runtime.GOOS, runtime.GOARCH)
+
+// stallTimeout bounds how long a request waits for the server: to connect, to respond, and for each read of the body.
+// A slow download goes on as long as data keeps coming, a stalled one fails.
+var stallTimeout = 30 * time.Second
+
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: stallTimeout}).DialContext,
+		TLSHandshakeTimeout:   stallTimeout,
+		ResponseHeaderTimeout: stallTimeout,
+	},
+}
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	HTMLURL    string  `json:"html_url"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Version returns the version of the release without the "v" prefix, as constants.Version is.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// IsNewer reports whether version is newer than current. A current version that is not a release, e.g. "dev", is never outdated.
+func IsNewer(version string, current string) bool {
+	v, cur := "v"+version, "v"+current
+	if !semver.IsValid(v) || !semver.IsValid(cur) {
+		return false
+	}
+	return semver.Compare(v, cur) > 0
+}
+
+// IsRelease reports whether the running binary is built by a release, instead of from source.
+func IsRelease() bool {
+	return semver.IsValid("v" + constants.Version)
+}
+
+// stallReader cancels the request when no data is read from the body for stallTimeout.
+type stallReader struct {
+	io.ReadCloser
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	stalled atomic.Bool
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && r.stalled.Load() {
+		return n, fmt.Errorf("no data received for %s", stallTimeout)
+	}
+	r.timer.Reset(stallTimeout)
+	return n, err
+}
+
+func (r *stallReader) Close() error {
+	r.timer.Stop()
+	r.cancel()
+	return r.ReadCloser.Close()
+}
+
+func get(ctx context.Context, url string, accept string) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	// A token raises the rate limit of the GitHub API, which is 60 requests per hour without one.
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("get %s: %s", url, resp.Status)
+	}
+	body := &stallReader{ReadCloser: resp.Body, cancel: cancel}
+	body.timer = time.AfterFunc(
+		stallTimeout, func() {
+			body.stalled.Store(true)
+			cancel()
+		},
+	)
+	resp.Body = body
+	return resp, nil
+}
+
+// Latest returns the latest release of the channel: the latest release for stable,
+// the release with the highest version for nightly, pre-releases included.
+func Latest(ctx context.Context, channel string) (*Release, error) {
+	if config.Offline() {
+		return nil, errors.New("not available in offline mode")
+	}
+	defer utils.Track(utils.ProfileNetwork)()
+
+	url := releasesURL + "/latest"
+	if channel == ChannelNightly {
+		url = releasesURL + "?per_page=30"
+	}
+	resp, err := get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if channel != ChannelNightly {
+		var r Release
+		err = json.NewDecoder(resp.Body).Decode(&r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release: %w", err)
+		}
+		return &r, nil
+	}
+
+	var releases []Release
+	err = json.NewDecoder(resp.Body).Decode(&releases)
+	if err != nil {
+		return nil, fmt.Errorf("invalid releases: %w", err)
+	}
+	return highestRelease(releases)
+}
+
+// highestRelease returns the release with the highest version, skipping drafts and tags of other packages in the repo.
+func highestRelease(releases []Release) (*Release, error) {
+	var latest *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || !semver.IsValid(r.TagName) {
+			continue
+		}
+		if latest == nil || semver.Compare(r.TagName, latest.TagName) > 0 {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no release found")
+	}
+	return latest, nil
+}
+
+// AssetName returns the name of the release archive for the platform, see name_template in .goreleaser.yaml.
+func AssetName(goos string, goarch string) string {
+	osName := goos
+	if goos == "darwin" {
+		osName = "macOS"
+	}
+	arch := goarch
+	if goarch == "amd64" {
+		arch = "x86_64"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s%s", constants.CmdName, osName, arch, ext)
+}
+
+// parseChecksums finds the sha256 checksum of the file in checksums.txt, lines of "<checksum>  <filename>".
+func parseChecksums(data []byte, filename string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == filename {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("checksum of %s not found", filename)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	defer utils.Track(utils.ProfileNetwork)()
+	resp, err := get(ctx, url, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Download downloads the archive of the release for the running platform, verifies it against checksums.txt
+// of the release, and returns the leetgo binary in it.
+func Download(ctx context.Context, r *Release) ([]byte, error) {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	archive, checksums := r.asset(name), r.asset(checksumsAsset)
+	if archive == nil {
+		return nil, ErrNoAsset
+	}
+	if checksums == nil {
+		return nil, fmt.Errorf("release %s has no %s", r.TagName, checksumsAsset)
+	}
+
+	sums, err := download(ctx, checksums.URL)
+	if err != nil {
+		return nil, err
+	}
+	want, err := parseChecksums(sums, name)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("downloading", "url", archive.URL, "size", utils.FormatBytes(archive.Size))
+	data, err := download(ctx, archive.URL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch of %s: got %s, expected %s", name, got, want)
+	}
+	log.Debug("checksum verified", "file", name, "sha256", want)
+
+	binName := constants.CmdName
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	if strings.HasSuffix(name, ".zip") {
+		return extractZip(data, binName)
+	}
+	return extractTarGz(data, binName)
+}
+
+func extractTarGz(data []byte, binName string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binName {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in the archive", binName)
+}
+
+func extractZip(data []byte, binName string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || filepath.Base(f.Name) != binName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in the archive", binName)
+}
+
+// Executable returns the path of the running binary, with symlinks resolved.
+func Executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// PackageManager returns the command to upgrade leetgo installed by a package manager, empty if it's not.
+func PackageManager(exe string) string {
+	p := filepath.ToSlash(exe)
+	switch {
+	case strings.Contains(p, "/Cellar/") || strings.Contains(p, "/homebrew/"):
+		return "brew upgrade leetgo"
+	case strings.Contains(strings.ToLower(p), "/scoop/"):
+		return "scoop update leetgo"
+	}
+	return ""
+}
+
+// Replace replaces the binary at exe with bin in place.
+// The new binary is written next to it and renamed over it, so that exe is never left half written.
+// On Windows the running binary can not be overwritten but can be renamed, so it's moved aside first.
+func Replace(exe string, bin []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(exe)+".new-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	_, err = tmp.Write(bin)
+	if err1 := tmp.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmpName, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		return os.Rename(tmpName, exe)
+	}
+	old := exe + ".old"
+	_ = os.Remove(old)
+	err = os.Rename(exe, old)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpName, exe)
+	if err != nil {
+		_ = os.Rename(old, exe)
+		return err
+	}
+	// The old binary is still running, it's removed by the next upgrade.
+	return nil
+}
+
+// releaseCheck is the result of the last check for the latest release.
+type releaseCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Channel   string    `json:"channel"`
+	Version   string    `json:"version"`
+}
+
+// CachedLatest returns the latest version of the channel found by the last check,
+// and whether it's due to check again.
+func CachedLatest(channel string) (version string, stale bool) {
+	data, err := os.ReadFile(config.Get().ReleaseCheckFile())
+	if err != nil {
+		return "", true
+	}
+	var c releaseCheck
+	if err := json.Unmarshal(data, &c); err != nil || c.Channel != channel {
+		return "", true
+	}
+	return c.Version, time.Since(c.CheckedAt) >= checkInterval
+}
+
+// SaveCheck records the latest version of the channel, so that it's checked at most once a day.
+func SaveCheck(channel string, version string) {
+	data, err := json.Marshal(releaseCheck{CheckedAt: time.Now(), Channel: channel, Version: version})
+	if err == nil {
+		err = utils.WriteFile(config.Get().ReleaseCheckFile(), data)
+	}
+	if err != nil {
+		log.Debug("failed to save release check", "err", err)
+	}
+}
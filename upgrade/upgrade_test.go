@@ -0,0 +1,130 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsNewer(t *testing.T) {
+	cases := []struct {
+		version string
+		current string
+		want    bool
+	}{
+		{"1.5.0", "1.4.2", true},
+		{"1.4.2", "1.4.2", false},
+		{"1.4.0", "1.4.2", false},
+		{"1.5.0-nightly.20240301", "1.4.2", true},
+		{"1.5.0", "1.5.0-nightly.20240301", true},
+		{"1.5.0", "dev", false},
+	}
+	for _, c := range cases {
+		if got := IsNewer(c.version, c.current); got != c.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", c.version, c.current, got, c.want)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	cases := []struct {
+		goos, goarch, want string
+	}{
+		{"darwin", "arm64", "leetgo_macOS_arm64.tar.gz"},
+		{"linux", "amd64", "leetgo_linux_x86_64.tar.gz"},
+		{"windows", "amd64", "leetgo_windows_x86_64.zip"},
+	}
+	for _, c := range cases {
+		if got := AssetName(c.goos, c.goarch); got != c.want {
+			t.Errorf("AssetName(%q, %q) = %q, want %q", c.goos, c.goarch, got, c.want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	data := []byte("abc123  leetgo_linux_x86_64.tar.gz\nDEF456  leetgo_windows_x86_64.zip\n")
+	got, err := parseChecksums(data, "leetgo_windows_x86_64.zip")
+	if err != nil || got != "def456" {
+		t.Errorf("parseChecksums() = %q, %v", got, err)
+	}
+	if _, err := parseChecksums(data, "leetgo_macOS_arm64.tar.gz"); err == nil {
+		t.Errorf("missing checksum should fail")
+	}
+}
+
+func TestHighestRelease(t *testing.T) {
+	releases := []Release{
+		{TagName: "testutils/python/v0.2.0"},
+		{TagName: "v1.5.0-nightly.20240301", Prerelease: true},
+		{TagName: "v1.6.0", Draft: true},
+		{TagName: "v1.4.2"},
+	}
+	r, err := highestRelease(releases)
+	if err != nil || r.TagName != "v1.5.0-nightly.20240301" {
+		t.Errorf("highestRelease() = %v, %v", r, err)
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"LICENSE": "MIT", "leetgo": "binary"} {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+
+	bin, err := extractTarGz(buf.Bytes(), "leetgo")
+	if err != nil || string(bin) != "binary" {
+		t.Errorf("extractTarGz() = %q, %v", bin, err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "leetgo")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(exe)
+	info, _ := os.Stat(exe)
+	if string(data) != "new" || info.Mode().Perm() != 0o755 {
+		t.Errorf("replaced binary = %q, mode %v", data, info.Mode().Perm())
+	}
+}
+
+func TestDownloadStalled(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("part"))
+				if r.URL.Path == "/stalled" {
+					w.(http.Flusher).Flush()
+					<-r.Context().Done()
+				}
+			},
+		),
+	)
+	defer srv.Close()
+	timeout := stallTimeout
+	stallTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { stallTimeout = timeout })
+
+	if data, err := download(context.Background(), srv.URL+"/complete"); err != nil || string(data) != "part" {
+		t.Errorf("download() = %q, %v", data, err)
+	}
+	if _, err := download(context.Background(), srv.URL+"/stalled"); err == nil {
+		t.Errorf("stalled download should fail")
+	}
+}
@@ -2,8 +2,12 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
+	"time"
 )
 
 // IsExist checks if a file or directory exists
@@ -18,6 +22,62 @@ func IsExist(path string) bool {
 	return false
 }
 
+// CheckWritable checks that files can be created in dir, creating the directory if needed.
+// It detects permission and read-only filesystem errors before anything is written.
+func CheckWritable(dir string) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".leetgo-write-test-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// Timing of LockFile: how long to wait for another process, and how old a lock is taken as left by a crashed one.
+const (
+	lockTimeout  = 5 * time.Second
+	lockStaleAge = 30 * time.Second
+	lockRetry    = 20 * time.Millisecond
+)
+
+// LockFile takes an advisory lock on file across processes, by creating file.lock exclusively.
+// It waits while another process holds the lock, a lock older than lockStaleAge is removed as stale.
+// The returned function releases the lock.
+func LockFile(file string) (func(), error) {
+	lock := file + ".lock"
+	if err := MakeDir(filepath.Dir(lock)); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if fi, err := os.Stat(lock); err == nil && time.Since(fi.ModTime()) > lockStaleAge {
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s, remove it if no other leetgo is running", lock)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// IsPermissionError reports whether err is caused by lack of permission or a read-only filesystem.
+func IsPermissionError(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
+
 func MakeDir(dir string) error {
 	return os.MkdirAll(dir, 0o755)
 }
@@ -45,6 +105,7 @@ func CreateIfNotExists(path string, isDir bool) error {
 }
 
 func WriteFile(file string, content []byte) error {
+	defer Track(ProfileDisk)()
 	err := CreateIfNotExists(file, false)
 	if err != nil {
 		return err
@@ -57,6 +118,7 @@ func WriteFile(file string, content []byte) error {
 }
 
 func WriteOrAppendFile(file string, content []byte) error {
+	defer Track(ProfileDisk)()
 	_, err := os.Stat(file)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -111,3 +173,26 @@ func RelToCwd(path string) string {
 	}
 	return filepath.ToSlash(relPath)
 }
+
+// OpenLogFile opens the log file for appending. It's rotated before opening if it has grown over maxSize bytes:
+// file is renamed to file.1, file.1 to file.2 and so on, at most backups old files are kept.
+func OpenLogFile(file string, maxSize int64, backups int) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(file); err == nil && info.Size() >= maxSize {
+		_ = os.Remove(fmt.Sprintf("%s.%d", file, backups))
+		for i := backups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", file, i), fmt.Sprintf("%s.%d", file, i+1))
+		}
+		if backups > 0 {
+			err = os.Rename(file, file+".1")
+		} else {
+			err = os.Remove(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
@@ -0,0 +1,80 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/utils"
+)
+
+func TestOpenLogFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "leetgo.log")
+	for _, content := range []string{"first", "second", "third"} {
+		f, err := utils.OpenLogFile(file, 5, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.WriteString(content)
+		_ = f.Close()
+	}
+
+	cases := map[string]string{
+		file:        "third",
+		file + ".1": "second",
+	}
+	for name, want := range cases {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", filepath.Base(name), got, err, want)
+		}
+	}
+	if utils.IsExist(file + ".2") {
+		t.Errorf("only one old file should be kept")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "jobs.json")
+	unlock, err := utils.LockFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := utils.LockFile(file)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("lock should be held until released")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("lock should be taken after released")
+	}
+
+	// A lock left by a crashed process is taken over.
+	stale := time.Now().Add(-time.Hour)
+	if err := os.WriteFile(file+".lock", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file+".lock", stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = utils.LockFile(file)
+	if err != nil {
+		t.Fatalf("stale lock should be removed: %v", err)
+	}
+	unlock()
+}
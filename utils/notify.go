@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// powershellAppID is the AppUserModelID of Windows PowerShell. Windows only shows toasts of registered apps,
+// leetgo is not installed as one, so its toasts are shown as from PowerShell.
+const powershellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// Notify shows a desktop notification by notify-send on Linux, osascript on macOS and a toast on Windows.
+func Notify(title string, message string) error {
+	cmd := notifyCommand(runtime.GOOS, title, message)
+	done := Track(ProfileSubprocess)
+	defer done()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// notifyCommand returns the command showing the notification on goos.
+func notifyCommand(goos string, title string, message string) *exec.Cmd {
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, escape(message), escape(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		escape := strings.NewReplacer(`'`, `''`).Replace
+		script := fmt.Sprintf(
+			`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode('%s')) > $null
+$x.Item(1).AppendChild($t.CreateTextNode('%s')) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('%s').Show([Windows.UI.Notifications.ToastNotification]::new($t))`,
+			escape(title),
+			escape(message),
+			powershellAppID,
+		)
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		cmd = exec.Command("notify-send", "--app-name", "leetgo", title, message)
+	}
+	return cmd
+}
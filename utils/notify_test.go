@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	title, message := `Time's up`, `"two-sum" \ 25m`
+
+	cmd := notifyCommand("linux", title, message)
+	if want := []string{"notify-send", "--app-name", "leetgo", title, message}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("linux: args = %q, want %q", cmd.Args, want)
+	}
+
+	cmd = notifyCommand("darwin", title, message)
+	want := `display notification "\"two-sum\" \\ 25m" with title "Time's up"`
+	if len(cmd.Args) != 3 || cmd.Args[0] != "osascript" || cmd.Args[2] != want {
+		t.Errorf("darwin: args = %q, want script %q", cmd.Args, want)
+	}
+
+	cmd = notifyCommand("windows", title, message)
+	script := cmd.Args[len(cmd.Args)-1]
+	for _, s := range []string{
+		`CreateTextNode('Time''s up')`,
+		`CreateTextNode('"two-sum" \ 25m')`,
+		`CreateToastNotifier('` + powershellAppID + `')`,
+	} {
+		if !strings.Contains(script, s) {
+			t.Errorf("windows: script does not contain %q:\n%s", s, script)
+		}
+	}
+	if cmd.Args[0] != "powershell" {
+		t.Errorf("windows: args = %q", cmd.Args)
+	}
+}
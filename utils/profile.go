@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime/pprof"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Categories of time spent, reported by --profile.
+const (
+	ProfileNetwork    = "network"
+	ProfileDisk       = "disk"
+	ProfileSubprocess = "subprocess"
+	ProfileTemplate   = "template"
+)
+
+var profileCategories = []string{ProfileNetwork, ProfileDisk, ProfileSubprocess, ProfileTemplate}
+
+type profileEntry struct {
+	count   int
+	elapsed time.Duration
+}
+
+// profileSection is an operation being timed.
+type profileSection struct {
+	category string
+}
+
+var profiler struct {
+	enabled atomic.Bool
+	mu      sync.Mutex
+	start   time.Time
+	entries map[string]*profileEntry
+	cpuFile *os.File
+	// active sections in the order they started, and the time of the last start or stop.
+	active    []*profileSection
+	lastEvent time.Time
+}
+
+// profileNow returns the current time, it's replaced in tests.
+var profileNow = time.Now
+
+// Track starts timing an operation of the category, the returned function stops it.
+// It's a no-op unless profiling is enabled, usage: defer utils.Track(utils.ProfileDisk)()
+// Time is only counted for the innermost section, e.g. a subprocess started in a network section,
+// so that nested or concurrent sections don't count the same time twice.
+func Track(category string) func() {
+	if !profiler.enabled.Load() {
+		return func() {}
+	}
+	s := &profileSection{category: category}
+	profiler.mu.Lock()
+	chargeActive(profileNow())
+	profiler.active = append(profiler.active, s)
+	profiler.mu.Unlock()
+	return func() {
+		profiler.mu.Lock()
+		defer profiler.mu.Unlock()
+		chargeActive(profileNow())
+		if i := slices.Index(profiler.active, s); i >= 0 {
+			profiler.active = slices.Delete(profiler.active, i, i+1)
+		}
+		profileEntryOf(category).count++
+	}
+}
+
+// chargeActive counts the time since the last start or stop to the section started last, profiler.mu must be held.
+func chargeActive(now time.Time) {
+	if n := len(profiler.active); n > 0 {
+		profileEntryOf(profiler.active[n-1].category).elapsed += now.Sub(profiler.lastEvent)
+	}
+	profiler.lastEvent = now
+}
+
+func profileEntryOf(category string) *profileEntry {
+	e := profiler.entries[category]
+	if e == nil {
+		e = &profileEntry{}
+		profiler.entries[category] = e
+	}
+	return e
+}
+
+// bytesTransferred is the number of bytes sent and received over HTTP by tracked transports.
+var bytesTransferred atomic.Int64
+
+// BytesTransferred returns the number of bytes of request and response bodies transferred so far,
+// response bodies are counted as received, i.e. before decompression.
+func BytesTransferred() int64 {
+	return bytesTransferred.Load()
+}
+
+// FormatBytes formats a byte count for humans, e.g. 1536 is "1.5 KB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// countingReader counts the bytes read from the response body.
+type countingReader struct {
+	io.ReadCloser
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	bytesTransferred.Add(int64(n))
+	return n, err
+}
+
+// trackedTransport records the time of HTTP round trips as network time, and counts the bytes transferred.
+type trackedTransport struct {
+	http.RoundTripper
+}
+
+func (t trackedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer Track(ProfileNetwork)()
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if req.ContentLength > 0 {
+		bytesTransferred.Add(req.ContentLength)
+	}
+	if resp.Body != nil {
+		resp.Body = countingReader{resp.Body}
+	}
+	return resp, nil
+}
+
+// TrackTransport wraps the transport to record network time and bytes transferred.
+func TrackTransport(rt http.RoundTripper) http.RoundTripper {
+	return trackedTransport{rt}
+}
+
+// TrackDefaultTransport wraps http.DefaultTransport to record network time and bytes transferred, once.
+func TrackDefaultTransport() {
+	if _, ok := http.DefaultTransport.(trackedTransport); !ok {
+		http.DefaultTransport = TrackTransport(http.DefaultTransport)
+	}
+}
+
+// StartProfile enables timing of operations, and writes a CPU profile in pprof format to cpuFile if it's not empty.
+func StartProfile(cpuFile string) error {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	if profiler.enabled.Load() {
+		return nil
+	}
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+		if err != nil {
+			return err
+		}
+		err = pprof.StartCPUProfile(f)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		profiler.cpuFile = f
+	}
+	profiler.start = profileNow()
+	profiler.entries = make(map[string]*profileEntry)
+	profiler.active = nil
+	TrackDefaultTransport()
+	profiler.enabled.Store(true)
+	return nil
+}
+
+// StopProfile stops profiling and writes the report of time spent to w.
+func StopProfile(w io.Writer) {
+	if !profiler.enabled.Swap(false) {
+		return
+	}
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	if profiler.cpuFile != nil {
+		pprof.StopCPUProfile()
+		_ = profiler.cpuFile.Close()
+		_, _ = fmt.Fprintf(w, "CPU profile written to %s\n", profiler.cpuFile.Name())
+	}
+
+	now := profileNow()
+	chargeActive(now)
+	total := now.Sub(profiler.start)
+	_, _ = fmt.Fprintf(
+		w,
+		"Time spent (total %s, %s transferred):\n",
+		total.Round(time.Millisecond),
+		FormatBytes(BytesTransferred()),
+	)
+	for _, category := range profileCategories {
+		e := profiler.entries[category]
+		if e == nil {
+			e = &profileEntry{}
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			"  %-12s %10s  %5.1f%%  %d calls\n",
+			category,
+			e.elapsed.Round(time.Microsecond),
+			float64(e.elapsed)/float64(total)*100,
+			e.count,
+		)
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrack(t *testing.T) {
+	now := time.Unix(0, 0)
+	profileNow = func() time.Time { return now }
+	t.Cleanup(func() { profileNow = time.Now })
+	elapse := func(d time.Duration) { now = now.Add(d) }
+
+	// Disabled profiling tracks nothing.
+	Track(ProfileDisk)()
+
+	if err := StartProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { StopProfile(&strings.Builder{}) })
+
+	stopNetwork := Track(ProfileNetwork)
+	elapse(time.Second)
+	// A subprocess started in a network section is not network time.
+	stopSubprocess := Track(ProfileSubprocess)
+	elapse(2 * time.Second)
+	stopSubprocess()
+	elapse(time.Second)
+	// Nor are concurrent requests counted twice.
+	stopRequest := Track(ProfileNetwork)
+	elapse(time.Second)
+	stopNetwork()
+	elapse(time.Second)
+	stopRequest()
+	elapse(time.Second)
+	done := Track(ProfileDisk)
+	elapse(3 * time.Second)
+	done()
+
+	var sb strings.Builder
+	StopProfile(&sb)
+	want := []string{
+		"Time spent (total 10s, ",
+		"  network              4s   40.0%  2 calls",
+		"  disk                 3s   30.0%  1 calls",
+		"  subprocess           2s   20.0%  1 calls",
+		"  template             0s    0.0%  0 calls",
+	}
+	for _, line := range want {
+		if !strings.Contains(sb.String(), line) {
+			t.Errorf("report %q, want line %q", sb.String(), line)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package utils
 import (
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf16"
 	"unsafe"
@@ -195,3 +197,22 @@ func DecodeRawUnicodeEscape(s string) string {
 	}
 	return buf.String()
 }
+
+// ParseAge parses a duration like "30d" or "2w", with days and weeks in addition to the units of time.ParseDuration.
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 30d, 2w or 12h", s)
+	}
+	return d, nil
+}
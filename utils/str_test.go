@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/j178/leetgo/utils"
 )
@@ -76,3 +77,25 @@ func TestDecodeRawUnicodeEscape(t *testing.T) {
 		)
 	}
 }
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := utils.ParseAge(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+	for _, input := range []string{"", "d", "-1d", "30 days"} {
+		if _, err := utils.ParseAge(input); err == nil {
+			t.Errorf("ParseAge(%q) should fail", input)
+		}
+	}
+}